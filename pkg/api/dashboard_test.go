@@ -126,7 +126,7 @@ func newTestLive(t *testing.T, store db.DB) *live.GrafanaLive {
 	cfg.AppURL = "http://localhost:3000/"
 	gLive, err := live.ProvideService(nil, cfg,
 		routing.NewRouteRegister(),
-		nil, nil, nil, nil,
+		nil, nil, nil, nil, nil,
 		store,
 		nil,
 		&usagestats.UsageStatsMock{T: t},
@@ -226,6 +226,39 @@ grafana-cli user-manager conflicts ingest-file <filepath>
 	},
 }
 
+var alertingCommands = []*cli.Command{
+	{
+		Name:   "import-prometheus-rules",
+		Usage:  "import-prometheus-rules <rule file>",
+		Action: runPluginCommand(importPrometheusRulesCommand),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "url",
+				Usage: "URL of the Grafana instance",
+				Value: "http://localhost:3000",
+			},
+			&cli.StringFlag{
+				Name:    "token",
+				Usage:   "Service account token used to authenticate with Grafana",
+				EnvVars: []string{"GF_CLI_TOKEN"},
+			},
+			&cli.StringFlag{
+				Name:  "folder-uid",
+				Usage: "UID of the folder the rules are imported to",
+			},
+			&cli.StringFlag{
+				Name:  "datasource-uid",
+				Usage: "UID of the Prometheus data source the rules query",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Print the changes without saving them",
+				Value: false,
+			},
+		},
+	},
+}
+
 var Commands = []*cli.Command{
 	{
 		Name:        "plugins",
@@ -237,4 +270,9 @@ var Commands = []*cli.Command{
 		Usage:       "Grafana admin commands",
 		Subcommands: adminCommands,
 	},
+	{
+		Name:        "alerting",
+		Usage:       "Manage alert rules of a Grafana instance",
+		Subcommands: alertingCommands,
+	},
 }
@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/services"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+type importRulesOptions struct {
+	grafanaURL    string
+	token         string
+	folderUID     string
+	datasourceUID string
+	dryRun        bool
+}
+
+func importPrometheusRulesCommand(c utils.CommandLine) error {
+	file := c.Args().First()
+	if file == "" {
+		return errors.New("please specify the Prometheus rule file to import")
+	}
+	opts := importRulesOptions{
+		grafanaURL:    c.String("url"),
+		token:         c.String("token"),
+		folderUID:     c.String("folder-uid"),
+		datasourceUID: c.String("datasource-uid"),
+		dryRun:        c.Bool("dry-run"),
+	}
+	if opts.folderUID == "" {
+		return errors.New("please specify the UID of the folder to import the rules to with --folder-uid")
+	}
+	if opts.datasourceUID == "" {
+		return errors.New("please specify the UID of the Prometheus data source with --datasource-uid")
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read the rule file: %w", err)
+	}
+	result, err := importPrometheusRules(services.HttpClient, opts, content)
+	if err != nil {
+		return err
+	}
+	logger.Info(formatImportReport(result))
+	return nil
+}
+
+// importPrometheusRules posts the Prometheus rule file to the rule import API of Grafana.
+func importPrometheusRules(client http.Client, opts importRulesOptions, content []byte) (apimodels.ImportPrometheusRulesResponse, error) {
+	var result apimodels.ImportPrometheusRulesResponse
+	u, err := url.Parse(strings.TrimSuffix(opts.grafanaURL, "/") + "/api/ruler/grafana/api/v1/rules/" + url.PathEscape(opts.folderUID) + "/import/prometheus")
+	if err != nil {
+		return result, fmt.Errorf("invalid Grafana URL: %w", err)
+	}
+	u.RawQuery = url.Values{
+		"datasourceUid": []string{opts.datasourceUID},
+		"dryRun":        []string{strconv.FormatBool(opts.dryRun)},
+	}.Encode()
+
+	req, err := http.NewRequest(http.MethodPost, u.String(), bytes.NewReader(content))
+	if err != nil {
+		return result, err
+	}
+	req.Header.Set("Content-Type", "application/yaml")
+	req.Header.Set("User-Agent", "grafana "+services.GrafanaVersion)
+	if opts.token != "" {
+		req.Header.Set("Authorization", "Bearer "+opts.token)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return result, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return result, err
+	}
+	if res.StatusCode != http.StatusAccepted {
+		var errResp struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(body, &errResp); err == nil && errResp.Message != "" {
+			return result, &services.BadRequestError{Status: res.Status, Message: errResp.Message}
+		}
+		return result, &services.BadRequestError{Status: res.Status}
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return result, fmt.Errorf("failed to parse the response: %w", err)
+	}
+	return result, nil
+}
+
+func formatImportReport(result apimodels.ImportPrometheusRulesResponse) string {
+	b := strings.Builder{}
+	if result.DryRun {
+		b.WriteString(color.YellowString("Dry run, no changes were saved.\n"))
+	}
+	for _, group := range result.Groups {
+		fmt.Fprintf(&b, "\nRule group %s\n", color.CyanString(group.Name))
+		if len(group.Created)+len(group.Updated)+len(group.Deleted) == 0 {
+			b.WriteString("  no changes\n")
+		}
+		for _, title := range group.Created {
+			b.WriteString(color.GreenString("  + %s\n", title))
+		}
+		for _, update := range group.Updated {
+			b.WriteString(color.YellowString("  ~ %s (%s)\n", update.Title, update.UID))
+			for _, diff := range update.Diff {
+				fmt.Fprintf(&b, "      %s\n", strings.ReplaceAll(diff, "\n", "\n      "))
+			}
+		}
+		for _, title := range group.Deleted {
+			b.WriteString(color.RedString("  - %s\n", title))
+		}
+		for _, skipped := range group.Skipped {
+			fmt.Fprintf(&b, "  skipped %s: %s\n", skipped.Name, skipped.Reason)
+		}
+	}
+	b.WriteString("\n")
+	return b.String()
+}
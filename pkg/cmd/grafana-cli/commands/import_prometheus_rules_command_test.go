@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestImportPrometheusRules(t *testing.T) {
+	const ruleFile = "groups: []\n"
+
+	t.Run("should post the rule file to the import API", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/api/ruler/grafana/api/v1/rules/folder/import/prometheus", r.URL.Path)
+			assert.Equal(t, "prom", r.URL.Query().Get("datasourceUid"))
+			assert.Equal(t, "true", r.URL.Query().Get("dryRun"))
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, ruleFile, string(body))
+
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"dryRun":true,"groups":[{"name":"api","created":["HighErrorRate"],"updated":[{"uid":"abc","title":"InstanceDown","diff":["For"]}],"deleted":["Stale"]}]}`))
+		}))
+		defer server.Close()
+
+		result, err := importPrometheusRules(http.Client{}, importRulesOptions{
+			grafanaURL:    server.URL + "/",
+			token:         "token",
+			folderUID:     "folder",
+			datasourceUID: "prom",
+			dryRun:        true,
+		}, []byte(ruleFile))
+		require.NoError(t, err)
+		require.Equal(t, apimodels.ImportPrometheusRulesResponse{
+			DryRun: true,
+			Groups: []apimodels.ImportedRuleGroupResponse{{
+				Name:    "api",
+				Created: []string{"HighErrorRate"},
+				Updated: []apimodels.ImportedRuleUpdate{{UID: "abc", Title: "InstanceDown", Diff: []string{"For"}}},
+				Deleted: []string{"Stale"},
+			}},
+		}, result)
+
+		report := formatImportReport(result)
+		require.Contains(t, report, "HighErrorRate")
+		require.Contains(t, report, "InstanceDown (abc)")
+		require.Contains(t, report, "Stale")
+	})
+
+	t.Run("should return the error message of the API", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message":"invalid Prometheus rule file"}`))
+		}))
+		defer server.Close()
+
+		_, err := importPrometheusRules(http.Client{}, importRulesOptions{grafanaURL: server.URL, folderUID: "folder", datasourceUID: "prom"}, []byte(ruleFile))
+		require.ErrorContains(t, err, "invalid Prometheus rule file")
+	})
+}
@@ -0,0 +1,348 @@
+package expr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// AnomalyBandLabel is the label added to the upper and lower band series returned by AnomalyCommand
+// so they can be told apart from the anomaly flag series, which keeps the labels of the input series.
+const AnomalyBandLabel = "anomaly_band"
+
+const (
+	anomalyBandUpper = "upper"
+	anomalyBandLower = "lower"
+
+	defaultAnomalyDeviations = 3.0
+
+	// madToStdDev scales the median absolute deviation so it is comparable to the standard deviation of normally distributed data.
+	madToStdDev = 1.4826
+)
+
+var supportedAnomalyMethods = []string{string(AnomalyMethodZScore), string(AnomalyMethodMAD), string(AnomalyMethodSeasonal)}
+
+// AnomalyCommand is an expression command that detects anomalies in time series without any external service.
+// For each point it calculates the expected band of values from the other points of the series, and flags the
+// point with 1 if the value is outside the band and 0 otherwise.
+type AnomalyCommand struct {
+	VarToCheck string
+	Method     AnomalyMethod
+	Deviations float64
+	// Window limits the baseline of the zscore and mad methods to the points in the window before each point.
+	// The whole series is used if it is zero.
+	Window time.Duration
+	// Season is the distance between a point and the point it is compared to by the seasonal method.
+	Season time.Duration
+	Output AnomalyOutput
+	refID  string
+}
+
+// NewAnomalyCommand creates a new AnomalyCommand.
+func NewAnomalyCommand(refID, varToCheck string, method AnomalyMethod, deviations float64, rawWindow, rawSeason string, output AnomalyOutput) (*AnomalyCommand, error) {
+	cmd := &AnomalyCommand{
+		VarToCheck: varToCheck,
+		Method:     method,
+		Deviations: deviations,
+		Output:     output,
+		refID:      refID,
+	}
+	if cmd.Deviations == 0 {
+		cmd.Deviations = defaultAnomalyDeviations
+	}
+	if cmd.Deviations < 0 {
+		return nil, fmt.Errorf("anomaly deviations must be a positive number, got %v", deviations)
+	}
+	switch output {
+	case "":
+		cmd.Output = AnomalyOutputAll
+	case AnomalyOutputAll, AnomalyOutputFlag:
+	default:
+		return nil, fmt.Errorf("anomaly output '%s' is not supported. Supported only: [%s,%s]", output, AnomalyOutputAll, AnomalyOutputFlag)
+	}
+
+	var err error
+	if rawWindow != "" {
+		cmd.Window, err = gtime.ParseDuration(rawWindow)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to parse anomaly "window" duration field %q: %w`, rawWindow, err)
+		}
+	}
+	switch method {
+	case AnomalyMethodZScore, AnomalyMethodMAD:
+	case AnomalyMethodSeasonal:
+		if rawSeason == "" {
+			return nil, errors.New("the seasonal anomaly method requires a season, for example 1d or 1w")
+		}
+		cmd.Season, err = gtime.ParseDuration(rawSeason)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to parse anomaly "season" duration field %q: %w`, rawSeason, err)
+		}
+		if cmd.Season <= 0 {
+			return nil, fmt.Errorf("anomaly season must be greater than zero, got %s", rawSeason)
+		}
+	default:
+		return nil, fmt.Errorf("expected anomaly method to be one of [%s], got %s", strings.Join(supportedAnomalyMethods, ", "), method)
+	}
+	return cmd, nil
+}
+
+// UnmarshalAnomalyCommand creates an AnomalyCommand from Grafana's frontend query.
+func UnmarshalAnomalyCommand(rn *rawNode) (*AnomalyCommand, error) {
+	rawVar, ok := rn.Query["expression"]
+	if !ok {
+		return nil, errors.New("no expression ID to check for anomalies. must be a reference to an existing query or expression")
+	}
+	varToCheck, ok := rawVar.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected anomaly input variable to be type string, but got type %T", rawVar)
+	}
+	varToCheck = strings.TrimPrefix(varToCheck, "$")
+
+	rawMethod, ok := rn.Query["method"]
+	if !ok {
+		return nil, errors.New("no method specified in anomaly command")
+	}
+	method, ok := rawMethod.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected anomaly method to be a string, got type %T", rawMethod)
+	}
+
+	var deviations float64
+	if rawDeviations, ok := rn.Query["deviations"]; ok {
+		deviations, ok = rawDeviations.(float64)
+		if !ok {
+			return nil, fmt.Errorf("expected anomaly deviations to be a number, got type %T", rawDeviations)
+		}
+	}
+
+	var window, season, output string
+	for key, dst := range map[string]*string{"window": &window, "season": &season, "output": &output} {
+		raw, ok := rn.Query[key]
+		if !ok {
+			continue
+		}
+		if *dst, ok = raw.(string); !ok {
+			return nil, fmt.Errorf("expected anomaly %s to be a string, got type %T", key, raw)
+		}
+	}
+
+	return NewAnomalyCommand(rn.RefID, varToCheck, AnomalyMethod(method), deviations, window, season, AnomalyOutput(output))
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (ac *AnomalyCommand) NeedsVars() []string {
+	return []string{ac.VarToCheck}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (ac *AnomalyCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteAnomaly")
+	defer span.End()
+	span.SetAttributes(attribute.String("method", string(ac.Method)))
+
+	newRes := mathexp.Results{}
+	for _, val := range vars[ac.VarToCheck].Values {
+		switch v := val.(type) {
+		case mathexp.Series:
+			flag, upper, lower := ac.detect(v)
+			newRes.Values = append(newRes.Values, flag)
+			if ac.Output == AnomalyOutputAll {
+				newRes.Values = append(newRes.Values, upper, lower)
+			}
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, v.New())
+		default:
+			return newRes, fmt.Errorf("can only detect anomalies in type series, got type %v", val.Type())
+		}
+	}
+	return newRes, nil
+}
+
+func (ac *AnomalyCommand) Type() string {
+	return TypeAnomaly.String()
+}
+
+type anomalyPoint struct {
+	t time.Time
+	v *float64
+}
+
+// detect returns the anomaly flag series and the upper and lower band series for the input series.
+// Points where the band cannot be calculated, because there are not enough numeric points in the baseline,
+// have null bands and a null flag. Null and NaN input values also result in a null flag.
+func (ac *AnomalyCommand) detect(s mathexp.Series) (flag, upper, lower mathexp.Series) {
+	points := make([]anomalyPoint, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		points[i].t, points[i].v = s.GetPoint(i)
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].t.Before(points[j].t)
+	})
+
+	flag = mathexp.NewSeries(ac.refID, s.GetLabels(), len(points))
+	upper = mathexp.NewSeries(ac.refID, bandLabels(s.GetLabels(), anomalyBandUpper), len(points))
+	lower = mathexp.NewSeries(ac.refID, bandLabels(s.GetLabels(), anomalyBandLower), len(points))
+
+	var bandAt func(i int) (float64, float64, bool)
+	switch ac.Method {
+	case AnomalyMethodSeasonal:
+		bandAt = ac.seasonalBand(points)
+	default:
+		bandAt = ac.baselineBand(points)
+	}
+
+	for i, p := range points {
+		lo, hi, ok := bandAt(i)
+		if !ok {
+			flag.SetPoint(i, p.t, nil)
+			upper.SetPoint(i, p.t, nil)
+			lower.SetPoint(i, p.t, nil)
+			continue
+		}
+		upper.SetPoint(i, p.t, &hi)
+		lower.SetPoint(i, p.t, &lo)
+		if !isNumber(p.v) {
+			flag.SetPoint(i, p.t, nil)
+			continue
+		}
+		f := float64(0)
+		if *p.v > hi || *p.v < lo {
+			f = 1
+		}
+		flag.SetPoint(i, p.t, &f)
+	}
+	return flag, upper, lower
+}
+
+// baselineBand returns a function that calculates the band at a point from the centre and spread of the baseline.
+// The baseline is either the whole series or, if the command has a window, the points in the window before the point.
+func (ac *AnomalyCommand) baselineBand(points []anomalyPoint) func(i int) (float64, float64, bool) {
+	centreAndSpread := meanAndStdDev
+	if ac.Method == AnomalyMethodMAD {
+		centreAndSpread = medianAndMAD
+	}
+
+	if ac.Window == 0 {
+		values := make([]float64, 0, len(points))
+		for _, p := range points {
+			if isNumber(p.v) {
+				values = append(values, *p.v)
+			}
+		}
+		centre, spread, ok := centreAndSpread(values)
+		return func(int) (float64, float64, bool) {
+			return centre - ac.Deviations*spread, centre + ac.Deviations*spread, ok
+		}
+	}
+
+	return func(i int) (float64, float64, bool) {
+		from := points[i].t.Add(-ac.Window)
+		var values []float64
+		for j := i - 1; j >= 0 && !points[j].t.Before(from); j-- {
+			if isNumber(points[j].v) {
+				values = append(values, *points[j].v)
+			}
+		}
+		centre, spread, ok := centreAndSpread(values)
+		return centre - ac.Deviations*spread, centre + ac.Deviations*spread, ok
+	}
+}
+
+// seasonalBand returns a function that calculates the band at a point from the value one season earlier.
+// The width of the band is based on the standard deviation of the differences between all points and their previous season.
+func (ac *AnomalyCommand) seasonalBand(points []anomalyPoint) func(i int) (float64, float64, bool) {
+	previous := make([]*float64, len(points))
+	var residuals []float64
+	j := 0
+	for i, p := range points {
+		target := p.t.Add(-ac.Season)
+		// find the last point at or before the same time in the previous season
+		for j < i && !points[j].t.After(target) {
+			j++
+		}
+		if j == 0 || !isNumber(points[j-1].v) || target.Sub(points[j-1].t) >= ac.Season {
+			continue
+		}
+		previous[i] = points[j-1].v
+		if isNumber(p.v) {
+			residuals = append(residuals, *p.v-*previous[i])
+		}
+	}
+	_, spread, ok := meanAndStdDev(residuals)
+
+	return func(i int) (float64, float64, bool) {
+		if !ok || previous[i] == nil {
+			return 0, 0, false
+		}
+		return *previous[i] - ac.Deviations*spread, *previous[i] + ac.Deviations*spread, true
+	}
+}
+
+// meanAndStdDev returns the mean and the population standard deviation of the values.
+// It returns false if there are fewer than two values.
+func meanAndStdDev(values []float64) (float64, float64, bool) {
+	if len(values) < 2 {
+		return 0, 0, false
+	}
+	var mean float64
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	var sumSq float64
+	for _, v := range values {
+		sumSq += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(sumSq / float64(len(values))), true
+}
+
+// medianAndMAD returns the median and the median absolute deviation of the values scaled to the standard deviation.
+// It returns false if there are fewer than two values.
+func medianAndMAD(values []float64) (float64, float64, bool) {
+	if len(values) < 2 {
+		return 0, 0, false
+	}
+	m := median(values)
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - m)
+	}
+	return m, madToStdDev * median(deviations), true
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	l := len(sorted)
+	if l%2 == 1 {
+		return sorted[l/2]
+	}
+	return (sorted[l/2-1] + sorted[l/2]) / 2
+}
+
+func isNumber(f *float64) bool {
+	return f != nil && !math.IsNaN(*f) && !math.IsInf(*f, 0)
+}
+
+func bandLabels(labels data.Labels, band string) data.Labels {
+	l := labels.Copy()
+	if l == nil {
+		l = data.Labels{}
+	}
+	l[AnomalyBandLabel] = band
+	return l
+}
@@ -0,0 +1,202 @@
+package expr
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestNewAnomalyCommand(t *testing.T) {
+	t.Run("should use defaults", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", AnomalyMethodZScore, 0, "", "", "")
+		require.NoError(t, err)
+		assert.Equal(t, defaultAnomalyDeviations, cmd.Deviations)
+		assert.Equal(t, AnomalyOutputAll, cmd.Output)
+		assert.Zero(t, cmd.Window)
+		assert.Equal(t, []string{"A"}, cmd.NeedsVars())
+	})
+
+	t.Run("should parse window and season", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", AnomalyMethodSeasonal, 2, "1h", "1w", AnomalyOutputFlag)
+		require.NoError(t, err)
+		assert.Equal(t, time.Hour, cmd.Window)
+		assert.Equal(t, 7*24*time.Hour, cmd.Season)
+	})
+
+	errorCases := map[string]func() (*AnomalyCommand, error){
+		"unknown method": func() (*AnomalyCommand, error) {
+			return NewAnomalyCommand("B", "A", "prophet", 0, "", "", "")
+		},
+		"negative deviations": func() (*AnomalyCommand, error) {
+			return NewAnomalyCommand("B", "A", AnomalyMethodMAD, -1, "", "", "")
+		},
+		"unknown output": func() (*AnomalyCommand, error) {
+			return NewAnomalyCommand("B", "A", AnomalyMethodMAD, 0, "", "", "bands")
+		},
+		"invalid window": func() (*AnomalyCommand, error) {
+			return NewAnomalyCommand("B", "A", AnomalyMethodZScore, 0, "often", "", "")
+		},
+		"seasonal without season": func() (*AnomalyCommand, error) {
+			return NewAnomalyCommand("B", "A", AnomalyMethodSeasonal, 0, "", "", "")
+		},
+		"seasonal with negative season": func() (*AnomalyCommand, error) {
+			return NewAnomalyCommand("B", "A", AnomalyMethodSeasonal, 0, "", "-1d", "")
+		},
+	}
+	for name, newCmd := range errorCases {
+		t.Run("should fail on "+name, func(t *testing.T) {
+			_, err := newCmd()
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestUnmarshalAnomalyCommand(t *testing.T) {
+	cmd, err := UnmarshalAnomalyCommand(&rawNode{
+		RefID: "B",
+		Query: map[string]any{
+			"expression": "$A",
+			"method":     "mad",
+			"deviations": 2.5,
+			"window":     "30m",
+			"output":     "flag",
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "A", cmd.VarToCheck)
+	assert.Equal(t, AnomalyMethodMAD, cmd.Method)
+	assert.Equal(t, 2.5, cmd.Deviations)
+	assert.Equal(t, 30*time.Minute, cmd.Window)
+	assert.Equal(t, AnomalyOutputFlag, cmd.Output)
+
+	_, err = UnmarshalAnomalyCommand(&rawNode{RefID: "B", Query: map[string]any{"expression": "$A"}})
+	require.ErrorContains(t, err, "no method")
+
+	_, err = UnmarshalAnomalyCommand(&rawNode{RefID: "B", Query: map[string]any{"expression": "$A", "method": "zscore", "deviations": "3"}})
+	require.Error(t, err)
+}
+
+func TestAnomalyCommand_Execute(t *testing.T) {
+	start := time.Unix(0, 0)
+	newSeries := func(labels data.Labels, step time.Duration, values ...*float64) mathexp.Series {
+		s := mathexp.NewSeries("A", labels, len(values))
+		for i, v := range values {
+			s.SetPoint(i, start.Add(time.Duration(i)*step), v)
+		}
+		return s
+	}
+	flags := func(s mathexp.Value) []*float64 {
+		series := s.(mathexp.Series)
+		result := make([]*float64, series.Len())
+		for i := range result {
+			_, result[i] = series.GetPoint(i)
+		}
+		return result
+	}
+	execute := func(t *testing.T, cmd *AnomalyCommand, values ...mathexp.Value) mathexp.Results {
+		t.Helper()
+		res, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": mathexp.Results{Values: values},
+		}, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		return res
+	}
+	f := util.Pointer[float64]
+	zero, one := f(0), f(1)
+
+	t.Run("zscore should flag the outlier and return the bands", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", AnomalyMethodZScore, 2, "", "", "")
+		require.NoError(t, err)
+		labels := data.Labels{"host": "a"}
+		input := newSeries(labels, time.Minute, f(10), f(11), f(9), f(10), f(50), f(10), f(11), f(9))
+
+		res := execute(t, cmd, input)
+		require.Len(t, res.Values, 3)
+		assert.Equal(t, labels, res.Values[0].GetLabels())
+		assert.Equal(t, []*float64{zero, zero, zero, zero, one, zero, zero, zero}, flags(res.Values[0]))
+		assert.Equal(t, data.Labels{"host": "a", AnomalyBandLabel: "upper"}, res.Values[1].GetLabels())
+		assert.Equal(t, data.Labels{"host": "a", AnomalyBandLabel: "lower"}, res.Values[2].GetLabels())
+
+		upper := res.Values[1].(mathexp.Series)
+		lower := res.Values[2].(mathexp.Series)
+		for i := 0; i < upper.Len(); i++ {
+			_, u := upper.GetPoint(i)
+			_, l := lower.GetPoint(i)
+			require.NotNil(t, u)
+			require.NotNil(t, l)
+			assert.InDelta(t, 2*15, *u+*l, 1e-9, "band should be centred on the mean")
+		}
+	})
+
+	t.Run("mad should not be skewed by the outlier", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", AnomalyMethodMAD, 3, "", "", AnomalyOutputFlag)
+		require.NoError(t, err)
+		input := newSeries(nil, time.Minute, f(10), f(11), f(9), f(10), f(15), f(10), f(11), f(9), f(1000))
+
+		res := execute(t, cmd, input)
+		require.Len(t, res.Values, 1)
+		assert.Equal(t, []*float64{zero, zero, zero, zero, one, zero, zero, zero, one}, flags(res.Values[0]))
+	})
+
+	t.Run("window should only use the points before each point", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", AnomalyMethodZScore, 3, "3m", "", AnomalyOutputFlag)
+		require.NoError(t, err)
+		input := newSeries(nil, time.Minute, f(1), f(2), f(1), f(2), f(1), f(100), f(101), f(100), f(101))
+
+		res := execute(t, cmd, input)
+		// the first two points do not have enough points in the window before them
+		assert.Equal(t, []*float64{nil, nil, zero, zero, zero, one, zero, zero, zero}, flags(res.Values[0]))
+	})
+
+	t.Run("seasonal should compare with the previous season", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", AnomalyMethodSeasonal, 2, "", "4m", AnomalyOutputFlag)
+		require.NoError(t, err)
+		input := newSeries(nil, time.Minute,
+			f(0), f(10), f(20), f(10),
+			f(1), f(11), f(21), f(11),
+			f(0), f(10), f(80), f(10),
+		)
+
+		res := execute(t, cmd, input)
+		assert.Equal(t, []*float64{
+			nil, nil, nil, nil,
+			zero, zero, zero, zero,
+			zero, zero, one, zero,
+		}, flags(res.Values[0]))
+	})
+
+	t.Run("should return null flags for null and NaN values", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", AnomalyMethodZScore, 3, "", "", AnomalyOutputFlag)
+		require.NoError(t, err)
+		input := newSeries(nil, time.Minute, f(1), nil, f(2), f(math.NaN()), f(1))
+
+		res := execute(t, cmd, input)
+		assert.Equal(t, []*float64{zero, nil, zero, nil, zero}, flags(res.Values[0]))
+	})
+
+	t.Run("should return NoData for NoData", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", AnomalyMethodZScore, 3, "", "", "")
+		require.NoError(t, err)
+		res := execute(t, cmd, mathexp.NoData{}.New())
+		require.Len(t, res.Values, 1)
+		assert.Equal(t, mathexp.NoData{}.New(), res.Values[0])
+	})
+
+	t.Run("should fail for numbers", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", AnomalyMethodZScore, 3, "", "", "")
+		require.NoError(t, err)
+		_, err = cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{mathexp.NewNumber("A", nil)}},
+		}, tracing.InitializeTracerForTest())
+		require.Error(t, err)
+	})
+}
@@ -3,6 +3,7 @@ package classic
 import (
 	"math"
 	"sort"
+	"strconv"
 
 	"github.com/grafana/grafana/pkg/expr/mathexp"
 )
@@ -15,6 +16,8 @@ func (cr reducer) ValidReduceFunc() bool {
 		return true
 	case "diff", "diff_abs", "percent_diff", "percent_diff_abs", "count_non_null":
 		return true
+	case "first", "last_non_null", "range", "stddev", "p50", "p90", "p95", "p99":
+		return true
 	}
 	return false
 }
@@ -96,7 +99,16 @@ func (cr reducer) Reduce(series mathexp.Series) mathexp.Number {
 	case "count":
 		value = float64(ff.Len())
 		allNull = false
-	case "last":
+	case "first":
+		for i := 0; i < ff.Len(); i++ {
+			f := ff.GetValue(i)
+			if !nilOrNaN(f) {
+				value = *f
+				allNull = false
+				break
+			}
+		}
+	case "last", "last_non_null":
 		for i := ff.Len() - 1; i >= 0; i-- {
 			f := ff.GetValue(i)
 			if !nilOrNaN(f) {
@@ -124,6 +136,27 @@ func (cr reducer) Reduce(series mathexp.Series) mathexp.Number {
 				value = (values[(length/2)-1] + values[length/2]) / 2
 			}
 		}
+	case "p50", "p90", "p95", "p99":
+		values := nonNullValues(ff)
+		if len(values) >= 1 {
+			allNull = false
+			p, _ := strconv.ParseFloat(string(cr)[1:], 64)
+			sort.Float64s(values)
+			value = mathexp.SortedPercentile(values, p)
+		}
+	case "range":
+		values := nonNullValues(ff)
+		if len(values) >= 1 {
+			allNull = false
+			sort.Float64s(values)
+			value = values[len(values)-1] - values[0]
+		}
+	case "stddev":
+		values := nonNullValues(ff)
+		if len(values) >= 1 {
+			allNull = false
+			value = mathexp.PopulationStdDev(values)
+		}
 	case "diff":
 		allNull, value = calculateDiff(ff, allNull, value, diff)
 	case "diff_abs":
@@ -182,6 +215,19 @@ func calculateDiff(ff mathexp.Float64Field, allNull bool, value float64, fn func
 	return allNull, value
 }
 
+// nonNullValues returns the values of the field that are neither null nor NaN.
+func nonNullValues(ff mathexp.Float64Field) []float64 {
+	var values []float64
+	for i := 0; i < ff.Len(); i++ {
+		f := ff.GetValue(i)
+		if nilOrNaN(f) {
+			continue
+		}
+		values = append(values, *f)
+	}
+	return values
+}
+
 func nilOrNaN(f *float64) bool {
 	return f == nil || math.IsNaN(*f)
 }
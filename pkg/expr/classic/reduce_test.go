@@ -115,6 +115,54 @@ func TestReducer(t *testing.T) {
 			inputSeries:    newSeries(nil, nil),
 			expectedNumber: newNumber(nil),
 		},
+		{
+			name:           "first should ignore null values",
+			reducer:        reducer("first"),
+			inputSeries:    newSeries(nil, util.Pointer(math.NaN()), util.Pointer(2.0), util.Pointer(3.0)),
+			expectedNumber: newNumber(util.Pointer(2.0)),
+		},
+		{
+			name:           "last_non_null should ignore null values",
+			reducer:        reducer("last_non_null"),
+			inputSeries:    newSeries(util.Pointer(1.0), util.Pointer(2.0), nil),
+			expectedNumber: newNumber(util.Pointer(2.0)),
+		},
+		{
+			name:           "range",
+			reducer:        reducer("range"),
+			inputSeries:    newSeries(util.Pointer(3.0), nil, util.Pointer(-1.0), util.Pointer(2.0)),
+			expectedNumber: newNumber(util.Pointer(4.0)),
+		},
+		{
+			name:           "range with only nulls",
+			reducer:        reducer("range"),
+			inputSeries:    newSeries(nil, nil),
+			expectedNumber: newNumber(nil),
+		},
+		{
+			name:           "stddev",
+			reducer:        reducer("stddev"),
+			inputSeries:    newSeries(util.Pointer(2.0), util.Pointer(4.0), nil, util.Pointer(4.0), util.Pointer(4.0), util.Pointer(5.0), util.Pointer(5.0), util.Pointer(7.0), util.Pointer(9.0)),
+			expectedNumber: newNumber(util.Pointer(2.0)),
+		},
+		{
+			name:           "p50 is the same as median",
+			reducer:        reducer("p50"),
+			inputSeries:    newSeries(util.Pointer(1.0), util.Pointer(2.0), util.Pointer(4.0), util.Pointer(3000.0)),
+			expectedNumber: newNumber(util.Pointer(3.0)),
+		},
+		{
+			name:           "p90 interpolates between values",
+			reducer:        reducer("p90"),
+			inputSeries:    newSeries(util.Pointer(10.0), nil, util.Pointer(0.0), util.Pointer(5.0)),
+			expectedNumber: newNumber(util.Pointer(9.0)),
+		},
+		{
+			name:           "p99 with one value",
+			reducer:        reducer("p99"),
+			inputSeries:    newSeries(util.Pointer(1.0)),
+			expectedNumber: newNumber(util.Pointer(1.0)),
+		},
 	}
 
 	for _, tt := range tests {
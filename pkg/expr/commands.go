@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -217,22 +218,35 @@ type ResampleCommand struct {
 	Downsampler   mathexp.ReducerID
 	Upsampler     mathexp.Upsampler
 	TimeRange     TimeRange
+	Alignment     ResampleAlignment
+	Location      *time.Location
 	refID         string
 }
 
 // NewResampleCommand creates a new ResampleCMD.
-func NewResampleCommand(refID, rawWindow, varToResample string, downsampler mathexp.ReducerID, upsampler mathexp.Upsampler, tr TimeRange) (*ResampleCommand, error) {
+func NewResampleCommand(refID, rawWindow, varToResample string, downsampler mathexp.ReducerID, upsampler mathexp.Upsampler, tr TimeRange, alignment ResampleAlignment, timezone string) (*ResampleCommand, error) {
 	// TODO: validate reducer here, before execution
 	window, err := gtime.ParseDuration(rawWindow)
 	if err != nil {
 		return nil, fmt.Errorf(`failed to parse resample "window" duration field %q: %w`, window, err)
 	}
+	switch alignment {
+	case "", ResampleAlignmentFrom, ResampleAlignmentCalendar:
+	default:
+		return nil, fmt.Errorf("resample alignment '%s' is not supported. Supported only: [%s,%s]", alignment, ResampleAlignmentFrom, ResampleAlignmentCalendar)
+	}
+	loc, err := time.LoadLocation(timezone) // empty string is UTC
+	if err != nil {
+		return nil, fmt.Errorf(`failed to parse resample "timezone" field %q: %w`, timezone, err)
+	}
 	return &ResampleCommand{
 		Window:        window,
 		VarToResample: varToResample,
 		Downsampler:   downsampler,
 		Upsampler:     upsampler,
 		TimeRange:     tr,
+		Alignment:     alignment,
+		Location:      loc,
 		refID:         refID,
 	}, nil
 }
@@ -280,11 +294,27 @@ func UnmarshalResampleCommand(rn *rawNode) (*ResampleCommand, error) {
 		return nil, fmt.Errorf("expected resample downsampler to be a string, got type %T", upsampler)
 	}
 
+	var alignment, timezone string
+	if rawAlignment, ok := rn.Query["alignment"]; ok {
+		alignment, ok = rawAlignment.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected resample alignment to be a string, got type %T", rawAlignment)
+		}
+	}
+	if rawTimezone, ok := rn.Query["timezone"]; ok {
+		timezone, ok = rawTimezone.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected resample timezone to be a string, got type %T", rawTimezone)
+		}
+	}
+
 	return NewResampleCommand(rn.RefID, window,
 		varToResample,
 		mathexp.ReducerID(downsampler),
 		mathexp.Upsampler(upsampler),
-		rn.TimeRange)
+		rn.TimeRange,
+		ResampleAlignment(alignment),
+		timezone)
 }
 
 // NeedsVars returns the variable names (refIds) that are dependencies
@@ -300,6 +330,9 @@ func (gr *ResampleCommand) Execute(ctx context.Context, now time.Time, vars math
 	defer span.End()
 	newRes := mathexp.Results{}
 	timeRange := gr.TimeRange.AbsoluteTime(now)
+	if gr.Alignment == ResampleAlignmentCalendar {
+		timeRange.From = mathexp.CalendarAlign(timeRange.From, gr.Window, gr.Location)
+	}
 	for _, val := range vars[gr.VarToResample].Values {
 		if val == nil {
 			continue
@@ -325,6 +358,226 @@ func (gr *ResampleCommand) Type() string {
 	return TypeResample.String()
 }
 
+// ForecastCommand is an expression command that extrapolates each time series of its input
+// and turns it into a Number.
+type ForecastCommand struct {
+	VarToForecast string
+	Method        mathexp.ForecastMethod
+	Horizon       time.Duration
+	Output        ForecastOutput
+	// Threshold is only used by the time to threshold output.
+	Threshold   float64
+	HoltWinters mathexp.HoltWintersParams
+	refID       string
+}
+
+// NewForecastCommand creates a new ForecastCommand. Settings are optional and only used by the Holt-Winters method.
+func NewForecastCommand(refID, varToForecast string, method mathexp.ForecastMethod, rawHorizon string, output ForecastOutput, threshold *float64, settings *ForecastSettings) (*ForecastCommand, error) {
+	switch method {
+	case mathexp.ForecastLinear, mathexp.ForecastHoltWinters:
+	default:
+		return nil, fmt.Errorf("forecast method '%s' is not supported. Supported only: [%s,%s]", method, mathexp.ForecastLinear, mathexp.ForecastHoltWinters)
+	}
+	horizon, err := gtime.ParseDuration(rawHorizon)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to parse forecast "horizon" duration field %q: %w`, rawHorizon, err)
+	}
+	if horizon <= 0 {
+		return nil, fmt.Errorf("forecast horizon must be greater than zero, got %s", rawHorizon)
+	}
+	cmd := &ForecastCommand{
+		VarToForecast: varToForecast,
+		Method:        method,
+		Horizon:       horizon,
+		Output:        output,
+		HoltWinters: mathexp.HoltWintersParams{
+			Alpha: defaultForecastAlpha,
+			Beta:  defaultForecastBeta,
+			Gamma: defaultForecastGamma,
+		},
+		refID: refID,
+	}
+	switch output {
+	case "":
+		cmd.Output = ForecastOutputValue
+	case ForecastOutputValue:
+	case ForecastOutputTimeToThreshold:
+		if threshold == nil {
+			return nil, errors.New("forecast output time_to_threshold requires a threshold")
+		}
+		cmd.Threshold = *threshold
+	default:
+		return nil, fmt.Errorf("forecast output '%s' is not supported. Supported only: [%s,%s]", output, ForecastOutputValue, ForecastOutputTimeToThreshold)
+	}
+	if settings != nil {
+		if settings.Alpha != nil {
+			cmd.HoltWinters.Alpha = *settings.Alpha
+		}
+		if settings.Beta != nil {
+			cmd.HoltWinters.Beta = *settings.Beta
+		}
+		if settings.Gamma != nil {
+			cmd.HoltWinters.Gamma = *settings.Gamma
+		}
+		if settings.Season != "" {
+			cmd.HoltWinters.Season, err = gtime.ParseDuration(settings.Season)
+			if err != nil {
+				return nil, fmt.Errorf(`failed to parse forecast "season" duration field %q: %w`, settings.Season, err)
+			}
+		}
+	}
+	if err := cmd.HoltWinters.Validate(); err != nil {
+		return nil, err
+	}
+	return cmd, nil
+}
+
+const (
+	defaultForecastAlpha = 0.5
+	defaultForecastBeta  = 0.1
+	defaultForecastGamma = 0.1
+)
+
+// UnmarshalForecastCommand creates a ForecastCommand from Grafana's frontend query.
+func UnmarshalForecastCommand(rn *rawNode) (*ForecastCommand, error) {
+	rawVar, ok := rn.Query["expression"]
+	if !ok {
+		return nil, errors.New("no expression ID to forecast. must be a reference to an existing query or expression")
+	}
+	varToForecast, ok := rawVar.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected forecast input variable to be type string, but got type %T", rawVar)
+	}
+	varToForecast = strings.TrimPrefix(varToForecast, "$")
+
+	rawMethod, ok := rn.Query["method"]
+	if !ok {
+		return nil, errors.New("no method specified in forecast command")
+	}
+	method, ok := rawMethod.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected forecast method to be a string, got type %T", rawMethod)
+	}
+
+	rawHorizon, ok := rn.Query["horizon"]
+	if !ok {
+		return nil, errors.New("no horizon specified in forecast command")
+	}
+	horizon, ok := rawHorizon.(string)
+	if !ok {
+		return nil, fmt.Errorf("forecast horizon is expected to be a string, got %T", rawHorizon)
+	}
+
+	var output string
+	if rawOutput, ok := rn.Query["output"]; ok {
+		output, ok = rawOutput.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected forecast output to be a string, got type %T", rawOutput)
+		}
+	}
+
+	var threshold *float64
+	if rawThreshold, ok := rn.Query["threshold"]; ok {
+		t, ok := rawThreshold.(float64)
+		if !ok {
+			return nil, fmt.Errorf("expected forecast threshold to be a number, got type %T", rawThreshold)
+		}
+		threshold = &t
+	}
+
+	var settings *ForecastSettings
+	if rawSettings, ok := rn.Query["settings"]; ok {
+		settingsMap, ok := rawSettings.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("field settings must be an object, got %T for refId %v", rawSettings, rn.RefID)
+		}
+		settings = &ForecastSettings{}
+		for key, dst := range map[string]**float64{"alpha": &settings.Alpha, "beta": &settings.Beta, "gamma": &settings.Gamma} {
+			raw, ok := settingsMap[key]
+			if !ok {
+				continue
+			}
+			f, ok := raw.(float64)
+			if !ok {
+				return nil, fmt.Errorf("expected forecast setting %s to be a number, got type %T", key, raw)
+			}
+			*dst = &f
+		}
+		if rawSeason, ok := settingsMap["season"]; ok {
+			settings.Season, ok = rawSeason.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected forecast setting season to be a string, got type %T", rawSeason)
+			}
+		}
+	}
+
+	return NewForecastCommand(rn.RefID, varToForecast, mathexp.ForecastMethod(method), horizon, ForecastOutput(output), threshold, settings)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (gf *ForecastCommand) NeedsVars() []string {
+	return []string{gf.VarToForecast}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute. The forecast is made from the time now: the value output is the
+// predicted value at now plus the horizon and the time to threshold output is the number
+// of seconds from now until the threshold is reached, or +Inf if it is not reached within the horizon.
+// The Number has no value if the series does not have enough points to forecast.
+func (gf *ForecastCommand) Execute(ctx context.Context, now time.Time, vars mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteForecast")
+	defer span.End()
+	span.SetAttributes(attribute.String("method", string(gf.Method)))
+
+	newRes := mathexp.Results{}
+	for _, val := range vars[gf.VarToForecast].Values {
+		switch v := val.(type) {
+		case mathexp.Series:
+			num := mathexp.NewNumber(gf.refID, v.GetLabels())
+			num.SetValue(gf.forecast(v, now))
+			newRes.Values = append(newRes.Values, num)
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, v.New())
+		default:
+			return newRes, fmt.Errorf("can only forecast type series, got type %v", val.Type())
+		}
+	}
+	return newRes, nil
+}
+
+func (gf *ForecastCommand) forecast(s mathexp.Series, now time.Time) *float64 {
+	var forecaster mathexp.Forecaster
+	var step time.Duration
+	var ok bool
+	switch gf.Method {
+	case mathexp.ForecastHoltWinters:
+		forecaster, step, ok = s.HoltWintersForecaster(gf.HoltWinters)
+	default:
+		// a line crosses the threshold at most once, so there is no need to sample it in between
+		forecaster, ok = s.LinearForecaster()
+		step = gf.Horizon
+	}
+	if !ok {
+		return nil
+	}
+
+	if gf.Output == ForecastOutputTimeToThreshold {
+		// +Inf rather than no value, so that "time to threshold is below" conditions are false instead of no data
+		seconds := math.Inf(1)
+		if d, ok := mathexp.TimeToThreshold(forecaster, now, gf.Horizon, step, gf.Threshold); ok {
+			seconds = d.Seconds()
+		}
+		return &seconds
+	}
+	value := forecaster(now.Add(gf.Horizon))
+	return &value
+}
+
+func (gf *ForecastCommand) Type() string {
+	return TypeForecast.String()
+}
+
 // CommandType is the type of the expression command.
 type CommandType int
 
@@ -343,6 +596,10 @@ const (
 	TypeThreshold
 	// TypeSQL is the CMDType for running SQL expressions
 	TypeSQL
+	// TypeAnomaly is the CMDType for detecting anomalies in time series
+	TypeAnomaly
+	// TypeForecast is the CMDType for extrapolating time series
+	TypeForecast
 )
 
 func (gt CommandType) String() string {
@@ -359,6 +616,10 @@ func (gt CommandType) String() string {
 		return "threshold"
 	case TypeSQL:
 		return "sql"
+	case TypeAnomaly:
+		return "anomaly"
+	case TypeForecast:
+		return "forecast"
 	default:
 		return "unknown"
 	}
@@ -379,6 +640,10 @@ func ParseCommandType(s string) (CommandType, error) {
 		return TypeThreshold, nil
 	case "sql":
 		return TypeSQL, nil
+	case "anomaly":
+		return TypeAnomaly, nil
+	case "forecast":
+		return TypeForecast, nil
 	default:
 		return TypeUnknown, fmt.Errorf("'%v' is not a recognized expression type", s)
 	}
@@ -221,7 +221,7 @@ func TestResampleCommand_Execute(t *testing.T) {
 		From: -10 * time.Second,
 		To:   0,
 	}
-	cmd, err := NewResampleCommand(util.GenerateShortUID(), "1s", varToReduce, "sum", "pad", tr)
+	cmd, err := NewResampleCommand(util.GenerateShortUID(), "1s", varToReduce, "sum", "pad", tr, "", "")
 	require.NoError(t, err)
 
 	var tests = []struct {
@@ -273,3 +273,177 @@ func TestResampleCommand_Execute(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestResampleCommand_Alignment(t *testing.T) {
+	varToResample := util.GenerateShortUID()
+	tr := AbsoluteTimeRange{
+		From: time.Date(2024, 1, 1, 10, 7, 30, 0, time.UTC),
+		To:   time.Date(2024, 1, 1, 10, 40, 0, 0, time.UTC),
+	}
+	input := mathexp.Vars{
+		varToResample: mathexp.Results{Values: mathexp.Values{mathexp.NewSeries(varToResample, nil, 0)}},
+	}
+
+	t.Run("should align to the start of the time range by default", func(t *testing.T) {
+		cmd, err := NewResampleCommand("B", "15m", varToResample, "last", "fillna", tr, "", "")
+		require.NoError(t, err)
+		result, err := cmd.Execute(context.Background(), time.Now(), input, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Equal(t, tr.From, result.Values[0].(mathexp.Series).GetTime(0))
+	})
+
+	t.Run("should align to calendar boundaries in the time zone", func(t *testing.T) {
+		cmd, err := NewResampleCommand("B", "15m", varToResample, "last", "fillna", tr, ResampleAlignmentCalendar, "Asia/Kolkata")
+		require.NoError(t, err)
+		result, err := cmd.Execute(context.Background(), time.Now(), input, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		// Asia/Kolkata is UTC+05:30, so 10:07:30 UTC is 15:37:30 local time which aligns to 15:30.
+		require.Equal(t, time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), result.Values[0].(mathexp.Series).GetTime(0).UTC())
+	})
+
+	t.Run("should fail on invalid alignment", func(t *testing.T) {
+		_, err := NewResampleCommand("B", "15m", varToResample, "last", "fillna", tr, "week", "")
+		require.Error(t, err)
+	})
+
+	t.Run("should fail on unknown time zone", func(t *testing.T) {
+		_, err := NewResampleCommand("B", "15m", varToResample, "last", "fillna", tr, ResampleAlignmentCalendar, "Mars/Olympus_Mons")
+		require.Error(t, err)
+	})
+}
+
+func TestForecastCommand_Execute(t *testing.T) {
+	varToForecast := util.GenerateShortUID()
+	now := time.Unix(0, 0).Add(time.Hour)
+	// disk usage growing by 10 every hour until now
+	usage := mathexp.NewSeries(varToForecast, data.Labels{"host": "a"}, 5)
+	for i := 0; i < 5; i++ {
+		usage.SetPoint(i, now.Add(time.Duration(i-4)*15*time.Minute), util.Pointer(50+float64(i)*2.5))
+	}
+	execute := func(t *testing.T, cmd *ForecastCommand, value mathexp.Value) mathexp.Results {
+		t.Helper()
+		result, err := cmd.Execute(context.Background(), now, mathexp.Vars{
+			varToForecast: mathexp.Results{Values: mathexp.Values{value}},
+		}, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		return result
+	}
+
+	var tests = []struct {
+		name      string
+		method    mathexp.ForecastMethod
+		output    ForecastOutput
+		threshold *float64
+		horizon   string
+		expected  *float64
+	}{
+		{
+			name:     "linear value at the horizon",
+			method:   mathexp.ForecastLinear,
+			horizon:  "4h",
+			expected: util.Pointer(100.0),
+		},
+		{
+			name:      "linear time until the threshold",
+			method:    mathexp.ForecastLinear,
+			horizon:   "4h",
+			output:    ForecastOutputTimeToThreshold,
+			threshold: util.Pointer(95.0),
+			expected:  util.Pointer(3.5 * 3600),
+		},
+		{
+			name:      "infinity when the threshold is beyond the horizon",
+			method:    mathexp.ForecastLinear,
+			horizon:   "1h",
+			output:    ForecastOutputTimeToThreshold,
+			threshold: util.Pointer(95.0),
+			expected:  util.Pointer(math.Inf(1)),
+		},
+		{
+			name:     "holt-winters value at the horizon",
+			method:   mathexp.ForecastHoltWinters,
+			horizon:  "4h",
+			expected: util.Pointer(100.0),
+		},
+		{
+			name:      "holt-winters time until the threshold",
+			method:    mathexp.ForecastHoltWinters,
+			horizon:   "4h",
+			output:    ForecastOutputTimeToThreshold,
+			threshold: util.Pointer(95.0),
+			expected:  util.Pointer(3.5 * 3600),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cmd, err := NewForecastCommand("B", varToForecast, test.method, test.horizon, test.output, test.threshold, nil)
+			require.NoError(t, err)
+			result := execute(t, cmd, usage)
+			require.Len(t, result.Values, 1)
+			num, ok := result.Values[0].(mathexp.Number)
+			require.True(t, ok)
+			require.Equal(t, data.Labels{"host": "a"}, num.GetLabels())
+			if test.expected == nil {
+				require.Nil(t, num.GetFloat64Value())
+				return
+			}
+			require.NotNil(t, num.GetFloat64Value())
+			if math.IsInf(*test.expected, 1) {
+				require.True(t, math.IsInf(*num.GetFloat64Value(), 1))
+				return
+			}
+			require.InDelta(t, *test.expected, *num.GetFloat64Value(), 1e-6)
+		})
+	}
+
+	t.Run("should return no value when the series is too short", func(t *testing.T) {
+		cmd, err := NewForecastCommand("B", varToForecast, mathexp.ForecastLinear, "1h", "", nil, nil)
+		require.NoError(t, err)
+		result := execute(t, cmd, mathexp.NewSeries(varToForecast, nil, 0))
+		require.Nil(t, result.Values[0].(mathexp.Number).GetFloat64Value())
+	})
+
+	t.Run("should return NoData when input NoData", func(t *testing.T) {
+		cmd, err := NewForecastCommand("B", varToForecast, mathexp.ForecastLinear, "1h", "", nil, nil)
+		require.NoError(t, err)
+		result := execute(t, cmd, mathexp.NoData{})
+		require.Equal(t, parse.TypeNoData, result.Values[0].Type())
+	})
+
+	t.Run("should return error when input Number", func(t *testing.T) {
+		cmd, err := NewForecastCommand("B", varToForecast, mathexp.ForecastLinear, "1h", "", nil, nil)
+		require.NoError(t, err)
+		_, err = cmd.Execute(context.Background(), now, mathexp.Vars{
+			varToForecast: mathexp.Results{Values: mathexp.Values{mathexp.NewNumber("A", nil)}},
+		}, tracing.InitializeTracerForTest())
+		require.Error(t, err)
+	})
+}
+
+func TestUnmarshalForecastCommand(t *testing.T) {
+	q := `{ "expression": "$A", "method": "holt_winters", "horizon": "1d", "output": "time_to_threshold", "threshold": 0,
+		"settings": { "alpha": 0.3, "season": "1h" } }`
+	var qmap = make(map[string]any)
+	require.NoError(t, json.Unmarshal([]byte(q), &qmap))
+
+	cmd, err := UnmarshalForecastCommand(&rawNode{RefID: "B", Query: qmap})
+	require.NoError(t, err)
+	require.Equal(t, "A", cmd.VarToForecast)
+	require.Equal(t, 24*time.Hour, cmd.Horizon)
+	require.Equal(t, ForecastOutputTimeToThreshold, cmd.Output)
+	require.Equal(t, mathexp.HoltWintersParams{Alpha: 0.3, Beta: defaultForecastBeta, Gamma: defaultForecastGamma, Season: time.Hour}, cmd.HoltWinters)
+
+	invalid := []string{
+		`{ "expression": "$A", "horizon": "1d" }`,
+		`{ "expression": "$A", "method": "arima", "horizon": "1d" }`,
+		`{ "expression": "$A", "method": "linear", "horizon": "0s" }`,
+		`{ "expression": "$A", "method": "linear", "horizon": "1d", "output": "time_to_threshold" }`,
+		`{ "expression": "$A", "method": "linear", "horizon": "1d", "settings": { "beta": 2 } }`,
+	}
+	for _, q := range invalid {
+		var qmap = make(map[string]any)
+		require.NoError(t, json.Unmarshal([]byte(q), &qmap))
+		_, err := UnmarshalForecastCommand(&rawNode{RefID: "B", Query: qmap})
+		require.Error(t, err, q)
+	}
+}
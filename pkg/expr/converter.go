@@ -20,10 +20,24 @@ type ResultConverter struct {
 	Tracer   tracing.Tracer
 }
 
+// Convert converts the frames of a data source response to mathexp.Results. It returns the response type
+// that describes how the frames were converted.
 func (c *ResultConverter) Convert(ctx context.Context,
 	datasourceType string,
 	frames data.Frames,
 	allowLongFrames bool,
+) (string, mathexp.Results, error) {
+	responseType, result, err := c.convert(ctx, datasourceType, frames, allowLongFrames)
+	if err == nil {
+		recordConversion(ctx, responseType)
+	}
+	return responseType, result, err
+}
+
+func (c *ResultConverter) convert(ctx context.Context,
+	datasourceType string,
+	frames data.Frames,
+	allowLongFrames bool,
 ) (string, mathexp.Results, error) {
 	if len(frames) == 0 {
 		return "no-data", mathexp.Results{Values: mathexp.Values{mathexp.NewNoData()}}, nil
@@ -77,6 +91,7 @@ func (c *ResultConverter) Convert(ctx context.Context,
 		// This check should be removed once inconsistencies in data source responses are solved.
 		if schema.Type == data.TimeSeriesTypeNot && datasourceType == datasources.DS_INFLUXDB {
 			logger.Warn("Ignoring InfluxDB data frame due to missing numeric fields")
+			recordDroppedFrame(ctx, fmt.Sprintf("frame %q of InfluxDB response has no numeric fields", frame.Name))
 			continue
 		}
 
@@ -0,0 +1,242 @@
+package expr
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+// DebugRefID is the refID of the response that contains the PipelineDebug of a request in debug mode.
+const DebugRefID = "__expr_debug__"
+
+// NodeDebug is the debug information of a node of an expression pipeline.
+type NodeDebug struct {
+	// RefID is the refID of the node.
+	RefID string `json:"refId"`
+	// Order is the position of the node in the resolved dependency order, starting with 0.
+	Order int `json:"order"`
+	// NodeType is the type of the node, for example Expression or Datasource.
+	NodeType string `json:"nodeType"`
+	// Type is the type of the command of an expression, or the type of the data source of a query.
+	Type string `json:"type,omitempty"`
+	// DependsOn are the refIDs of the nodes the node needs.
+	DependsOn []string `json:"dependsOn"`
+	// Executed is false if the node was not executed because a node it depends on failed.
+	Executed bool `json:"executed"`
+	// Duration is the time it took to execute the node. Data source queries that are executed as a group
+	// share the duration of the group.
+	Duration time.Duration `json:"duration"`
+	// ResponseType is how the response of a data source query was converted, for example "multi frame series".
+	ResponseType string `json:"responseType,omitempty"`
+	// DroppedFrames describes the frames of a data source response that were dropped during the conversion.
+	DroppedFrames []string `json:"droppedFrames,omitempty"`
+	// ValueTypes are the types of the values of the node's result, for example "seriesSet" or "numberSet".
+	ValueTypes []string `json:"valueTypes,omitempty"`
+	// Values is the number of values of the node's result.
+	Values int `json:"values"`
+	// Error is the error of the node's result.
+	Error string `json:"error,omitempty"`
+}
+
+// PipelineDebug is the debug information of the execution of an expression pipeline.
+type PipelineDebug struct {
+	mtx sync.Mutex
+	// Nodes are the nodes of the pipeline in the resolved dependency order.
+	Nodes []*NodeDebug `json:"nodes"`
+
+	datasourceDuration time.Duration
+}
+
+func newPipelineDebug(pipeline DataPipeline) *PipelineDebug {
+	d := &PipelineDebug{Nodes: make([]*NodeDebug, 0, len(pipeline))}
+	for i, node := range pipeline {
+		n := &NodeDebug{
+			RefID:     node.RefID(),
+			Order:     i,
+			NodeType:  node.NodeType().String(),
+			DependsOn: node.NeedsVars(),
+		}
+		switch t := node.(type) {
+		case *CMDNode:
+			if t.Command != nil {
+				n.Type = t.Command.Type()
+			}
+		case *DSNode:
+			if t.datasource != nil {
+				n.Type = t.datasource.Type
+			}
+		case *MLNode:
+			if t.command != nil {
+				n.Type = t.command.Type()
+			}
+		}
+		if n.DependsOn == nil {
+			n.DependsOn = []string{}
+		}
+		d.Nodes = append(d.Nodes, n)
+	}
+	return d
+}
+
+// Node returns the debug information of the node with the refID, or nil if there is no such node.
+func (d *PipelineDebug) Node(refID string) *NodeDebug {
+	if d == nil {
+		return nil
+	}
+	for _, n := range d.Nodes {
+		if n.RefID == refID {
+			return n
+		}
+	}
+	return nil
+}
+
+// setResult records the execution of the node with its result.
+func (d *PipelineDebug) setResult(refID string, duration time.Duration, res mathexp.Results) {
+	n := d.Node(refID)
+	if n == nil {
+		return
+	}
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	n.Executed = true
+	n.Duration = duration
+	n.Values = len(res.Values)
+	n.ValueTypes = n.ValueTypes[:0]
+	for _, v := range res.Values {
+		t := v.Type().String()
+		if !slices.Contains(n.ValueTypes, t) {
+			n.ValueTypes = append(n.ValueTypes, t)
+		}
+	}
+	n.Error = ""
+	if res.Error != nil {
+		n.Error = res.Error.Error()
+	}
+}
+
+// DatasourceDuration returns the total time it took to execute the data source queries of the pipeline.
+// Unlike the sum of the durations of the nodes, queries that are executed as a group are counted once.
+func (d *PipelineDebug) DatasourceDuration() time.Duration {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	return d.datasourceDuration
+}
+
+func (d *PipelineDebug) addDatasourceDuration(duration time.Duration) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	d.datasourceDuration += duration
+}
+
+// setSkipped records that the node with the refID was not executed because of the error.
+func (d *PipelineDebug) setSkipped(refID string, err error) {
+	n := d.Node(refID)
+	if n == nil {
+		return
+	}
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	n.Executed = false
+	n.Error = err.Error()
+}
+
+func (d *PipelineDebug) setResponseType(n *NodeDebug, responseType string) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	n.ResponseType = responseType
+}
+
+func (d *PipelineDebug) dropFrame(n *NodeDebug, reason string) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	n.DroppedFrames = append(n.DroppedFrames, reason)
+}
+
+// Frame returns the debug information as a table with a row for each node.
+// The frame has the PipelineDebug as custom metadata.
+func (d *PipelineDebug) Frame() *data.Frame {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	frame := data.NewFrame("Expression pipeline",
+		data.NewField("Order", nil, make([]int64, 0, len(d.Nodes))),
+		data.NewField("RefID", nil, make([]string, 0, len(d.Nodes))),
+		data.NewField("Node type", nil, make([]string, 0, len(d.Nodes))),
+		data.NewField("Type", nil, make([]string, 0, len(d.Nodes))),
+		data.NewField("Depends on", nil, make([]string, 0, len(d.Nodes))),
+		data.NewField("Executed", nil, make([]bool, 0, len(d.Nodes))),
+		data.NewField("Duration", nil, make([]float64, 0, len(d.Nodes))).SetConfig(&data.FieldConfig{Unit: "ms"}),
+		data.NewField("Response type", nil, make([]string, 0, len(d.Nodes))),
+		data.NewField("Dropped frames", nil, make([]string, 0, len(d.Nodes))),
+		data.NewField("Value types", nil, make([]string, 0, len(d.Nodes))),
+		data.NewField("Values", nil, make([]int64, 0, len(d.Nodes))),
+		data.NewField("Error", nil, make([]string, 0, len(d.Nodes))),
+	)
+	for _, n := range d.Nodes {
+		frame.AppendRow(
+			int64(n.Order),
+			n.RefID,
+			n.NodeType,
+			n.Type,
+			strings.Join(n.DependsOn, ", "),
+			n.Executed,
+			float64(n.Duration.Nanoseconds())/float64(time.Millisecond),
+			n.ResponseType,
+			strings.Join(n.DroppedFrames, "; "),
+			strings.Join(n.ValueTypes, ", "),
+			int64(n.Values),
+			n.Error,
+		)
+	}
+	frame.RefID = DebugRefID
+	frame.SetMeta(&data.FrameMeta{
+		Type:   data.FrameTypeTable,
+		Custom: d.Nodes,
+	})
+	return frame
+}
+
+type pipelineDebugKey struct{}
+
+type nodeDebugKey struct{}
+
+func withPipelineDebug(ctx context.Context, d *PipelineDebug) context.Context {
+	return context.WithValue(ctx, pipelineDebugKey{}, d)
+}
+
+func pipelineDebugFromContext(ctx context.Context) *PipelineDebug {
+	d, _ := ctx.Value(pipelineDebugKey{}).(*PipelineDebug)
+	return d
+}
+
+// withNodeDebug returns a context that records debug information of the node with the refID,
+// if the pipeline is executed in debug mode.
+func withNodeDebug(ctx context.Context, refID string) context.Context {
+	d := pipelineDebugFromContext(ctx)
+	if d == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, nodeDebugKey{}, d.Node(refID))
+}
+
+// recordConversion records how the response of the node in the context was converted.
+func recordConversion(ctx context.Context, responseType string) {
+	d := pipelineDebugFromContext(ctx)
+	if n, ok := ctx.Value(nodeDebugKey{}).(*NodeDebug); ok && d != nil && n != nil {
+		d.setResponseType(n, responseType)
+	}
+}
+
+// recordDroppedFrame records that a frame of the response of the node in the context was dropped.
+func recordDroppedFrame(ctx context.Context, reason string) {
+	d := pipelineDebugFromContext(ctx)
+	if n, ok := ctx.Value(nodeDebugKey{}).(*NodeDebug); ok && d != nil && n != nil {
+		d.dropFrame(n, reason)
+	}
+}
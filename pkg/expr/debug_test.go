@@ -0,0 +1,177 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	datafakes "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginconfig"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestTransformDataDebug(t *testing.T) {
+	me := &mockEndpoint{
+		Responses: map[string]backend.DataResponse{
+			"A": {Frames: data.Frames{data.NewFrame("test",
+				data.NewField("time", nil, []time.Time{time.Unix(1, 0), time.Unix(2, 0)}),
+				data.NewField("value", data.Labels{"host": "a"}, []*float64{fp(2), fp(3)}),
+				data.NewField("value", data.Labels{"host": "b"}, []*float64{fp(4), fp(5)}),
+			)}},
+			"E": {Error: errors.New("failed")},
+		},
+	}
+
+	pCtxProvider := plugincontext.ProvideService(setting.NewCfg(), nil, &pluginstore.FakePluginStore{
+		PluginList: []pluginstore.Plugin{
+			{JSONData: plugins.JSONData{ID: "test"}},
+		},
+	}, &datafakes.FakeCacheService{}, &datafakes.FakeDataSourceService{}, nil, pluginconfig.NewFakePluginRequestConfigProvider())
+
+	cfg := setting.NewCfg()
+	cfg.ExpressionsEnabled = true
+	features := featuremgmt.WithFeatures()
+	s := Service{
+		cfg:          cfg,
+		dataService:  me,
+		pCtxProvider: pCtxProvider,
+		features:     features,
+		tracer:       tracing.InitializeTracerForTest(),
+		metrics:      newMetrics(nil),
+		converter: &ResultConverter{
+			Features: features,
+			Tracer:   tracing.InitializeTracerForTest(),
+		},
+	}
+
+	ds := &datasources.DataSource{OrgID: 1, UID: "test", Type: "test"}
+	queries := []Query{
+		{
+			RefID:      "C",
+			DataSource: dataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "reduce", "reducer": "last", "expression": "$B" }`),
+		},
+		{
+			RefID:      "B",
+			DataSource: dataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "math", "expression": "$A * 2", "hide": true }`),
+		},
+		{
+			RefID:      "A",
+			DataSource: ds,
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "test" }, "intervalMs": 1000, "maxDataPoints": 1000 }`),
+			TimeRange:  AbsoluteTimeRange{},
+		},
+		{
+			RefID:      "E",
+			DataSource: ds,
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "test" }, "intervalMs": 1000, "maxDataPoints": 1000 }`),
+			TimeRange:  AbsoluteTimeRange{},
+		},
+		{
+			RefID:      "F",
+			DataSource: dataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "math", "expression": "$E + 1" }`),
+		},
+	}
+
+	t.Run("should not return debug information by default", func(t *testing.T) {
+		res, err := s.TransformData(context.Background(), time.Now(), &Request{Queries: queries, User: &user.SignedInUser{}})
+		require.NoError(t, err)
+		require.NotContains(t, res.Responses, DebugRefID)
+		require.NotContains(t, res.Responses, "B")
+	})
+
+	t.Run("should return the results and debug information of all nodes", func(t *testing.T) {
+		res, err := s.TransformData(context.Background(), time.Now(), &Request{Queries: queries, User: &user.SignedInUser{}, Debug: true})
+		require.NoError(t, err)
+		for _, refID := range []string{"A", "B", "C", "E", "F"} {
+			require.Contains(t, res.Responses, refID)
+		}
+		require.Contains(t, res.Responses, DebugRefID)
+		frames := res.Responses[DebugRefID].Frames
+		require.Len(t, frames, 1)
+		require.Equal(t, 5, frames[0].Rows())
+
+		nodes := frames[0].Meta.Custom.([]*NodeDebug)
+		order := make(map[string]int, len(nodes))
+		for i, n := range nodes {
+			require.Equal(t, i, n.Order)
+			order[n.RefID] = n.Order
+		}
+		require.Less(t, order["A"], order["B"])
+		require.Less(t, order["B"], order["C"])
+		require.Less(t, order["E"], order["F"])
+
+		debug := &PipelineDebug{Nodes: nodes}
+		a := debug.Node("A")
+		require.True(t, a.Executed)
+		require.Equal(t, "Datasource", a.NodeType)
+		require.Equal(t, "test", a.Type)
+		require.Equal(t, "single frame series", a.ResponseType)
+		require.Equal(t, 2, a.Values)
+		require.Equal(t, []string{"seriesSet"}, a.ValueTypes)
+		require.Empty(t, a.DependsOn)
+
+		b := debug.Node("B")
+		require.True(t, b.Executed)
+		require.Equal(t, "math", b.Type)
+		require.Equal(t, []string{"A"}, b.DependsOn)
+		require.Equal(t, 2, b.Values)
+
+		c := debug.Node("C")
+		require.Equal(t, "reduce", c.Type)
+		require.Equal(t, []string{"numberSet"}, c.ValueTypes)
+
+		e := debug.Node("E")
+		require.True(t, e.Executed)
+		require.Contains(t, e.Error, "failed")
+
+		f := debug.Node("F")
+		require.False(t, f.Executed)
+		require.NotEmpty(t, f.Error)
+	})
+
+	t.Run("should sum the durations of data source queries", func(t *testing.T) {
+		pipeline, err := s.BuildPipeline(&Request{Queries: queries, User: &user.SignedInUser{}})
+		require.NoError(t, err)
+		_, debug, err := s.ExecutePipelineWithDebug(context.Background(), time.Now(), pipeline)
+		require.NoError(t, err)
+		require.Equal(t, debug.Node("A").Duration+debug.Node("E").Duration, debug.DatasourceDuration())
+	})
+}
+
+func TestConverterDebug(t *testing.T) {
+	debug := &PipelineDebug{Nodes: []*NodeDebug{{RefID: "A"}}}
+	ctx := withNodeDebug(withPipelineDebug(context.Background(), debug), "A")
+
+	c := &ResultConverter{Features: featuremgmt.WithFeatures(), Tracer: tracing.InitializeTracerForTest()}
+	frames := data.Frames{
+		data.NewFrame("strings", data.NewField("value", nil, []string{"a"})),
+		data.NewFrame("series",
+			data.NewField("time", nil, []time.Time{time.Unix(1, 0)}),
+			data.NewField("value", nil, []*float64{fp(1)}),
+		),
+	}
+	responseType, res, err := c.Convert(ctx, datasources.DS_INFLUXDB, frames, false)
+	require.NoError(t, err)
+	require.Len(t, res.Values, 1)
+
+	n := debug.Node("A")
+	require.Equal(t, responseType, n.ResponseType)
+	require.Len(t, n.DroppedFrames, 1)
+	require.Contains(t, n.DroppedFrames[0], `"strings"`)
+}
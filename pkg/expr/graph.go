@@ -62,6 +62,7 @@ type DataPipeline []Node
 // map of the refId of the of each command
 func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (mathexp.Vars, error) {
 	vars := make(mathexp.Vars)
+	debug := pipelineDebugFromContext(c)
 
 	groupByDSFlag := s.features.IsEnabled(c, featuremgmt.FlagSseGroupByDatasource)
 	// Execute datasource nodes first, and grouped by datasource.
@@ -74,7 +75,15 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 			dsNodes = append(dsNodes, node.(*DSNode))
 		}
 
+		start := time.Now()
 		executeDSNodesGrouped(c, now, vars, s, dsNodes)
+		if debug != nil {
+			duration := time.Since(start)
+			debug.addDatasourceDuration(duration)
+			for _, dn := range dsNodes {
+				debug.setResult(dn.RefID(), duration, vars[dn.RefID()])
+			}
+		}
 	}
 
 	s.allowLongFrames = hasSqlExpression(*dp)
@@ -93,6 +102,9 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 						Error: MakeDependencyError(node.RefID(), neededVar),
 					}
 					vars[node.RefID()] = errResult
+					if debug != nil {
+						debug.setSkipped(node.RefID(), errResult.Error)
+					}
 					hasDepError = true
 					break
 				}
@@ -115,12 +127,20 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 			return vars, makeUnexpectedNodeTypeError(node.RefID(), node.NodeType().String())
 		}
 
-		res, err := execNode.Execute(c, now, vars, s)
+		start := time.Now()
+		res, err := execNode.Execute(withNodeDebug(c, node.RefID()), now, vars, s)
 		if err != nil {
 			res.Error = err
 		}
 
 		vars[node.RefID()] = res
+		if debug != nil {
+			duration := time.Since(start)
+			if node.NodeType() == TypeDatasourceNode {
+				debug.addDatasourceDuration(duration)
+			}
+			debug.setResult(node.RefID(), duration, res)
+		}
 	}
 	return vars, nil
 }
@@ -279,7 +299,7 @@ func (s *Service) buildGraph(req *Request) (*simple.DirectedGraph, error) {
 		case TypeDatasourceNode:
 			node, err = s.buildDSNode(dp, rn, req)
 		case TypeCMDNode:
-			node, err = buildCMDNode(rn, s.features)
+			node, err = buildCMDNode(rn, s.features, s.sqlEngine)
 		case TypeMLNode:
 			if s.features.IsEnabledGlobally(featuremgmt.FlagMlExpressions) {
 				node, err = s.buildMLNode(dp, rn, req)
@@ -0,0 +1,246 @@
+package expr
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// HysteresisSeriesState is the state of a single series in MultiLevelHysteresisCommand.
+type HysteresisSeriesState struct {
+	// Level is the level the series is at, 0 if it is at no level.
+	Level int `json:"level"`
+	// Unloading is the number of consecutive evaluations in which the series met the unloading condition of its level.
+	Unloading int `json:"unloading"`
+}
+
+// HysteresisState is the state of all series of a MultiLevelHysteresisCommand by the fingerprint of their labels.
+type HysteresisState map[data.Fingerprint]HysteresisSeriesState
+
+// HysteresisLevel is a level of a MultiLevelHysteresisCommand, for example warning or critical.
+type HysteresisLevel struct {
+	// Loading is the condition for a series to enter the level.
+	Loading ThresholdCommand
+	// Unloading is the condition for a series to leave the level. If it is nil, a series leaves the level
+	// as soon as it does not meet the loading condition anymore.
+	Unloading *ThresholdCommand
+	// UnloadEvaluations is the number of consecutive evaluations a series must meet the unloading condition
+	// before it leaves the level.
+	UnloadEvaluations int
+}
+
+func (l HysteresisLevel) loads(v float64) bool {
+	return l.Loading.predicate.Eval(v)
+}
+
+func (l HysteresisLevel) unloads(v float64) bool {
+	if l.Unloading == nil {
+		return !l.loads(v)
+	}
+	return l.Unloading.predicate.Eval(v)
+}
+
+// MultiLevelHysteresisCommand is a stateful threshold with several levels, each with its own loading and unloading
+// condition. A series enters the highest level whose loading condition it meets and stays at its level until it meets
+// the unloading condition of the level for UnloadEvaluations consecutive evaluations. It then drops to the highest lower
+// level it has not unloaded from. The state of the series from the previous evaluation is provided in State.
+// The result is a number for each series with the level the series is at, or 0 if it is at no level.
+// The frame of each number has the new HysteresisSeriesState of the series as custom metadata.
+type MultiLevelHysteresisCommand struct {
+	RefID        string
+	ReferenceVar string
+	Levels       []HysteresisLevel
+	State        HysteresisState
+}
+
+// NewMultiLevelHysteresisCommand creates a new MultiLevelHysteresisCommand.
+func NewMultiLevelHysteresisCommand(refID, referenceVar string, levels []HysteresisLevel, state HysteresisState) (*MultiLevelHysteresisCommand, error) {
+	if len(levels) == 0 {
+		return nil, fmt.Errorf("multi-level hysteresis requires at least one level")
+	}
+	for i, l := range levels {
+		if l.UnloadEvaluations < 0 {
+			return nil, fmt.Errorf("unload evaluations of level %d must not be negative, got %d", i+1, l.UnloadEvaluations)
+		}
+	}
+	return &MultiLevelHysteresisCommand{
+		RefID:        refID,
+		ReferenceVar: referenceVar,
+		Levels:       levels,
+		State:        state,
+	}, nil
+}
+
+func (h *MultiLevelHysteresisCommand) NeedsVars() []string {
+	return []string{h.ReferenceVar}
+}
+
+func (h *MultiLevelHysteresisCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteMultiLevelHysteresis")
+	span.SetAttributes(attribute.Int("levels", len(h.Levels)))
+	span.SetAttributes(attribute.Int("previousStates", len(h.State)))
+	defer span.End()
+
+	results := vars[h.ReferenceVar]
+	if results.IsNoData() {
+		return mathexp.Results{Values: mathexp.Values{mathexp.NewNoData()}}, nil
+	}
+
+	newRes := mathexp.Results{Values: make(mathexp.Values, 0, len(results.Values))}
+	for _, val := range results.Values {
+		var n mathexp.Number
+		switch v := val.(type) {
+		case mathexp.Number:
+			n = v
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, mathexp.NewNoData())
+			continue
+		default:
+			return newRes, fmt.Errorf("multi-level hysteresis requires numbers, got type %s. Use a reduce expression to reduce series to numbers", val.Type())
+		}
+
+		current := h.State[n.GetLabels().Fingerprint()]
+		result := mathexp.NewNumber(h.RefID, n.GetLabels())
+		next := current
+		if value := n.GetFloat64Value(); value != nil {
+			next = h.next(current, *value)
+			result.SetValue(util.Pointer(float64(next.Level)))
+		}
+		result.Frame.Meta.Custom = next
+		newRes.Values = append(newRes.Values, result)
+	}
+	return newRes, nil
+}
+
+// next returns the state of a series after an evaluation with the value.
+func (h *MultiLevelHysteresisCommand) next(current HysteresisSeriesState, value float64) HysteresisSeriesState {
+	if current.Level > len(h.Levels) {
+		// the levels changed since the previous evaluation
+		current = HysteresisSeriesState{Level: len(h.Levels)}
+	}
+
+	// the series holds levels up to its current level until it meets their unloading condition,
+	// and enters higher levels when it meets their loading condition
+	target := 0
+	for i := len(h.Levels); i > 0; i-- {
+		level := h.Levels[i-1]
+		if (i > current.Level && level.loads(value)) || (i <= current.Level && !level.unloads(value)) {
+			target = i
+			break
+		}
+	}
+	if target >= current.Level {
+		return HysteresisSeriesState{Level: target}
+	}
+
+	current.Unloading++
+	if current.Unloading >= h.Levels[current.Level-1].UnloadEvaluations {
+		return HysteresisSeriesState{Level: target}
+	}
+	return current
+}
+
+func (h *MultiLevelHysteresisCommand) Type() string {
+	return "multi_level_hysteresis"
+}
+
+// newMultiLevelHysteresisCommand creates a MultiLevelHysteresisCommand from the conditions of a threshold expression,
+// where each condition is a level.
+func newMultiLevelHysteresisCommand(refID, referenceVar string, conditions []ThresholdConditionJSON, loadedState *data.Frame) (*MultiLevelHysteresisCommand, error) {
+	levels := make([]HysteresisLevel, 0, len(conditions))
+	for i, c := range conditions {
+		loading, err := NewThresholdCommand(refID, referenceVar, c.Evaluator.Type, c.Evaluator.Params)
+		if err != nil {
+			return nil, fmt.Errorf("invalid condition %d: %w", i+1, err)
+		}
+		level := HysteresisLevel{Loading: *loading, UnloadEvaluations: c.UnloadEvaluations}
+		if c.UnloadEvaluator != nil {
+			level.Unloading, err = NewThresholdCommand(refID, referenceVar, c.UnloadEvaluator.Type, c.UnloadEvaluator.Params)
+			if err != nil {
+				return nil, fmt.Errorf("invalid unloadCondition %d: %w", i+1, err)
+			}
+		}
+		levels = append(levels, level)
+	}
+	var state HysteresisState
+	if loadedState != nil {
+		var err error
+		state, err = HysteresisStateFromFrame(loadedState)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse loaded state: %w", err)
+		}
+	}
+	return NewMultiLevelHysteresisCommand(refID, referenceVar, levels, state)
+}
+
+// isMultiLevelHysteresis returns true if the conditions of a threshold expression describe a multi-level hysteresis:
+// there is more than one condition, or a condition needs several evaluations to unload.
+func isMultiLevelHysteresis(conditions []ThresholdConditionJSON) bool {
+	if len(conditions) > 1 {
+		return true
+	}
+	return len(conditions) == 1 && conditions[0].UnloadEvaluations > 1
+}
+
+const hysteresisStateFrameType = "hysteresis_state"
+
+// HysteresisStateFromFrame converts data.Frame to HysteresisState.
+// The input data frame must have the uint64 field fingerprint and the int64 fields level and unloading.
+// Returns error if the input data frame has invalid format
+func HysteresisStateFromFrame(frame *data.Frame) (HysteresisState, error) {
+	frameType, frameVersion := frame.TypeInfo("")
+	if frameType != hysteresisStateFrameType {
+		return nil, fmt.Errorf("invalid format of hysteresis state frame: expected frame type '%s'", hysteresisStateFrameType)
+	}
+	if frameVersion.Greater(data.FrameTypeVersion{1, 0}) {
+		return nil, fmt.Errorf("invalid format of hysteresis state frame: expected frame type '%s' of version 1.0 or lower", hysteresisStateFrameType)
+	}
+	if len(frame.Fields) != 3 {
+		return nil, fmt.Errorf("invalid format of hysteresis state frame: expected 3 fields but got %d", len(frame.Fields))
+	}
+	fingerprints, levels, unloading := frame.Fields[0], frame.Fields[1], frame.Fields[2]
+	if fingerprints.Type() != data.FieldTypeUint64 || levels.Type() != data.FieldTypeInt64 || unloading.Type() != data.FieldTypeInt64 {
+		return nil, fmt.Errorf("invalid format of hysteresis state frame: expected fields of types uint64, int64 and int64 but got %s, %s and %s",
+			fingerprints.Type(), levels.Type(), unloading.Type())
+	}
+	result := make(HysteresisState, fingerprints.Len())
+	for i := 0; i < fingerprints.Len(); i++ {
+		result[data.Fingerprint(fingerprints.At(i).(uint64))] = HysteresisSeriesState{
+			Level:     int(levels.At(i).(int64)),
+			Unloading: int(unloading.At(i).(int64)),
+		}
+	}
+	return result, nil
+}
+
+// HysteresisStateToFrame converts HysteresisState to data.Frame. Series at no level are left out.
+func HysteresisStateToFrame(state HysteresisState) *data.Frame {
+	fingerprints := make([]uint64, 0, len(state))
+	levels := make([]int64, 0, len(state))
+	unloading := make([]int64, 0, len(state))
+	for fingerprint, s := range state {
+		if s.Level == 0 {
+			continue
+		}
+		fingerprints = append(fingerprints, uint64(fingerprint))
+		levels = append(levels, int64(s.Level))
+		unloading = append(unloading, int64(s.Unloading))
+	}
+	frame := data.NewFrame("",
+		data.NewField("fingerprint", nil, fingerprints),
+		data.NewField("level", nil, levels),
+		data.NewField("unloading", nil, unloading),
+	)
+	frame.SetMeta(&data.FrameMeta{
+		Type:        hysteresisStateFrameType,
+		TypeVersion: data.FrameTypeVersion{1, 0},
+	})
+	return frame
+}
@@ -0,0 +1,164 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+)
+
+func TestMultiLevelHysteresisExecute(t *testing.T) {
+	tracer := tracing.InitializeTracerForTest()
+	labels := data.Labels{"label": "value"}
+
+	threshold := func(thresholdType ThresholdType, value float64) *ThresholdCommand {
+		cmd, err := NewThresholdCommand("B", "A", thresholdType, []float64{value})
+		require.NoError(t, err)
+		return cmd
+	}
+	// warning above 80 until below 70, critical above 95 until below 90 for 3 evaluations
+	cmd, err := NewMultiLevelHysteresisCommand("B", "A", []HysteresisLevel{
+		{Loading: *threshold(ThresholdIsAbove, 80), Unloading: threshold(ThresholdIsBelow, 70)},
+		{Loading: *threshold(ThresholdIsAbove, 95), Unloading: threshold(ThresholdIsBelow, 90), UnloadEvaluations: 3},
+	}, nil)
+	require.NoError(t, err)
+
+	// evaluate runs the command with the value, passing on the state like alerting does
+	evaluate := func(t *testing.T, value *float64) (*float64, HysteresisSeriesState) {
+		t.Helper()
+		n := mathexp.NewNumber("A", labels)
+		n.SetValue(value)
+		res, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{n}}}, tracer)
+		require.NoError(t, err)
+		require.Len(t, res.Values, 1)
+		result := res.Values[0].(mathexp.Number)
+		require.Equal(t, labels, result.GetLabels())
+		state := result.Frame.Meta.Custom.(HysteresisSeriesState)
+		cmd.State = HysteresisState{labels.Fingerprint(): state}
+		return result.GetFloat64Value(), state
+	}
+
+	steps := []struct {
+		value     *float64
+		expected  *float64
+		unloading int
+	}{
+		{value: fp(50), expected: fp(0)},
+		{value: fp(85), expected: fp(1)},
+		{value: fp(75), expected: fp(1)}, // warning until below 70
+		{value: fp(99), expected: fp(2)},
+		{value: fp(92), expected: fp(2)},                 // critical until below 90
+		{value: fp(85), expected: fp(2), unloading: 1},   // below 90 for 1 evaluation
+		{value: nil, expected: nil, unloading: 1},        // no data keeps the state
+		{value: fp(85), expected: fp(2), unloading: 2},   // below 90 for 2 evaluations
+		{value: fp(91), expected: fp(2)},                 // above 90 resets the count
+		{value: fp(60), expected: fp(2), unloading: 1},   // below 70 but critical needs 3 evaluations
+		{value: fp(60), expected: fp(2), unloading: 2},   //
+		{value: fp(60), expected: fp(0)},                 // drops past warning because it is below 70
+		{value: fp(100), expected: fp(2)},                // enters critical directly
+		{value: fp(80.5), expected: fp(2), unloading: 1}, //
+		{value: fp(80.5), expected: fp(2), unloading: 2}, //
+		{value: fp(80.5), expected: fp(1)},               // drops to warning because it is above 70
+	}
+	for i, step := range steps {
+		value, state := evaluate(t, step.value)
+		require.Equalf(t, step.expected, value, "step %d", i)
+		require.Equalf(t, step.unloading, state.Unloading, "step %d", i)
+	}
+
+	t.Run("should return NoData for NoData", func(t *testing.T) {
+		res, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{mathexp.NewNoData()}}}, tracer)
+		require.NoError(t, err)
+		require.True(t, res.IsNoData())
+	})
+
+	t.Run("should fail for series", func(t *testing.T) {
+		s := mathexp.NewSeries("A", labels, 0)
+		_, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{s}}}, tracer)
+		require.ErrorContains(t, err, "requires numbers")
+	})
+
+	t.Run("should leave the level without an unloading condition when the loading condition is not met", func(t *testing.T) {
+		cmd, err := NewMultiLevelHysteresisCommand("B", "A", []HysteresisLevel{
+			{Loading: *threshold(ThresholdIsAbove, 80), UnloadEvaluations: 2},
+		}, HysteresisState{labels.Fingerprint(): {Level: 1}})
+		require.NoError(t, err)
+		require.Equal(t, HysteresisSeriesState{Level: 1, Unloading: 1}, cmd.next(cmd.State[labels.Fingerprint()], 80))
+		require.Equal(t, HysteresisSeriesState{Level: 1}, cmd.next(HysteresisSeriesState{Level: 1, Unloading: 1}, 81))
+		require.Equal(t, HysteresisSeriesState{}, cmd.next(HysteresisSeriesState{Level: 1, Unloading: 1}, 80))
+	})
+}
+
+func TestUnmarshalMultiLevelHysteresisCommand(t *testing.T) {
+	query := `{
+		"expression" : "A",
+		"type": "threshold",
+		"conditions": [
+			{"evaluator": {"type": "gt", "params": [80]}, "unloadEvaluator": {"type": "lt", "params": [70]}},
+			{"evaluator": {"type": "gt", "params": [95]}, "unloadEvaluations": 3}
+		]
+	}`
+
+	t.Run("should create the command if recovery thresholds are enabled", func(t *testing.T) {
+		node := &rawNode{RefID: "B", QueryRaw: []byte(query)}
+		cmd, err := UnmarshalThresholdCommand(node, featuremgmt.WithFeatures(featuremgmt.FlagRecoveryThreshold))
+		require.NoError(t, err)
+		require.IsType(t, &MultiLevelHysteresisCommand{}, cmd)
+		levels := cmd.(*MultiLevelHysteresisCommand).Levels
+		require.Len(t, levels, 2)
+		require.NotNil(t, levels[0].Unloading)
+		require.Nil(t, levels[1].Unloading)
+		require.Equal(t, 3, levels[1].UnloadEvaluations)
+	})
+
+	t.Run("should fail if recovery thresholds are disabled", func(t *testing.T) {
+		node := &rawNode{RefID: "B", QueryRaw: []byte(query)}
+		_, err := UnmarshalThresholdCommand(node, featuremgmt.WithFeatures())
+		require.ErrorContains(t, err, "exactly one condition")
+	})
+
+	t.Run("should read the loaded state", func(t *testing.T) {
+		var q map[string]any
+		require.NoError(t, json.Unmarshal([]byte(query), &q))
+		require.True(t, IsMultiLevelHysteresisExpression(q))
+		require.False(t, IsHysteresisExpression(q))
+
+		state := HysteresisState{1: {Level: 2, Unloading: 1}, 2: {Level: 1}, 3: {}}
+		require.NoError(t, SetStateToMultiLevelHysteresisCommand(q, state))
+		raw, err := json.Marshal(q)
+		require.NoError(t, err)
+
+		node := &rawNode{RefID: "B", QueryRaw: raw}
+		cmd, err := UnmarshalThresholdCommand(node, featuremgmt.WithFeatures(featuremgmt.FlagRecoveryThreshold))
+		require.NoError(t, err)
+		require.Equal(t, HysteresisState{1: {Level: 2, Unloading: 1}, 2: {Level: 1}}, cmd.(*MultiLevelHysteresisCommand).State)
+	})
+
+	t.Run("should not detect single level thresholds", func(t *testing.T) {
+		var q map[string]any
+		require.NoError(t, json.Unmarshal([]byte(`{"type": "threshold", "conditions": [{"evaluator": {"type": "gt", "params": [1]}, "unloadEvaluator": {"type": "lt", "params": [1]}, "unloadEvaluations": 1}]}`), &q))
+		require.False(t, IsMultiLevelHysteresisExpression(q))
+		require.Error(t, SetStateToMultiLevelHysteresisCommand(q, HysteresisState{}))
+	})
+}
+
+func TestHysteresisStateFromFrame(t *testing.T) {
+	t.Run("should fail for other frame types", func(t *testing.T) {
+		_, err := HysteresisStateFromFrame(FingerprintsToFrame(Fingerprints{1: {}}))
+		require.Error(t, err)
+	})
+
+	t.Run("should fail for invalid fields", func(t *testing.T) {
+		frame := HysteresisStateToFrame(HysteresisState{1: {Level: 1}})
+		frame.Fields[1] = data.NewField("level", nil, []float64{1})
+		_, err := HysteresisStateFromFrame(frame)
+		require.Error(t, err)
+	})
+}
@@ -214,28 +214,8 @@ func (e *State) union(aResults, bResults Results, biNode *parse.BinaryNode) []*U
 	aMatched := make([]bool, len(aResults.Values))
 	bMatched := make([]bool, len(bResults.Values))
 	collectDrops := func() {
-		check := func(v string, matchArray []bool, r *Results) {
-			for i, b := range matchArray {
-				if b {
-					continue
-				}
-				if e.Drops == nil {
-					e.Drops = make(map[string]map[string][]data.Labels)
-				}
-				if e.Drops[biNode.String()] == nil {
-					e.Drops[biNode.String()] = make(map[string][]data.Labels)
-				}
-
-				if r.Values[i].Type() == parse.TypeNoData {
-					continue
-				}
-
-				e.DropCount++
-				e.Drops[biNode.String()][v] = append(e.Drops[biNode.String()][v], r.Values[i].GetLabels())
-			}
-		}
-		check(aVar, aMatched, &aResults)
-		check(bVar, bMatched, &bResults)
+		e.collectDrops(biNode, aVar, aMatched, aResults)
+		e.collectDrops(biNode, bVar, bMatched, bResults)
 	}
 
 	aValueLen := len(aResults.Values)
@@ -306,6 +286,149 @@ func (e *State) union(aResults, bResults Results, biNode *parse.BinaryNode) []*U
 	return unions
 }
 
+// collectDrops records the values of a side of the binary operation that were not matched with a value of the other side.
+func (e *State) collectDrops(biNode *parse.BinaryNode, v string, matchArray []bool, r Results) {
+	for i, b := range matchArray {
+		if b {
+			continue
+		}
+		if e.Drops == nil {
+			e.Drops = make(map[string]map[string][]data.Labels)
+		}
+		if e.Drops[biNode.String()] == nil {
+			e.Drops[biNode.String()] = make(map[string][]data.Labels)
+		}
+
+		if r.Values[i].Type() == parse.TypeNoData {
+			continue
+		}
+
+		e.DropCount++
+		e.Drops[biNode.String()][v] = append(e.Drops[biNode.String()][v], r.Values[i].GetLabels())
+	}
+}
+
+// matchingUnion creates Union objects by pairing the values of both sides that have the same labels,
+// according to the label matching modifiers of the binary operation (on, ignoring, group_left and group_right).
+// Unlike union, it fails instead of guessing if a value matches more than one value of the other side,
+// unless grouping allows it.
+func (e *State) matchingUnion(aResults, bResults Results, biNode *parse.BinaryNode) ([]*Union, error) {
+	m := biNode.Matching
+	unions := []*Union{}
+	if len(aResults.Values) == 0 || len(bResults.Values) == 0 {
+		return unions, nil
+	}
+	for _, r := range []Results{aResults, bResults} {
+		for _, v := range r.Values {
+			if v.Type() == parse.TypeScalar {
+				return nil, fmt.Errorf("label matching in %q is only allowed between series and numbers, got a scalar", biNode)
+			}
+		}
+	}
+	if aResults.Values[0].Type() == parse.TypeNoData || bResults.Values[0].Type() == parse.TypeNoData {
+		return append(unions, &Union{A: aResults.Values[0], B: bResults.Values[0]}), nil
+	}
+
+	// the "one" side is the side that must not have duplicate signatures, which is the right side unless group_right
+	many, one := aResults, bResults
+	if m.Card == parse.MatchOneToMany {
+		many, one = bResults, aResults
+	}
+	oneBySignature := make(map[string]int, len(one.Values))
+	for i, v := range one.Values {
+		sig := matchingSignature(v.GetLabels(), m)
+		if _, ok := oneBySignature[sig]; ok {
+			side := "right"
+			if m.Card == parse.MatchOneToMany {
+				side = "left"
+			}
+			if m.Card == parse.MatchOneToOne {
+				return nil, fmt.Errorf("found duplicate series for the match group %s on the %s side of %q, use group_left or group_right for many-to-one matching", sig, side, biNode)
+			}
+			return nil, fmt.Errorf("found duplicate series for the match group %s on the %s side of %q, the %s side must be unique", sig, side, biNode, side)
+		}
+		oneBySignature[sig] = i
+	}
+
+	manyMatched := make([]bool, len(many.Values))
+	oneMatched := make([]bool, len(one.Values))
+	for i, v := range many.Values {
+		sig := matchingSignature(v.GetLabels(), m)
+		j, ok := oneBySignature[sig]
+		if !ok {
+			continue
+		}
+		if m.Card == parse.MatchOneToOne && oneMatched[j] {
+			return nil, fmt.Errorf("found duplicate series for the match group %s on the left side of %q, use group_left or group_right for many-to-one matching", sig, biNode)
+		}
+		manyMatched[i] = true
+		oneMatched[j] = true
+
+		u := &Union{Labels: matchingLabels(v.GetLabels(), one.Values[j].GetLabels(), m)}
+		if m.Card == parse.MatchOneToMany {
+			u.A, u.B = one.Values[j], v
+		} else {
+			u.A, u.B = v, one.Values[j]
+		}
+		unions = append(unions, u)
+	}
+
+	if m.Card == parse.MatchOneToMany {
+		e.collectDrops(biNode, biNode.Args[0].String(), oneMatched, one)
+		e.collectDrops(biNode, biNode.Args[1].String(), manyMatched, many)
+	} else {
+		e.collectDrops(biNode, biNode.Args[0].String(), manyMatched, many)
+		e.collectDrops(biNode, biNode.Args[1].String(), oneMatched, one)
+	}
+	return unions, nil
+}
+
+// matchingSignature returns the labels that must be equal for two values to match as a string.
+func matchingSignature(labels data.Labels, m *parse.VectorMatching) string {
+	return matchingLabelsOf(labels, m).String()
+}
+
+// matchingLabelsOf returns the labels that are compared when matching: the on labels, or all labels except the ignored ones.
+func matchingLabelsOf(labels data.Labels, m *parse.VectorMatching) data.Labels {
+	result := data.Labels{}
+	if m.On {
+		for _, name := range m.Labels {
+			if v, ok := labels[name]; ok {
+				result[name] = v
+			}
+		}
+		return result
+	}
+	for name, v := range labels {
+		result[name] = v
+	}
+	for _, name := range m.Labels {
+		delete(result, name)
+	}
+	return result
+}
+
+// matchingLabels returns the labels of the result of a matched pair. For one-to-one matching these are the
+// labels that were matched on. For many-to-one and one-to-many matching these are the labels of the "many" side
+// and the included labels of the "one" side.
+func matchingLabels(many, one data.Labels, m *parse.VectorMatching) data.Labels {
+	if m.Card == parse.MatchOneToOne {
+		return matchingLabelsOf(many, m)
+	}
+	result := many.Copy()
+	if result == nil {
+		result = data.Labels{}
+	}
+	for _, name := range m.Include {
+		if v, ok := one[name]; ok {
+			result[name] = v
+		} else {
+			delete(result, name)
+		}
+	}
+	return result
+}
+
 func (e *State) walkBinary(node *parse.BinaryNode) (Results, error) {
 	res := Results{Values: Values{}}
 	ar, err := e.walk(node.Args[0])
@@ -316,7 +439,15 @@ func (e *State) walkBinary(node *parse.BinaryNode) (Results, error) {
 	if err != nil {
 		return res, err
 	}
-	unions := e.union(ar, br, node)
+	var unions []*Union
+	if node.Matching != nil {
+		unions, err = e.matchingUnion(ar, br, node)
+		if err != nil {
+			return res, err
+		}
+	} else {
+		unions = e.union(ar, br, node)
+	}
 	for _, uni := range unions {
 		var value Value
 		switch at := uni.A.(type) {
@@ -587,6 +718,9 @@ func (e *State) walkFunc(node *parse.FuncNode) (Results, error) {
 
 	f := reflect.ValueOf(node.F.F)
 
+	if node.F.Grouping {
+		in = append([]reflect.Value{reflect.ValueOf(node.Grouping)}, in...)
+	}
 	fr := f.Call(append([]reflect.Value{reflect.ValueOf(e)}, in...))
 
 	res = fr[0].Interface().(Results)
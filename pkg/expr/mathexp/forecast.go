@@ -0,0 +1,213 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// The forecast method
+// +enum
+type ForecastMethod string
+
+const (
+	// Least-squares linear regression
+	ForecastLinear ForecastMethod = "linear"
+
+	// Additive Holt-Winters (triple exponential smoothing), or double exponential smoothing without a season
+	ForecastHoltWinters ForecastMethod = "holt_winters"
+)
+
+// HoltWintersParams are the parameters of the Holt-Winters forecast.
+type HoltWintersParams struct {
+	// Alpha is the smoothing factor of the level.
+	Alpha float64
+	// Beta is the smoothing factor of the trend.
+	Beta float64
+	// Gamma is the smoothing factor of the season. It is not used if Season is zero.
+	Gamma float64
+	// Season is the duration of a season. The forecast has no seasonal component if it is zero.
+	Season time.Duration
+}
+
+// Validate returns an error if a smoothing factor is not between 0 and 1 or the season is negative.
+func (p HoltWintersParams) Validate() error {
+	for name, f := range map[string]float64{"alpha": p.Alpha, "beta": p.Beta, "gamma": p.Gamma} {
+		if f <= 0 || f >= 1 {
+			return fmt.Errorf("holt-winters %s must be between 0 and 1 exclusive, got %v", name, f)
+		}
+	}
+	if p.Season < 0 {
+		return fmt.Errorf("holt-winters season must not be negative, got %s", p.Season)
+	}
+	return nil
+}
+
+// Forecaster returns the predicted value of a series at the given time.
+type Forecaster func(t time.Time) float64
+
+type forecastPoint struct {
+	t time.Time
+	v float64
+}
+
+// numericPoints returns the points of the series that have a value that is not NaN or Inf, sorted by time.
+func (s Series) numericPoints() []forecastPoint {
+	points := make([]forecastPoint, 0, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		t, v := s.GetPoint(i)
+		if v == nil || math.IsNaN(*v) || math.IsInf(*v, 0) {
+			continue
+		}
+		points = append(points, forecastPoint{t: t, v: *v})
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].t.Before(points[j].t)
+	})
+	return points
+}
+
+// LinearForecaster fits a line to the series with the least-squares method.
+// It returns false if the series has fewer than two points with a value at different times.
+func (s Series) LinearForecaster() (Forecaster, bool) {
+	points := s.numericPoints()
+	if len(points) < 2 {
+		return nil, false
+	}
+	// use seconds since the first point to keep the sums small
+	origin := points[0].t
+	var sumX, sumY, sumXY, sumXX float64
+	for _, p := range points {
+		x := p.t.Sub(origin).Seconds()
+		sumX += x
+		sumY += p.v
+		sumXY += x * p.v
+		sumXX += x * x
+	}
+	n := float64(len(points))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return nil, false
+	}
+	slope := (n*sumXY - sumX*sumY) / denominator
+	intercept := (sumY - slope*sumX) / n
+	return func(t time.Time) float64 {
+		return intercept + slope*t.Sub(origin).Seconds()
+	}, true
+}
+
+// HoltWintersForecaster applies additive Holt-Winters smoothing to the series and returns the forecaster and the step
+// of the series, which is the median interval between its points. The points are expected to be evenly spaced,
+// so a series with gaps should be resampled first. It returns false if there are not enough points: at least two,
+// or two seasons if there is a season.
+func (s Series) HoltWintersForecaster(params HoltWintersParams) (Forecaster, time.Duration, bool) {
+	points := s.numericPoints()
+	if len(points) < 2 {
+		return nil, 0, false
+	}
+	step := medianStep(points)
+	if step <= 0 {
+		return nil, 0, false
+	}
+
+	seasonLength := int(math.Round(float64(params.Season) / float64(step)))
+	if params.Season == 0 || seasonLength < 2 {
+		seasonLength = 0
+	}
+
+	var level, trend float64
+	var seasonal []float64
+	first := 1
+	if seasonLength == 0 {
+		level = points[0].v
+		trend = points[1].v - points[0].v
+	} else {
+		if len(points) < 2*seasonLength {
+			return nil, 0, false
+		}
+		var firstSeason, secondSeason float64
+		for i := 0; i < seasonLength; i++ {
+			firstSeason += points[i].v
+			secondSeason += points[i+seasonLength].v
+		}
+		firstSeason /= float64(seasonLength)
+		secondSeason /= float64(seasonLength)
+		level = firstSeason
+		trend = (secondSeason - firstSeason) / float64(seasonLength)
+		seasonal = make([]float64, seasonLength)
+		for i := range seasonal {
+			seasonal[i] = points[i].v - firstSeason
+		}
+		first = seasonLength
+	}
+
+	for i := first; i < len(points); i++ {
+		x := points[i].v
+		var s float64
+		if seasonLength > 0 {
+			s = seasonal[i%seasonLength]
+		}
+		previousLevel := level
+		level = params.Alpha*(x-s) + (1-params.Alpha)*(level+trend)
+		trend = params.Beta*(level-previousLevel) + (1-params.Beta)*trend
+		if seasonLength > 0 {
+			seasonal[i%seasonLength] = params.Gamma*(x-level) + (1-params.Gamma)*s
+		}
+	}
+
+	last := len(points) - 1
+	lastTime := points[last].t
+	return func(t time.Time) float64 {
+		h := float64(t.Sub(lastTime)) / float64(step)
+		v := level + h*trend
+		if seasonLength > 0 {
+			idx := (last + int(math.Round(h))) % seasonLength
+			if idx < 0 {
+				idx += seasonLength
+			}
+			v += seasonal[idx]
+		}
+		return v
+	}, step, true
+}
+
+func medianStep(points []forecastPoint) time.Duration {
+	steps := make([]time.Duration, 0, len(points)-1)
+	for i := 1; i < len(points); i++ {
+		steps = append(steps, points[i].t.Sub(points[i-1].t))
+	}
+	sort.Slice(steps, func(i, j int) bool {
+		return steps[i] < steps[j]
+	})
+	return steps[len(steps)/2]
+}
+
+// TimeToThreshold returns the duration from the time from until the forecast first reaches the threshold,
+// looking ahead no further than the horizon. The forecast is sampled every step and linearly interpolated
+// between samples. It returns zero if the forecast at from already meets the threshold, that is if it is at
+// or above the threshold and not falling over the first step, or at or below the threshold and falling.
+// It returns false if the threshold is not reached within the horizon.
+func TimeToThreshold(f Forecaster, from time.Time, horizon, step time.Duration, threshold float64) (time.Duration, bool) {
+	if step <= 0 || step > horizon {
+		step = horizon
+	}
+	previous := f(from) - threshold
+	if falling := f(from.Add(step)) < f(from); previous == 0 || (previous > 0 && !falling) || (previous < 0 && falling) {
+		return 0, true
+	}
+	var previousOffset time.Duration
+	for offset := step; previousOffset < horizon; offset += step {
+		if offset > horizon {
+			offset = horizon
+		}
+		current := f(from.Add(offset)) - threshold
+		if current == 0 || math.Signbit(current) != math.Signbit(previous) {
+			// interpolate between the samples to find where the forecast crosses the threshold
+			fraction := previous / (previous - current)
+			return previousOffset + time.Duration(fraction*float64(offset-previousOffset)), true
+		}
+		previous, previousOffset = current, offset
+	}
+	return 0, false
+}
@@ -0,0 +1,108 @@
+package mathexp
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func forecastSeries(step time.Duration, values ...*float64) Series {
+	s := NewSeries("A", nil, len(values))
+	for i, v := range values {
+		s.SetPoint(i, time.Unix(0, 0).Add(time.Duration(i)*step), v)
+	}
+	return s
+}
+
+func TestLinearForecaster(t *testing.T) {
+	t.Run("should fit a line", func(t *testing.T) {
+		s := forecastSeries(time.Minute, float64Pointer(10), float64Pointer(12), nil, float64Pointer(16), NaN)
+		f, ok := s.LinearForecaster()
+		require.True(t, ok)
+		assert.InDelta(t, 10, f(time.Unix(0, 0)), 1e-9)
+		assert.InDelta(t, 30, f(time.Unix(0, 0).Add(10*time.Minute)), 1e-9)
+	})
+
+	t.Run("should not forecast less than two points", func(t *testing.T) {
+		_, ok := forecastSeries(time.Minute, float64Pointer(10), nil).LinearForecaster()
+		require.False(t, ok)
+	})
+}
+
+func TestHoltWintersForecaster(t *testing.T) {
+	params := HoltWintersParams{Alpha: 0.5, Beta: 0.5, Gamma: 0.5}
+
+	t.Run("should follow a linear trend without a season", func(t *testing.T) {
+		s := forecastSeries(time.Minute, float64Pointer(0), float64Pointer(2), float64Pointer(4), float64Pointer(6), float64Pointer(8))
+		f, step, ok := s.HoltWintersForecaster(params)
+		require.True(t, ok)
+		assert.Equal(t, time.Minute, step)
+		assert.InDelta(t, 18, f(time.Unix(0, 0).Add(9*time.Minute)), 1e-9)
+	})
+
+	t.Run("should repeat the season", func(t *testing.T) {
+		values := make([]*float64, 0, 12)
+		for i := 0; i < 3; i++ {
+			values = append(values, float64Pointer(10), float64Pointer(20), float64Pointer(30), float64Pointer(20))
+		}
+		params := params
+		params.Season = 4 * time.Minute
+		f, _, ok := forecastSeries(time.Minute, values...).HoltWintersForecaster(params)
+		require.True(t, ok)
+		for i, expected := range []float64{10, 20, 30, 20} {
+			assert.InDelta(t, expected, f(time.Unix(0, 0).Add(time.Duration(12+i)*time.Minute)), 1e-9)
+		}
+	})
+
+	t.Run("should require two seasons", func(t *testing.T) {
+		params := params
+		params.Season = 4 * time.Minute
+		s := forecastSeries(time.Minute, float64Pointer(1), float64Pointer(2), float64Pointer(3), float64Pointer(4), float64Pointer(5))
+		_, _, ok := s.HoltWintersForecaster(params)
+		require.False(t, ok)
+	})
+
+	t.Run("should validate params", func(t *testing.T) {
+		require.NoError(t, params.Validate())
+		require.Error(t, HoltWintersParams{Alpha: 1, Beta: 0.5, Gamma: 0.5}.Validate())
+		require.Error(t, HoltWintersParams{Alpha: 0.5, Beta: 0, Gamma: 0.5}.Validate())
+		require.Error(t, HoltWintersParams{Alpha: 0.5, Beta: 0.5, Gamma: 0.5, Season: -time.Hour}.Validate())
+	})
+}
+
+func TestTimeToThreshold(t *testing.T) {
+	from := time.Unix(0, 0)
+	rising := func(t time.Time) float64 { return t.Sub(from).Hours() * 10 }
+
+	tests := []struct {
+		name      string
+		f         Forecaster
+		horizon   time.Duration
+		step      time.Duration
+		threshold float64
+		expected  time.Duration
+		found     bool
+	}{
+		{name: "should interpolate a single step", f: rising, horizon: 4 * time.Hour, threshold: 25, expected: 150 * time.Minute, found: true},
+		{name: "should find the first crossing", f: func(t time.Time) float64 {
+			return math.Sin(t.Sub(from).Hours())
+		}, horizon: 10 * time.Hour, step: time.Minute, threshold: 0.5, expected: 31 * time.Minute, found: true},
+		{name: "should work for falling forecasts", f: func(t time.Time) float64 { return 100 - rising(t) }, horizon: 24 * time.Hour, step: time.Hour, threshold: 0, expected: 10 * time.Hour, found: true},
+		{name: "should return zero when at the threshold", f: rising, horizon: time.Hour, threshold: 0, expected: 0, found: true},
+		{name: "should not find the threshold beyond the horizon", f: rising, horizon: time.Hour, step: time.Minute, threshold: 11},
+		{name: "should return zero when already above the threshold", f: rising, horizon: time.Hour, threshold: -1, expected: 0, found: true},
+		{name: "should return zero when already below the threshold and falling", f: func(t time.Time) float64 { return -rising(t) }, horizon: time.Hour, threshold: 1, expected: 0, found: true},
+		{name: "should find the threshold when falling towards it", f: func(t time.Time) float64 { return 10 - rising(t) }, horizon: 2 * time.Hour, threshold: 5, expected: 30 * time.Minute, found: true},
+		{name: "should not find the threshold when moving away from it", f: func(t time.Time) float64 { return -rising(t) }, horizon: time.Hour, threshold: -11},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, ok := TimeToThreshold(tt.f, from, tt.horizon, tt.step, tt.threshold)
+			require.Equal(t, tt.found, ok)
+			assert.InDelta(t, tt.expected.Seconds(), d.Seconds(), 30)
+		})
+	}
+}
@@ -1,7 +1,13 @@
 package mathexp
 
 import (
+	"fmt"
 	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 
 	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
 )
@@ -68,6 +74,83 @@ var builtins = map[string]parse.Func{
 		VariantReturn: true,
 		F:             floor,
 	},
+	"delta": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      delta,
+	},
+	"derivative": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      derivative,
+	},
+	"rate": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      rate,
+	},
+	"cumsum": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      cumsum,
+	},
+	"moving_avg": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeString},
+		Return: parse.TypeSeriesSet,
+		F:      movingAvg,
+		Check:  checkDurationArg(1, false),
+	},
+	"timeshift": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeString},
+		Return: parse.TypeSeriesSet,
+		F:      timeshift,
+		Check:  checkDurationArg(1, true),
+	},
+	"label_replace": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet, parse.TypeString, parse.TypeString, parse.TypeString, parse.TypeString},
+		VariantReturn: true,
+		F:             labelReplace,
+		Check:         checkLabelReplace,
+	},
+	"label_join": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet, parse.TypeString, parse.TypeString, parse.TypeString},
+		VariantReturn: true,
+		Variadic:      true,
+		F:             labelJoin,
+		Check:         checkLabelJoin,
+	},
+	"label_drop": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet, parse.TypeString},
+		VariantReturn: true,
+		Variadic:      true,
+		F:             labelDrop,
+		Check:         checkLabelList,
+	},
+	"label_keep": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet, parse.TypeString},
+		VariantReturn: true,
+		Variadic:      true,
+		F:             labelKeep,
+		Check:         checkLabelList,
+	},
+	"sum": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet},
+		VariantReturn: true,
+		Grouping:      true,
+		F:             aggregateFunc("sum", sumFloats),
+	},
+	"avg": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet},
+		VariantReturn: true,
+		Grouping:      true,
+		F:             aggregateFunc("avg", avgFloats),
+	},
+	"max": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet},
+		VariantReturn: true,
+		Grouping:      true,
+		F:             aggregateFunc("max", maxFloats),
+	},
 }
 
 // abs returns the absolute value for each result in NumberSet, SeriesSet, or Scalar
@@ -307,3 +390,220 @@ func floor(e *State, varSet Results) (Results, error) {
 	}
 	return newRes, nil
 }
+
+// delta returns, for each point of each series in the SeriesSet, the difference between the point's
+// value and the value of the previous numeric point. See perSeriesDiff for null and NaN handling.
+func delta(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "delta", varSet, func(s Series) Series {
+		return perSeriesDiff(e, s, func(prevT, t time.Time, prev, cur float64) *float64 {
+			d := cur - prev
+			return &d
+		})
+	})
+}
+
+// derivative returns, for each point of each series in the SeriesSet, the per-second change between
+// the point's value and the value of the previous numeric point. See perSeriesDiff for null and NaN handling.
+func derivative(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "derivative", varSet, func(s Series) Series {
+		return perSeriesDiff(e, s, func(prevT, t time.Time, prev, cur float64) *float64 {
+			secs := t.Sub(prevT).Seconds()
+			if secs <= 0 {
+				return nil
+			}
+			d := (cur - prev) / secs
+			return &d
+		})
+	})
+}
+
+// rate returns, for each point of each series in the SeriesSet, the per-second increase of a monotonic counter
+// between the point's value and the value of the previous numeric point. A decrease in value is treated as a counter
+// reset, so the increase is the current value. See perSeriesDiff for null and NaN handling.
+func rate(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "rate", varSet, func(s Series) Series {
+		return perSeriesDiff(e, s, func(prevT, t time.Time, prev, cur float64) *float64 {
+			secs := t.Sub(prevT).Seconds()
+			if secs <= 0 {
+				return nil
+			}
+			increase := cur - prev
+			if cur < prev {
+				increase = cur
+			}
+			r := increase / secs
+			return &r
+		})
+	})
+}
+
+// cumsum returns the running total of each series in the SeriesSet.
+// Null and NaN points are returned as is and are not added to the total.
+func cumsum(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "cumsum", varSet, func(s Series) Series {
+		points := sortedPoints(s)
+		newSeries := NewSeries(e.RefID, s.GetLabels(), len(points))
+		total := float64(0)
+		for i, p := range points {
+			if !isNumeric(p.v) {
+				newSeries.SetPoint(i, p.t, p.v)
+				continue
+			}
+			total += *p.v
+			nF := total
+			newSeries.SetPoint(i, p.t, &nF)
+		}
+		return newSeries
+	})
+}
+
+// movingAvg returns, for each point of each series in the SeriesSet, the mean of the numeric values
+// within the trailing window (t-window, t]. Null and NaN values are ignored, and the point is null
+// when the window has no numeric values.
+func movingAvg(e *State, varSet Results, rawWindow string) (Results, error) {
+	window, err := parseSeriesDuration(rawWindow)
+	if err != nil {
+		return Results{}, err
+	}
+	return perSeries(e, "moving_avg", varSet, func(s Series) Series {
+		points := sortedPoints(s)
+		newSeries := NewSeries(e.RefID, s.GetLabels(), len(points))
+		sum, count, start := float64(0), 0, 0
+		for i, p := range points {
+			if isNumeric(p.v) {
+				sum += *p.v
+				count++
+			}
+			for ; start <= i && !points[start].t.After(p.t.Add(-window)); start++ {
+				if isNumeric(points[start].v) {
+					sum -= *points[start].v
+					count--
+				}
+			}
+			if count == 0 {
+				newSeries.SetPoint(i, p.t, nil)
+				continue
+			}
+			avg := sum / float64(count)
+			newSeries.SetPoint(i, p.t, &avg)
+		}
+		return newSeries
+	})
+}
+
+// timeshift returns each series in the SeriesSet with its time stamps moved by the given duration.
+// A positive duration moves points forward in time, so $A - timeshift($A, "1d") compares each point with
+// the same time of the previous day.
+func timeshift(e *State, varSet Results, rawShift string) (Results, error) {
+	shift, err := parseSeriesDuration(rawShift)
+	if err != nil {
+		return Results{}, err
+	}
+	return perSeries(e, "timeshift", varSet, func(s Series) Series {
+		points := sortedPoints(s)
+		newSeries := NewSeries(e.RefID, s.GetLabels(), len(points))
+		for i, p := range points {
+			newSeries.SetPoint(i, p.t.Add(shift), p.v)
+		}
+		return newSeries
+	})
+}
+
+// seriesPoint is a time/value pair of a Series.
+type seriesPoint struct {
+	t time.Time
+	v *float64
+}
+
+// sortedPoints returns the points of the series ordered by time, oldest first.
+// Unlike Series.SortByTime it does not modify the series, as the same variable may be used elsewhere in the expression.
+func sortedPoints(s Series) []seriesPoint {
+	points := make([]seriesPoint, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		points[i].t, points[i].v = s.GetPoint(i)
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].t.Before(points[j].t)
+	})
+	return points
+}
+
+// isNumeric returns true if the value is neither null nor NaN.
+func isNumeric(f *float64) bool {
+	return f != nil && !math.IsNaN(*f)
+}
+
+// perSeries passes each Series of varSet to seriesF. NoData is returned as is.
+// Numbers and Scalars result in an error since they have no time dimension.
+func perSeries(e *State, name string, varSet Results, seriesF func(s Series) Series) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		switch v := res.(type) {
+		case Series:
+			newRes.Values = append(newRes.Values, seriesF(v))
+		case NoData:
+			newRes.Values = append(newRes.Values, NewNoData())
+		default:
+			return newRes, fmt.Errorf("%s can only be used on time series, got %s", name, res.Type())
+		}
+	}
+	return newRes, nil
+}
+
+// perSeriesDiff passes each numeric point of the series, along with the previous numeric point, to diffF.
+// Null and NaN points are returned as is and are skipped over when looking for the previous point,
+// so a gap in the data does not reset the difference. The first numeric point is null since it has no previous point.
+func perSeriesDiff(e *State, s Series, diffF func(prevT, t time.Time, prev, cur float64) *float64) Series {
+	points := sortedPoints(s)
+	newSeries := NewSeries(e.RefID, s.GetLabels(), len(points))
+	var prev *seriesPoint
+	for i := range points {
+		p := points[i]
+		if !isNumeric(p.v) {
+			newSeries.SetPoint(i, p.t, p.v)
+			continue
+		}
+		if prev == nil {
+			newSeries.SetPoint(i, p.t, nil)
+		} else {
+			newSeries.SetPoint(i, p.t, diffF(prev.t, p.t, *prev.v, *p.v))
+		}
+		prev = &points[i]
+	}
+	return newSeries
+}
+
+// parseSeriesDuration parses a duration string such as "5m" or "1d". A leading "-" makes the duration negative.
+func parseSeriesDuration(raw string) (time.Duration, error) {
+	trimmed := strings.TrimPrefix(raw, "-")
+	d, err := gtime.ParseDuration(trimmed)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: %w", raw, err)
+	}
+	if trimmed != raw {
+		d = -d
+	}
+	return d, nil
+}
+
+// checkDurationArg returns a parse time check that the string argument at argIdx is a valid duration.
+// Zero durations are rejected, and negative durations are only accepted when allowNegative is true.
+func checkDurationArg(argIdx int, allowNegative bool) func(*parse.Tree, *parse.FuncNode) error {
+	return func(t *parse.Tree, f *parse.FuncNode) error {
+		arg, ok := f.Args[argIdx].(*parse.StringNode)
+		if !ok {
+			return fmt.Errorf("parse: expected a duration string for argument %v of %s", argIdx, f.Name)
+		}
+		d, err := parseSeriesDuration(arg.Text)
+		if err != nil {
+			return fmt.Errorf("parse: %s: %w", f.Name, err)
+		}
+		if d == 0 {
+			return fmt.Errorf("parse: %s: duration %q must not be zero", f.Name, arg.Text)
+		}
+		if d < 0 && !allowNegative {
+			return fmt.Errorf("parse: %s: duration %q must be greater than zero", f.Name, arg.Text)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,293 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// checkLabelNames fails if any of the string arguments at the given indexes is empty.
+func checkLabelNames(f *parse.FuncNode, indexes ...int) error {
+	for _, i := range indexes {
+		if s, ok := f.Args[i].(*parse.StringNode); ok && s.Text == "" {
+			return fmt.Errorf("%s: label name in argument %d must not be empty", f.Name, i)
+		}
+	}
+	return nil
+}
+
+// argsFrom returns the indexes of the arguments of the function from index first on.
+func argsFrom(f *parse.FuncNode, first int) []int {
+	var indexes []int
+	for i := first; i < len(f.Args); i++ {
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
+
+func checkLabelJoin(_ *parse.Tree, f *parse.FuncNode) error {
+	return checkLabelNames(f, append([]int{1}, argsFrom(f, 3)...)...)
+}
+
+func checkLabelList(_ *parse.Tree, f *parse.FuncNode) error {
+	return checkLabelNames(f, argsFrom(f, 1)...)
+}
+
+func checkLabelReplace(_ *parse.Tree, f *parse.FuncNode) error {
+	if err := checkLabelNames(f, 1); err != nil {
+		return err
+	}
+	if s, ok := f.Args[4].(*parse.StringNode); ok {
+		if _, err := compileLabelRegex(s.Text); err != nil {
+			return fmt.Errorf("label_replace: invalid regular expression %q: %w", s.Text, err)
+		}
+	}
+	return nil
+}
+
+// compileLabelRegex compiles the regular expression so it must match the whole label value, like in PromQL.
+func compileLabelRegex(regex string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + regex + ")$")
+}
+
+// labelReplace sets the label dst to the replacement if the value of the label src matches the regular expression.
+// The replacement can refer to capture groups of the regular expression, for example $1. The label dst is removed
+// if the replacement is empty. Values that do not match keep their labels.
+func labelReplace(e *State, varSet Results, dst, replacement, src, regex string) (Results, error) {
+	re, err := compileLabelRegex(regex)
+	if err != nil {
+		return Results{}, fmt.Errorf("label_replace: invalid regular expression %q: %w", regex, err)
+	}
+	return relabel(e, varSet, func(labels data.Labels) {
+		value := labels[src]
+		indexes := re.FindStringSubmatchIndex(value)
+		if indexes == nil {
+			return
+		}
+		result := string(re.ExpandString(nil, replacement, value, indexes))
+		if result == "" {
+			delete(labels, dst)
+			return
+		}
+		labels[dst] = result
+	})
+}
+
+// labelJoin sets the label dst to the values of the src labels joined by the separator.
+func labelJoin(e *State, varSet Results, dst, separator string, src ...string) (Results, error) {
+	return relabel(e, varSet, func(labels data.Labels) {
+		values := make([]string, 0, len(src))
+		for _, name := range src {
+			values = append(values, labels[name])
+		}
+		result := strings.Join(values, separator)
+		if result == "" {
+			delete(labels, dst)
+			return
+		}
+		labels[dst] = result
+	})
+}
+
+// labelDrop removes the given labels.
+func labelDrop(e *State, varSet Results, names ...string) (Results, error) {
+	return relabel(e, varSet, func(labels data.Labels) {
+		for _, name := range names {
+			delete(labels, name)
+		}
+	})
+}
+
+// labelKeep removes all labels except the given labels.
+func labelKeep(e *State, varSet Results, names ...string) (Results, error) {
+	return relabel(e, varSet, func(labels data.Labels) {
+		for name := range labels {
+			if !slices.Contains(names, name) {
+				delete(labels, name)
+			}
+		}
+	})
+}
+
+// relabel returns copies of the values with the labels changed by relabelF, which gets a copy of the labels of each value.
+// It fails if two values end up with the same labels, because they could no longer be told apart.
+func relabel(e *State, varSet Results, relabelF func(labels data.Labels)) (Results, error) {
+	newRes := Results{}
+	seen := make(map[string]struct{}, len(varSet.Values))
+	for _, val := range varSet.Values {
+		var newVal Value
+		switch v := val.(type) {
+		case Scalar, NoData:
+			newRes.Values = append(newRes.Values, val)
+			continue
+		case Number:
+			labels := copyLabels(v.GetLabels())
+			relabelF(labels)
+			n := NewNumber(e.RefID, labels)
+			n.SetValue(v.GetFloat64Value())
+			newVal = n
+		case Series:
+			labels := copyLabels(v.GetLabels())
+			relabelF(labels)
+			s := NewSeries(e.RefID, labels, v.Len())
+			for i := 0; i < v.Len(); i++ {
+				t, f := v.GetPoint(i)
+				s.SetPoint(i, t, f)
+			}
+			newVal = s
+		default:
+			return newRes, fmt.Errorf("can not change the labels of type %s", val.Type())
+		}
+		key := newVal.GetLabels().String()
+		if _, ok := seen[key]; ok {
+			return newRes, fmt.Errorf("changing the labels results in more than one %s with the labels %s", val.Type(), key)
+		}
+		seen[key] = struct{}{}
+		newRes.Values = append(newRes.Values, newVal)
+	}
+	return newRes, nil
+}
+
+func copyLabels(labels data.Labels) data.Labels {
+	result := make(data.Labels, len(labels))
+	for k, v := range labels {
+		result[k] = v
+	}
+	return result
+}
+
+// groupLabels returns the labels of the group the labels belong to.
+// Without a grouping, all values belong to the same group without labels.
+func groupLabels(labels data.Labels, grouping *parse.Grouping) data.Labels {
+	result := data.Labels{}
+	if grouping == nil {
+		return result
+	}
+	for k, v := range labels {
+		if slices.Contains(grouping.Labels, k) != grouping.Without {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// aggregateFunc returns a function that aggregates the numbers or series of each group with aggregateF.
+// Null values are skipped, and the result is null if a group has no values. Series are aggregated for each
+// time stamp that any series of the group has a point at.
+func aggregateFunc(name string, aggregateF func(values []float64) float64) func(*State, *parse.Grouping, Results) (Results, error) {
+	aggregate := func(values []*float64) *float64 {
+		floats := make([]float64, 0, len(values))
+		for _, v := range values {
+			if v != nil {
+				floats = append(floats, *v)
+			}
+		}
+		if len(floats) == 0 {
+			return nil
+		}
+		f := aggregateF(floats)
+		return &f
+	}
+
+	return func(e *State, grouping *parse.Grouping, varSet Results) (Results, error) {
+		type group struct {
+			labels data.Labels
+			values []Value
+		}
+		var groups []*group
+		byKey := map[string]*group{}
+		newRes := Results{}
+		for _, val := range varSet.Values {
+			switch val.(type) {
+			case Scalar, NoData:
+				newRes.Values = append(newRes.Values, val)
+				continue
+			case Number, Series:
+			default:
+				return newRes, fmt.Errorf("%s can not aggregate type %s", name, val.Type())
+			}
+			labels := groupLabels(val.GetLabels(), grouping)
+			key := labels.String()
+			g, ok := byKey[key]
+			if !ok {
+				g = &group{labels: labels}
+				byKey[key] = g
+				groups = append(groups, g)
+			}
+			g.values = append(g.values, val)
+		}
+
+		for _, g := range groups {
+			if _, ok := g.values[0].(Number); ok {
+				values := make([]*float64, 0, len(g.values))
+				for _, v := range g.values {
+					n, ok := v.(Number)
+					if !ok {
+						return newRes, fmt.Errorf("%s can not aggregate numbers and series in the same group %s", name, g.labels)
+					}
+					values = append(values, n.GetFloat64Value())
+				}
+				n := NewNumber(e.RefID, g.labels)
+				n.SetValue(aggregate(values))
+				newRes.Values = append(newRes.Values, n)
+				continue
+			}
+
+			pointsByTime := map[time.Time][]*float64{}
+			var times []time.Time
+			for _, v := range g.values {
+				s, ok := v.(Series)
+				if !ok {
+					return newRes, fmt.Errorf("%s can not aggregate numbers and series in the same group %s", name, g.labels)
+				}
+				for i := 0; i < s.Len(); i++ {
+					t, f := s.GetPoint(i)
+					if _, ok := pointsByTime[t]; !ok {
+						times = append(times, t)
+					}
+					pointsByTime[t] = append(pointsByTime[t], f)
+				}
+			}
+			sort.Slice(times, func(i, j int) bool {
+				return times[i].Before(times[j])
+			})
+			s := NewSeries(e.RefID, g.labels, len(times))
+			for i, t := range times {
+				s.SetPoint(i, t, aggregate(pointsByTime[t]))
+			}
+			newRes.Values = append(newRes.Values, s)
+		}
+		return newRes, nil
+	}
+}
+
+func sumFloats(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum
+}
+
+func avgFloats(values []float64) float64 {
+	return sumFloats(values) / float64(len(values))
+}
+
+func maxFloats(values []float64) float64 {
+	result := math.Inf(-1)
+	for _, v := range values {
+		if math.IsNaN(v) {
+			return math.NaN()
+		}
+		result = math.Max(result, v)
+	}
+	return result
+}
@@ -0,0 +1,179 @@
+package mathexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestLabelFuncs(t *testing.T) {
+	numbers := Vars{
+		"A": resultValuesNoErr(
+			makeNumber("", data.Labels{"instance": "web-1:9100", "dc": "eu", "env": "prod"}, float64Pointer(1)),
+			makeNumber("", data.Labels{"instance": "web-2:9100", "dc": "eu", "env": "dev"}, float64Pointer(2)),
+			makeNumber("", data.Labels{"instance": "db-1:9100", "dc": "us", "env": "prod"}, nil),
+		),
+	}
+
+	var tests = []struct {
+		name      string
+		expr      string
+		newErrIs  assert.ErrorAssertionFunc
+		execErrIs assert.ErrorAssertionFunc
+		results   Results
+	}{
+		{
+			name:      "label_replace sets the label from capture groups",
+			expr:      `label_replace($A, "host", "$1", "instance", "(.*):.*")`,
+			newErrIs:  assert.NoError,
+			execErrIs: assert.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"instance": "web-1:9100", "host": "web-1", "dc": "eu", "env": "prod"}, float64Pointer(1)),
+				makeNumber("", data.Labels{"instance": "web-2:9100", "host": "web-2", "dc": "eu", "env": "dev"}, float64Pointer(2)),
+				makeNumber("", data.Labels{"instance": "db-1:9100", "host": "db-1", "dc": "us", "env": "prod"}, nil),
+			),
+		},
+		{
+			name:      "label_replace keeps labels that do not match the whole value",
+			expr:      `label_replace($A, "role", "database", "instance", "db")`,
+			newErrIs:  assert.NoError,
+			execErrIs: assert.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"instance": "web-1:9100", "dc": "eu", "env": "prod"}, float64Pointer(1)),
+				makeNumber("", data.Labels{"instance": "web-2:9100", "dc": "eu", "env": "dev"}, float64Pointer(2)),
+				makeNumber("", data.Labels{"instance": "db-1:9100", "dc": "us", "env": "prod"}, nil),
+			),
+		},
+		{
+			name:     "label_replace fails on an invalid regular expression",
+			expr:     `label_replace($A, "host", "$1", "instance", "(.*")`,
+			newErrIs: assert.Error,
+		},
+		{
+			name:      "label_join joins the labels",
+			expr:      `label_drop(label_join($A, "where", "/", "dc", "env"), "instance", "dc", "env")`,
+			newErrIs:  assert.NoError,
+			execErrIs: assert.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"where": "eu/prod"}, float64Pointer(1)),
+				makeNumber("", data.Labels{"where": "eu/dev"}, float64Pointer(2)),
+				makeNumber("", data.Labels{"where": "us/prod"}, nil),
+			),
+		},
+		{
+			name:      "label_keep keeps only the labels",
+			expr:      `label_keep($A, "instance")`,
+			newErrIs:  assert.NoError,
+			execErrIs: assert.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"instance": "web-1:9100"}, float64Pointer(1)),
+				makeNumber("", data.Labels{"instance": "web-2:9100"}, float64Pointer(2)),
+				makeNumber("", data.Labels{"instance": "db-1:9100"}, nil),
+			),
+		},
+		{
+			name:      "label_drop fails if labels are no longer unique",
+			expr:      `label_drop($A, "instance", "env")`,
+			newErrIs:  assert.NoError,
+			execErrIs: assert.Error,
+		},
+		{
+			name:     "label_drop requires a label",
+			expr:     `label_drop($A)`,
+			newErrIs: assert.Error,
+		},
+		{
+			name:     "label_drop requires label names",
+			expr:     `label_drop($A, "dc", "")`,
+			newErrIs: assert.Error,
+		},
+		{
+			name:      "sum by aggregates the groups and skips nulls",
+			expr:      `sum by(dc) ($A)`,
+			newErrIs:  assert.NoError,
+			execErrIs: assert.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"dc": "eu"}, float64Pointer(3)),
+				makeNumber("", data.Labels{"dc": "us"}, nil),
+			),
+		},
+		{
+			name:      "avg without removes the labels",
+			expr:      `avg without(instance, dc) ($A)`,
+			newErrIs:  assert.NoError,
+			execErrIs: assert.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"env": "prod"}, float64Pointer(1)),
+				makeNumber("", data.Labels{"env": "dev"}, float64Pointer(2)),
+			),
+		},
+		{
+			name:      "max without grouping aggregates everything",
+			expr:      `max($A) * 10`,
+			newErrIs:  assert.NoError,
+			execErrIs: assert.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", nil, float64Pointer(20)),
+			),
+		},
+		{
+			name:     "by is only allowed for aggregations",
+			expr:     `abs by(dc) ($A)`,
+			newErrIs: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if err != nil {
+				return
+			}
+			res, err := e.Execute("", numbers, tracing.InitializeTracerForTest())
+			tt.execErrIs(t, err)
+			if err != nil {
+				return
+			}
+			require.Len(t, res.Values, len(tt.results.Values))
+			for i, expected := range tt.results.Values {
+				assert.Equal(t, expected.GetLabels(), res.Values[i].GetLabels())
+				assert.Equal(t, expected.(Number).GetFloat64Value(), res.Values[i].(Number).GetFloat64Value())
+			}
+		})
+	}
+}
+
+func TestAggregateSeries(t *testing.T) {
+	vars := Vars{
+		"A": resultValuesNoErr(
+			makeSeries("", data.Labels{"host": "a", "cpu": "0"},
+				tp{time.Unix(5, 0), float64Pointer(1)},
+				tp{time.Unix(10, 0), float64Pointer(2)},
+			),
+			makeSeries("", data.Labels{"host": "a", "cpu": "1"},
+				tp{time.Unix(10, 0), float64Pointer(4)},
+				tp{time.Unix(15, 0), nil},
+			),
+		),
+	}
+	e, err := New(`sum by(host) ($A)`)
+	require.NoError(t, err)
+	assert.Equal(t, "sum by(host) ($A)", e.Tree.String())
+	res, err := e.Execute("", vars, tracing.InitializeTracerForTest())
+	require.NoError(t, err)
+	require.Len(t, res.Values, 1)
+	assert.Equal(t, makeSeries("", data.Labels{"host": "a"},
+		tp{time.Unix(5, 0), float64Pointer(1)},
+		tp{time.Unix(10, 0), float64Pointer(6)},
+		tp{time.Unix(15, 0), nil},
+	), res.Values[0])
+
+	// the input is not changed
+	assert.Equal(t, data.Labels{"host": "a", "cpu": "0"}, vars["A"].Values[0].GetLabels())
+}
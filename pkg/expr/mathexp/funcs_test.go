@@ -5,6 +5,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/stretchr/testify/require"
 )
@@ -140,3 +142,224 @@ func TestIsNumberFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestSeriesWindowFuncs(t *testing.T) {
+	counter := Vars{
+		"A": resultValuesNoErr(
+			makeSeries("", data.Labels{"host": "a"},
+				tp{time.Unix(0, 0), float64Pointer(10)},
+				tp{time.Unix(10, 0), float64Pointer(30)},
+				tp{time.Unix(20, 0), nil},
+				tp{time.Unix(30, 0), float64Pointer(70)},
+				tp{time.Unix(40, 0), float64Pointer(20)}),
+		),
+	}
+	var tests = []struct {
+		name      string
+		expr      string
+		vars      Vars
+		newErrIs  require.ErrorAssertionFunc
+		execErrIs require.ErrorAssertionFunc
+		results   Results
+	}{
+		{
+			name:      "delta skips over null points",
+			expr:      "delta($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(0, 0), nil},
+					tp{time.Unix(10, 0), float64Pointer(20)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), float64Pointer(40)},
+					tp{time.Unix(40, 0), float64Pointer(-50)}),
+			),
+		},
+		{
+			name:      "derivative is per second and can be negative",
+			expr:      "derivative($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(0, 0), nil},
+					tp{time.Unix(10, 0), float64Pointer(2)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), float64Pointer(2)},
+					tp{time.Unix(40, 0), float64Pointer(-5)}),
+			),
+		},
+		{
+			name:      "rate treats a decrease as a counter reset",
+			expr:      "rate($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(0, 0), nil},
+					tp{time.Unix(10, 0), float64Pointer(2)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), float64Pointer(2)},
+					tp{time.Unix(40, 0), float64Pointer(2)}),
+			),
+		},
+		{
+			name:      "cumsum keeps null points",
+			expr:      "cumsum($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(0, 0), float64Pointer(10)},
+					tp{time.Unix(10, 0), float64Pointer(40)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), float64Pointer(110)},
+					tp{time.Unix(40, 0), float64Pointer(130)}),
+			),
+		},
+		{
+			name:      "moving_avg over a trailing window ignores null points",
+			expr:      `moving_avg($A, "20s")`,
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(0, 0), float64Pointer(10)},
+					tp{time.Unix(10, 0), float64Pointer(20)},
+					tp{time.Unix(20, 0), float64Pointer(30)},
+					tp{time.Unix(30, 0), float64Pointer(70)},
+					tp{time.Unix(40, 0), float64Pointer(45)}),
+			),
+		},
+		{
+			name:      "timeshift moves points forward",
+			expr:      `timeshift($A, "1m")`,
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(60, 0), float64Pointer(10)},
+					tp{time.Unix(70, 0), float64Pointer(30)},
+					tp{time.Unix(80, 0), nil},
+					tp{time.Unix(90, 0), float64Pointer(70)},
+					tp{time.Unix(100, 0), float64Pointer(20)}),
+			),
+		},
+		{
+			name:      "timeshift with a negative duration moves points backward",
+			expr:      `timeshift($A, "-10s") - $A`,
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(0, 0), float64Pointer(20)},
+					tp{time.Unix(10, 0), nil},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(30, 0), float64Pointer(-50)}),
+			),
+		},
+		{
+			name: "unsorted series are ordered by time",
+			expr: "delta($A)",
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("", nil,
+						tp{time.Unix(10, 0), float64Pointer(5)},
+						tp{time.Unix(0, 0), float64Pointer(2)}),
+				),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), nil},
+					tp{time.Unix(10, 0), float64Pointer(3)}),
+			),
+		},
+		{
+			name:      "no data is passed through",
+			expr:      "rate($A)",
+			vars:      Vars{"A": resultValuesNoErr(NewNoData())},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   resultValuesNoErr(NewNoData()),
+		},
+		{
+			name:      "rate on number - should error",
+			expr:      "rate($A)",
+			vars:      Vars{"A": resultValuesNoErr(makeNumber("", nil, float64Pointer(1)))},
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+			results:   Results{},
+		},
+		{
+			name:     "rate on scalar - should error",
+			expr:     "rate(1)",
+			newErrIs: require.Error,
+		},
+		{
+			name:     "moving_avg with invalid window - should error",
+			expr:     `moving_avg($A, "5 minutes")`,
+			newErrIs: require.Error,
+		},
+		{
+			name:     "moving_avg with negative window - should error",
+			expr:     `moving_avg($A, "-5m")`,
+			newErrIs: require.Error,
+		},
+		{
+			name:     "moving_avg without window - should error",
+			expr:     `moving_avg($A)`,
+			newErrIs: require.Error,
+		},
+		{
+			name:     "timeshift with zero duration - should error",
+			expr:     `timeshift($A, "0s")`,
+			newErrIs: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if e != nil {
+				res, err := e.Execute("", tt.vars, tracing.InitializeTracerForTest())
+				tt.execErrIs(t, err)
+				require.Equal(t, tt.results, res)
+			}
+		})
+	}
+}
+
+func TestSeriesWindowFuncsNaN(t *testing.T) {
+	vars := Vars{
+		"A": resultValuesNoErr(
+			makeSeries("", nil,
+				tp{time.Unix(0, 0), float64Pointer(1)},
+				tp{time.Unix(10, 0), float64Pointer(math.NaN())},
+				tp{time.Unix(20, 0), float64Pointer(4)}),
+		),
+	}
+	for _, expr := range []string{"delta($A)", "rate($A)", "derivative($A)", "cumsum($A)"} {
+		t.Run(expr, func(t *testing.T) {
+			e, err := New(expr)
+			require.NoError(t, err)
+			res, err := e.Execute("", vars, tracing.InitializeTracerForTest())
+			require.NoError(t, err)
+			require.Len(t, res.Values, 1)
+			s := res.Values[0].(Series)
+			require.Equal(t, 3, s.Len())
+			require.True(t, math.IsNaN(*s.GetValue(1)), "NaN points should be returned as NaN")
+			require.NotNil(t, s.GetValue(2))
+			require.False(t, math.IsNaN(*s.GetValue(2)), "NaN points should not affect later points")
+		})
+	}
+}
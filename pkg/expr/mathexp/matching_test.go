@@ -0,0 +1,141 @@
+package mathexp
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestLabelMatching(t *testing.T) {
+	// used bytes per host and mount point, and capacity per host
+	used := Vars{
+		"A": resultValuesNoErr(
+			makeNumber("", data.Labels{"host": "a", "mount": "/"}, float64Pointer(10)),
+			makeNumber("", data.Labels{"host": "a", "mount": "/var"}, float64Pointer(20)),
+			makeNumber("", data.Labels{"host": "b", "mount": "/"}, float64Pointer(30)),
+		),
+		"B": resultValuesNoErr(
+			makeNumber("", data.Labels{"host": "a", "role": "db"}, float64Pointer(100)),
+			makeNumber("", data.Labels{"host": "b", "role": "web"}, float64Pointer(200)),
+			makeNumber("", data.Labels{"host": "c", "role": "web"}, float64Pointer(300)),
+		),
+		"C": resultValuesNoErr(
+			makeNumber("", data.Labels{"host": "a", "mount": "/"}, float64Pointer(1)),
+			makeNumber("", data.Labels{"host": "b", "mount": "/"}, float64Pointer(2)),
+		),
+	}
+
+	var tests = []struct {
+		name      string
+		expr      string
+		execErrIs assert.ErrorAssertionFunc
+		results   Results
+	}{
+		{
+			name:      "group_left keeps the labels of the left side and includes labels of the right side",
+			expr:      "$A / on(host) group_left(role) $B",
+			execErrIs: assert.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"host": "a", "mount": "/", "role": "db"}, float64Pointer(0.1)),
+				makeNumber("", data.Labels{"host": "a", "mount": "/var", "role": "db"}, float64Pointer(0.2)),
+				makeNumber("", data.Labels{"host": "b", "mount": "/", "role": "web"}, float64Pointer(0.15)),
+			),
+		},
+		{
+			name:      "group_right keeps the labels of the right side",
+			expr:      "$B - on(host) group_right $A",
+			execErrIs: assert.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"host": "a", "mount": "/"}, float64Pointer(90)),
+				makeNumber("", data.Labels{"host": "a", "mount": "/var"}, float64Pointer(80)),
+				makeNumber("", data.Labels{"host": "b", "mount": "/"}, float64Pointer(170)),
+			),
+		},
+		{
+			name:      "one-to-one on returns only the matched labels",
+			expr:      "$C + on(host) $B",
+			execErrIs: assert.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"host": "a"}, float64Pointer(101)),
+				makeNumber("", data.Labels{"host": "b"}, float64Pointer(202)),
+			),
+		},
+		{
+			name:      "one-to-one ignoring drops the ignored labels",
+			expr:      "$A * ignoring(mount) $C",
+			execErrIs: assert.Error, // host a has two series in A
+		},
+		{
+			name:      "one-to-one ignoring matches the other labels",
+			expr:      "$C * ignoring(mount) $C",
+			execErrIs: assert.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"host": "a"}, float64Pointer(1)),
+				makeNumber("", data.Labels{"host": "b"}, float64Pointer(4)),
+			),
+		},
+		{
+			name:      "many-to-many fails without grouping",
+			expr:      "$A / on(host) $B",
+			execErrIs: assert.Error,
+		},
+		{
+			name:      "duplicates on the one side fail",
+			expr:      "$B / on(role) group_left $A",
+			execErrIs: assert.Error,
+		},
+		{
+			name:      "scalars cannot be matched",
+			expr:      "$A * on(host) 2",
+			execErrIs: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			require.NoError(t, err)
+			res, err := e.Execute("", used, tracing.InitializeTracerForTest())
+			tt.execErrIs(t, err)
+			if err != nil {
+				return
+			}
+			require.Len(t, res.Values, len(tt.results.Values))
+			for i, expected := range tt.results.Values {
+				assert.Equal(t, expected.GetLabels(), res.Values[i].GetLabels())
+				assert.InDelta(t, *expected.(Number).GetFloat64Value(), *res.Values[i].(Number).GetFloat64Value(), 1e-9)
+			}
+		})
+	}
+}
+
+func TestLabelMatchingParse(t *testing.T) {
+	var tests = []struct {
+		expr    string
+		errIs   assert.ErrorAssertionFunc
+		printed string
+	}{
+		{expr: "$A/on(host)$B", errIs: assert.NoError, printed: "$A / on(host) $B"},
+		{expr: `$A + ignoring(k8s_pod, "service.name") $B`, errIs: assert.NoError, printed: "$A + ignoring(k8s_pod, service.name) $B"},
+		{expr: "$A * on() group_left $B", errIs: assert.NoError, printed: "$A * on() group_left() $B"},
+		{expr: "$A > on(host) group_right(role, dc) abs($B)", errIs: assert.NoError, printed: "$A > on(host) group_right(role, dc) abs($B)"},
+		{expr: "$A + on(host) group_left(host) $B", errIs: assert.Error},
+		{expr: "$A + on host $B", errIs: assert.Error},
+		{expr: "$A + on(host,) $B", errIs: assert.NoError, printed: "$A + on(host) $B"},
+		{expr: "$A + on(,) $B", errIs: assert.Error},
+		{expr: "$A + group_left $B", errIs: assert.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.errIs(t, err)
+			if err == nil {
+				assert.Equal(t, tt.printed, e.Tree.String())
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ package parse
 import (
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 // A Node is an element in the parse tree. The interface is trivial.
@@ -125,6 +126,31 @@ type FuncNode struct {
 	F      *Func
 	Args   []Node
 	Prefix string
+	// Grouping is nil unless the function has a by or without modifier.
+	Grouping *Grouping
+}
+
+// Grouping holds the by or without modifier of an aggregation function.
+type Grouping struct {
+	// Without is true if the values are grouped by all labels except Labels, and false if they are grouped by Labels only.
+	Without bool
+	Labels  []string
+}
+
+// String returns the modifier as it is written in an expression.
+func (g *Grouping) String() string {
+	keyword := "by"
+	if g.Without {
+		keyword = "without"
+	}
+	return fmt.Sprintf("%s(%s)", keyword, strings.Join(g.Labels, ", "))
+}
+
+func (f *FuncNode) name() string {
+	if f.Grouping != nil {
+		return fmt.Sprintf("%s %s ", f.Name, f.Grouping)
+	}
+	return f.Name
 }
 
 func newFunc(pos Pos, name string, f Func) *FuncNode {
@@ -137,7 +163,7 @@ func (f *FuncNode) append(arg Node) {
 
 // String returns the string representation of the FuncNode so it fulfills the Node interface.
 func (f *FuncNode) String() string {
-	s := f.Name + "("
+	s := f.name() + "("
 	for i, arg := range f.Args {
 		if i > 0 {
 			s += ", "
@@ -150,7 +176,7 @@ func (f *FuncNode) String() string {
 
 // StringAST returns the string representation of abstract syntax tree of the FuncNode so it fulfills the Node interface.
 func (f *FuncNode) StringAST() string {
-	s := f.Name + "("
+	s := f.name() + "("
 	for i, arg := range f.Args {
 		if i > 0 {
 			s += ", "
@@ -165,12 +191,12 @@ func (f *FuncNode) StringAST() string {
 func (f *FuncNode) Check(t *Tree) error {
 	if len(f.Args) < len(f.F.Args) {
 		return fmt.Errorf("parse: not enough arguments for %s", f.Name)
-	} else if len(f.Args) > len(f.F.Args) {
+	} else if len(f.Args) > len(f.F.Args) && !f.F.Variadic {
 		return fmt.Errorf("parse: too many arguments for %s", f.Name)
 	}
 
 	for i, arg := range f.Args {
-		funcType := f.F.Args[i]
+		funcType := f.F.Args[min(i, len(f.F.Args)-1)]
 		argType := arg.Return()
 		// if funcType == TypeNumberSet && argType == TypeScalar {
 		// 	argType = TypeNumberSet
@@ -300,22 +326,69 @@ type BinaryNode struct {
 	Args     [2]Node
 	Operator item
 	OpStr    string
+	// Matching is nil unless the operator has label matching modifiers.
+	Matching *VectorMatching
 }
 
-func newBinary(operator item, arg1, arg2 Node) *BinaryNode {
-	return &BinaryNode{NodeType: NodeBinary, Pos: operator.pos, Args: [2]Node{arg1, arg2}, Operator: operator, OpStr: operator.val}
+func newBinary(operator item, arg1, arg2 Node, matching *VectorMatching) *BinaryNode {
+	return &BinaryNode{NodeType: NodeBinary, Pos: operator.pos, Args: [2]Node{arg1, arg2}, Operator: operator, OpStr: operator.val, Matching: matching}
 }
 
 // String returns the string representation of the BinaryNode so it fulfills the Node interface.
 func (b *BinaryNode) String() string {
+	if b.Matching != nil {
+		return fmt.Sprintf("%s %s %s %s", b.Args[0], b.Operator.val, b.Matching, b.Args[1])
+	}
 	return fmt.Sprintf("%s %s %s", b.Args[0], b.Operator.val, b.Args[1])
 }
 
 // StringAST returns the string representation of abstract syntax tree of the BinaryNode so it fulfills the Node interface.
 func (b *BinaryNode) StringAST() string {
+	if b.Matching != nil {
+		return fmt.Sprintf("%s %s(%s, %s)", b.Operator.val, b.Matching, b.Args[0], b.Args[1])
+	}
 	return fmt.Sprintf("%s(%s, %s)", b.Operator.val, b.Args[0], b.Args[1])
 }
 
+// MatchCardinality is the cardinality of the label matching of a binary operation.
+type MatchCardinality int
+
+const (
+	// MatchOneToOne pairs each value on the left with at most one value on the right.
+	MatchOneToOne MatchCardinality = iota
+	// MatchManyToOne pairs many values on the left with one value on the right (group_left).
+	MatchManyToOne
+	// MatchOneToMany pairs one value on the left with many values on the right (group_right).
+	MatchOneToMany
+)
+
+// VectorMatching describes how the values on the two sides of a binary operation are paired by their labels,
+// like the on, ignoring, group_left and group_right modifiers of PromQL.
+type VectorMatching struct {
+	Card MatchCardinality
+	// On is true if values are matched on Labels only, and false if they are matched on all labels except Labels.
+	On     bool
+	Labels []string
+	// Include are the labels copied from the "one" side to the result of a many-to-one or one-to-many match.
+	Include []string
+}
+
+// String returns the modifiers as they are written in an expression.
+func (m *VectorMatching) String() string {
+	keyword := "ignoring"
+	if m.On {
+		keyword = "on"
+	}
+	s := fmt.Sprintf("%s(%s)", keyword, strings.Join(m.Labels, ", "))
+	switch m.Card {
+	case MatchManyToOne:
+		s += fmt.Sprintf(" group_left(%s)", strings.Join(m.Include, ", "))
+	case MatchOneToMany:
+		s += fmt.Sprintf(" group_right(%s)", strings.Join(m.Include, ", "))
+	}
+	return s
+}
+
 // Check performs parse time checking on the BinaryNode so it fulfills the Node interface.
 func (b *BinaryNode) Check(t *Tree) error {
 	return nil
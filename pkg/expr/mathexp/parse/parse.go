@@ -35,6 +35,11 @@ type Func struct {
 	F             interface{}
 	VariantReturn bool
 	Check         func(*Tree, *FuncNode) error
+	// Variadic allows the last argument to be repeated.
+	Variadic bool
+	// Grouping allows the by and without modifiers. The *Grouping, which is nil without a modifier,
+	// is passed to F before the arguments.
+	Grouping bool
 }
 
 // Parse returns a Tree, created by parsing the expression described in the
@@ -165,16 +170,19 @@ func (t *Tree) parse() {
 }
 
 /* Grammar:
-O -> A {"||" A}
-A -> C {"&&" C}
-C -> P {( "==" | "!=" | ">" | ">=" | "<" | "<=") P}
-P -> M {( "+" | "-" ) M}
-M -> E {( "*" | "/" ) F}
-E -> F {( "**" ) F}
+O -> A {"||" [Match] A}
+A -> C {"&&" [Match] C}
+C -> P {( "==" | "!=" | ">" | ">=" | "<" | "<=") [Match] P}
+P -> M {( "+" | "-" ) [Match] M}
+M -> E {( "*" | "/" ) [Match] F}
+E -> F {( "**" ) [Match] F}
 F -> v | "(" O ")" | "!" O | "-" O
 v -> number | func(..) | queryVar
-Func -> name "(" param {"," param} ")"
-param -> number | "string" | queryVar
+Func -> name [( "by" | "without" ) Labels] "(" param {"," param} ")"
+param -> O | "string"
+Match -> ( "on" | "ignoring" ) Labels [( "group_left" | "group_right" ) [Labels]]
+Labels -> "(" [label {"," label}] ")"
+label -> name | "string"
 */
 
 // expr:
@@ -185,7 +193,7 @@ func (t *Tree) O() Node {
 	for {
 		switch t.peek().typ {
 		case itemOr:
-			n = newBinary(t.next(), n, t.A())
+			n = t.binary(t.next(), n, t.A)
 		default:
 			return n
 		}
@@ -198,7 +206,7 @@ func (t *Tree) A() Node {
 	for {
 		switch t.peek().typ {
 		case itemAnd:
-			n = newBinary(t.next(), n, t.C())
+			n = t.binary(t.next(), n, t.C)
 		default:
 			return n
 		}
@@ -211,7 +219,7 @@ func (t *Tree) C() Node {
 	for {
 		switch t.peek().typ {
 		case itemEq, itemNotEq, itemGreater, itemGreaterEq, itemLess, itemLessEq:
-			n = newBinary(t.next(), n, t.P())
+			n = t.binary(t.next(), n, t.P)
 		default:
 			return n
 		}
@@ -224,7 +232,7 @@ func (t *Tree) P() Node {
 	for {
 		switch t.peek().typ {
 		case itemPlus, itemMinus:
-			n = newBinary(t.next(), n, t.M())
+			n = t.binary(t.next(), n, t.M)
 		default:
 			return n
 		}
@@ -237,7 +245,7 @@ func (t *Tree) M() Node {
 	for {
 		switch t.peek().typ {
 		case itemMult, itemDiv, itemMod:
-			n = newBinary(t.next(), n, t.E())
+			n = t.binary(t.next(), n, t.E)
 		default:
 			return n
 		}
@@ -250,13 +258,86 @@ func (t *Tree) E() Node {
 	for {
 		switch t.peek().typ {
 		case itemPow:
-			n = newBinary(t.next(), n, t.F())
+			n = t.binary(t.next(), n, t.F)
 		default:
 			return n
 		}
 	}
 }
 
+// binary parses the optional Match of the operator and the right operand, and returns the BinaryNode.
+func (t *Tree) binary(operator item, left Node, right func() Node) Node {
+	matching := t.Match()
+	return newBinary(operator, left, right(), matching)
+}
+
+// Match is ( "on" | "ignoring" ) Labels [( "group_left" | "group_right" ) [Labels]] in the grammar.
+// It returns nil if the next token is not "on" or "ignoring".
+func (t *Tree) Match() *VectorMatching {
+	token := t.peek()
+	if token.typ != itemFunc || (token.val != "on" && token.val != "ignoring") {
+		return nil
+	}
+	t.next()
+	m := &VectorMatching{
+		Card:   MatchOneToOne,
+		On:     token.val == "on",
+		Labels: t.Labels(token.val),
+	}
+
+	token = t.peek()
+	if token.typ != itemFunc || (token.val != "group_left" && token.val != "group_right") {
+		return m
+	}
+	t.next()
+	m.Card = MatchManyToOne
+	if token.val == "group_right" {
+		m.Card = MatchOneToMany
+	}
+	if t.peek().typ == itemLeftParen {
+		m.Include = t.Labels(token.val)
+	}
+	if m.On {
+		for _, l := range m.Include {
+			for _, on := range m.Labels {
+				if l == on {
+					t.errorf("label %q must not occur in on and %s at the same time", l, token.val)
+				}
+			}
+		}
+	}
+	return m
+}
+
+// Labels is "(" [label {"," label}] ")" in the grammar.
+func (t *Tree) Labels(context string) []string {
+	t.expect(itemLeftParen, context)
+	labels := []string{}
+	for {
+		switch token := t.next(); token.typ {
+		case itemFunc:
+			labels = append(labels, token.val)
+		case itemString:
+			s, err := strconv.Unquote(token.val)
+			if err != nil {
+				t.errorf("Unquoting error: %s", err)
+			}
+			labels = append(labels, s)
+		case itemRightParen:
+			return labels
+		default:
+			t.unexpected(token, context)
+		}
+		switch token := t.next(); token.typ {
+		case itemComma:
+		case itemRightParen:
+			return labels
+		default:
+			t.unexpected(token, context)
+		}
+	}
+}
+
 // F is v | "(" O ")" | "!" O | "-" O in the grammar.
 func (t *Tree) F() Node {
 	switch token := t.peek(); token.typ {
@@ -313,6 +394,13 @@ func (t *Tree) Func() (f *FuncNode) {
 		t.errorf("non existent function %s", token.val)
 	}
 	f = newFunc(token.pos, token.val, funcv)
+	if next := t.peek(); next.typ == itemFunc && (next.val == "by" || next.val == "without") {
+		t.next()
+		if !funcv.Grouping {
+			t.errorf("function %s does not support %s", token.val, next.val)
+		}
+		f.Grouping = &Grouping{Without: next.val == "without", Labels: t.Labels(next.val)}
+	}
 	t.expect(itemLeftParen, "func")
 	for {
 		switch token = t.next(); token.typ {
@@ -329,6 +417,10 @@ func (t *Tree) Func() (f *FuncNode) {
 				t.errorf("Unquoting error: %s", err)
 			}
 			f.append(newString(token.pos, token.val, s))
+		case itemComma:
+			if len(f.Args) == 0 {
+				t.unexpected(token, "func")
+			}
 		case itemRightParen:
 			return
 		}
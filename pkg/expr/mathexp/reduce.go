@@ -3,6 +3,7 @@ package mathexp
 import (
 	"fmt"
 	"math"
+	"sort"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 )
@@ -20,11 +21,26 @@ const (
 	ReducerMax   ReducerID = "max"
 	ReducerCount ReducerID = "count"
 	ReducerLast  ReducerID = "last"
+
+	ReducerFirst        ReducerID = "first"
+	ReducerLastNonNull  ReducerID = "last_non_null"
+	ReducerCountNonNull ReducerID = "count_non_null"
+	ReducerMedian       ReducerID = "median"
+	ReducerP50          ReducerID = "p50"
+	ReducerP90          ReducerID = "p90"
+	ReducerP95          ReducerID = "p95"
+	ReducerP99          ReducerID = "p99"
+	ReducerStdDev       ReducerID = "stddev"
+	ReducerRange        ReducerID = "range"
 )
 
 // GetSupportedReduceFuncs returns collection of supported function names
 func GetSupportedReduceFuncs() []ReducerID {
-	return []ReducerID{ReducerSum, ReducerMean, ReducerMin, ReducerMax, ReducerCount, ReducerLast}
+	return []ReducerID{
+		ReducerSum, ReducerMean, ReducerMin, ReducerMax, ReducerCount, ReducerLast,
+		ReducerFirst, ReducerLastNonNull, ReducerCountNonNull, ReducerMedian,
+		ReducerP50, ReducerP90, ReducerP95, ReducerP99, ReducerStdDev, ReducerRange,
+	}
 }
 
 func Sum(fv *Float64Field) *float64 {
@@ -98,6 +114,108 @@ func Last(fv *Float64Field) *float64 {
 	return fv.GetValue(fv.Len() - 1)
 }
 
+func First(fv *Float64Field) *float64 {
+	var f float64
+	if fv.Len() == 0 {
+		f = math.NaN()
+		return &f
+	}
+	return fv.GetValue(0)
+}
+
+// LastNonNull returns the last value that is neither null nor NaN, or NaN if there is no such value.
+func LastNonNull(fv *Float64Field) *float64 {
+	for i := fv.Len() - 1; i >= 0; i-- {
+		v := fv.GetValue(i)
+		if v != nil && !math.IsNaN(*v) {
+			return v
+		}
+	}
+	nan := math.NaN()
+	return &nan
+}
+
+// CountNonNull returns the number of values that are neither null nor NaN.
+func CountNonNull(fv *Float64Field) *float64 {
+	var f float64
+	for i := 0; i < fv.Len(); i++ {
+		v := fv.GetValue(i)
+		if v != nil && !math.IsNaN(*v) {
+			f++
+		}
+	}
+	return &f
+}
+
+// StdDev returns the population standard deviation of the values.
+func StdDev(fv *Float64Field) *float64 {
+	avg := Avg(fv)
+	if math.IsNaN(*avg) {
+		return avg
+	}
+	values := make([]float64, 0, fv.Len())
+	for i := 0; i < fv.Len(); i++ {
+		values = append(values, *fv.GetValue(i))
+	}
+	f := PopulationStdDev(values)
+	return &f
+}
+
+// PopulationStdDev returns the population standard deviation of the values, which must not be empty.
+func PopulationStdDev(values []float64) float64 {
+	var mean float64
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	var sumSq float64
+	for _, v := range values {
+		sumSq += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(sumSq / float64(len(values)))
+}
+
+// Range returns the difference between the largest and the smallest value.
+func Range(fv *Float64Field) *float64 {
+	minV := Min(fv)
+	if math.IsNaN(*minV) {
+		return minV
+	}
+	f := *Max(fv) - *minV
+	return &f
+}
+
+// Percentile returns a ReducerFunc that calculates the p-th percentile (0 <= p <= 100) of the values.
+// The percentile is linearly interpolated between the two closest ranks.
+func Percentile(p float64) ReducerFunc {
+	return func(fv *Float64Field) *float64 {
+		nan := math.NaN()
+		if fv.Len() == 0 {
+			return &nan
+		}
+		values := make([]float64, 0, fv.Len())
+		for i := 0; i < fv.Len(); i++ {
+			v := fv.GetValue(i)
+			if v == nil || math.IsNaN(*v) {
+				return &nan
+			}
+			values = append(values, *v)
+		}
+		sort.Float64s(values)
+		f := SortedPercentile(values, p)
+		return &f
+	}
+}
+
+// SortedPercentile returns the p-th percentile (0 <= p <= 100) of the sorted values, which must not be empty.
+// The percentile is linearly interpolated between the two closest ranks.
+func SortedPercentile(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
 func GetReduceFunc(rFunc ReducerID) (ReducerFunc, error) {
 	switch rFunc {
 	case ReducerSum:
@@ -112,6 +230,24 @@ func GetReduceFunc(rFunc ReducerID) (ReducerFunc, error) {
 		return Count, nil
 	case ReducerLast:
 		return Last, nil
+	case ReducerFirst:
+		return First, nil
+	case ReducerLastNonNull:
+		return LastNonNull, nil
+	case ReducerCountNonNull:
+		return CountNonNull, nil
+	case ReducerMedian, ReducerP50:
+		return Percentile(50), nil
+	case ReducerP90:
+		return Percentile(90), nil
+	case ReducerP95:
+		return Percentile(95), nil
+	case ReducerP99:
+		return Percentile(99), nil
+	case ReducerStdDev:
+		return StdDev, nil
+	case ReducerRange:
+		return Range, nil
 	default:
 		return nil, fmt.Errorf("reduction %v not implemented", rFunc)
 	}
@@ -20,8 +20,26 @@ const (
 
 	// Do not fill values (nill)
 	UpsamplerFillNA Upsampler = "fillna"
+
+	// Linear interpolation between the last seen and the next value
+	UpsamplerLinear Upsampler = "linear"
+
+	// Use the value closest in time, either the last seen or the next value
+	UpsamplerNearest Upsampler = "nearest"
 )
 
+// CalendarAlign returns the calendar boundary at or before t for buckets of the given interval in the location loc.
+// Intervals shorter than a day are aligned to a multiple of the interval since midnight, e.g. a 15m interval
+// is aligned to :00, :15, :30 and :45. Intervals of a day or longer are aligned to midnight.
+func CalendarAlign(t time.Time, interval time.Duration, loc *time.Location) time.Time {
+	local := t.In(loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	if interval <= 0 || interval >= 24*time.Hour {
+		return midnight
+	}
+	return midnight.Add(local.Sub(midnight).Truncate(interval))
+}
+
 // Resample turns the Series into a Number based on the given reduction function
 func (s Series) Resample(refID string, interval time.Duration, downsampler ReducerID, upsampler Upsampler, from, to time.Time) (Series, error) {
 	newSeriesLength := int(float64(to.Sub(from).Nanoseconds()) / float64(interval.Nanoseconds()))
@@ -31,6 +49,7 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 	resampled := NewSeries(refID, s.GetLabels(), newSeriesLength+1)
 	bookmark := 0
 	var lastSeen *float64
+	var lastSeenTime time.Time
 	idx := 0
 	t := from
 	for !t.After(to) && idx <= newSeriesLength {
@@ -47,6 +66,7 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 			bookmark++
 			sIdx++
 			lastSeen = v
+			lastSeenTime = st
 			vals = append(vals, v)
 		}
 		var value *float64
@@ -66,6 +86,34 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 				}
 			case UpsamplerFillNA:
 				value = nil
+			case UpsamplerLinear:
+				if bookmark == 0 || sIdx == s.Len() { // nothing to interpolate between
+					value = nil
+					break
+				}
+				nextTime, next := s.GetPoint(sIdx)
+				if lastSeen == nil || next == nil {
+					value = nil
+					break
+				}
+				ratio := float64(t.Sub(lastSeenTime)) / float64(nextTime.Sub(lastSeenTime))
+				interpolated := *lastSeen + (*next-*lastSeen)*ratio
+				value = &interpolated
+			case UpsamplerNearest:
+				switch {
+				case bookmark == 0 && sIdx == s.Len():
+					value = nil
+				case bookmark == 0:
+					_, value = s.GetPoint(sIdx)
+				case sIdx == s.Len():
+					value = lastSeen
+				default:
+					nextTime, next := s.GetPoint(sIdx)
+					value = lastSeen
+					if nextTime.Sub(t) < t.Sub(lastSeenTime) {
+						value = next
+					}
+				}
 			default:
 				return s, fmt.Errorf("upsampling %v not implemented", upsampler)
 			}
@@ -74,22 +122,11 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 		} else { // downsampling
 			fVec := data.NewField("", s.GetLabels(), vals)
 			ff := Float64Field(*fVec)
-			var tmp *float64
-			switch downsampler {
-			case ReducerSum:
-				tmp = Sum(&ff)
-			case ReducerMean:
-				tmp = Avg(&ff)
-			case ReducerMin:
-				tmp = Min(&ff)
-			case ReducerMax:
-				tmp = Max(&ff)
-			case ReducerLast:
-				tmp = Last(&ff)
-			default:
+			reduceFunc, err := GetReduceFunc(downsampler)
+			if err != nil {
 				return s, fmt.Errorf("downsampling %v not implemented", downsampler)
 			}
-			value = tmp
+			value = reduceFunc(&ff)
 		}
 		resampled.SetPoint(idx, t, value)
 		t = t.Add(interval)
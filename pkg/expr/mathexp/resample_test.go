@@ -277,6 +277,62 @@ func TestResampleSeries(t *testing.T) {
 				time.Unix(9, 0), float64Pointer(0),
 			}),
 		},
+		{
+			name:        "resample series: upsampling (linear)",
+			interval:    time.Second * 2,
+			downsampler: "last",
+			upsampler:   "linear",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(10, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(2, 0), float64Pointer(2),
+			}, tp{
+				time.Unix(7, 0), float64Pointer(12),
+			}),
+			series: makeSeries("", nil, tp{
+				time.Unix(0, 0), nil,
+			}, tp{
+				time.Unix(2, 0), float64Pointer(2),
+			}, tp{
+				time.Unix(4, 0), float64Pointer(6),
+			}, tp{
+				time.Unix(6, 0), float64Pointer(10),
+			}, tp{
+				time.Unix(8, 0), float64Pointer(12),
+			}, tp{
+				time.Unix(10, 0), nil,
+			}),
+		},
+		{
+			name:        "resample series: upsampling (nearest)",
+			interval:    time.Second * 2,
+			downsampler: "last",
+			upsampler:   "nearest",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(10, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(3, 0), float64Pointer(2),
+			}, tp{
+				time.Unix(7, 0), float64Pointer(12),
+			}),
+			series: makeSeries("", nil, tp{
+				time.Unix(0, 0), float64Pointer(2),
+			}, tp{
+				time.Unix(2, 0), float64Pointer(2),
+			}, tp{
+				time.Unix(4, 0), float64Pointer(2),
+			}, tp{
+				time.Unix(6, 0), float64Pointer(12),
+			}, tp{
+				time.Unix(8, 0), float64Pointer(12),
+			}, tp{
+				time.Unix(10, 0), float64Pointer(12),
+			}),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -290,3 +346,58 @@ func TestResampleSeries(t *testing.T) {
 		})
 	}
 }
+
+func TestCalendarAlign(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	ts := time.Date(2024, 3, 5, 22, 47, 13, 0, time.UTC) // 23:47:13 in Berlin
+
+	var tests = []struct {
+		name     string
+		interval time.Duration
+		loc      *time.Location
+		expected time.Time
+	}{
+		{
+			name:     "minute in UTC",
+			interval: time.Minute,
+			loc:      time.UTC,
+			expected: time.Date(2024, 3, 5, 22, 47, 0, 0, time.UTC),
+		},
+		{
+			name:     "15 minutes in UTC",
+			interval: 15 * time.Minute,
+			loc:      time.UTC,
+			expected: time.Date(2024, 3, 5, 22, 45, 0, 0, time.UTC),
+		},
+		{
+			name:     "hour in UTC",
+			interval: time.Hour,
+			loc:      time.UTC,
+			expected: time.Date(2024, 3, 5, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "day in UTC",
+			interval: 24 * time.Hour,
+			loc:      time.UTC,
+			expected: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "day in a time zone",
+			interval: 24 * time.Hour,
+			loc:      berlin,
+			expected: time.Date(2024, 3, 5, 0, 0, 0, 0, berlin),
+		},
+		{
+			name:     "6 hours in a time zone",
+			interval: 6 * time.Hour,
+			loc:      berlin,
+			expected: time.Date(2024, 3, 5, 18, 0, 0, 0, berlin),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.True(t, tt.expected.Equal(CalendarAlign(ts, tt.interval, tt.loc)))
+		})
+	}
+}
@@ -3,6 +3,8 @@ package expr
 import (
 	"embed"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
 	"github.com/grafana/grafana/pkg/expr/classic"
 	"github.com/grafana/grafana/pkg/expr/mathexp"
 )
@@ -29,6 +31,12 @@ const (
 
 	// SQL query via DuckDB
 	QueryTypeSQL QueryType = "sql"
+
+	// Detect anomalies in query results
+	QueryTypeAnomaly QueryType = "anomaly"
+
+	// Forecast query results
+	QueryTypeForecast QueryType = "forecast"
 )
 
 type MathQuery struct {
@@ -60,20 +68,71 @@ type ResampleQuery struct {
 
 	// The upsample function
 	Upsampler mathexp.Upsampler `json:"upsampler"`
+
+	// How the resampled time stamps are aligned, defaults to the start of the time range
+	Alignment ResampleAlignment `json:"alignment,omitempty"`
+
+	// The IANA time zone used for calendar alignment, defaults to UTC
+	Timezone string `json:"timezone,omitempty" jsonschema:"example=UTC,example=Europe/Berlin"`
+}
+
+// QueryType = forecast
+type ForecastQuery struct {
+	// Reference to single query result
+	Expression string `json:"expression" jsonschema:"minLength=1,example=$A"`
+
+	// The forecast method
+	Method mathexp.ForecastMethod `json:"method"`
+
+	// How far ahead of the evaluation time to forecast
+	Horizon string `json:"horizon" jsonschema:"minLength=1,example=4h,example=7d"`
+
+	// What the forecast returns, defaults to value
+	Output ForecastOutput `json:"output,omitempty"`
+
+	// Only valid when output is time_to_threshold
+	Threshold *float64 `json:"threshold,omitempty"`
+
+	// Holt-Winters options
+	Settings *ForecastSettings `json:"settings,omitempty"`
 }
 
 type ThresholdQuery struct {
 	// Reference to single query result
 	Expression string `json:"expression" jsonschema:"minLength=1,example=$A"`
 
-	// Threshold Conditions
+	// Threshold Conditions. More than one condition describes a multi-level hysteresis, where each condition is a level
 	Conditions []ThresholdConditionJSON `json:"conditions"`
+
+	// The state of the multi-level hysteresis from the previous evaluation, set by alerting
+	LoadedState *data.Frame `json:"loadedState,omitempty"`
 }
 
 type ClassicQuery struct {
 	Conditions []classic.ConditionJSON `json:"conditions"`
 }
 
+// QueryType = anomaly
+type AnomalyQuery struct {
+	// Reference to single query result
+	Expression string `json:"expression" jsonschema:"minLength=1,example=$A"`
+
+	// The method used to calculate the expected band of values
+	Method AnomalyMethod `json:"method"`
+
+	// The width of the band in standard deviations, defaults to 3
+	Deviations float64 `json:"deviations,omitempty" jsonschema:"example=3"`
+
+	// Only compare each point to the points in this duration before it, defaults to the whole series
+	Window string `json:"window,omitempty" jsonschema:"example=1h,example=1d"`
+
+	// The season of the seasonal method
+	Season string `json:"season,omitempty" jsonschema:"example=1d,example=1w"`
+
+	// What the expression returns, defaults to all
+	Output AnomalyOutput `json:"output,omitempty"`
+}
+
 // SQLQuery requires the sqlExpression feature flag
 type SQLExpression struct {
 	Expression string `json:"expression" jsonschema:"minLength=1,example=SELECT * FROM A LIMIT 1"`
@@ -91,6 +150,20 @@ type ReduceSettings struct {
 	ReplaceWithValue *float64 `json:"replaceWithValue,omitempty"`
 }
 
+type ForecastSettings struct {
+	// Level smoothing factor between 0 and 1, defaults to 0.5
+	Alpha *float64 `json:"alpha,omitempty"`
+
+	// Trend smoothing factor between 0 and 1, defaults to 0.1
+	Beta *float64 `json:"beta,omitempty"`
+
+	// Seasonal smoothing factor between 0 and 1, defaults to 0.1
+	Gamma *float64 `json:"gamma,omitempty"`
+
+	// The duration of a season, no seasonality when empty
+	Season string `json:"season,omitempty" jsonschema:"example=1d"`
+}
+
 // Non-Number behavior mode
 // +enum
 type ReduceMode string
@@ -103,6 +176,57 @@ const (
 	ReduceModeReplace ReduceMode = "replaceNN"
 )
 
+// Resample time stamp alignment
+// +enum
+type ResampleAlignment string
+
+const (
+	// Align to the start of the time range
+	ResampleAlignmentFrom ResampleAlignment = "from"
+
+	// Align to calendar boundaries (minute, hour, day) in the time zone
+	ResampleAlignmentCalendar ResampleAlignment = "calendar"
+)
+
+// Forecast output
+// +enum
+type ForecastOutput string
+
+const (
+	// The predicted value at the horizon
+	ForecastOutputValue ForecastOutput = "value"
+
+	// The number of seconds until the threshold is reached
+	ForecastOutputTimeToThreshold ForecastOutput = "time_to_threshold"
+)
+
+// Anomaly detection method
+// +enum
+type AnomalyMethod string
+
+const (
+	// Mean and standard deviation
+	AnomalyMethodZScore AnomalyMethod = "zscore"
+
+	// Median and median absolute deviation
+	AnomalyMethodMAD AnomalyMethod = "mad"
+
+	// Value of the previous season and standard deviation of the seasonal differences
+	AnomalyMethodSeasonal AnomalyMethod = "seasonal"
+)
+
+// Anomaly detection output
+// +enum
+type AnomalyOutput string
+
+const (
+	// The anomaly flag and the upper and lower band
+	AnomalyOutputAll AnomalyOutput = "all"
+
+	// Only the anomaly flag
+	AnomalyOutputFlag AnomalyOutput = "flag"
+)
+
 //go:embed query.types.json
 var f embed.FS
 
@@ -11,6 +11,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/expr/classic"
 	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/util"
 )
 
 func TestQueryTypeDefinitions(t *testing.T) {
@@ -22,9 +23,14 @@ func TestQueryTypeDefinitions(t *testing.T) {
 				CodePath:    "./",
 			}},
 			Enums: []reflect.Type{
-				reflect.TypeOf(mathexp.ReducerSum),   // pick an example value (not the root)
-				reflect.TypeOf(mathexp.UpsamplerPad), // pick an example value (not the root)
-				reflect.TypeOf(ReduceModeDrop),       // pick an example value (not the root)
+				reflect.TypeOf(mathexp.ReducerSum),     // pick an example value (not the root)
+				reflect.TypeOf(mathexp.UpsamplerPad),   // pick an example value (not the root)
+				reflect.TypeOf(ReduceModeDrop),         // pick an example value (not the root)
+				reflect.TypeOf(ResampleAlignmentFrom),  // pick an example value (not the root)
+				reflect.TypeOf(AnomalyMethodZScore),    // pick an example value (not the root)
+				reflect.TypeOf(AnomalyOutputAll),       // pick an example value (not the root)
+				reflect.TypeOf(mathexp.ForecastLinear), // pick an example value (not the root)
+				reflect.TypeOf(ForecastOutputValue),    // pick an example value (not the root)
 				reflect.TypeOf(ThresholdIsAbove),
 				reflect.TypeOf(classic.ConditionOperatorAnd),
 			},
@@ -80,6 +86,36 @@ func TestQueryTypeDefinitions(t *testing.T) {
 				},
 			},
 		},
+		schemabuilder.QueryTypeInfo{
+			Discriminators: data.NewDiscriminators("type", QueryTypeForecast),
+			GoType:         reflect.TypeOf(&ForecastQuery{}),
+			Examples: []data.QueryExample{
+				{
+					Name: "seconds until A reaches 95 within the next 4 hours",
+					SaveModel: data.AsUnstructured(ForecastQuery{
+						Expression: "$A",
+						Method:     mathexp.ForecastLinear,
+						Horizon:    "4h",
+						Output:     ForecastOutputTimeToThreshold,
+						Threshold:  util.Pointer(95.0),
+					}),
+				},
+			},
+		},
+		schemabuilder.QueryTypeInfo{
+			Discriminators: data.NewDiscriminators("type", QueryTypeAnomaly),
+			GoType:         reflect.TypeOf(&AnomalyQuery{}),
+			Examples: []data.QueryExample{
+				{
+					Name: "compare A with the same time yesterday",
+					SaveModel: data.AsUnstructured(AnomalyQuery{
+						Expression: "$A",
+						Method:     AnomalyMethodSeasonal,
+						Season:     "1d",
+					}),
+				},
+			},
+		},
 		schemabuilder.QueryTypeInfo{
 			Discriminators: data.NewDiscriminators("type", QueryTypeSQL),
 			GoType:         reflect.TypeOf(&SQLExpression{}),
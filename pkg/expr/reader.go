@@ -10,6 +10,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/expr/classic"
 	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/sql"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 )
 
@@ -34,6 +35,8 @@ func (q ExpressionQuery) ID() int64 {
 
 type ExpressionQueryReader struct {
 	features featuremgmt.FeatureToggles
+	// sqlEngine runs SQL expressions, DuckDB if nil
+	sqlEngine sql.Engine
 }
 
 func NewExpressionQueryReader(features featuremgmt.FeatureToggles) *ExpressionQueryReader {
@@ -112,6 +115,8 @@ func (h *ExpressionQueryReader) ReadQuery(
 					From: tr.GetFromAsTimeUTC(),
 					To:   tr.GetToAsTimeUTC(),
 				},
+				q.Alignment,
+				q.Timezone,
 			)
 		}
 
@@ -128,7 +133,29 @@ func (h *ExpressionQueryReader) ReadQuery(
 		err = iter.ReadVal(q)
 		if err == nil {
 			eq.Properties = q
-			eq.Command, err = NewSQLCommand(common.RefID, q.Expression)
+			eq.Command, err = NewSQLCommand(common.RefID, q.Expression, h.sqlEngine)
+		}
+
+	case QueryTypeAnomaly:
+		q := &AnomalyQuery{}
+		err = iter.ReadVal(q)
+		if err == nil {
+			referenceVar, err = getReferenceVar(q.Expression, common.RefID)
+		}
+		if err == nil {
+			eq.Properties = q
+			eq.Command, err = NewAnomalyCommand(common.RefID, referenceVar, q.Method, q.Deviations, q.Window, q.Season, q.Output)
+		}
+
+	case QueryTypeForecast:
+		q := &ForecastQuery{}
+		err = iter.ReadVal(q)
+		if err == nil {
+			referenceVar, err = getReferenceVar(q.Expression, common.RefID)
+		}
+		if err == nil {
+			eq.Properties = q
+			eq.Command, err = NewForecastCommand(common.RefID, referenceVar, q.Method, q.Horizon, q.Output, q.Threshold, q.Settings)
 		}
 
 	case QueryTypeThreshold:
@@ -137,6 +164,11 @@ func (h *ExpressionQueryReader) ReadQuery(
 		if err == nil {
 			referenceVar, err = getReferenceVar(q.Expression, common.RefID)
 		}
+		if err == nil && isMultiLevelHysteresis(q.Conditions) && h.features.IsEnabledGlobally(featuremgmt.FlagRecoveryThreshold) {
+			eq.Properties = q
+			eq.Command, err = newMultiLevelHysteresisCommand(common.RefID, referenceVar, q.Conditions, q.LoadedState)
+			break
+		}
 		if err == nil {
 			// we only support one condition for now, we might want to turn this in to "OR" expressions later
 			if len(q.Conditions) != 1 {
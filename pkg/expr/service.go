@@ -11,6 +11,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/expr/sql"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/services/datasources"
@@ -61,6 +62,7 @@ type Service struct {
 	pCtxProvider pluginContextProvider
 	features     featuremgmt.FeatureToggles
 	converter    *ResultConverter
+	sqlEngine    sql.Engine
 
 	pluginsClient backend.CallResourceHandler
 
@@ -84,6 +86,7 @@ func ProvideService(cfg *setting.Cfg, pluginClient plugins.Client, pCtxProvider
 		tracer:        tracer,
 		metrics:       newMetrics(registerer),
 		pluginsClient: pluginClient,
+		sqlEngine:     sql.NewEngine(cfg),
 		converter: &ResultConverter{
 			Features: features,
 			Tracer:   tracer,
@@ -121,6 +124,17 @@ func (s *Service) ExecutePipeline(ctx context.Context, now time.Time, pipeline D
 	return res, nil
 }
 
+// ExecutePipelineWithDebug executes an expression pipeline like ExecutePipeline, and also returns the debug information
+// of each node: the resolved dependency order, the execution time, and how the responses of data sources were converted.
+func (s *Service) ExecutePipelineWithDebug(ctx context.Context, now time.Time, pipeline DataPipeline) (*backend.QueryDataResponse, *PipelineDebug, error) {
+	debug := newPipelineDebug(pipeline)
+	res, err := s.ExecutePipeline(withPipelineDebug(ctx, debug), now, pipeline)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, debug, nil
+}
+
 // Create a datasources.DataSource struct from NodeType. Returns error if kind is TypeDatasourceNode or unknown one.
 func DataSourceModelFromNodeType(kind NodeType) (*datasources.DataSource, error) {
 	switch kind {
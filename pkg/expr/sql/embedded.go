@@ -0,0 +1,505 @@
+package sql
+
+import (
+	"context"
+	gosql "database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/mattn/go-sqlite3"
+)
+
+// The SQLite types of the columns of the input tables, which are used to map result columns back to field types.
+const (
+	sqliteInteger   = "INTEGER"
+	sqliteReal      = "REAL"
+	sqliteText      = "TEXT"
+	sqliteBoolean   = "BOOLEAN"
+	sqliteTimestamp = "TIMESTAMP"
+)
+
+type embeddedEngine struct {
+	limits Limits
+}
+
+// NewEmbeddedEngine returns an engine that loads the frames into an in-memory SQLite database in process,
+// so it does not depend on any external binary. It supports SQLite's dialect, including joins, aggregations
+// and window functions.
+func NewEmbeddedEngine(limits Limits) Engine {
+	return &embeddedEngine{limits: limits}
+}
+
+func (e *embeddedEngine) TablesList(rawSQL string) ([]string, error) {
+	return TablesListFromTokens(rawSQL)
+}
+
+func (e *embeddedEngine) QueryFramesInto(ctx context.Context, name string, query string, frames []*data.Frame, f *data.Frame) error {
+	if err := checkSingleStatement(query); err != nil {
+		return err
+	}
+
+	db, err := gosql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return fmt.Errorf("failed to open embedded database: %w", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			logger.Error("failed to close embedded database", "error", err)
+		}
+	}()
+
+	// every connection to :memory: opens a new database, so all statements must run on the same connection
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open embedded database: %w", err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			logger.Error("failed to close embedded database connection", "error", err)
+		}
+	}()
+
+	if err := e.applyDatabaseLimit(ctx, conn); err != nil {
+		return err
+	}
+
+	for _, t := range tablesFromFrames(frames) {
+		if err := t.load(ctx, conn); err != nil {
+			return e.wrapError(err)
+		}
+	}
+
+	if err := restrictToReads(conn); err != nil {
+		return err
+	}
+
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return e.wrapError(err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			logger.Error("failed to close rows", "error", err)
+		}
+	}()
+
+	fields, err := e.readFields(rows)
+	if err != nil {
+		return e.wrapError(err)
+	}
+
+	f.Name = name
+	f.Fields = fields
+	return nil
+}
+
+// applyDatabaseLimit limits the number of pages of the database the input tables are loaded into. Temporary
+// tables, for example for sorting, are kept in memory as well, so that the engine never writes to disk.
+// The heap limit pragmas of SQLite are not used because they apply to every database of the process,
+// including the Grafana database if it is SQLite.
+func (e *embeddedEngine) applyDatabaseLimit(ctx context.Context, conn *gosql.Conn) error {
+	if _, err := conn.ExecContext(ctx, "PRAGMA temp_store = MEMORY"); err != nil {
+		return fmt.Errorf("failed to configure embedded database: %w", err)
+	}
+	if e.limits.MaxDatabaseBytes <= 0 {
+		return nil
+	}
+	var pageSize int64
+	if err := conn.QueryRowContext(ctx, "PRAGMA page_size").Scan(&pageSize); err != nil {
+		return fmt.Errorf("failed to configure embedded database: %w", err)
+	}
+	maxPages := (e.limits.MaxDatabaseBytes + pageSize - 1) / pageSize
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("PRAGMA max_page_count = %d", maxPages)); err != nil {
+		return fmt.Errorf("failed to configure embedded database: %w", err)
+	}
+	return nil
+}
+
+// sqliteRecursive is the authorizer action of a recursive common table expression, which go-sqlite3 does not export.
+const sqliteRecursive = 33
+
+// checkSingleStatement fails if the query has more than one statement.
+func checkSingleStatement(query string) error {
+	tokens, err := tokenize(query)
+	if err != nil {
+		return err
+	}
+	for i, tok := range tokens {
+		if tok.kind == tokenPunctuation && tok.text == ";" && i != len(tokens)-1 {
+			return fmt.Errorf("error in sql: only a single statement is allowed")
+		}
+	}
+	return nil
+}
+
+// restrictToReads installs an authorizer on the connection that only allows the actions of a SELECT statement,
+// so that the query can not modify the tables, change the settings with PRAGMA, or open and create files with
+// ATTACH or VACUUM INTO.
+func restrictToReads(conn *gosql.Conn) error {
+	return conn.Raw(func(driverConn any) error {
+		c, ok := driverConn.(*sqlite3.SQLiteConn)
+		if !ok {
+			return fmt.Errorf("unexpected embedded database connection %T", driverConn)
+		}
+		c.RegisterAuthorizer(func(action int, _, _, _ string) int {
+			switch action {
+			case sqlite3.SQLITE_SELECT, sqlite3.SQLITE_READ, sqlite3.SQLITE_FUNCTION, sqliteRecursive:
+				return sqlite3.SQLITE_OK
+			default:
+				return sqlite3.SQLITE_DENY
+			}
+		})
+		return nil
+	})
+}
+
+// wrapError replaces the errors SQLite returns when the database reaches the page limit, or when the query
+// does something other than reading, with readable ones.
+func (e *embeddedEngine) wrapError(err error) error {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code {
+		case sqlite3.ErrFull:
+			return fmt.Errorf("input tables exceeded the database size limit of %d bytes", e.limits.MaxDatabaseBytes)
+		case sqlite3.ErrAuth:
+			return fmt.Errorf("error in sql: only read-only SELECT statements are allowed")
+		}
+	}
+	return err
+}
+
+// readFields reads all rows of the result into fields. It fails if there are more rows than the limit.
+func (e *embeddedEngine) readFields(rows *gosql.Rows) ([]*data.Field, error) {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	values := make([][]any, len(columnTypes))
+	row := make([]any, len(columnTypes))
+	dest := make([]any, len(columnTypes))
+	for i := range row {
+		dest[i] = &row[i]
+	}
+
+	var count int64
+	for rows.Next() {
+		count++
+		if e.limits.MaxRows > 0 && count > e.limits.MaxRows {
+			return nil, fmt.Errorf("query returned more than the limit of %d rows", e.limits.MaxRows)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		for i, v := range row {
+			values[i] = append(values[i], v)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	fields := make([]*data.Field, len(columnTypes))
+	for i, ct := range columnTypes {
+		fields[i] = fieldFromValues(ct.Name(), strings.ToUpper(ct.DatabaseTypeName()), values[i])
+	}
+	return fields, nil
+}
+
+// table is an input table of the query, which holds all frames with the same RefID.
+type table struct {
+	name    string
+	columns []string
+	types   []string
+	index   map[string]int
+	frames  []*data.Frame
+}
+
+// tablesFromFrames groups the frames by RefID. Each field becomes a column, and so does each label of a field,
+// unless there is a field with the same name. Fields with the same name in different frames share a column,
+// and rows of frames without the column are null.
+func tablesFromFrames(frames []*data.Frame) []*table {
+	tables := []*table{}
+	byName := map[string]*table{}
+	for _, frame := range frames {
+		t, ok := byName[frame.RefID]
+		if !ok {
+			t = &table{name: frame.RefID, index: map[string]int{}}
+			byName[frame.RefID] = t
+			tables = append(tables, t)
+		}
+		t.frames = append(t.frames, frame)
+		for _, field := range frame.Fields {
+			t.addColumn(fieldName(field), sqliteType(field.Type()))
+		}
+	}
+	for _, t := range tables {
+		for _, frame := range t.frames {
+			for _, field := range frame.Fields {
+				for label := range field.Labels {
+					t.addColumn(label, sqliteText)
+				}
+			}
+		}
+	}
+	return tables
+}
+
+func (t *table) addColumn(name, sqliteType string) {
+	if _, ok := t.index[name]; ok {
+		return
+	}
+	t.index[name] = len(t.columns)
+	t.columns = append(t.columns, name)
+	t.types = append(t.types, sqliteType)
+}
+
+// load creates the table and inserts the rows of its frames.
+func (t *table) load(ctx context.Context, conn *gosql.Conn) error {
+	definitions := make([]string, len(t.columns))
+	placeholders := make([]string, len(t.columns))
+	for i, column := range t.columns {
+		definitions[i] = quoteIdentifier(column) + " " + t.types[i]
+		placeholders[i] = "?"
+	}
+	create := fmt.Sprintf("CREATE TABLE %s (%s)", quoteIdentifier(t.name), strings.Join(definitions, ", "))
+	if _, err := conn.ExecContext(ctx, create); err != nil {
+		return fmt.Errorf("failed to create table %s: %w", t.name, err)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// does nothing if the transaction was committed
+		_ = tx.Rollback()
+	}()
+	insert, err := tx.PrepareContext(ctx, fmt.Sprintf("INSERT INTO %s VALUES (%s)", quoteIdentifier(t.name), strings.Join(placeholders, ", ")))
+	if err != nil {
+		return err
+	}
+
+	row := make([]any, len(t.columns))
+	for _, frame := range t.frames {
+		// the labels of the frame do not change by row, and fields take precedence over labels
+		labels := map[int]string{}
+		for _, field := range frame.Fields {
+			for label, value := range field.Labels {
+				labels[t.index[label]] = value
+			}
+		}
+		for _, field := range frame.Fields {
+			delete(labels, t.index[fieldName(field)])
+		}
+
+		for r := 0; r < frame.Rows(); r++ {
+			clear(row)
+			for i, value := range labels {
+				row[i] = value
+			}
+			for _, field := range frame.Fields {
+				row[t.index[fieldName(field)]] = sqliteValue(field, r)
+			}
+			if _, err := insert.ExecContext(ctx, row...); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+func fieldName(field *data.Field) string {
+	if field.Config != nil && field.Config.DisplayName != "" {
+		return field.Config.DisplayName
+	}
+	return field.Name
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// sqliteType returns the declared type of the column for the field type.
+func sqliteType(fieldType data.FieldType) string {
+	switch fieldType.NonNullableType() {
+	case data.FieldTypeBool:
+		return sqliteBoolean
+	case data.FieldTypeTime:
+		return sqliteTimestamp
+	case data.FieldTypeFloat32, data.FieldTypeFloat64:
+		return sqliteReal
+	case data.FieldTypeInt8, data.FieldTypeInt16, data.FieldTypeInt32, data.FieldTypeInt64,
+		data.FieldTypeUint8, data.FieldTypeUint16, data.FieldTypeUint32, data.FieldTypeUint64, data.FieldTypeEnum:
+		return sqliteInteger
+	default:
+		return sqliteText
+	}
+}
+
+// sqliteValue returns the value of the field at the row as a value the SQLite driver can store.
+func sqliteValue(field *data.Field, row int) any {
+	v, ok := field.ConcreteAt(row)
+	if !ok {
+		return nil
+	}
+	switch x := v.(type) {
+	case time.Time:
+		return x.UTC()
+	case uint64:
+		if x > math.MaxInt64 {
+			return float64(x)
+		}
+		return int64(x)
+	case data.EnumItemIndex:
+		return int64(x)
+	case json.RawMessage:
+		return string(x)
+	default:
+		return v
+	}
+}
+
+// fieldFromValues creates a nullable field from the values the driver returned for a result column.
+// The field type is inferred from the values, which for computed columns can differ from row to row,
+// and from the declared type of the column if all values are null.
+func fieldFromValues(name, declType string, values []any) *data.Field {
+	var hasInt, hasFloat, hasBool, hasTime, hasString bool
+	for _, v := range values {
+		switch v.(type) {
+		case nil:
+		case int64:
+			hasInt = true
+		case float64:
+			hasFloat = true
+		case bool:
+			hasBool = true
+		case time.Time:
+			hasTime = true
+		default:
+			hasString = true
+		}
+	}
+
+	if !hasInt && !hasFloat && !hasBool && !hasTime && !hasString {
+		switch declType {
+		case sqliteInteger:
+			hasInt = true
+		case sqliteBoolean:
+			hasBool = true
+		case sqliteTimestamp:
+			hasTime = true
+		case sqliteText:
+			hasString = true
+		default:
+			hasFloat = true
+		}
+	}
+
+	// computed columns such as min(time) lose the declared type, so strings are converted back to times
+	// if all of them are timestamps
+	if hasString && !hasInt && !hasFloat && !hasBool {
+		if times, ok := parseTimes(values); ok {
+			values, hasTime, hasString = times, true, false
+		}
+	}
+
+	field := data.NewFieldFromFieldType(resultFieldType(hasInt, hasFloat, hasBool, hasTime, hasString), len(values))
+	field.Name = name
+	for i, v := range values {
+		if v == nil {
+			continue
+		}
+		field.SetConcrete(i, convertValue(field.Type(), v))
+	}
+	return field
+}
+
+func resultFieldType(hasInt, hasFloat, hasBool, hasTime, hasString bool) data.FieldType {
+	switch {
+	case hasString, hasTime && (hasInt || hasFloat || hasBool):
+		return data.FieldTypeNullableString
+	case hasTime:
+		return data.FieldTypeNullableTime
+	case hasFloat:
+		return data.FieldTypeNullableFloat64
+	case hasInt:
+		return data.FieldTypeNullableInt64
+	default:
+		return data.FieldTypeNullableBool
+	}
+}
+
+// convertValue converts a value the driver returned to the type of the field.
+func convertValue(fieldType data.FieldType, v any) any {
+	switch fieldType {
+	case data.FieldTypeNullableString:
+		switch x := v.(type) {
+		case []byte:
+			return string(x)
+		case time.Time:
+			return x.Format(time.RFC3339Nano)
+		default:
+			return fmt.Sprint(x)
+		}
+	case data.FieldTypeNullableFloat64:
+		switch x := v.(type) {
+		case int64:
+			return float64(x)
+		case bool:
+			return boolToFloat(x)
+		}
+	case data.FieldTypeNullableInt64:
+		if x, ok := v.(bool); ok {
+			return int64(boolToFloat(x))
+		}
+	}
+	return v
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// parseTimes parses all strings with the formats the driver stores times in.
+// It returns false if a value is not a string or not a time.
+func parseTimes(values []any) ([]any, bool) {
+	times := make([]any, len(values))
+	for i, v := range values {
+		if v == nil {
+			continue
+		}
+		var s string
+		switch x := v.(type) {
+		case string:
+			s = x
+		case []byte:
+			s = string(x)
+		default:
+			return nil, false
+		}
+		t, ok := parseTime(s)
+		if !ok {
+			return nil, false
+		}
+		times[i] = t
+	}
+	return times, true
+}
+
+func parseTime(s string) (time.Time, bool) {
+	s = strings.TrimSuffix(s, "Z")
+	for _, format := range sqlite3.SQLiteTimestampFormats {
+		if t, err := time.ParseInLocation(format, s, time.UTC); err == nil {
+			return t.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,171 @@
+package sql
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seriesFrame(refID, host string, values ...float64) *data.Frame {
+	times := make([]time.Time, len(values))
+	points := make([]*float64, len(values))
+	for i := range values {
+		times[i] = time.Unix(int64(i)*60, 0)
+		points[i] = &values[i]
+	}
+	frame := data.NewFrame("",
+		data.NewField("Time", nil, times),
+		data.NewField(refID, data.Labels{"host": host}, points),
+	)
+	frame.RefID = refID
+	return frame
+}
+
+func queryEmbedded(t *testing.T, limits Limits, query string, frames ...*data.Frame) (*data.Frame, error) {
+	t.Helper()
+	f := &data.Frame{}
+	err := NewEmbeddedEngine(limits).QueryFramesInto(context.Background(), "B", query, frames, f)
+	return f, err
+}
+
+func TestEmbeddedEngine(t *testing.T) {
+	a1 := seriesFrame("A", "a", 1, 2, 3)
+	a2 := seriesFrame("A", "b", 10, 20, 30)
+
+	t.Run("should load the frames of a ref as one table with label columns", func(t *testing.T) {
+		f, err := queryEmbedded(t, Limits{}, "SELECT Time, host, A FROM A ORDER BY host, Time", a1, a2)
+		require.NoError(t, err)
+		require.Equal(t, 6, f.Rows())
+		require.Equal(t, "B", f.Name)
+		require.Equal(t, data.FieldTypeNullableTime, f.Fields[0].Type())
+		require.Equal(t, data.FieldTypeNullableString, f.Fields[1].Type())
+		require.Equal(t, data.FieldTypeNullableFloat64, f.Fields[2].Type())
+
+		ts, _ := f.Fields[0].ConcreteAt(1)
+		assert.True(t, time.Unix(60, 0).Equal(ts.(time.Time)))
+		host, _ := f.Fields[1].ConcreteAt(3)
+		assert.Equal(t, "b", host)
+		v, _ := f.Fields[2].ConcreteAt(5)
+		assert.Equal(t, 30.0, v)
+	})
+
+	t.Run("should group by and keep the type of computed columns", func(t *testing.T) {
+		f, err := queryEmbedded(t, Limits{}, "SELECT host, count(*) AS n, avg(A) AS mean, max(Time) AS last FROM A GROUP BY host ORDER BY host", a1, a2)
+		require.NoError(t, err)
+		require.Equal(t, 2, f.Rows())
+		require.Equal(t, data.FieldTypeNullableInt64, f.Fields[1].Type())
+		require.Equal(t, data.FieldTypeNullableFloat64, f.Fields[2].Type())
+		require.Equal(t, data.FieldTypeNullableTime, f.Fields[3].Type())
+		n, _ := f.Fields[1].ConcreteAt(0)
+		assert.Equal(t, int64(3), n)
+		mean, _ := f.Fields[2].ConcreteAt(1)
+		assert.Equal(t, 20.0, mean)
+		last, _ := f.Fields[3].ConcreteAt(0)
+		assert.True(t, time.Unix(120, 0).Equal(last.(time.Time)))
+	})
+
+	t.Run("should join tables and run window functions", func(t *testing.T) {
+		limits := data.NewFrame("",
+			data.NewField("host", nil, []string{"a", "b"}),
+			data.NewField("limit", nil, []int32{2, 25}),
+			data.NewField("enabled", nil, []*bool{boolPointer(true), nil}),
+		)
+		limits.RefID = "C"
+		f, err := queryEmbedded(t, Limits{}, `
+			SELECT A.host, A.A - C."limit" AS excess, C.enabled,
+				sum(A.A) OVER (PARTITION BY A.host ORDER BY A.Time) AS running
+			FROM A JOIN C ON A.host = C.host
+			ORDER BY A.host, A.Time`, a1, a2, limits)
+		require.NoError(t, err)
+		require.Equal(t, 6, f.Rows())
+		excess, _ := f.Fields[1].ConcreteAt(0)
+		assert.Equal(t, -1.0, excess)
+		require.Equal(t, data.FieldTypeNullableBool, f.Fields[2].Type())
+		enabled, _ := f.Fields[2].ConcreteAt(0)
+		assert.Equal(t, true, enabled)
+		_, ok := f.Fields[2].ConcreteAt(3)
+		assert.False(t, ok)
+		running, _ := f.Fields[3].ConcreteAt(5)
+		assert.Equal(t, 60.0, running)
+	})
+
+	t.Run("should fill missing columns with null", func(t *testing.T) {
+		other := data.NewFrame("", data.NewField("other", nil, []int64{7}))
+		other.RefID = "A"
+		f, err := queryEmbedded(t, Limits{}, "SELECT count(other) AS n, count(*) AS total FROM A", a1, other)
+		require.NoError(t, err)
+		n, _ := f.Fields[0].ConcreteAt(0)
+		assert.Equal(t, int64(1), n)
+		total, _ := f.Fields[1].ConcreteAt(0)
+		assert.Equal(t, int64(4), total)
+	})
+
+	t.Run("should fail if the result has more rows than the limit", func(t *testing.T) {
+		_, err := queryEmbedded(t, Limits{MaxRows: 5}, "SELECT * FROM A", a1, a2)
+		require.ErrorContains(t, err, "more than the limit of 5 rows")
+
+		_, err = queryEmbedded(t, Limits{MaxRows: 6}, "SELECT * FROM A", a1, a2)
+		require.NoError(t, err)
+	})
+
+	t.Run("should fail if the input exceeds the database size limit", func(t *testing.T) {
+		values := make([]string, 10000)
+		for i := range values {
+			values[i] = "a long enough string value to fill a few pages"
+		}
+		big := data.NewFrame("", data.NewField("s", nil, values))
+		big.RefID = "A"
+		_, err := queryEmbedded(t, Limits{MaxDatabaseBytes: 64 * 1024}, "SELECT count(*) FROM A", big)
+		require.ErrorContains(t, err, "database size limit of 65536 bytes")
+	})
+
+	t.Run("should stop when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := NewEmbeddedEngine(Limits{}).QueryFramesInto(ctx, "B", "SELECT * FROM A", []*data.Frame{a1}, &data.Frame{})
+		require.Error(t, err)
+	})
+
+	t.Run("should only allow a single read-only statement", func(t *testing.T) {
+		dir := t.TempDir()
+		for _, query := range []string{
+			"ATTACH DATABASE '" + filepath.Join(dir, "attached.db") + "' AS x",
+			"VACUUM INTO '" + filepath.Join(dir, "vacuum.db") + "'",
+			"PRAGMA max_page_count = 1000000",
+			"SELECT * FROM pragma_table_info('A')",
+			"DELETE FROM A",
+			"INSERT INTO A (host) VALUES ('c')",
+			"CREATE TABLE C (x INTEGER)",
+			"DROP TABLE A",
+		} {
+			_, err := queryEmbedded(t, Limits{}, query, a1)
+			require.ErrorContains(t, err, "only read-only SELECT statements are allowed", query)
+		}
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+
+		_, err = queryEmbedded(t, Limits{}, "SELECT 1; ATTACH DATABASE ':memory:' AS x", a1)
+		require.ErrorContains(t, err, "only a single statement is allowed")
+
+		f, err := queryEmbedded(t, Limits{}, "WITH RECURSIVE n(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM n WHERE x < 3) SELECT count(*) FROM n;", a1)
+		require.NoError(t, err)
+		n, _ := f.Fields[0].ConcreteAt(0)
+		assert.Equal(t, int64(3), n)
+	})
+
+	t.Run("should return sql errors", func(t *testing.T) {
+		_, err := queryEmbedded(t, Limits{}, "SELECT nope FROM A", a1)
+		require.ErrorContains(t, err, "no such column")
+	})
+}
+
+func boolPointer(b bool) *bool {
+	return &b
+}
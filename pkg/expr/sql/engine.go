@@ -0,0 +1,60 @@
+package sql
+
+import (
+	"context"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/scottlepp/go-duck/duck"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// EngineDuckDB runs the queries with the external duckdb binary.
+	EngineDuckDB = "duckdb"
+	// EngineEmbedded runs the queries in process with an embedded SQLite database.
+	EngineEmbedded = "embedded"
+)
+
+// Engine runs SQL queries over data frames. Each frame is loaded into the table named by its RefID.
+type Engine interface {
+	// TablesList returns the sorted list of tables the sql statement reads from.
+	TablesList(rawSQL string) ([]string, error)
+	// QueryFramesInto runs the query over the frames and writes the result into the frame f.
+	QueryFramesInto(ctx context.Context, name string, query string, frames []*data.Frame, f *data.Frame) error
+}
+
+// Limits are the limits of a single query of the embedded engine. Zero means no limit.
+type Limits struct {
+	// MaxRows is the maximum number of rows the query can return.
+	MaxRows int64
+	// MaxDatabaseBytes is the maximum size of the database the input frames are loaded into.
+	MaxDatabaseBytes int64
+}
+
+// NewEngine returns the engine configured in the [expressions] section.
+func NewEngine(cfg *setting.Cfg) Engine {
+	if cfg == nil || cfg.SQLExpressionsEngine != EngineEmbedded {
+		return NewDuckDBEngine()
+	}
+	return NewEmbeddedEngine(Limits{
+		MaxRows:          cfg.SQLExpressionsMaxRows,
+		MaxDatabaseBytes: cfg.SQLExpressionsMaxDatabaseBytes,
+	})
+}
+
+type duckDBEngine struct{}
+
+// NewDuckDBEngine returns an engine that runs the queries with the duckdb binary, which must be installed.
+func NewDuckDBEngine() Engine {
+	return duckDBEngine{}
+}
+
+func (duckDBEngine) TablesList(rawSQL string) ([]string, error) {
+	return TablesList(rawSQL)
+}
+
+func (duckDBEngine) QueryFramesInto(_ context.Context, name string, query string, frames []*data.Frame, f *data.Frame) error {
+	duckDB := duck.NewInMemoryDB()
+	return duckDB.QueryFramesInto(name, query, frames, f)
+}
@@ -0,0 +1,210 @@
+package sql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenKeyword tokenKind = iota
+	tokenIdentifier
+	tokenPunctuation
+	tokenLiteral
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// keywords that can not be used as unquoted table names or aliases.
+var keywords = map[string]bool{
+	"ALL": true, "AND": true, "AS": true, "ASC": true, "BETWEEN": true, "BY": true, "CASE": true, "CAST": true,
+	"CROSS": true, "CURRENT": true, "DESC": true, "DISTINCT": true, "ELSE": true, "END": true, "EXCEPT": true,
+	"EXISTS": true, "FILTER": true, "FOLLOWING": true, "FROM": true, "FULL": true, "GROUP": true, "HAVING": true,
+	"IN": true, "INNER": true, "INTERSECT": true, "IS": true, "JOIN": true, "LEFT": true, "LIKE": true,
+	"LIMIT": true, "NATURAL": true, "NOT": true, "NULL": true, "OFFSET": true, "ON": true, "OR": true,
+	"ORDER": true, "OUTER": true, "OVER": true, "PARTITION": true, "PRECEDING": true, "RANGE": true,
+	"RECURSIVE": true, "RIGHT": true, "ROWS": true, "SELECT": true, "THEN": true, "UNBOUNDED": true,
+	"UNION": true, "USING": true, "VALUES": true, "WHEN": true, "WHERE": true, "WINDOW": true, "WITH": true,
+}
+
+// TablesListFromTokens returns a sorted list of the tables the sql statement selects from.
+// Unlike TablesList it does not need DuckDB: it scans the tokens of the statement for the table
+// names after FROM and JOIN, skipping subqueries, table functions and common table expressions.
+func TablesListFromTokens(rawSQL string) ([]string, error) {
+	tokens, err := tokenize(rawSQL)
+	if err != nil {
+		return nil, err
+	}
+
+	// the state of the clause at each level of parentheses
+	type clause struct {
+		inFrom      bool
+		expectTable bool
+		inWith      bool
+		expectCTE   bool
+	}
+	stack := []*clause{{}}
+	tables := []string{}
+	ctes := map[string]bool{}
+
+	for i, tok := range tokens {
+		cur := stack[len(stack)-1]
+		switch {
+		case tok.kind == tokenPunctuation && tok.text == "(":
+			cur.expectTable = false
+			stack = append(stack, &clause{})
+		case tok.kind == tokenPunctuation && tok.text == ")":
+			if len(stack) == 1 {
+				return nil, fmt.Errorf("error in sql: unbalanced parentheses")
+			}
+			stack = stack[:len(stack)-1]
+		case tok.kind == tokenPunctuation && tok.text == ",":
+			cur.expectTable = cur.inFrom
+			cur.expectCTE = cur.inWith
+		case tok.kind == tokenKeyword:
+			switch tok.text {
+			case "WITH":
+				cur.inWith, cur.expectCTE = true, true
+			case "RECURSIVE", "AS":
+			case "FROM", "JOIN":
+				cur.inWith, cur.expectCTE = false, false
+				cur.inFrom, cur.expectTable = true, true
+			case "NATURAL", "LEFT", "RIGHT", "FULL", "INNER", "OUTER", "CROSS":
+			default:
+				cur.inWith, cur.expectCTE = false, false
+				cur.inFrom, cur.expectTable = false, false
+			}
+		case tok.kind == tokenIdentifier:
+			switch {
+			case cur.expectCTE:
+				ctes[strings.ToLower(tok.text)] = true
+				cur.expectCTE = false
+			case cur.expectTable:
+				cur.expectTable = false
+				if i+1 < len(tokens) && tokens[i+1].text == "(" {
+					// a table function
+					continue
+				}
+				if !existsInList(tok.text, tables) {
+					tables = append(tables, tok.text)
+				}
+			}
+		}
+	}
+	if len(stack) != 1 {
+		return nil, fmt.Errorf("error in sql: unbalanced parentheses")
+	}
+
+	result := []string{}
+	for _, table := range tables {
+		if !ctes[strings.ToLower(table)] {
+			result = append(result, table)
+		}
+	}
+	sort.Strings(result)
+
+	logger.Debug("tables found in sql", "tables", result)
+
+	return result, nil
+}
+
+// tokenize splits the sql statement into tokens. Comments are dropped, and quoted identifiers are unquoted.
+// A qualified name such as schema.table is returned as a single identifier.
+func tokenize(rawSQL string) ([]token, error) {
+	tokens := []token{}
+	runes := []rune(rawSQL)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			end := i + 2
+			for end+1 < len(runes) && (runes[end] != '*' || runes[end+1] != '/') {
+				end++
+			}
+			if end+1 >= len(runes) {
+				return nil, fmt.Errorf("error in sql: unterminated comment")
+			}
+			i = end + 2
+		case r == '\'':
+			_, next, err := readQuoted(runes, i, '\'')
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenLiteral})
+			i = next
+		case r == '"' || r == '`' || r == '[':
+			closing := r
+			if r == '[' {
+				closing = ']'
+			}
+			text, next, err := readQuoted(runes, i, closing)
+			if err != nil {
+				return nil, err
+			}
+			tokens = appendIdentifier(tokens, text)
+			i = next
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || runes[i] == '$' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			word := string(runes[start:i])
+			if keywords[strings.ToUpper(word)] {
+				tokens = append(tokens, token{kind: tokenKeyword, text: strings.ToUpper(word)})
+			} else {
+				tokens = appendIdentifier(tokens, word)
+			}
+		case unicode.IsDigit(r):
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || unicode.IsLetter(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenLiteral})
+		default:
+			tokens = append(tokens, token{kind: tokenPunctuation, text: string(r)})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// appendIdentifier appends the identifier to the tokens, or joins it with the previous identifier if they are
+// separated by a dot.
+func appendIdentifier(tokens []token, text string) []token {
+	n := len(tokens)
+	if n >= 2 && tokens[n-1].text == "." && tokens[n-1].kind == tokenPunctuation && tokens[n-2].kind == tokenIdentifier {
+		tokens[n-2].text += "." + text
+		return tokens[:n-1]
+	}
+	return append(tokens, token{kind: tokenIdentifier, text: text})
+}
+
+// readQuoted reads the quoted text starting at the opening quote at index start, where a doubled closing quote
+// is an escaped quote. It returns the unquoted text and the index after the closing quote.
+func readQuoted(runes []rune, start int, closing rune) (string, int, error) {
+	var sb strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		if runes[i] != closing {
+			sb.WriteRune(runes[i])
+			continue
+		}
+		if i+1 < len(runes) && runes[i+1] == closing && closing != ']' {
+			sb.WriteRune(closing)
+			i++
+			continue
+		}
+		return sb.String(), i + 1, nil
+	}
+	return "", 0, fmt.Errorf("error in sql: unterminated quote %c", runes[start])
+}
@@ -0,0 +1,42 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTablesListFromTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		expected []string
+	}{
+		{name: "single table", sql: "select * from foo", expected: []string{"foo"}},
+		{name: "comma separated tables", sql: "select * from foo,bar, baz", expected: []string{"bar", "baz", "foo"}},
+		{name: "aliases", sql: "select f.a from foo f, bar AS b where f.a = b.a", expected: []string{"bar", "foo"}},
+		{name: "joins", sql: "SELECT * FROM A LEFT OUTER JOIN B ON A.x = B.x JOIN C USING (x)", expected: []string{"A", "B", "C"}},
+		{name: "quoted identifiers", sql: `select * from "my ""table""" join [B] on 1 join ` + "`C`", expected: []string{"B", "C", `my "table"`}},
+		{name: "subqueries", sql: "select * from (select a from A) x, B where a in (select a from C)", expected: []string{"A", "B", "C"}},
+		{name: "common table expressions", sql: "with x(a) as (select a from A), y as (select * from x) select * from y join B on 1", expected: []string{"A", "B"}},
+		{name: "table functions", sql: "select * from A, json_each(A.labels)", expected: []string{"A"}},
+		{name: "window functions", sql: "select avg(value) over (partition by host order by time rows between 2 preceding and current row) from A", expected: []string{"A"}},
+		{name: "strings and comments", sql: "select 'from X' -- from Y\n/* from Z */ from A", expected: []string{"A"}},
+		{name: "duplicates", sql: "select * from A union all select * from A", expected: []string{"A"}},
+		{name: "no tables", sql: "select 1", expected: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tables, err := TablesListFromTokens(tt.sql)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, tables)
+		})
+	}
+
+	t.Run("should fail on invalid sql", func(t *testing.T) {
+		for _, sql := range []string{"select * from (A", "select * from A)", "select 'a", "select /* a"} {
+			_, err := TablesListFromTokens(sql)
+			require.Error(t, err, sql)
+		}
+	})
+}
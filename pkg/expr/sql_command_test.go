@@ -1,13 +1,23 @@
 package expr
 
 import (
+	"context"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/sql"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/util"
 )
 
 func TestNewCommand(t *testing.T) {
 	t.Skip()
-	cmd, err := NewSQLCommand("a", "select a from foo, bar")
+	cmd, err := NewSQLCommand("a", "select a from foo, bar", nil)
 	if err != nil && strings.Contains(err.Error(), "feature is not enabled") {
 		return
 	}
@@ -25,3 +35,23 @@ func TestNewCommand(t *testing.T) {
 		return
 	}
 }
+
+func TestSQLCommandWithEmbeddedEngine(t *testing.T) {
+	cmd, err := NewSQLCommand("B", "SELECT count(*) AS n, max(A) AS max FROM A", sql.NewEmbeddedEngine(sql.Limits{MaxRows: 10}))
+	require.NoError(t, err)
+	require.Equal(t, []string{"A"}, cmd.NeedsVars())
+
+	s := mathexp.NewSeries("A", data.Labels{"host": "a"}, 2)
+	s.SetPoint(0, time.Unix(0, 0), util.Pointer(1.0))
+	s.SetPoint(1, time.Unix(60, 0), util.Pointer(3.0))
+	vars := mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{s}}}
+
+	res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+	require.NoError(t, err)
+	require.NoError(t, res.Error)
+	require.Len(t, res.Values, 1)
+	frame := res.Values[0].AsDataFrame()
+	require.Equal(t, "B", frame.RefID)
+	maxValue, _ := frame.Fields[1].ConcreteAt(0)
+	require.Equal(t, 3.0, maxValue)
+}
@@ -98,6 +98,10 @@ func UnmarshalThresholdCommand(rn *rawNode, features featuremgmt.FeatureToggles)
 	}
 	referenceVar := cmdConfig.Expression
 
+	if isMultiLevelHysteresis(cmdConfig.Conditions) && features.IsEnabledGlobally(featuremgmt.FlagRecoveryThreshold) {
+		return newMultiLevelHysteresisCommand(rn.RefID, referenceVar, cmdConfig.Conditions, cmdConfig.LoadedState)
+	}
+
 	// we only support one condition for now, we might want to turn this in to "OR" expressions later
 	if len(cmdConfig.Conditions) != 1 {
 		return nil, fmt.Errorf("threshold expression requires exactly one condition")
@@ -190,14 +194,17 @@ func IsSupportedThresholdFunc(name string) bool {
 }
 
 type ThresholdCommandConfig struct {
-	Expression string                   `json:"expression"`
-	Conditions []ThresholdConditionJSON `json:"conditions"`
+	Expression  string                   `json:"expression"`
+	Conditions  []ThresholdConditionJSON `json:"conditions"`
+	LoadedState *data.Frame              `json:"loadedState,omitempty"`
 }
 
 type ThresholdConditionJSON struct {
 	Evaluator        ConditionEvalJSON  `json:"evaluator"`
 	UnloadEvaluator  *ConditionEvalJSON `json:"unloadEvaluator,omitempty"`
 	LoadedDimensions *data.Frame        `json:"loadedDimensions,omitempty"`
+	// The number of consecutive evaluations the unload condition must be met before the level is left
+	UnloadEvaluations int `json:"unloadEvaluations,omitempty"`
 }
 
 // IsHysteresisExpression returns true if the raw model describes a hysteresis command:
@@ -226,6 +233,34 @@ func SetLoadedDimensionsToHysteresisCommand(query map[string]any, fingerprints F
 	return nil
 }
 
+// IsMultiLevelHysteresisExpression returns true if the raw model describes a multi-level hysteresis command:
+// - field 'type' has value "threshold",
+// - field 'conditions' has more than one element, or its only element has field 'unloadEvaluations' greater than 1
+func IsMultiLevelHysteresisExpression(query map[string]any) bool {
+	t, err := GetExpressionCommandType(query)
+	if err != nil || t != TypeThreshold {
+		return false
+	}
+	raw, err := json.Marshal(query["conditions"])
+	if err != nil {
+		return false
+	}
+	var conditions []ThresholdConditionJSON
+	if err := json.Unmarshal(raw, &conditions); err != nil {
+		return false
+	}
+	return isMultiLevelHysteresis(conditions)
+}
+
+// SetStateToMultiLevelHysteresisCommand mutates the input map and sets field "loadedState" with the data frame created from the provided state.
+func SetStateToMultiLevelHysteresisCommand(query map[string]any, state HysteresisState) error {
+	if !IsMultiLevelHysteresisExpression(query) {
+		return errors.New("not a multi-level hysteresis command")
+	}
+	query["loadedState"] = HysteresisStateToFrame(state)
+	return nil
+}
+
 func getConditionForHysteresisCommand(query map[string]any) (map[string]any, error) {
 	t, err := GetExpressionCommandType(query)
 	if err != nil {
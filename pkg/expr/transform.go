@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/services/datasources"
@@ -93,6 +94,16 @@ func (s *Service) TransformData(ctx context.Context, now time.Time, req *Request
 		return nil, err
 	}
 
+	// In debug mode, return the results of all nodes, including hidden ones, and the debug information of the pipeline.
+	if req.Debug {
+		responses, debug, err := s.ExecutePipelineWithDebug(ctx, now, pipeline)
+		if err != nil {
+			return nil, err
+		}
+		responses.Responses[DebugRefID] = backend.DataResponse{Frames: data.Frames{debug.Frame()}}
+		return responses, nil
+	}
+
 	// Execute the pipeline
 	responses, err := s.ExecutePipeline(ctx, now, pipeline)
 	if err != nil {
@@ -0,0 +1,191 @@
+// Package sqlconn builds the connection strings of the databases of PostgreSQL and MySQL
+// data sources, for the features that write to these databases directly instead of
+// querying them through the data source plugins.
+package sqlconn
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+const (
+	// DriverPostgres is the name of the database/sql driver of PostgreSQL data sources.
+	DriverPostgres = "postgres"
+	// DriverMySQL is the name of the database/sql driver of MySQL data sources.
+	DriverMySQL = "mysql"
+)
+
+// ConnectionString returns the name of the database/sql driver and the connection string
+// of the database of a PostgreSQL or MySQL data source. decryptedSecureJSONData are the
+// decrypted secure settings of the data source.
+func ConnectionString(ds *datasources.DataSource, decryptedSecureJSONData map[string]string) (string, string, error) {
+	switch ds.Type {
+	case datasources.DS_POSTGRES, "postgres":
+		cnnstr, err := postgresConnectionString(ds, decryptedSecureJSONData)
+		if err != nil {
+			return "", "", err
+		}
+		return DriverPostgres, cnnstr, nil
+	case datasources.DS_MYSQL:
+		cnnstr, err := mysqlConnectionString(ds, decryptedSecureJSONData)
+		if err != nil {
+			return "", "", err
+		}
+		return DriverMySQL, cnnstr, nil
+	default:
+		return "", "", fmt.Errorf("data source type %s is not supported, only PostgreSQL and MySQL data sources are", ds.Type)
+	}
+}
+
+// database returns the name of the database of the data source.
+func database(ds *datasources.DataSource) string {
+	if ds.JsonData != nil {
+		if db := ds.JsonData.Get("database").MustString(); db != "" {
+			return db
+		}
+	}
+	return ds.Database
+}
+
+// escapePostgresParameter escapes single quotes and backslashes in Postgres connection string parameters.
+func escapePostgresParameter(input string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(input, `\`, `\\`), "'", `\'`)
+}
+
+func postgresConnectionString(ds *datasources.DataSource, decryptedSecureJSONData map[string]string) (string, error) {
+	host := ds.URL
+	port := ""
+	if !strings.HasPrefix(ds.URL, "/") {
+		if h, p, err := net.SplitHostPort(ds.URL); err == nil {
+			host, port = h, p
+		} else {
+			host = strings.Trim(ds.URL, "[]")
+		}
+	}
+	cnnstr := fmt.Sprintf("user='%s' password='%s' host='%s' dbname='%s'",
+		escapePostgresParameter(ds.User), escapePostgresParameter(decryptedSecureJSONData["password"]), escapePostgresParameter(host), escapePostgresParameter(database(ds)))
+	if port != "" {
+		if _, err := strconv.Atoi(port); err != nil {
+			return "", fmt.Errorf("invalid port in host specifier %q", port)
+		}
+		cnnstr += " port=" + port
+	}
+
+	mode := "verify-full"
+	if ds.JsonData != nil {
+		mode = ds.JsonData.Get("sslmode").MustString(mode)
+	}
+	if mode != "disable" {
+		certParams, err := postgresCertificateParameters(ds, decryptedSecureJSONData)
+		if err != nil {
+			return "", err
+		}
+		cnnstr += certParams
+	}
+	cnnstr += fmt.Sprintf(" sslmode='%s'", escapePostgresParameter(mode))
+	return cnnstr, nil
+}
+
+// postgresCertificateParameters returns the connection string parameters of the root and client
+// certificates of a PostgreSQL data source. Certificates configured by content are passed inline.
+func postgresCertificateParameters(ds *datasources.DataSource, decryptedSecureJSONData map[string]string) (string, error) {
+	if ds.JsonData == nil {
+		return "", nil
+	}
+	var rootCert, cert, key string
+	inline := ds.JsonData.Get("tlsConfigurationMethod").MustString() == "file-content"
+	if inline {
+		rootCert, cert, key = decryptedSecureJSONData["tlsCACert"], decryptedSecureJSONData["tlsClientCert"], decryptedSecureJSONData["tlsClientKey"]
+	} else {
+		rootCert, cert, key = ds.JsonData.Get("sslRootCertFile").MustString(), ds.JsonData.Get("sslCertFile").MustString(), ds.JsonData.Get("sslKeyFile").MustString()
+	}
+	if (cert == "") != (key == "") {
+		return "", errors.New("PostgreSQL data source has a TLS client certificate without a key or a key without a certificate")
+	}
+
+	var params string
+	if inline && (rootCert != "" || cert != "") {
+		params += " sslinline='true'"
+	}
+	if rootCert != "" {
+		params += fmt.Sprintf(" sslrootcert='%s'", escapePostgresParameter(rootCert))
+	}
+	if cert != "" {
+		params += fmt.Sprintf(" sslcert='%s' sslkey='%s'", escapePostgresParameter(cert), escapePostgresParameter(key))
+	}
+	return params, nil
+}
+
+func mysqlConnectionString(ds *datasources.DataSource, decryptedSecureJSONData map[string]string) (string, error) {
+	cfg := mysql.NewConfig()
+	cfg.User = ds.User
+	cfg.Passwd = decryptedSecureJSONData["password"]
+	cfg.Net = "tcp"
+	if strings.HasPrefix(ds.URL, "/") {
+		cfg.Net = "unix"
+	}
+	cfg.Addr = ds.URL
+	cfg.DBName = database(ds)
+	cfg.Collation = "utf8mb4_unicode_ci"
+	cfg.ParseTime = true
+	cfg.Loc = time.UTC
+	cfg.AllowNativePasswords = true
+
+	tlsConfig, err := mysqlTLSConfig(ds, decryptedSecureJSONData)
+	if err != nil {
+		return "", err
+	}
+	if tlsConfig != nil {
+		// The driver looks up TLS configurations by name, the configuration of a data source
+		// is replaced every time its connection string is built.
+		cfg.TLSConfig = fmt.Sprintf("sqlconn-%d-%s", ds.OrgID, ds.UID)
+		if err := mysql.RegisterTLSConfig(cfg.TLSConfig, tlsConfig); err != nil {
+			return "", err
+		}
+	}
+	return cfg.FormatDSN(), nil
+}
+
+// mysqlTLSConfig returns the TLS configuration of a MySQL data source, or nil if it does not use TLS.
+func mysqlTLSConfig(ds *datasources.DataSource, decryptedSecureJSONData map[string]string) (*tls.Config, error) {
+	if ds.JsonData == nil {
+		return nil, nil
+	}
+	tlsAuth := ds.JsonData.Get("tlsAuth").MustBool()
+	tlsAuthWithCACert := ds.JsonData.Get("tlsAuthWithCACert").MustBool()
+	tlsSkipVerify := ds.JsonData.Get("tlsSkipVerify").MustBool()
+	if !tlsAuth && !tlsAuthWithCACert && !tlsSkipVerify {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         ds.JsonData.Get("serverName").MustString(),
+		InsecureSkipVerify: tlsSkipVerify, // #nosec G402 -- skipping verification is configured on the data source
+		MinVersion:         tls.VersionTLS12,
+	}
+	if tlsAuthWithCACert {
+		caPool := x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM([]byte(decryptedSecureJSONData["tlsCACert"])) {
+			return nil, errors.New("failed to parse the TLS CA certificate of the MySQL data source")
+		}
+		tlsConfig.RootCAs = caPool
+	}
+	if tlsAuth {
+		cert, err := tls.X509KeyPair([]byte(decryptedSecureJSONData["tlsClientCert"]), []byte(decryptedSecureJSONData["tlsClientKey"]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the TLS client certificate of the MySQL data source: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
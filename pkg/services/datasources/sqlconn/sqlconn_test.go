@@ -0,0 +1,133 @@
+package sqlconn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+func TestConnectionString(t *testing.T) {
+	t.Run("should build PostgreSQL connection string", func(t *testing.T) {
+		ds := &datasources.DataSource{
+			Type:     datasources.DS_POSTGRES,
+			URL:      "localhost:5432",
+			User:     "grafana",
+			JsonData: simplejson.NewFromAny(map[string]any{"database": "iot", "sslmode": "disable"}),
+		}
+		driverName, cnnstr, err := ConnectionString(ds, map[string]string{"password": "it's"})
+		require.NoError(t, err)
+		require.Equal(t, DriverPostgres, driverName)
+		require.Equal(t, `user='grafana' password='it\'s' host='localhost' dbname='iot' port=5432 sslmode='disable'`, cnnstr)
+
+		ds.URL = "db.local:port"
+		_, _, err = ConnectionString(ds, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("should build PostgreSQL connection string with certificate files", func(t *testing.T) {
+		ds := &datasources.DataSource{
+			Type:     datasources.DS_POSTGRES,
+			URL:      "/var/run/postgresql",
+			Database: "iot",
+			JsonData: simplejson.NewFromAny(map[string]any{
+				"sslmode":         "verify-ca",
+				"sslRootCertFile": "/etc/ssl/ca.pem",
+				"sslCertFile":     "/etc/ssl/client.pem",
+				"sslKeyFile":      "/etc/ssl/client.key",
+			}),
+		}
+		_, cnnstr, err := ConnectionString(ds, nil)
+		require.NoError(t, err)
+		require.Equal(t, `user='' password='' host='/var/run/postgresql' dbname='iot' sslrootcert='/etc/ssl/ca.pem' sslcert='/etc/ssl/client.pem' sslkey='/etc/ssl/client.key' sslmode='verify-ca'`, cnnstr)
+	})
+
+	t.Run("should build PostgreSQL connection string with certificates configured by content", func(t *testing.T) {
+		ds := &datasources.DataSource{
+			Type:     datasources.DS_POSTGRES,
+			URL:      "localhost",
+			Database: "iot",
+			JsonData: simplejson.NewFromAny(map[string]any{"sslmode": "verify-full", "tlsConfigurationMethod": "file-content"}),
+		}
+		_, cnnstr, err := ConnectionString(ds, map[string]string{"tlsCACert": "ca", "tlsClientCert": "cert", "tlsClientKey": "key"})
+		require.NoError(t, err)
+		require.Equal(t, `user='' password='' host='localhost' dbname='iot' sslinline='true' sslrootcert='ca' sslcert='cert' sslkey='key' sslmode='verify-full'`, cnnstr)
+
+		_, _, err = ConnectionString(ds, map[string]string{"tlsClientCert": "cert"})
+		require.ErrorContains(t, err, "without a key")
+	})
+
+	t.Run("should build MySQL connection string", func(t *testing.T) {
+		driverName, cnnstr, err := ConnectionString(&datasources.DataSource{
+			Type:     datasources.DS_MYSQL,
+			URL:      "localhost:3306",
+			User:     "grafana",
+			Database: "iot",
+		}, map[string]string{"password": "secret"})
+		require.NoError(t, err)
+		require.Equal(t, DriverMySQL, driverName)
+		require.Equal(t, "grafana:secret@tcp(localhost:3306)/iot?collation=utf8mb4_unicode_ci&parseTime=true", cnnstr)
+	})
+
+	t.Run("should build MySQL connection string with TLS", func(t *testing.T) {
+		certPEM, keyPEM := generateTestCertificate(t)
+		ds := &datasources.DataSource{
+			OrgID:    1,
+			UID:      "mysql",
+			Type:     datasources.DS_MYSQL,
+			URL:      "localhost:3306",
+			Database: "iot",
+			JsonData: simplejson.NewFromAny(map[string]any{"tlsAuth": true, "tlsAuthWithCACert": true, "serverName": "db.local"}),
+		}
+		_, cnnstr, err := ConnectionString(ds, map[string]string{"tlsCACert": certPEM, "tlsClientCert": certPEM, "tlsClientKey": keyPEM})
+		require.NoError(t, err)
+		require.Contains(t, cnnstr, "tls=sqlconn-1-mysql")
+
+		_, _, err = ConnectionString(ds, map[string]string{"tlsCACert": "invalid", "tlsClientCert": certPEM, "tlsClientKey": keyPEM})
+		require.ErrorContains(t, err, "CA certificate")
+
+		_, _, err = ConnectionString(ds, map[string]string{"tlsCACert": certPEM, "tlsClientCert": certPEM})
+		require.ErrorContains(t, err, "client certificate")
+
+		ds.JsonData = simplejson.NewFromAny(map[string]any{"tlsSkipVerify": true})
+		_, cnnstr, err = ConnectionString(ds, nil)
+		require.NoError(t, err)
+		require.Contains(t, cnnstr, "tls=sqlconn-1-mysql")
+	})
+
+	t.Run("should reject other data sources", func(t *testing.T) {
+		_, _, err := ConnectionString(&datasources.DataSource{Type: datasources.DS_MSSQL}, nil)
+		require.Error(t, err)
+	})
+}
+
+func generateTestCertificate(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "db.local"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return string(certPEM), string(keyPEM)
+}
@@ -75,7 +75,7 @@ type CoreGrafanaScope struct {
 
 func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, routeRegister routing.RouteRegister,
 	pluginStore pluginstore.Store, pluginClient plugins.Client, cacheService *localcache.CacheService,
-	dataSourceCache datasources.CacheService, sqlStore db.DB, secretsService secrets.Service,
+	dataSourceCache datasources.CacheService, dataSourceService datasources.DataSourceService, sqlStore db.DB, secretsService secrets.Service,
 	usageStatsService usagestats.Service, queryDataService query.Service, toggles featuremgmt.FeatureToggles,
 	accessControl accesscontrol.AccessControl, dashboardService dashboards.DashboardService, annotationsRepo annotations.Repository,
 	orgService org.Service) (*GrafanaLive, error) {
@@ -88,6 +88,7 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 		pluginClient:          pluginClient,
 		CacheService:          cacheService,
 		DataSourceCache:       dataSourceCache,
+		DataSourceService:     dataSourceService,
 		SQLStore:              sqlStore,
 		SecretsService:        secretsService,
 		queryDataService:      queryDataService,
@@ -148,22 +149,46 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 		}
 	}
 
+	historyConfig := managedstream.HistoryConfig{
+		Size:           cfg.LiveManagedStreamHistorySize,
+		Duration:       cfg.LiveManagedStreamHistoryDuration,
+		MaxBytesPerOrg: cfg.LiveManagedStreamHistoryMaxBytesPerOrg,
+	}
 	if redisClient != nil {
+		g.redisFrameCache = managedstream.NewRedisFrameCache(redisClient, historyConfig)
 		managedStreamRunner = managedstream.NewRunner(
 			g.Publish,
 			channelLocalPublisher,
-			managedstream.NewRedisFrameCache(redisClient),
+			g.redisFrameCache,
 		)
 	} else {
 		managedStreamRunner = managedstream.NewRunner(
 			g.Publish,
 			channelLocalPublisher,
-			managedstream.NewMemoryFrameCache(),
+			managedstream.NewMemoryFrameCache(historyConfig),
 		)
 	}
 
 	g.ManagedStreamRunner = managedStreamRunner
 
+	if cfg.LivePipelineEnabled {
+		storage := pipeline.NewSQLStorage(g.SQLStore, g.SecretsService)
+		g.pipelineStorage = storage
+		builder := &pipeline.StorageRuleBuilder{
+			Node:                 node,
+			ManagedStream:        g.ManagedStreamRunner,
+			FrameStorage:         pipeline.NewFrameStorage(),
+			Storage:              storage,
+			ChannelHandlerGetter: g,
+			SecretsService:       g.SecretsService,
+			DataSourceService:    g.DataSourceService,
+		}
+		g.Pipeline, err = pipeline.New(pipeline.NewCacheSegmentedTree(builder))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	g.contextGetter = liveplugin.NewContextGetter(g.PluginContextProvider, g.DataSourceCache)
 	pipelinedChannelLocalPublisher := liveplugin.NewChannelLocalPublisher(node, g.Pipeline)
 	numLocalSubscribersGetter := liveplugin.NewNumLocalSubscribersGetter(node)
@@ -378,6 +403,7 @@ type GrafanaLive struct {
 	RouteRegister         routing.RouteRegister
 	CacheService          *localcache.CacheService
 	DataSourceCache       datasources.CacheService
+	DataSourceService     datasources.DataSourceService
 	SQLStore              db.DB
 	SecretsService        secrets.Service
 	pluginStore           pluginstore.Store
@@ -401,6 +427,7 @@ type GrafanaLive struct {
 	GrafanaScope CoreGrafanaScope
 
 	ManagedStreamRunner *managedstream.Runner
+	redisFrameCache     *managedstream.RedisFrameCache
 	Pipeline            *pipeline.Pipeline
 	pipelineStorage     pipeline.Storage
 
@@ -456,6 +483,12 @@ func (g *GrafanaLive) Run(ctx context.Context) error {
 		}
 	})
 
+	if g.redisFrameCache != nil {
+		eGroup.Go(func() error {
+			return g.redisFrameCache.Run(eCtx)
+		})
+	}
+
 	if g.runStreamManager != nil {
 		// Only run stream manager if GrafanaLive properly initialized.
 		eGroup.Go(func() error {
@@ -725,40 +758,20 @@ func (g *GrafanaLive) handleOnPublish(ctx context.Context, client *centrifuge.Cl
 		return centrifuge.PublishReply{}, centrifuge.ErrorPermissionDenied
 	}
 
-	if g.Pipeline != nil {
-		rule, ok, err := g.Pipeline.Get(user.GetOrgID(), channel)
-		if err != nil {
-			logger.Error("Error getting channel rule", "user", client.UserID(), "client", client.ID(), "channel", e.Channel, "error", err)
-			return centrifuge.PublishReply{}, centrifuge.ErrorInternal
-		}
-		if ok {
-			if rule.PublishAuth != nil {
-				ok, err := rule.PublishAuth.CanPublish(client.Context(), user)
-				if err != nil {
-					logger.Error("Error checking publish permissions", "user", client.UserID(), "client", client.ID(), "channel", e.Channel, "error", err)
-					return centrifuge.PublishReply{}, centrifuge.ErrorInternal
-				}
-				if !ok {
-					// using HTTP error codes for WS errors too.
-					code, text := publishStatusToHTTPError(backend.PublishStreamStatusPermissionDenied)
-					return centrifuge.PublishReply{}, &centrifuge.Error{Code: uint32(code), Message: text}
-				}
-			} else {
-				if !user.HasRole(org.RoleAdmin) {
-					// using HTTP error codes for WS errors too.
-					code, text := publishStatusToHTTPError(backend.PublishStreamStatusPermissionDenied)
-					return centrifuge.PublishReply{}, &centrifuge.Error{Code: uint32(code), Message: text}
-				}
-			}
-			_, err := g.Pipeline.ProcessInput(client.Context(), user.GetOrgID(), channel, e.Data)
-			if err != nil {
-				logger.Error("Error processing input", "user", client.UserID(), "client", client.ID(), "channel", e.Channel, "error", err)
-				return centrifuge.PublishReply{}, centrifuge.ErrorInternal
-			}
-			return centrifuge.PublishReply{
-				Result: &centrifuge.PublishResult{},
-			}, nil
+	ruleFound, err := g.PublishToPipeline(client.Context(), user, channel, e.Data)
+	if err != nil {
+		if errors.Is(err, ErrPipelinePublishForbidden) {
+			// using HTTP error codes for WS errors too.
+			code, text := publishStatusToHTTPError(backend.PublishStreamStatusPermissionDenied)
+			return centrifuge.PublishReply{}, &centrifuge.Error{Code: uint32(code), Message: text}
 		}
+		logger.Error("Error processing input", "user", client.UserID(), "client", client.ID(), "channel", e.Channel, "error", err)
+		return centrifuge.PublishReply{}, centrifuge.ErrorInternal
+	}
+	if ruleFound {
+		return centrifuge.PublishReply{
+			Result: &centrifuge.PublishResult{},
+		}, nil
 	}
 
 	handler, addr, err := g.GetChannelHandler(ctx, user, channel)
@@ -806,6 +819,41 @@ func (g *GrafanaLive) handleOnPublish(ctx context.Context, client *centrifuge.Cl
 	return centrifugeReply, nil
 }
 
+// ErrPipelinePublishForbidden is returned when the channel rule does not allow the user to publish to the channel.
+var ErrPipelinePublishForbidden = errors.New("publishing to channel is forbidden")
+
+// PublishToPipeline processes the data published to the channel with Live pipeline if there is a channel
+// rule for it. Publishing is allowed by the PublishAuth of the rule, or to admins if the rule has none.
+// All the ways to push data to Live pipeline must use it, so that the channel rules apply to all of them.
+// It returns false if Live pipeline is disabled or there is no rule for the channel.
+func (g *GrafanaLive) PublishToPipeline(ctx context.Context, user identity.Requester, channel string, data []byte) (bool, error) {
+	if g.Pipeline == nil {
+		return false, nil
+	}
+	rule, ok, err := g.Pipeline.Get(user.GetOrgID(), channel)
+	if err != nil {
+		return false, fmt.Errorf("error getting channel rule: %w", err)
+	}
+	if !ok {
+		return false, nil
+	}
+	if rule.PublishAuth != nil {
+		ok, err := rule.PublishAuth.CanPublish(ctx, user)
+		if err != nil {
+			return true, fmt.Errorf("error checking publish permissions: %w", err)
+		}
+		if !ok {
+			return true, ErrPipelinePublishForbidden
+		}
+	} else if !user.HasRole(org.RoleAdmin) {
+		return true, ErrPipelinePublishForbidden
+	}
+	if _, err := g.Pipeline.ProcessInput(ctx, user.GetOrgID(), channel, data); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
 func subscribeStatusToHTTPError(status backend.SubscribeStreamStatus) (int, string) {
 	switch status {
 	case backend.SubscribeStreamStatusNotFound:
@@ -960,34 +1008,16 @@ func (g *GrafanaLive) HandleHTTPPublish(ctx *contextmodel.ReqContext) response.R
 	user := ctx.SignedInUser
 	channel := cmd.Channel
 
-	if g.Pipeline != nil {
-		rule, ok, err := g.Pipeline.Get(user.GetOrgID(), channel)
-		if err != nil {
-			logger.Error("Error getting channel rule", "user", user, "channel", channel, "error", err)
-			return response.Error(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError), nil)
-		}
-		if ok {
-			if rule.PublishAuth != nil {
-				ok, err := rule.PublishAuth.CanPublish(ctx.Req.Context(), user)
-				if err != nil {
-					logger.Error("Error checking publish permissions", "user", user, "channel", channel, "error", err)
-					return response.Error(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError), nil)
-				}
-				if !ok {
-					return response.Error(http.StatusForbidden, http.StatusText(http.StatusForbidden), nil)
-				}
-			} else {
-				if !user.HasRole(org.RoleAdmin) {
-					return response.Error(http.StatusForbidden, http.StatusText(http.StatusForbidden), nil)
-				}
-			}
-			_, err := g.Pipeline.ProcessInput(ctx.Req.Context(), user.GetOrgID(), channel, cmd.Data)
-			if err != nil {
-				logger.Error("Error processing input", "user", user, "channel", channel, "error", err)
-				return response.Error(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError), nil)
-			}
-			return response.JSON(http.StatusOK, dtos.LivePublishResponse{})
+	ruleFound, err := g.PublishToPipeline(ctx.Req.Context(), user, channel, cmd.Data)
+	if err != nil {
+		if errors.Is(err, ErrPipelinePublishForbidden) {
+			return response.Error(http.StatusForbidden, http.StatusText(http.StatusForbidden), nil)
 		}
+		logger.Error("Error processing input", "user", user, "channel", channel, "error", err)
+		return response.Error(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError), nil)
+	}
+	if ruleFound {
+		return response.JSON(http.StatusOK, dtos.LivePublishResponse{})
 	}
 
 	channelHandler, addr, err := g.GetChannelHandler(ctx.Req.Context(), ctx.SignedInUser, cmd.Channel)
@@ -1133,6 +1163,8 @@ func (g *GrafanaLive) HandlePipelineConvertTestHTTP(c *contextmodel.ReqContext)
 		FrameStorage:         pipeline.NewFrameStorage(),
 		Storage:              storage,
 		ChannelHandlerGetter: g,
+		DataSourceService:    g.DataSourceService,
+		DryRun:               true,
 	}
 	channelRuleGetter := pipeline.NewCacheSegmentedTree(builder)
 	pipe, err := pipeline.New(channelRuleGetter)
@@ -1194,6 +1226,9 @@ func (g *GrafanaLive) HandleChannelRulesPutHTTP(c *contextmodel.ReqContext) resp
 	}
 	rule, err := g.pipelineStorage.UpdateChannelRule(c.Req.Context(), c.SignedInUser.GetOrgID(), cmd)
 	if err != nil {
+		if errors.Is(err, pipeline.ErrVersionConflict) {
+			return response.Error(http.StatusConflict, "Channel rule was changed by someone else", err)
+		}
 		return response.Error(http.StatusInternalServerError, "Failed to update channel rule", err)
 	}
 	return response.JSON(http.StatusOK, util.DynMap{
@@ -1217,6 +1252,9 @@ func (g *GrafanaLive) HandleChannelRulesDeleteHTTP(c *contextmodel.ReqContext) r
 	}
 	err = g.pipelineStorage.DeleteChannelRule(c.Req.Context(), c.SignedInUser.GetOrgID(), cmd)
 	if err != nil {
+		if errors.Is(err, pipeline.ErrChannelRuleNotFound) {
+			return response.Error(http.StatusNotFound, "Channel rule not found", err)
+		}
 		return response.Error(http.StatusInternalServerError, "Failed to delete channel rule", err)
 	}
 	return response.JSON(http.StatusOK, util.DynMap{})
@@ -1305,6 +1343,9 @@ func (g *GrafanaLive) HandleWriteConfigsPutHTTP(c *contextmodel.ReqContext) resp
 	}
 	result, err := g.pipelineStorage.UpdateWriteConfig(c.Req.Context(), c.SignedInUser.GetOrgID(), cmd)
 	if err != nil {
+		if errors.Is(err, pipeline.ErrVersionConflict) {
+			return response.Error(http.StatusConflict, "Write config was changed by someone else", err)
+		}
 		return response.Error(http.StatusInternalServerError, "Failed to update write config", err)
 	}
 	return response.JSON(http.StatusOK, util.DynMap{
@@ -1328,6 +1369,9 @@ func (g *GrafanaLive) HandleWriteConfigsDeleteHTTP(c *contextmodel.ReqContext) r
 	}
 	err = g.pipelineStorage.DeleteWriteConfig(c.Req.Context(), c.SignedInUser.GetOrgID(), cmd)
 	if err != nil {
+		if errors.Is(err, pipeline.ErrWriteConfigNotFound) {
+			return response.Error(http.StatusNotFound, "Write config not found", err)
+		}
 		return response.Error(http.StatusInternalServerError, "Failed to delete write config", err)
 	}
 	return response.JSON(http.StatusOK, util.DynMap{})
@@ -17,6 +17,9 @@ import (
 	"github.com/grafana/grafana/pkg/services/authz/zanzana"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tests/testsuite"
 )
@@ -32,7 +35,7 @@ func Test_provideLiveService_RedisUnavailable(t *testing.T) {
 
 	_, err := ProvideService(nil, cfg,
 		routing.NewRouteRegister(),
-		nil, nil, nil, nil,
+		nil, nil, nil, nil, nil,
 		db.InitTestDB(t),
 		nil,
 		&usagestats.UsageStatsMock{T: t},
@@ -230,3 +233,73 @@ func Test_getHistogramMetric(t *testing.T) {
 		})
 	}
 }
+
+type testChannelRuleGetter map[string]*pipeline.LiveChannelRule
+
+func (g testChannelRuleGetter) Get(_ int64, channel string) (*pipeline.LiveChannelRule, bool, error) {
+	rule, ok := g[channel]
+	return rule, ok, nil
+}
+
+type testDataOutputter struct {
+	data []string
+}
+
+func (o *testDataOutputter) Type() string {
+	return "test"
+}
+
+func (o *testDataOutputter) OutputData(_ context.Context, _ pipeline.Vars, data []byte) ([]*pipeline.ChannelData, error) {
+	o.data = append(o.data, string(data))
+	return nil, nil
+}
+
+func TestPublishToPipeline(t *testing.T) {
+	outputter := &testDataOutputter{}
+	pipe, err := pipeline.New(testChannelRuleGetter{
+		"stream/test/admins": {DataOutputters: []pipeline.DataOutputter{outputter}},
+		"stream/test/editors": {
+			PublishAuth:    pipeline.NewRoleCheckAuthorizer(org.RoleEditor),
+			DataOutputters: []pipeline.DataOutputter{outputter},
+		},
+	})
+	require.NoError(t, err)
+	g := &GrafanaLive{Pipeline: pipe}
+
+	admin := &user.SignedInUser{OrgID: 1, OrgRole: org.RoleAdmin}
+	editor := &user.SignedInUser{OrgID: 1, OrgRole: org.RoleEditor}
+	viewer := &user.SignedInUser{OrgID: 1, OrgRole: org.RoleViewer}
+
+	testCases := []struct {
+		name      string
+		user      *user.SignedInUser
+		channel   string
+		ruleFound bool
+		err       error
+	}{
+		{name: "admin can publish without publish auth", user: admin, channel: "stream/test/admins", ruleFound: true},
+		{name: "editor can't publish without publish auth", user: editor, channel: "stream/test/admins", ruleFound: true, err: ErrPipelinePublishForbidden},
+		{name: "editor can publish with publish auth", user: editor, channel: "stream/test/editors", ruleFound: true},
+		{name: "viewer can't publish with publish auth", user: viewer, channel: "stream/test/editors", ruleFound: true, err: ErrPipelinePublishForbidden},
+		{name: "no channel rule", user: admin, channel: "stream/test/unknown"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			outputter.data = nil
+			ruleFound, err := g.PublishToPipeline(context.Background(), tc.user, tc.channel, []byte("data"))
+			require.ErrorIs(t, err, tc.err)
+			require.Equal(t, tc.ruleFound, ruleFound)
+			if tc.ruleFound && tc.err == nil {
+				require.Equal(t, []string{"data"}, outputter.data)
+			} else {
+				require.Empty(t, outputter.data)
+			}
+		})
+	}
+
+	t.Run("should not find rules if pipeline is disabled", func(t *testing.T) {
+		ruleFound, err := (&GrafanaLive{}).PublishToPipeline(context.Background(), admin, "stream/test/admins", []byte("data"))
+		require.NoError(t, err)
+		require.False(t, ruleFound)
+	})
+}
@@ -3,6 +3,7 @@ package managedstream
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 )
@@ -13,6 +14,99 @@ type FrameCache interface {
 	GetActiveChannels(orgID int64) (map[string]json.RawMessage, error)
 	// GetFrame returns full JSON frame for a channel in org.
 	GetFrame(ctx context.Context, orgID int64, channel string) (json.RawMessage, bool, error)
-	// Update updates frame cache and returns true if schema changed.
+	// GetHistory returns the full JSON frames in the history of a channel in org, oldest first.
+	// It returns no frames if the history is disabled.
+	GetHistory(ctx context.Context, orgID int64, channel string) ([]json.RawMessage, error)
+	// Update updates frame cache and returns true if schema changed. The frame is added to the
+	// history of the channel, which is reset when the schema changes.
 	Update(ctx context.Context, orgID int64, channel string, frameJson data.FrameJSONCache) (bool, error)
 }
+
+// HistoryConfig configures the history of recent frames kept for each channel, which is replayed
+// to new subscribers. The history is disabled if both Size and Duration are zero.
+type HistoryConfig struct {
+	// Size is the maximum number of frames kept per channel. Zero means no limit.
+	Size int
+	// Duration is the maximum age of the frames kept per channel. Zero means no limit.
+	Duration time.Duration
+	// MaxBytesPerOrg limits the total size of the history of all the channels of an org. When
+	// it is exceeded, the oldest frames of the channel that is updated are dropped. Frames older
+	// than Duration don't count toward the limit, even in channels that are no longer updated: the
+	// memory cache drops them on every update, the Redis cache periodically. Zero means no limit.
+	MaxBytesPerOrg int64
+}
+
+func (c HistoryConfig) enabled() bool {
+	return c.Size > 0 || c.Duration > 0
+}
+
+// minTime returns the time of the oldest frame that can be kept in the history.
+func (c HistoryConfig) minTime(now time.Time) time.Time {
+	if c.Duration <= 0 {
+		return time.Time{}
+	}
+	return now.Add(-c.Duration)
+}
+
+type historyEntry struct {
+	time  time.Time
+	frame json.RawMessage
+}
+
+// trimHistory drops the oldest entries of a channel history, always keeping the latest one. It returns
+// the kept entries and the size of the history of the org after trimming.
+func trimHistory(cfg HistoryConfig, entries []historyEntry, orgBytes int64, now time.Time) ([]historyEntry, int64) {
+	minTime := cfg.minTime(now)
+	for len(entries) > 1 {
+		oldest := entries[0]
+		if (cfg.Size > 0 && len(entries) > cfg.Size) ||
+			oldest.time.Before(minTime) ||
+			(cfg.MaxBytesPerOrg > 0 && orgBytes > cfg.MaxBytesPerOrg) {
+			entries = entries[1:]
+			orgBytes -= int64(len(oldest.frame))
+			continue
+		}
+		break
+	}
+	return entries, orgBytes
+}
+
+// dropExpiredHistory drops the entries of a channel history that are older than the duration of the
+// history, including the latest one, as the channel is not being updated. It returns the kept entries
+// and the size of the dropped ones.
+func dropExpiredHistory(cfg HistoryConfig, entries []historyEntry, now time.Time) ([]historyEntry, int64) {
+	minTime := cfg.minTime(now)
+	var dropped int64
+	for len(entries) > 0 && entries[0].time.Before(minTime) {
+		dropped += int64(len(entries[0].frame))
+		entries = entries[1:]
+	}
+	return entries, dropped
+}
+
+// mergeFrames merges JSON frames with the same schema into a single frame JSON with the rows of
+// all of them, so they can be sent to a subscriber at once.
+func mergeFrames(frames []json.RawMessage) (json.RawMessage, error) {
+	var merged *data.Frame
+	for _, frameJSON := range frames {
+		var frame data.Frame
+		if err := json.Unmarshal(frameJSON, &frame); err != nil {
+			return nil, err
+		}
+		if merged == nil {
+			merged = &frame
+			continue
+		}
+		if len(frame.Fields) != len(merged.Fields) {
+			// Frames of a history have the same schema, since it is reset when the schema changes.
+			continue
+		}
+		for i := 0; i < frame.Rows(); i++ {
+			merged.AppendRow(frame.RowCopy(i)...)
+		}
+	}
+	if merged == nil {
+		return nil, nil
+	}
+	return data.FrameToJSON(merged, data.IncludeAll)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"sync"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
@@ -12,16 +13,24 @@ import (
 
 // MemoryFrameCache ...
 type MemoryFrameCache struct {
-	mu     sync.RWMutex
-	frames map[int64]map[string]data.FrameJSONCache
-	log    log.Logger
+	mu            sync.RWMutex
+	frames        map[int64]map[string]data.FrameJSONCache
+	history       map[int64]map[string][]historyEntry
+	historyBytes  map[int64]map[string]int64
+	historyConfig HistoryConfig
+	now           func() time.Time
+	log           log.Logger
 }
 
 // NewMemoryFrameCache ...
-func NewMemoryFrameCache() *MemoryFrameCache {
+func NewMemoryFrameCache(historyConfig HistoryConfig) *MemoryFrameCache {
 	return &MemoryFrameCache{
-		frames: map[int64]map[string]data.FrameJSONCache{},
-		log:    log.New("live.memoryframecache"),
+		frames:        map[int64]map[string]data.FrameJSONCache{},
+		history:       map[int64]map[string][]historyEntry{},
+		historyBytes:  map[int64]map[string]int64{},
+		historyConfig: historyConfig,
+		now:           time.Now,
+		log:           log.New("live.memoryframecache"),
 	}
 }
 
@@ -52,6 +61,19 @@ func (c *MemoryFrameCache) GetFrame(ctx context.Context, orgID int64, channel st
 	return raw, ok, nil
 }
 
+func (c *MemoryFrameCache) GetHistory(_ context.Context, orgID int64, channel string) ([]json.RawMessage, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	minTime := c.historyConfig.minTime(c.now())
+	var frames []json.RawMessage
+	for _, entry := range c.history[orgID][channel] {
+		if !entry.time.Before(minTime) {
+			frames = append(frames, entry.frame)
+		}
+	}
+	return frames, nil
+}
+
 func (c *MemoryFrameCache) Update(ctx context.Context, orgID int64, channel string, jsonFrame data.FrameJSONCache) (bool, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -61,6 +83,9 @@ func (c *MemoryFrameCache) Update(ctx context.Context, orgID int64, channel stri
 	cachedJsonFrame, exists := c.frames[orgID][channel]
 	schemaUpdated := !exists || !cachedJsonFrame.SameSchema(&jsonFrame)
 	c.frames[orgID][channel] = jsonFrame
+	if c.historyConfig.enabled() {
+		c.updateHistory(orgID, channel, schemaUpdated, jsonFrame.Bytes(data.IncludeAll))
+	}
 	c.log.Debug("Cache update",
 		"orgId", orgID,
 		"channel", channel,
@@ -68,3 +93,39 @@ func (c *MemoryFrameCache) Update(ctx context.Context, orgID int64, channel stri
 	)
 	return schemaUpdated, nil
 }
+
+func (c *MemoryFrameCache) updateHistory(orgID int64, channel string, reset bool, frame json.RawMessage) {
+	if _, ok := c.history[orgID]; !ok {
+		c.history[orgID] = map[string][]historyEntry{}
+		c.historyBytes[orgID] = map[string]int64{}
+	}
+	now := c.now()
+	var orgBytes int64
+	for other, entries := range c.history[orgID] {
+		if other != channel {
+			// Frames of channels that are no longer updated expire without being trimmed.
+			entries, dropped := dropExpiredHistory(c.historyConfig, entries, now)
+			if len(entries) == 0 {
+				delete(c.history[orgID], other)
+				delete(c.historyBytes[orgID], other)
+				continue
+			}
+			c.history[orgID][other] = entries
+			c.historyBytes[orgID][other] -= dropped
+		}
+		orgBytes += c.historyBytes[orgID][other]
+	}
+	entries := c.history[orgID][channel]
+	channelBytes := c.historyBytes[orgID][channel]
+	if reset {
+		orgBytes -= channelBytes
+		channelBytes = 0
+		entries = nil
+	}
+	entries = append(entries, historyEntry{time: now, frame: frame})
+	channelBytes += int64(len(frame))
+	orgBytes += int64(len(frame))
+	trimmed, trimmedOrgBytes := trimHistory(c.historyConfig, entries, orgBytes, now)
+	c.history[orgID][channel] = trimmed
+	c.historyBytes[orgID][channel] = channelBytes - (orgBytes - trimmedOrgBytes)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
@@ -63,7 +64,117 @@ func testFrameCache(t *testing.T, c FrameCache) {
 }
 
 func TestMemoryFrameCache(t *testing.T) {
-	c := NewMemoryFrameCache()
+	c := NewMemoryFrameCache(HistoryConfig{})
 	require.NotNil(t, c)
 	testFrameCache(t, c)
 }
+
+func historyFrame(t *testing.T, value float64) data.FrameJSONCache {
+	t.Helper()
+	frameJsonCache, err := data.FrameToJSONCache(data.NewFrame("hello", data.NewField("value", nil, []float64{value})))
+	require.NoError(t, err)
+	return frameJsonCache
+}
+
+func historyValues(t *testing.T, c FrameCache, orgID int64, channel string) []float64 {
+	t.Helper()
+	history, err := c.GetHistory(context.Background(), orgID, channel)
+	require.NoError(t, err)
+	values := make([]float64, 0, len(history))
+	for _, frameJSON := range history {
+		var f data.Frame
+		require.NoError(t, json.Unmarshal(frameJSON, &f))
+		values = append(values, f.Fields[0].At(0).(float64))
+	}
+	return values
+}
+
+// testFrameCacheHistory tests the history of a frame cache. cleanup drops the expired history of the
+// channels that are not updated, if the cache does not do it on update.
+func testFrameCacheHistory(t *testing.T, newCache func(HistoryConfig) FrameCache, setNow func(FrameCache, time.Time), cleanup func(FrameCache)) {
+	now := time.Now()
+	update := func(c FrameCache, orgID int64, channel string, frame data.FrameJSONCache) {
+		t.Helper()
+		_, err := c.Update(context.Background(), orgID, channel, frame)
+		require.NoError(t, err)
+	}
+
+	t.Run("should keep the latest frames by count and duration", func(t *testing.T) {
+		c := newCache(HistoryConfig{Size: 3, Duration: time.Minute})
+		for i := 1; i <= 4; i++ {
+			setNow(c, now.Add(time.Duration(i)*time.Second))
+			update(c, 1, "test", historyFrame(t, float64(i)))
+		}
+		require.Equal(t, []float64{2, 3, 4}, historyValues(t, c, 1, "test"))
+		require.Empty(t, historyValues(t, c, 2, "test"))
+
+		setNow(c, now.Add(time.Minute+2*time.Second+time.Millisecond))
+		require.Equal(t, []float64{3, 4}, historyValues(t, c, 1, "test"))
+
+		update(c, 1, "test", historyFrame(t, 5))
+		require.Equal(t, []float64{3, 4, 5}, historyValues(t, c, 1, "test"))
+	})
+
+	t.Run("should reset history when schema changes", func(t *testing.T) {
+		c := newCache(HistoryConfig{Size: 3})
+		setNow(c, now)
+		update(c, 1, "test", historyFrame(t, 1))
+		update(c, 1, "test", historyFrame(t, 2))
+
+		frameJsonCache, err := data.FrameToJSONCache(data.NewFrame("hello", data.NewField("value", nil, []int64{3})))
+		require.NoError(t, err)
+		update(c, 1, "test", frameJsonCache)
+
+		history, err := c.GetHistory(context.Background(), 1, "test")
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+	})
+
+	t.Run("should drop the oldest frames of the updated channel when org exceeds the limit", func(t *testing.T) {
+		frame := historyFrame(t, 1)
+		frameSize := int64(len(frame.Bytes(data.IncludeAll)))
+		c := newCache(HistoryConfig{Size: 10, MaxBytesPerOrg: 4*frameSize + 100})
+		setNow(c, now)
+		for i := 1; i <= 3; i++ {
+			update(c, 1, "a", historyFrame(t, float64(i)))
+		}
+		for i := 4; i <= 6; i++ {
+			update(c, 1, "b", historyFrame(t, float64(i)))
+			update(c, 2, "b", historyFrame(t, float64(i)))
+		}
+		require.Equal(t, []float64{1, 2, 3}, historyValues(t, c, 1, "a"))
+		require.Equal(t, []float64{6}, historyValues(t, c, 1, "b"))
+		require.Equal(t, []float64{4, 5, 6}, historyValues(t, c, 2, "b"))
+	})
+
+	t.Run("should not count the expired frames of idle channels toward the org limit", func(t *testing.T) {
+		frame := historyFrame(t, 1)
+		frameSize := int64(len(frame.Bytes(data.IncludeAll)))
+		c := newCache(HistoryConfig{Size: 10, Duration: time.Minute, MaxBytesPerOrg: 4*frameSize + 100})
+		setNow(c, now)
+		for i := 1; i <= 3; i++ {
+			update(c, 1, "idle", historyFrame(t, float64(i)))
+		}
+		setNow(c, now.Add(2*time.Minute))
+		cleanup(c)
+		for i := 4; i <= 7; i++ {
+			update(c, 1, "b", historyFrame(t, float64(i)))
+		}
+		require.Empty(t, historyValues(t, c, 1, "idle"))
+		require.Equal(t, []float64{4, 5, 6, 7}, historyValues(t, c, 1, "b"))
+	})
+
+	t.Run("should not keep history if disabled", func(t *testing.T) {
+		c := newCache(HistoryConfig{})
+		update(c, 1, "test", historyFrame(t, 1))
+		require.Empty(t, historyValues(t, c, 1, "test"))
+	})
+}
+
+func TestMemoryFrameCacheHistory(t *testing.T) {
+	testFrameCacheHistory(t, func(cfg HistoryConfig) FrameCache {
+		return NewMemoryFrameCache(cfg)
+	}, func(c FrameCache, now time.Time) {
+		c.(*MemoryFrameCache).now = func() time.Time { return now }
+	}, func(FrameCache) {})
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,16 +17,20 @@ import (
 
 // RedisFrameCache ...
 type RedisFrameCache struct {
-	mu          sync.RWMutex
-	redisClient *redis.Client
-	frames      map[int64]map[string]data.FrameJSONCache
+	mu            sync.RWMutex
+	redisClient   *redis.Client
+	frames        map[int64]map[string]data.FrameJSONCache
+	historyConfig HistoryConfig
+	now           func() time.Time
 }
 
 // NewRedisFrameCache ...
-func NewRedisFrameCache(redisClient *redis.Client) *RedisFrameCache {
+func NewRedisFrameCache(redisClient *redis.Client, historyConfig HistoryConfig) *RedisFrameCache {
 	return &RedisFrameCache{
-		frames:      map[int64]map[string]data.FrameJSONCache{},
-		redisClient: redisClient,
+		frames:        map[int64]map[string]data.FrameJSONCache{},
+		redisClient:   redisClient,
+		historyConfig: historyConfig,
+		now:           time.Now,
 	}
 }
 
@@ -55,6 +61,27 @@ func (c *RedisFrameCache) GetFrame(ctx context.Context, orgID int64, channel str
 	return json.RawMessage(result["frame"]), true, nil
 }
 
+func (c *RedisFrameCache) GetHistory(ctx context.Context, orgID int64, channel string) ([]json.RawMessage, error) {
+	if !c.historyConfig.enabled() {
+		return nil, nil
+	}
+	key := getHistoryKey(orgID, channel)
+	result, err := c.redisClient.LRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	minTime := c.historyConfig.minTime(c.now())
+	var frames []json.RawMessage
+	for _, entry := range result {
+		t, frame, ok := parseHistoryEntry(entry)
+		if !ok || t.Before(minTime) {
+			continue
+		}
+		frames = append(frames, frame)
+	}
+	return frames, nil
+}
+
 const (
 	frameCacheTTL = 7 * 24 * time.Hour
 )
@@ -99,14 +126,200 @@ func (c *RedisFrameCache) Update(ctx context.Context, orgID int64, channel strin
 		if err != nil {
 			return false, err
 		}
-		if len(result) == 0 {
-			return true, nil
+		schemaUpdated := len(result) == 0 || result["schema"] != stringSchema
+		return schemaUpdated, c.updateHistory(ctx, orgID, channel, schemaUpdated, jsonFrame.Bytes(data.IncludeAll))
+	}
+	return true, c.updateHistory(ctx, orgID, channel, true, jsonFrame.Bytes(data.IncludeAll))
+}
+
+// historyCleanupInterval is how often the expired history of the channels that are no longer updated is dropped.
+const historyCleanupInterval = time.Minute
+
+// updateHistoryScript adds an entry to the history list of a channel and trims it like trimHistory,
+// keeping the size of the history of each channel of the org in a hash and the total in a counter.
+//
+// KEYS[1] is the history list, KEYS[2] the hash with the sizes of the histories of the org and KEYS[3]
+// the total size of the histories of the org.
+// ARGV is the channel, the entry, whether to reset the history, the maximum number of entries, the time
+// of the oldest entry that can be kept in milliseconds, the maximum size of the history of the org and the
+// TTL of the keys in seconds.
+var updateHistoryScript = redis.NewScript(`
+local channel, entry = ARGV[1], ARGV[2]
+local maxSize, minTime, maxBytes = tonumber(ARGV[4]), tonumber(ARGV[5]), tonumber(ARGV[6])
+if ARGV[3] == "1" or redis.call("EXISTS", KEYS[1]) == 0 then
+	local size = tonumber(redis.call("HGET", KEYS[2], channel) or "0")
+	redis.call("DEL", KEYS[1])
+	redis.call("HDEL", KEYS[2], channel)
+	redis.call("DECRBY", KEYS[3], size)
+end
+redis.call("RPUSH", KEYS[1], entry)
+redis.call("HINCRBY", KEYS[2], channel, #entry)
+local total = redis.call("INCRBY", KEYS[3], #entry)
+local length = redis.call("LLEN", KEYS[1])
+while length > 1 do
+	local oldest = redis.call("LINDEX", KEYS[1], 0)
+	local t = tonumber(string.sub(oldest, 1, string.find(oldest, ":", 1, true) - 1))
+	if (maxSize > 0 and length > maxSize) or t < minTime or (maxBytes > 0 and total > maxBytes) then
+		redis.call("LPOP", KEYS[1])
+		redis.call("HINCRBY", KEYS[2], channel, -#oldest)
+		total = redis.call("DECRBY", KEYS[3], #oldest)
+		length = length - 1
+	else
+		break
+	end
+end
+redis.call("EXPIRE", KEYS[1], ARGV[7])
+redis.call("EXPIRE", KEYS[2], ARGV[7])
+redis.call("EXPIRE", KEYS[3], ARGV[7])
+return length
+`)
+
+// cleanupHistoryScript drops the expired entries of the history lists of the channels of an org like
+// dropExpiredHistory, removes the channels whose history list expired from the hash of the sizes, and
+// recalculates the total size of the histories of the org.
+//
+// KEYS[1] is the hash with the sizes of the histories of the org, KEYS[2] the total size of the histories
+// of the org and the rest of KEYS the history lists of the channels.
+// ARGV is the time of the oldest entry that can be kept in milliseconds, the TTL of the keys in seconds and
+// the channels of the history lists.
+var cleanupHistoryScript = redis.NewScript(`
+local minTime = tonumber(ARGV[1])
+for i = 3, #KEYS do
+	local key, channel, dropped = KEYS[i], ARGV[i], 0
+	while minTime > 0 do
+		local oldest = redis.call("LINDEX", key, 0)
+		if not oldest or tonumber(string.sub(oldest, 1, string.find(oldest, ":", 1, true) - 1)) >= minTime then
+			break
+		end
+		redis.call("LPOP", key)
+		dropped = dropped + #oldest
+	end
+	if redis.call("EXISTS", key) == 0 then
+		redis.call("HDEL", KEYS[1], channel)
+	elseif dropped > 0 then
+		redis.call("HINCRBY", KEYS[1], channel, -dropped)
+	end
+end
+local total = 0
+for _, size in ipairs(redis.call("HVALS", KEYS[1])) do
+	total = total + tonumber(size)
+end
+if total > 0 then
+	redis.call("SET", KEYS[2], total, "EX", ARGV[2])
+else
+	redis.call("DEL", KEYS[2])
+end
+return total
+`)
+
+func (c *RedisFrameCache) updateHistory(ctx context.Context, orgID int64, channel string, reset bool, frame json.RawMessage) error {
+	if !c.historyConfig.enabled() {
+		return nil
+	}
+	now := c.now()
+	resetArg := "0"
+	if reset {
+		resetArg = "1"
+	}
+	return updateHistoryScript.Run(ctx, c.redisClient,
+		[]string{getHistoryKey(orgID, channel), getHistorySizesKey(orgID), getHistoryBytesKey(orgID)},
+		channel,
+		strconv.FormatInt(now.UnixMilli(), 10)+":"+string(frame),
+		resetArg,
+		c.historyConfig.Size,
+		c.historyMinTime(now),
+		c.historyConfig.MaxBytesPerOrg,
+		int64(frameCacheTTL.Seconds()),
+	).Err()
+}
+
+// historyMinTime returns the time of the oldest entry that can be kept in the history in milliseconds, or 0
+// if there is no limit.
+func (c *RedisFrameCache) historyMinTime(now time.Time) int64 {
+	if t := c.historyConfig.minTime(now); !t.IsZero() {
+		return t.UnixMilli()
+	}
+	return 0
+}
+
+// Run periodically drops the expired history of the channels that are no longer updated, so that it
+// does not count toward the size limit of the org. Updating a channel only trims its own history.
+func (c *RedisFrameCache) Run(ctx context.Context) error {
+	if !c.historyConfig.enabled() {
+		return nil
+	}
+	ticker := time.NewTicker(historyCleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.cleanupHistory(ctx); err != nil {
+				logger.Warn("Failed to clean up managed stream history", "error", err)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// cleanupHistory drops the expired history of the channels of the orgs that this instance updates.
+func (c *RedisFrameCache) cleanupHistory(ctx context.Context) error {
+	c.mu.RLock()
+	orgIDs := make([]int64, 0, len(c.frames))
+	for orgID := range c.frames {
+		orgIDs = append(orgIDs, orgID)
+	}
+	c.mu.RUnlock()
+
+	minTime := c.historyMinTime(c.now())
+	for _, orgID := range orgIDs {
+		channels, err := c.redisClient.HKeys(ctx, getHistorySizesKey(orgID)).Result()
+		if err != nil {
+			return err
+		}
+		keys := make([]string, 0, len(channels)+2)
+		keys = append(keys, getHistorySizesKey(orgID), getHistoryBytesKey(orgID))
+		args := make([]any, 0, len(channels)+2)
+		args = append(args, minTime, int64(frameCacheTTL.Seconds()))
+		for _, channel := range channels {
+			keys = append(keys, getHistoryKey(orgID, channel))
+			args = append(args, channel)
 		}
-		return result["schema"] != stringSchema, nil
+		if err := cleanupHistoryScript.Run(ctx, c.redisClient, keys, args...).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseHistoryEntry parses a history entry, which is the time it was added in milliseconds and the frame
+// separated by a colon.
+func parseHistoryEntry(entry string) (time.Time, json.RawMessage, bool) {
+	ms, frame, ok := strings.Cut(entry, ":")
+	if !ok {
+		return time.Time{}, nil, false
 	}
-	return true, nil
+	t, err := strconv.ParseInt(ms, 10, 64)
+	if err != nil {
+		return time.Time{}, nil, false
+	}
+	return time.UnixMilli(t), json.RawMessage(frame), true
 }
 
 func getCacheKey(channelID string) string {
 	return "gf_live.managed_stream." + channelID
 }
+
+// getHistoryKey returns the key of the history list of a channel. The keys of the history of an org
+// share the org ID as hash tag, so that the scripts that update several of them work with Redis Cluster.
+func getHistoryKey(orgID int64, channel string) string {
+	return "gf_live.managed_stream_history.{" + strconv.FormatInt(orgID, 10) + "}/" + channel
+}
+
+func getHistorySizesKey(orgID int64) string {
+	return "gf_live.managed_stream_history_sizes.{" + strconv.FormatInt(orgID, 10) + "}"
+}
+
+func getHistoryBytesKey(orgID int64) string {
+	return "gf_live.managed_stream_history_bytes.{" + strconv.FormatInt(orgID, 10) + "}"
+}
@@ -1,10 +1,15 @@
 package managedstream
 
 import (
+	"context"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/go-redis/redis/v8"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
 )
 
@@ -30,7 +35,58 @@ func TestIntegrationRedisCacheStorage(t *testing.T) {
 		Addr: addr,
 		DB:   db,
 	})
-	c := NewRedisFrameCache(redisClient)
+	c := NewRedisFrameCache(redisClient, HistoryConfig{})
 	require.NotNil(t, c)
 	testFrameCache(t, c)
 }
+
+func TestRedisFrameCacheHistory(t *testing.T) {
+	testFrameCacheHistory(t, func(cfg HistoryConfig) FrameCache {
+		redisServer := miniredis.RunT(t)
+		return NewRedisFrameCache(redis.NewClient(&redis.Options{Addr: redisServer.Addr()}), cfg)
+	}, func(c FrameCache, now time.Time) {
+		c.(*RedisFrameCache).now = func() time.Time { return now }
+	}, func(c FrameCache) {
+		require.NoError(t, c.(*RedisFrameCache).cleanupHistory(context.Background()))
+	})
+
+	t.Run("should not count the histories that expired in redis toward the org limit", func(t *testing.T) {
+		frame := historyFrame(t, 1)
+		frameSize := int64(len(frame.Bytes(data.IncludeAll)))
+		redisServer := miniredis.RunT(t)
+		c := NewRedisFrameCache(redis.NewClient(&redis.Options{Addr: redisServer.Addr()}), HistoryConfig{Size: 10, MaxBytesPerOrg: 4*frameSize + 100})
+		update := func(channel string, value float64) {
+			t.Helper()
+			_, err := c.Update(context.Background(), 1, channel, historyFrame(t, value))
+			require.NoError(t, err)
+		}
+		for i := 1; i <= 3; i++ {
+			update("idle", float64(i))
+		}
+		// The sizes of the org are kept by the updates of the other channel after the idle history expires.
+		redisServer.FastForward(frameCacheTTL - time.Hour)
+		update("b", 4)
+		redisServer.FastForward(2 * time.Hour)
+		require.NoError(t, c.cleanupHistory(context.Background()))
+		for i := 5; i <= 7; i++ {
+			update("b", float64(i))
+		}
+		require.Empty(t, historyValues(t, c, 1, "idle"))
+		require.Equal(t, []float64{4, 5, 6, 7}, historyValues(t, c, 1, "b"))
+	})
+
+	t.Run("should only use keys of the org hash slot", func(t *testing.T) {
+		redisServer := miniredis.RunT(t)
+		c := NewRedisFrameCache(redis.NewClient(&redis.Options{Addr: redisServer.Addr()}), HistoryConfig{Size: 10, Duration: time.Minute})
+		for _, channel := range []string{"a", "b"} {
+			_, err := c.Update(context.Background(), 1, channel, historyFrame(t, 1))
+			require.NoError(t, err)
+		}
+		require.NoError(t, c.cleanupHistory(context.Background()))
+		for _, key := range redisServer.Keys() {
+			if strings.HasPrefix(key, "gf_live.managed_stream_history") {
+				require.Contains(t, key, "{1}")
+			}
+		}
+	})
+}
@@ -239,8 +239,22 @@ func (s *NamespaceStream) GetHandlerForPath(_ string) (model.ChannelHandler, err
 	return s, nil
 }
 
+// OnSubscribe sends the recent frames of the channel to the new subscriber as initial data. If the history of
+// the channel is enabled, its frames are merged into one, otherwise only the latest frame is sent.
 func (s *NamespaceStream) OnSubscribe(ctx context.Context, u identity.Requester, e model.SubscribeEvent) (model.SubscribeReply, backend.SubscribeStreamStatus, error) {
 	reply := model.SubscribeReply{}
+	history, err := s.frameCache.GetHistory(ctx, u.GetOrgID(), e.Channel)
+	if err != nil {
+		return reply, 0, err
+	}
+	if len(history) > 0 {
+		frameJSON, err := mergeFrames(history)
+		if err != nil {
+			return reply, 0, err
+		}
+		reply.Data = frameJSON
+		return reply, backend.SubscribeStreamStatusOK, nil
+	}
 	frameJSON, ok, err := s.frameCache.GetFrame(ctx, u.GetOrgID(), e.Channel)
 	if err != nil {
 		return reply, 0, err
@@ -2,11 +2,16 @@ package managedstream
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/live/model"
+	"github.com/grafana/grafana/pkg/services/user"
 )
 
 type testPublisher struct {
@@ -19,13 +24,13 @@ func (p *testPublisher) publish(_ int64, _ string, _ []byte) error {
 
 func TestNewManagedStream(t *testing.T) {
 	publisher := &testPublisher{t: t}
-	c := NewNamespaceStream(1, "stream", "a", publisher.publish, nil, NewMemoryFrameCache())
+	c := NewNamespaceStream(1, "stream", "a", publisher.publish, nil, NewMemoryFrameCache(HistoryConfig{}))
 	require.NotNil(t, c)
 }
 
 func TestManagedStreamMinuteRate(t *testing.T) {
 	publisher := &testPublisher{t: t}
-	c := NewNamespaceStream(1, "stream", "a", publisher.publish, nil, NewMemoryFrameCache())
+	c := NewNamespaceStream(1, "stream", "a", publisher.publish, nil, NewMemoryFrameCache(HistoryConfig{}))
 	require.NotNil(t, c)
 
 	c.incRate("test1", time.Now().Unix())
@@ -47,7 +52,7 @@ func TestManagedStreamMinuteRate(t *testing.T) {
 
 func TestGetManagedStreams(t *testing.T) {
 	publisher := &testPublisher{t: t}
-	frameCache := NewMemoryFrameCache()
+	frameCache := NewMemoryFrameCache(HistoryConfig{})
 	runner := NewRunner(publisher.publish, nil, frameCache)
 	s1, err := runner.GetOrCreateStream(1, "stream", "test1")
 	require.NoError(t, err)
@@ -83,3 +88,41 @@ func TestGetManagedStreams(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, managedChannels, 7) // Not affected by other org.
 }
+
+func TestNamespaceStreamOnSubscribe(t *testing.T) {
+	publisher := &testPublisher{t: t}
+	push := func(t *testing.T, s *NamespaceStream, values ...float64) {
+		for _, v := range values {
+			frame := data.NewFrame("cpu",
+				data.NewField("time", nil, []time.Time{time.Unix(int64(v), 0)}),
+				data.NewField("value", nil, []float64{v}),
+			)
+			require.NoError(t, s.Push(context.Background(), "cpu1", frame))
+		}
+	}
+	subscribe := func(t *testing.T, s *NamespaceStream) *data.Frame {
+		reply, status, err := s.OnSubscribe(context.Background(), &user.SignedInUser{OrgID: 1}, model.SubscribeEvent{Channel: "stream/test/cpu1", Path: "cpu1"})
+		require.NoError(t, err)
+		require.Equal(t, backend.SubscribeStreamStatusOK, status)
+		var frame data.Frame
+		require.NoError(t, json.Unmarshal(reply.Data, &frame))
+		return &frame
+	}
+
+	t.Run("should replay history to new subscribers", func(t *testing.T) {
+		s := NewNamespaceStream(1, "stream", "test", publisher.publish, nil, NewMemoryFrameCache(HistoryConfig{Size: 3}))
+		push(t, s, 1, 2, 3, 4)
+		frame := subscribe(t, s)
+		require.Equal(t, 3, frame.Rows())
+		require.Equal(t, []any{time.Unix(2, 0).UTC(), 2.0}, frame.RowCopy(0))
+		require.Equal(t, []any{time.Unix(4, 0).UTC(), 4.0}, frame.RowCopy(2))
+	})
+
+	t.Run("should send the latest frame without history", func(t *testing.T) {
+		s := NewNamespaceStream(1, "stream", "test", publisher.publish, nil, NewMemoryFrameCache(HistoryConfig{}))
+		push(t, s, 1, 2)
+		frame := subscribe(t, s)
+		require.Equal(t, 1, frame.Rows())
+		require.Equal(t, 2.0, frame.Fields[1].At(0))
+	})
+}
@@ -32,6 +32,7 @@ type ChannelRule struct {
 	OrgId    int64               `json:"-"`
 	Pattern  string              `json:"pattern"`
 	Settings ChannelRuleSettings `json:"settings"`
+	Version  int64               `json:"version,omitempty"`
 }
 
 type ConverterConfig struct {
@@ -50,11 +51,51 @@ type KeepFieldsFrameProcessorConfig struct {
 	FieldNames []string `json:"fieldNames"`
 }
 
+type RenameFieldsFrameProcessorConfig struct {
+	// Renames maps the names of the fields to rename to their new names.
+	Renames map[string]string `json:"renames"`
+}
+
+type DeriveFieldFrameProcessorConfig struct {
+	// FieldName is the name of the computed field. An existing field with this name is replaced.
+	FieldName string `json:"fieldName"`
+	// Expression is a math expression over the values of the other fields of the same row,
+	// which are referenced by name, for example "$power / ($voltage * 1000)".
+	Expression string `json:"expression"`
+}
+
+type ConvertUnitsFrameProcessorConfig struct {
+	FieldName string `json:"fieldName"`
+	// From and To are unit IDs, for example "fahrenheit" and "celsius".
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type DownsampleFrameProcessorConfig struct {
+	// TimeFieldName is the name of the time field, the first time field by default.
+	TimeFieldName        string               `json:"timeFieldName,omitempty"`
+	IntervalMilliseconds int64                `json:"intervalMilliseconds"`
+	Aggregator           DownsampleAggregator `json:"aggregator"`
+}
+
+type DedupeFrameProcessorConfig struct {
+	Mode DedupeMode `json:"mode"`
+	// FieldNames are the fields compared in DedupeModeUnchanged, all fields except the time field by default.
+	FieldNames []string `json:"fieldNames,omitempty"`
+	// TimeFieldName is the name of the time field, the first time field by default.
+	TimeFieldName string `json:"timeFieldName,omitempty"`
+}
+
 type FrameProcessorConfig struct {
-	Type                      string                          `json:"type" ts_type:"Omit<keyof FrameProcessorConfig, 'type'>"`
-	DropFieldsProcessorConfig *DropFieldsFrameProcessorConfig `json:"dropFields,omitempty"`
-	KeepFieldsProcessorConfig *KeepFieldsFrameProcessorConfig `json:"keepFields,omitempty"`
-	MultipleProcessorConfig   *MultipleFrameProcessorConfig   `json:"multiple,omitempty"`
+	Type                        string                            `json:"type" ts_type:"Omit<keyof FrameProcessorConfig, 'type'>"`
+	DropFieldsProcessorConfig   *DropFieldsFrameProcessorConfig   `json:"dropFields,omitempty"`
+	KeepFieldsProcessorConfig   *KeepFieldsFrameProcessorConfig   `json:"keepFields,omitempty"`
+	MultipleProcessorConfig     *MultipleFrameProcessorConfig     `json:"multiple,omitempty"`
+	RenameFieldsProcessorConfig *RenameFieldsFrameProcessorConfig `json:"renameFields,omitempty"`
+	DeriveFieldProcessorConfig  *DeriveFieldFrameProcessorConfig  `json:"deriveField,omitempty"`
+	ConvertUnitsProcessorConfig *ConvertUnitsFrameProcessorConfig `json:"convertUnits,omitempty"`
+	DownsampleProcessorConfig   *DownsampleFrameProcessorConfig   `json:"downsample,omitempty"`
+	DedupeProcessorConfig       *DedupeFrameProcessorConfig       `json:"dedupe,omitempty"`
 }
 
 type MultipleFrameProcessorConfig struct {
@@ -79,6 +120,38 @@ type LokiOutputConfig struct {
 	UID string `json:"uid"`
 }
 
+// BatchConfig configures how outputters that write to external systems batch data.
+// Data that doesn't fit in the queue is dropped.
+type BatchConfig struct {
+	// BatchSize is the maximum number of items written at once, 100 by default.
+	BatchSize int `json:"batchSize,omitempty"`
+	// FlushIntervalMilliseconds is the maximum time items wait for a batch to fill, 1000 by default.
+	FlushIntervalMilliseconds int64 `json:"flushIntervalMilliseconds,omitempty"`
+	// QueueSize is the maximum number of items waiting to be written, 10000 by default.
+	QueueSize int `json:"queueSize,omitempty"`
+	// MaxRetries is the number of times a failed batch is retried before it's dropped, 3 by default.
+	MaxRetries int `json:"maxRetries,omitempty"`
+}
+
+type WebhookOutputConfig struct {
+	// UID of the write config with the webhook endpoint.
+	UID string `json:"uid"`
+	BatchConfig
+}
+
+type SQLOutputConfig struct {
+	// DatasourceUID is the UID of a PostgreSQL or MySQL data source.
+	DatasourceUID string `json:"datasourceUid"`
+	// Table to insert rows into, optionally with a schema, for example "telemetry.readings".
+	Table string `json:"table"`
+	// Columns maps field names to column names. Only the mapped fields are inserted when set,
+	// all fields are inserted into columns with the same names otherwise.
+	Columns map[string]string `json:"columns,omitempty"`
+	// ChannelColumn is an optional column to insert the channel of the frames into.
+	ChannelColumn string `json:"channelColumn,omitempty"`
+	BatchConfig
+}
+
 type MultipleSubscriberConfig struct {
 	Subscribers []SubscriberConfig `json:"subscribers"`
 }
@@ -109,6 +182,8 @@ type FrameOutputterConfig struct {
 	RemoteWriteOutputConfig *RemoteWriteOutputConfig   `json:"remoteWrite,omitempty"`
 	LokiOutputConfig        *LokiOutputConfig          `json:"loki,omitempty"`
 	ChangeLogOutputConfig   *ChangeLogOutputConfig     `json:"changeLog,omitempty"`
+	WebhookOutputConfig     *WebhookOutputConfig       `json:"webhook,omitempty"`
+	SQLOutputConfig         *SQLOutputConfig           `json:"sql,omitempty"`
 }
 
 type MultipleFrameConditionCheckerConfig struct {
@@ -0,0 +1,261 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBatchSize     = 100
+	defaultFlushInterval = time.Second
+	defaultQueueSize     = 10000
+	defaultMaxRetries    = 3
+	batchRetryBackoff    = time.Second
+	maxBatchRetryBackoff = 30 * time.Second
+	batchWriteTimeout    = 10 * time.Second
+)
+
+// permanentError wraps the errors of batches that would fail again if retried.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e permanentError) Unwrap() error {
+	return e.err
+}
+
+// batchWriter writes items to an external system in batches from a background
+// goroutine. Items are queued in a bounded queue and dropped when it's full, so
+// a slow or unavailable system never blocks the pipeline. Failed batches are
+// retried with exponential backoff, then dropped.
+type batchWriter[T any] struct {
+	name          string
+	batchSize     int
+	flushInterval time.Duration
+	maxRetries    int
+	retryBackoff  time.Duration
+	write         func(ctx context.Context, batch []T) error
+	onStop        func()
+
+	queue     chan T
+	startOnce sync.Once
+	stopOnce  sync.Once
+	stopCh    chan struct{}
+	doneCh    chan struct{}
+}
+
+func newBatchWriter[T any](name string, config BatchConfig, write func(ctx context.Context, batch []T) error, onStop func()) *batchWriter[T] {
+	w := &batchWriter[T]{
+		name:          name,
+		batchSize:     config.BatchSize,
+		flushInterval: time.Duration(config.FlushIntervalMilliseconds) * time.Millisecond,
+		maxRetries:    config.MaxRetries,
+		retryBackoff:  batchRetryBackoff,
+		write:         write,
+		onStop:        onStop,
+		stopCh:        make(chan struct{}),
+		doneCh:        make(chan struct{}),
+	}
+	if w.batchSize <= 0 {
+		w.batchSize = defaultBatchSize
+	}
+	if w.flushInterval <= 0 {
+		w.flushInterval = defaultFlushInterval
+	}
+	if w.maxRetries <= 0 {
+		w.maxRetries = defaultMaxRetries
+	}
+	queueSize := config.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+	w.queue = make(chan T, queueSize)
+	return w
+}
+
+// start runs the background goroutine, unless it's already running. Writers are
+// started on first use, so rules that are only built never start them.
+func (w *batchWriter[T]) start() {
+	w.startOnce.Do(func() {
+		go w.run()
+	})
+}
+
+// enqueue adds items to the queue. It returns false if some items were dropped because the queue is full.
+func (w *batchWriter[T]) enqueue(items ...T) bool {
+	w.start()
+	for i, item := range items {
+		select {
+		case w.queue <- item:
+		default:
+			logger.Warn("Output queue is full, dropping data", "output", w.name, "dropped", len(items)-i)
+			return false
+		}
+	}
+	return true
+}
+
+// stop writes the queued items and stops the background goroutine.
+func (w *batchWriter[T]) stop() {
+	w.stopOnce.Do(func() {
+		close(w.stopCh)
+	})
+	w.start()
+	<-w.doneCh
+}
+
+func (w *batchWriter[T]) run() {
+	defer close(w.doneCh)
+	if w.onStop != nil {
+		defer w.onStop()
+	}
+
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]T, 0, w.batchSize)
+	for {
+		select {
+		case item := <-w.queue:
+			batch = append(batch, item)
+			if len(batch) >= w.batchSize {
+				w.writeWithRetries(batch)
+				batch = make([]T, 0, w.batchSize)
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				w.writeWithRetries(batch)
+				batch = make([]T, 0, w.batchSize)
+			}
+		case <-w.stopCh:
+			w.drain(batch)
+			return
+		}
+	}
+}
+
+// drain writes the pending batch and the queued items once, without retries.
+func (w *batchWriter[T]) drain(batch []T) {
+	for {
+		select {
+		case item := <-w.queue:
+			batch = append(batch, item)
+		default:
+			for len(batch) > 0 {
+				n := min(len(batch), w.batchSize)
+				_ = w.writeOnce(batch[:n])
+				batch = batch[n:]
+			}
+			return
+		}
+	}
+}
+
+func (w *batchWriter[T]) writeWithRetries(batch []T) {
+	for attempt := 0; ; attempt++ {
+		err := w.writeOnce(batch)
+		if err == nil {
+			return
+		}
+		var permanent permanentError
+		if errors.As(err, &permanent) || attempt >= w.maxRetries {
+			logger.Error("Dropping batch", "output", w.name, "size", len(batch), "attempts", attempt+1, "error", err)
+			return
+		}
+		backoff := min(w.retryBackoff<<min(attempt, 10), maxBatchRetryBackoff)
+		select {
+		case <-time.After(backoff):
+		case <-w.stopCh:
+			logger.Error("Dropping batch", "output", w.name, "size", len(batch), "attempts", attempt+1, "error", err)
+			return
+		}
+	}
+}
+
+func (w *batchWriter[T]) writeOnce(batch []T) error {
+	ctx, cancel := context.WithTimeout(context.Background(), batchWriteTimeout)
+	defer cancel()
+	err := w.write(ctx, batch)
+	if err != nil {
+		logger.Warn("Error writing batch", "output", w.name, "size", len(batch), "error", err)
+	}
+	return err
+}
+
+// stoppableFrameOutput is a FrameOutputter with a background batch writer.
+type stoppableFrameOutput interface {
+	FrameOutputter
+	stop()
+}
+
+// frameOutputCache keeps the outputters with batch writers between rule builds,
+// so queued data survives the periodic rebuild of channel rules. An outputter is
+// replaced and stopped when the version of what it writes to changes, and stopped
+// when the rules of its organization are built without it.
+type frameOutputCache struct {
+	mu      sync.Mutex
+	outputs map[string]cachedFrameOutput
+}
+
+type cachedFrameOutput struct {
+	orgID   int64
+	version string
+	output  stoppableFrameOutput
+}
+
+// frameOutputBuild records the outputters requested while building the rules of an organization.
+type frameOutputBuild struct {
+	orgID int64
+	used  map[string]struct{}
+}
+
+func newFrameOutputBuild(orgID int64) *frameOutputBuild {
+	return &frameOutputBuild{orgID: orgID, used: map[string]struct{}{}}
+}
+
+// getOrCreate returns the outputter cached for key and version, or creates it with create.
+func (c *frameOutputCache) getOrCreate(build *frameOutputBuild, key string, version string, create func() (stoppableFrameOutput, error)) (FrameOutputter, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	build.used[key] = struct{}{}
+	if cached, ok := c.outputs[key]; ok {
+		if cached.version == version {
+			return cached.output, nil
+		}
+		go cached.output.stop()
+		delete(c.outputs, key)
+	}
+	output, err := create()
+	if err != nil {
+		return nil, err
+	}
+	if c.outputs == nil {
+		c.outputs = map[string]cachedFrameOutput{}
+	}
+	c.outputs[key] = cachedFrameOutput{orgID: build.orgID, version: version, output: output}
+	return output, nil
+}
+
+// stopUnused stops the outputters of the organization that were not requested in build.
+// It must only be called when the rules were built successfully, as the rules built
+// before keep using the outputters until they are replaced.
+func (c *frameOutputCache) stopUnused(build *frameOutputBuild) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, cached := range c.outputs {
+		if cached.orgID != build.orgID {
+			continue
+		}
+		if _, ok := build.used[key]; ok {
+			continue
+		}
+		go cached.output.stop()
+		delete(c.outputs, key)
+	}
+}
@@ -0,0 +1,219 @@
+package pipeline
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	_ "github.com/lib/pq"
+
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/datasources/sqlconn"
+)
+
+var sqlIdentifierRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// maxSQLParameters is the maximum number of parameters of a statement in PostgreSQL and MySQL.
+const maxSQLParameters = 65535
+
+// SQLFrameOutput can insert the rows of frames into a table of a PostgreSQL or MySQL
+// data source. Rows are inserted in batches, each batch in a transaction. Nullable
+// fields are inserted as NULL when they have no value.
+type SQLFrameOutput struct {
+	config     SQLOutputConfig
+	driverName string
+	db         *sql.DB
+	table      string
+	writer     *batchWriter[sqlRow]
+}
+
+// sqlRow is a row to insert, with the quoted names of its columns.
+type sqlRow struct {
+	columns []string
+	values  []any
+}
+
+// NewSQLFrameOutput returns an outputter that inserts rows with db, opened with the
+// postgres or mysql driver. The outputter closes db when it's stopped.
+func NewSQLFrameOutput(driverName string, db *sql.DB, config SQLOutputConfig) (*SQLFrameOutput, error) {
+	if driverName != sqlconn.DriverPostgres && driverName != sqlconn.DriverMySQL {
+		return nil, fmt.Errorf("unsupported SQL driver: %s", driverName)
+	}
+	out := &SQLFrameOutput{
+		config:     config,
+		driverName: driverName,
+		db:         db,
+	}
+	parts := strings.Split(config.Table, ".")
+	if len(parts) > 2 {
+		return nil, fmt.Errorf("invalid table name: %q", config.Table)
+	}
+	for i, part := range parts {
+		if !sqlIdentifierRegex.MatchString(part) {
+			return nil, fmt.Errorf("invalid table name: %q", config.Table)
+		}
+		parts[i] = out.quote(part)
+	}
+	out.table = strings.Join(parts, ".")
+	for _, column := range config.Columns {
+		if !sqlIdentifierRegex.MatchString(column) {
+			return nil, fmt.Errorf("invalid column name: %q", column)
+		}
+	}
+	if config.ChannelColumn != "" && !sqlIdentifierRegex.MatchString(config.ChannelColumn) {
+		return nil, fmt.Errorf("invalid column name: %q", config.ChannelColumn)
+	}
+	out.writer = newBatchWriter("sql", config.BatchConfig, out.insert, func() {
+		_ = db.Close()
+	})
+	return out, nil
+}
+
+const FrameOutputTypeSQL = "sql"
+
+func (out *SQLFrameOutput) Type() string {
+	return FrameOutputTypeSQL
+}
+
+func (out *SQLFrameOutput) OutputFrame(_ context.Context, vars Vars, frame *data.Frame) ([]*ChannelFrame, error) {
+	var columns []string
+	var fieldIndexes []int
+	for i, f := range frame.Fields {
+		column := f.Name
+		if out.config.Columns != nil {
+			var ok bool
+			if column, ok = out.config.Columns[f.Name]; !ok {
+				continue
+			}
+		} else if !sqlIdentifierRegex.MatchString(column) {
+			return nil, fmt.Errorf("field name %q is not a valid column name, map it to a column", f.Name)
+		}
+		columns = append(columns, out.quote(column))
+		fieldIndexes = append(fieldIndexes, i)
+	}
+	if len(columns) == 0 {
+		return nil, nil
+	}
+	if out.config.ChannelColumn != "" {
+		columns = append(columns, out.quote(out.config.ChannelColumn))
+	}
+
+	rows := make([]sqlRow, 0, frame.Rows())
+	for i := 0; i < frame.Rows(); i++ {
+		values := make([]any, 0, len(columns))
+		for _, fieldIndex := range fieldIndexes {
+			values = append(values, frame.Fields[fieldIndex].CopyAt(i))
+		}
+		if out.config.ChannelColumn != "" {
+			values = append(values, vars.Channel)
+		}
+		rows = append(rows, sqlRow{columns: columns, values: values})
+	}
+	out.writer.enqueue(rows...)
+	return nil, nil
+}
+
+// stop inserts the queued rows, stops the output and closes its database.
+func (out *SQLFrameOutput) stop() {
+	out.writer.stop()
+}
+
+func (out *SQLFrameOutput) quote(identifier string) string {
+	if out.driverName == sqlconn.DriverMySQL {
+		return "`" + identifier + "`"
+	}
+	return `"` + identifier + `"`
+}
+
+func (out *SQLFrameOutput) insert(ctx context.Context, rows []sqlRow) error {
+	tx, err := out.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	// Frames of a channel usually have the same fields, rows with the same
+	// columns are inserted with a single statement.
+	for start := 0; start < len(rows); {
+		maxRows := maxSQLParameters / len(rows[start].values)
+		end := start + 1
+		for end < len(rows) && end-start < maxRows && sameColumns(rows[start].columns, rows[end].columns) {
+			end++
+		}
+		query, args := out.insertQuery(rows[start:end])
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("error inserting rows into %s: %w", out.config.Table, err)
+		}
+		start = end
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	logger.Debug("Successfully inserted rows", "table", out.config.Table, "numRows", len(rows))
+	return nil
+}
+
+// insertQuery returns an INSERT statement for rows with the same columns.
+func (out *SQLFrameOutput) insertQuery(rows []sqlRow) (string, []any) {
+	columns := rows[0].columns
+	var sb strings.Builder
+	sb.WriteString("INSERT INTO ")
+	sb.WriteString(out.table)
+	sb.WriteString(" (")
+	sb.WriteString(strings.Join(columns, ", "))
+	sb.WriteString(") VALUES ")
+	args := make([]any, 0, len(rows)*len(columns))
+	for i, row := range rows {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("(")
+		for j, value := range row.values {
+			if j > 0 {
+				sb.WriteString(", ")
+			}
+			args = append(args, value)
+			if out.driverName == sqlconn.DriverMySQL {
+				sb.WriteString("?")
+			} else {
+				sb.WriteString("$" + strconv.Itoa(len(args)))
+			}
+		}
+		sb.WriteString(")")
+	}
+	return sb.String(), args
+}
+
+func sameColumns(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// openSQLDataSource opens a connection pool to the database of a PostgreSQL or MySQL data
+// source and returns the name of its driver.
+func openSQLDataSource(ds *datasources.DataSource, decryptedSecureJSONData map[string]string) (string, *sql.DB, error) {
+	driverName, cnnstr, err := sqlconn.ConnectionString(ds, decryptedSecureJSONData)
+	if err != nil {
+		return "", nil, err
+	}
+	db, err := sql.Open(driverName, cnnstr)
+	if err != nil {
+		return "", nil, err
+	}
+	db.SetMaxOpenConns(2)
+	db.SetMaxIdleConns(2)
+	db.SetConnMaxLifetime(time.Hour)
+	return driverName, db, nil
+}
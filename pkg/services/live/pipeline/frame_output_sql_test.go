@@ -0,0 +1,129 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestNewSQLFrameOutput(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config SQLOutputConfig
+	}{
+		{name: "invalid table", config: SQLOutputConfig{Table: "telemetry; DROP TABLE users"}},
+		{name: "too many table parts", config: SQLOutputConfig{Table: "a.b.c"}},
+		{name: "invalid column", config: SQLOutputConfig{Table: "telemetry", Columns: map[string]string{"value": "value\""}}},
+		{name: "invalid channel column", config: SQLOutputConfig{Table: "telemetry", ChannelColumn: "channel name"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewSQLFrameOutput("postgres", nil, tc.config)
+			require.Error(t, err)
+		})
+	}
+	_, err := NewSQLFrameOutput("sqlite3", nil, SQLOutputConfig{Table: "telemetry"})
+	require.Error(t, err)
+}
+
+func TestSQLFrameOutput(t *testing.T) {
+	frame := data.NewFrame("test",
+		data.NewField("time", nil, []time.Time{time.Unix(1, 0), time.Unix(2, 0)}),
+		data.NewField("temperature", nil, []*float64{util.Pointer(21.5), nil}),
+		data.NewField("state", nil, []string{"on", "off"}),
+	)
+
+	t.Run("should insert rows in batches", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		require.NoError(t, err)
+		out, err := NewSQLFrameOutput("postgres", db, SQLOutputConfig{
+			Table:         "iot.telemetry",
+			Columns:       map[string]string{"time": "ts", "temperature": "temperature"},
+			ChannelColumn: "channel",
+			BatchConfig:   BatchConfig{BatchSize: 10},
+		})
+		require.NoError(t, err)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO "iot"."telemetry" ("ts", "temperature", "channel") VALUES ($1, $2, $3), ($4, $5, $6), ($7, $8, $9)`).
+			WithArgs(time.Unix(1, 0), 21.5, "stream/test/a", time.Unix(2, 0), nil, "stream/test/a", time.Unix(1, 0), 21.5, "stream/test/b").
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectCommit()
+		mock.ExpectClose()
+
+		_, err = out.OutputFrame(context.Background(), Vars{Channel: "stream/test/a"}, frame)
+		require.NoError(t, err)
+		_, err = out.OutputFrame(context.Background(), Vars{Channel: "stream/test/b"}, data.NewFrame("test",
+			data.NewField("time", nil, []time.Time{time.Unix(1, 0)}),
+			data.NewField("temperature", nil, []*float64{util.Pointer(21.5)}),
+		))
+		require.NoError(t, err)
+		out.stop()
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should insert all fields with MySQL", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		require.NoError(t, err)
+		out, err := NewSQLFrameOutput("mysql", db, SQLOutputConfig{Table: "telemetry"})
+		require.NoError(t, err)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO `telemetry` (`time`, `temperature`, `state`) VALUES (?, ?, ?), (?, ?, ?)").
+			WithArgs(time.Unix(1, 0), 21.5, "on", time.Unix(2, 0), nil, "off").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+		mock.ExpectClose()
+
+		_, err = out.OutputFrame(context.Background(), Vars{Channel: "stream/test/a"}, frame)
+		require.NoError(t, err)
+		out.stop()
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should reject fields that are not valid columns", func(t *testing.T) {
+		out, err := NewSQLFrameOutput("postgres", nil, SQLOutputConfig{Table: "telemetry"})
+		require.NoError(t, err)
+		_, err = out.OutputFrame(context.Background(), Vars{}, data.NewFrame("test", data.NewField("current A", nil, []float64{1})))
+		require.Error(t, err)
+	})
+}
+
+func TestOpenSQLDataSource(t *testing.T) {
+	t.Run("should open PostgreSQL data source", func(t *testing.T) {
+		ds := &datasources.DataSource{
+			Type:     datasources.DS_POSTGRES,
+			URL:      "localhost:5432",
+			User:     "grafana",
+			JsonData: simplejson.NewFromAny(map[string]any{"database": "iot", "sslmode": "disable"}),
+		}
+		driverName, db, err := openSQLDataSource(ds, map[string]string{"password": "secret"})
+		require.NoError(t, err)
+		require.Equal(t, "postgres", driverName)
+		require.NoError(t, db.Close())
+	})
+
+	t.Run("should open MySQL data source", func(t *testing.T) {
+		driverName, db, err := openSQLDataSource(&datasources.DataSource{
+			Type:     datasources.DS_MYSQL,
+			URL:      "localhost:3306",
+			Database: "iot",
+		}, nil)
+		require.NoError(t, err)
+		require.Equal(t, "mysql", driverName)
+		require.NoError(t, db.Close())
+	})
+
+	t.Run("should reject other data sources", func(t *testing.T) {
+		_, _, err := openSQLDataSource(&datasources.DataSource{Type: datasources.DS_MSSQL}, nil)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,113 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// WebhookFrameOutput can output frames encoded to JSON to an HTTP endpoint. Frames
+// are sent in batches as a JSON object with a list of frames and their channels:
+//
+//	{"frames": [{"channel": "stream/sensors/room1", "frame": {"schema": ..., "data": ...}}]}
+//
+// Batches are retried if the endpoint can't be reached or responds with a 5xx, 408
+// or 429 status code.
+type WebhookFrameOutput struct {
+	writer *webhookWriter
+}
+
+func NewWebhookFrameOutput(endpoint string, basicAuth *BasicAuth, config BatchConfig) *WebhookFrameOutput {
+	return &WebhookFrameOutput{
+		writer: newWebhookWriter(endpoint, basicAuth, config),
+	}
+}
+
+const FrameOutputTypeWebhook = "webhook"
+
+func (out *WebhookFrameOutput) Type() string {
+	return FrameOutputTypeWebhook
+}
+
+type WebhookEntry struct {
+	Channel string          `json:"channel"`
+	Frame   json.RawMessage `json:"frame"`
+}
+
+type WebhookPayload struct {
+	Frames []WebhookEntry `json:"frames"`
+}
+
+func (out *WebhookFrameOutput) OutputFrame(_ context.Context, vars Vars, frame *data.Frame) ([]*ChannelFrame, error) {
+	if out.writer.endpoint == "" {
+		logger.Debug("Skip sending to webhook: no url")
+		return nil, nil
+	}
+	frameJSON, err := data.FrameToJSON(frame, data.IncludeAll)
+	if err != nil {
+		return nil, err
+	}
+	out.writer.enqueue(WebhookEntry{Channel: vars.Channel, Frame: frameJSON})
+	return nil, nil
+}
+
+// stop sends the queued frames and stops the output.
+func (out *WebhookFrameOutput) stop() {
+	out.writer.stop()
+}
+
+type webhookWriter struct {
+	*batchWriter[WebhookEntry]
+	httpClient *http.Client
+	endpoint   string
+	basicAuth  *BasicAuth
+}
+
+func newWebhookWriter(endpoint string, basicAuth *BasicAuth, config BatchConfig) *webhookWriter {
+	w := &webhookWriter{
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		endpoint:   endpoint,
+		basicAuth:  basicAuth,
+	}
+	w.batchWriter = newBatchWriter("webhook", config, w.send, nil)
+	return w
+}
+
+func (w *webhookWriter) send(ctx context.Context, entries []WebhookEntry) error {
+	body, err := json.Marshal(WebhookPayload{Frames: entries})
+	if err != nil {
+		return permanentError{fmt.Errorf("error encoding webhook payload: %w", err)}
+	}
+	logger.Debug("Sending to webhook endpoint", "url", w.endpoint, "numFrames", len(entries), "bodyLength", len(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return permanentError{fmt.Errorf("error constructing webhook request: %w", err)}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.basicAuth != nil {
+		req.SetBasicAuth(w.basicAuth.User, w.basicAuth.Password)
+	}
+
+	started := time.Now()
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending to webhook: %w", err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err := fmt.Errorf("unexpected response code from webhook endpoint: %d", resp.StatusCode)
+		if resp.StatusCode < 500 && resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+			return permanentError{err}
+		}
+		return err
+	}
+	logger.Debug("Successfully sent to webhook endpoint", "url", w.endpoint, "elapsed", time.Since(started))
+	return nil
+}
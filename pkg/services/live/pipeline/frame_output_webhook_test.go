@@ -0,0 +1,222 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+type webhookTestServer struct {
+	mu       sync.Mutex
+	statuses []int
+	payloads []WebhookPayload
+	requests int
+}
+
+func (s *webhookTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	if user, password, ok := r.BasicAuth(); !ok || user != "admin" || password != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if len(s.statuses) > 0 {
+		status := s.statuses[0]
+		s.statuses = s.statuses[1:]
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+	}
+	var payload WebhookPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.payloads = append(s.payloads, payload)
+}
+
+func (s *webhookTestServer) numRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func (s *webhookTestServer) channels() [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var batches [][]string
+	for _, payload := range s.payloads {
+		var channels []string
+		for _, entry := range payload.Frames {
+			channels = append(channels, entry.Channel)
+		}
+		batches = append(batches, channels)
+	}
+	return batches
+}
+
+func newTestWebhookFrameOutput(t *testing.T, server *webhookTestServer, config BatchConfig) *WebhookFrameOutput {
+	t.Helper()
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+	out := NewWebhookFrameOutput(ts.URL, &BasicAuth{User: "admin", Password: "secret"}, config)
+	out.writer.retryBackoff = time.Millisecond
+	t.Cleanup(out.stop)
+	return out
+}
+
+func outputTestFrames(t *testing.T, out FrameOutputter, channels ...string) {
+	t.Helper()
+	for _, channel := range channels {
+		_, err := out.OutputFrame(context.Background(), Vars{OrgID: 1, Channel: channel}, data.NewFrame("test",
+			data.NewField("time", nil, []time.Time{time.Unix(1, 0)}),
+			data.NewField("value", nil, []float64{1}),
+		))
+		require.NoError(t, err)
+	}
+}
+
+func TestWebhookFrameOutput(t *testing.T) {
+	t.Run("should send frames in batches", func(t *testing.T) {
+		server := &webhookTestServer{}
+		out := newTestWebhookFrameOutput(t, server, BatchConfig{BatchSize: 2, FlushIntervalMilliseconds: 60000})
+		outputTestFrames(t, out, "stream/test/1", "stream/test/2", "stream/test/3")
+
+		require.Eventually(t, func() bool { return len(server.channels()) == 1 }, time.Second, 10*time.Millisecond)
+		out.stop()
+		require.Equal(t, [][]string{{"stream/test/1", "stream/test/2"}, {"stream/test/3"}}, server.channels())
+
+		frame := &data.Frame{}
+		require.NoError(t, json.Unmarshal(server.payloads[0].Frames[0].Frame, frame))
+		require.Equal(t, 1, frame.Rows())
+	})
+
+	t.Run("should flush after interval", func(t *testing.T) {
+		server := &webhookTestServer{}
+		out := newTestWebhookFrameOutput(t, server, BatchConfig{BatchSize: 100, FlushIntervalMilliseconds: 10})
+		outputTestFrames(t, out, "stream/test/1")
+		require.Eventually(t, func() bool { return len(server.channels()) == 1 }, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("should retry failed batches", func(t *testing.T) {
+		server := &webhookTestServer{statuses: []int{http.StatusInternalServerError, http.StatusTooManyRequests}}
+		out := newTestWebhookFrameOutput(t, server, BatchConfig{BatchSize: 1, MaxRetries: 2})
+		outputTestFrames(t, out, "stream/test/1")
+		require.Eventually(t, func() bool { return len(server.channels()) == 1 }, time.Second, 10*time.Millisecond)
+		require.Equal(t, 3, server.numRequests())
+	})
+
+	t.Run("should drop batches after max retries", func(t *testing.T) {
+		server := &webhookTestServer{statuses: []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway}}
+		out := newTestWebhookFrameOutput(t, server, BatchConfig{BatchSize: 1, MaxRetries: 1})
+		outputTestFrames(t, out, "stream/test/1", "stream/test/2")
+		require.Eventually(t, func() bool { return len(server.channels()) == 1 }, time.Second, 10*time.Millisecond)
+		require.Equal(t, [][]string{{"stream/test/2"}}, server.channels())
+		require.Equal(t, 4, server.numRequests())
+	})
+
+	t.Run("should not retry client errors", func(t *testing.T) {
+		server := &webhookTestServer{statuses: []int{http.StatusBadRequest}}
+		out := newTestWebhookFrameOutput(t, server, BatchConfig{BatchSize: 1})
+		outputTestFrames(t, out, "stream/test/1", "stream/test/2")
+		require.Eventually(t, func() bool { return len(server.channels()) == 1 }, time.Second, 10*time.Millisecond)
+		require.Equal(t, [][]string{{"stream/test/2"}}, server.channels())
+		require.Equal(t, 2, server.numRequests())
+	})
+}
+
+func TestBatchWriter_QueueFull(t *testing.T) {
+	block := make(chan struct{})
+	var mu sync.Mutex
+	var written []int
+	w := newBatchWriter("test", BatchConfig{BatchSize: 1, QueueSize: 2}, func(_ context.Context, batch []int) error {
+		<-block
+		mu.Lock()
+		defer mu.Unlock()
+		written = append(written, batch...)
+		return nil
+	}, nil)
+
+	require.True(t, w.enqueue(1))
+	// Wait for the first item to be written, so the queue is empty.
+	require.Eventually(t, func() bool { return len(w.queue) == 0 }, time.Second, time.Millisecond)
+	require.True(t, w.enqueue(2, 3))
+	require.False(t, w.enqueue(4))
+
+	close(block)
+	w.stop()
+	require.Equal(t, []int{1, 2, 3}, written)
+}
+
+type stopRecordingFrameOutput struct {
+	stopped chan struct{}
+}
+
+func newStopRecordingFrameOutput() *stopRecordingFrameOutput {
+	return &stopRecordingFrameOutput{stopped: make(chan struct{})}
+}
+
+func (out *stopRecordingFrameOutput) Type() string {
+	return "test"
+}
+
+func (out *stopRecordingFrameOutput) OutputFrame(_ context.Context, _ Vars, _ *data.Frame) ([]*ChannelFrame, error) {
+	return nil, nil
+}
+
+func (out *stopRecordingFrameOutput) stop() {
+	close(out.stopped)
+}
+
+func (out *stopRecordingFrameOutput) isStopped() bool {
+	select {
+	case <-out.stopped:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestFrameOutputCache(t *testing.T) {
+	cache := &frameOutputCache{}
+	getOrCreate := func(build *frameOutputBuild, key, version string, out *stopRecordingFrameOutput) FrameOutputter {
+		t.Helper()
+		got, err := cache.getOrCreate(build, key, version, func() (stoppableFrameOutput, error) {
+			return out, nil
+		})
+		require.NoError(t, err)
+		return got
+	}
+
+	kept := newStopRecordingFrameOutput()
+	replaced := newStopRecordingFrameOutput()
+	removed := newStopRecordingFrameOutput()
+	otherOrg := newStopRecordingFrameOutput()
+	build := newFrameOutputBuild(1)
+	getOrCreate(build, "kept", "1", kept)
+	getOrCreate(build, "replaced", "1", replaced)
+	getOrCreate(build, "removed", "1", removed)
+	getOrCreate(newFrameOutputBuild(2), "other", "1", otherOrg)
+	cache.stopUnused(build)
+
+	replacement := newStopRecordingFrameOutput()
+	build = newFrameOutputBuild(1)
+	require.Same(t, kept, getOrCreate(build, "kept", "1", newStopRecordingFrameOutput()))
+	require.Same(t, replacement, getOrCreate(build, "replaced", "2", replacement))
+	cache.stopUnused(build)
+
+	require.Eventually(t, func() bool { return replaced.isStopped() && removed.isStopped() }, time.Second, time.Millisecond)
+	require.False(t, kept.isStopped())
+	require.False(t, replacement.isStopped())
+	require.False(t, otherOrg.isStopped())
+	require.Len(t, cache.outputs, 3)
+}
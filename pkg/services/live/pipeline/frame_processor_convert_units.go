@@ -0,0 +1,114 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// unitScale converts values of a unit to the base unit of its dimension with
+// base = value * factor + offset.
+type unitScale struct {
+	dimension string
+	factor    float64
+	offset    float64
+}
+
+// convertibleUnits are the units ConvertUnitsFrameProcessor can convert between, by
+// unit ID of the field config.
+var convertibleUnits = map[string]unitScale{
+	"celsius":      {dimension: "temperature", factor: 1},
+	"fahrenheit":   {dimension: "temperature", factor: 5.0 / 9, offset: -32 * 5.0 / 9},
+	"kelvin":       {dimension: "temperature", factor: 1, offset: -273.15},
+	"lengthmm":     {dimension: "length", factor: 0.001},
+	"lengthm":      {dimension: "length", factor: 1},
+	"lengthkm":     {dimension: "length", factor: 1000},
+	"lengthft":     {dimension: "length", factor: 0.3048},
+	"lengthmi":     {dimension: "length", factor: 1609.344},
+	"massmg":       {dimension: "mass", factor: 0.000001},
+	"massg":        {dimension: "mass", factor: 0.001},
+	"masslb":       {dimension: "mass", factor: 0.45359237},
+	"masskg":       {dimension: "mass", factor: 1},
+	"masst":        {dimension: "mass", factor: 1000},
+	"pressurembar": {dimension: "pressure", factor: 100},
+	"pressurehpa":  {dimension: "pressure", factor: 100},
+	"pressurekpa":  {dimension: "pressure", factor: 1000},
+	"pressurebar":  {dimension: "pressure", factor: 100000},
+	"pressurehg":   {dimension: "pressure", factor: 3386.389},
+	"pressurepsi":  {dimension: "pressure", factor: 6894.757293168},
+	"velocityms":   {dimension: "velocity", factor: 1},
+	"velocitykmh":  {dimension: "velocity", factor: 1 / 3.6},
+	"velocitymph":  {dimension: "velocity", factor: 0.44704},
+	"velocityknot": {dimension: "velocity", factor: 1852.0 / 3600},
+	"ns":           {dimension: "time", factor: 0.000000001},
+	"µs":           {dimension: "time", factor: 0.000001},
+	"ms":           {dimension: "time", factor: 0.001},
+	"s":            {dimension: "time", factor: 1},
+	"m":            {dimension: "time", factor: 60},
+	"h":            {dimension: "time", factor: 3600},
+	"d":            {dimension: "time", factor: 86400},
+	"percent":      {dimension: "ratio", factor: 0.01},
+	"percentunit":  {dimension: "ratio", factor: 1},
+}
+
+// ConvertUnitsFrameProcessor can convert the values of a numeric field of a data.Frame
+// to another unit of the same dimension, for example from fahrenheit to celsius. The
+// converted field is a nullable float64 field with the target unit in its config.
+type ConvertUnitsFrameProcessor struct {
+	config ConvertUnitsFrameProcessorConfig
+	from   unitScale
+	to     unitScale
+}
+
+func NewConvertUnitsFrameProcessor(config ConvertUnitsFrameProcessorConfig) (*ConvertUnitsFrameProcessor, error) {
+	if config.FieldName == "" {
+		return nil, errors.New("field name required")
+	}
+	from, ok := convertibleUnits[config.From]
+	if !ok {
+		return nil, fmt.Errorf("unknown unit: %s", config.From)
+	}
+	to, ok := convertibleUnits[config.To]
+	if !ok {
+		return nil, fmt.Errorf("unknown unit: %s", config.To)
+	}
+	if from.dimension != to.dimension {
+		return nil, fmt.Errorf("can't convert %s to %s", config.From, config.To)
+	}
+	return &ConvertUnitsFrameProcessor{config: config, from: from, to: to}, nil
+}
+
+const FrameProcessorTypeConvertUnits = "convertUnits"
+
+func (p *ConvertUnitsFrameProcessor) Type() string {
+	return FrameProcessorTypeConvertUnits
+}
+
+func (p *ConvertUnitsFrameProcessor) ProcessFrame(_ context.Context, _ Vars, frame *data.Frame) (*data.Frame, error) {
+	field, i := frame.FieldByName(p.config.FieldName)
+	if i < 0 {
+		return frame, nil
+	}
+	values := make([]*float64, field.Len())
+	for row := range values {
+		value, err := field.NullableFloatAt(row)
+		if err != nil {
+			return nil, fmt.Errorf("field %s is not numeric: %w", field.Name, err)
+		}
+		if value != nil {
+			converted := ((*value*p.from.factor + p.from.offset) - p.to.offset) / p.to.factor
+			values[row] = &converted
+		}
+	}
+	converted := data.NewField(field.Name, field.Labels, values)
+	config := data.FieldConfig{}
+	if field.Config != nil {
+		config = *field.Config
+	}
+	config.Unit = p.config.To
+	converted.Config = &config
+	frame.Fields[i] = converted
+	return frame, nil
+}
@@ -0,0 +1,58 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConvertUnitsFrameProcessor(t *testing.T) {
+	_, err := NewConvertUnitsFrameProcessor(ConvertUnitsFrameProcessorConfig{FieldName: "value", From: "celsius", To: "unknown"})
+	require.Error(t, err)
+	_, err = NewConvertUnitsFrameProcessor(ConvertUnitsFrameProcessorConfig{FieldName: "value", From: "celsius", To: "lengthm"})
+	require.Error(t, err)
+	_, err = NewConvertUnitsFrameProcessor(ConvertUnitsFrameProcessorConfig{From: "celsius", To: "kelvin"})
+	require.Error(t, err)
+}
+
+func TestConvertUnitsFrameProcessor_ProcessFrame(t *testing.T) {
+	testCases := []struct {
+		from  string
+		to    string
+		value float64
+		want  float64
+	}{
+		{from: "fahrenheit", to: "celsius", value: 212, want: 100},
+		{from: "celsius", to: "kelvin", value: 0, want: 273.15},
+		{from: "lengthkm", to: "lengthm", value: 1.5, want: 1500},
+		{from: "ms", to: "s", value: 250, want: 0.25},
+		{from: "percentunit", to: "percent", value: 0.5, want: 50},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.from+" to "+tc.to, func(t *testing.T) {
+			p, err := NewConvertUnitsFrameProcessor(ConvertUnitsFrameProcessorConfig{FieldName: "value", From: tc.from, To: tc.to})
+			require.NoError(t, err)
+			frame, err := p.ProcessFrame(context.Background(), Vars{}, data.NewFrame("test",
+				data.NewField("value", data.Labels{"room": "1"}, []float64{tc.value}),
+			))
+			require.NoError(t, err)
+			field := frame.Fields[0]
+			require.Equal(t, data.Labels{"room": "1"}, field.Labels)
+			require.Equal(t, tc.to, field.Config.Unit)
+			v, err := field.NullableFloatAt(0)
+			require.NoError(t, err)
+			require.InDelta(t, tc.want, *v, 1e-9)
+		})
+	}
+
+	t.Run("should ignore missing field", func(t *testing.T) {
+		p, err := NewConvertUnitsFrameProcessor(ConvertUnitsFrameProcessorConfig{FieldName: "value", From: "celsius", To: "kelvin"})
+		require.NoError(t, err)
+		frame := data.NewFrame("test", data.NewField("other", nil, []float64{1}))
+		processed, err := p.ProcessFrame(context.Background(), Vars{}, frame)
+		require.NoError(t, err)
+		require.Equal(t, frame, processed)
+	})
+}
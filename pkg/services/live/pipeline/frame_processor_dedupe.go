@@ -0,0 +1,131 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// DedupeMode defines which samples DedupeFrameProcessor drops.
+type DedupeMode string
+
+// Known DedupeMode types.
+const (
+	// DedupeModeDuplicate drops the samples that are not newer than the last sample,
+	// for example the messages that devices send again.
+	DedupeModeDuplicate DedupeMode = "duplicate"
+	// DedupeModeUnchanged drops the samples with the same values as the last sample.
+	DedupeModeUnchanged DedupeMode = "unchanged"
+)
+
+// DedupeFrameProcessor can drop the rows of a data.Frame that duplicate the last row
+// kept for the channel. Frames without any other row are dropped. The last row is kept
+// in memory, so each Grafana instance dedupes the data pushed to it.
+type DedupeFrameProcessor struct {
+	frameStorage FrameGetSetter
+	config       DedupeFrameProcessorConfig
+}
+
+func NewDedupeFrameProcessor(frameStorage FrameGetSetter, config DedupeFrameProcessorConfig) (*DedupeFrameProcessor, error) {
+	switch config.Mode {
+	case "":
+		config.Mode = DedupeModeDuplicate
+	case DedupeModeDuplicate, DedupeModeUnchanged:
+	default:
+		return nil, fmt.Errorf("unknown dedupe mode: %s", config.Mode)
+	}
+	return &DedupeFrameProcessor{frameStorage: frameStorage, config: config}, nil
+}
+
+const FrameProcessorTypeDedupe = "dedupe"
+
+func (p *DedupeFrameProcessor) Type() string {
+	return FrameProcessorTypeDedupe
+}
+
+func (p *DedupeFrameProcessor) ProcessFrame(_ context.Context, vars Vars, frame *data.Frame) (*data.Frame, error) {
+	var timeIndex int
+	var compared []int
+	if p.config.Mode == DedupeModeDuplicate {
+		var err error
+		timeIndex, err = timeFieldIndex(frame, p.config.TimeFieldName)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		compared = p.comparedFields(frame)
+	}
+
+	key := processorStateKey(FrameProcessorTypeDedupe, vars.Channel)
+	last, ok, err := p.frameStorage.Get(vars.OrgID, key)
+	if err != nil {
+		return nil, err
+	}
+	var lastRow []any
+	if ok && last.Rows() > 0 && sameSchema(last, frame) {
+		lastRow = last.RowCopy(0)
+	}
+
+	result := frame.EmptyCopy()
+	for i := 0; i < frame.Rows(); i++ {
+		row := frame.RowCopy(i)
+		if lastRow != nil {
+			if p.config.Mode == DedupeModeDuplicate && !isNewer(row[timeIndex], lastRow[timeIndex]) {
+				continue
+			}
+			if p.config.Mode == DedupeModeUnchanged && sameValues(row, lastRow, compared) {
+				continue
+			}
+		}
+		result.AppendRow(row...)
+		lastRow = row
+	}
+	if result.Rows() == 0 {
+		return nil, nil
+	}
+
+	state := frame.EmptyCopy()
+	state.AppendRow(lastRow...)
+	if err := p.frameStorage.Set(vars.OrgID, key, state); err != nil {
+		return nil, err
+	}
+	if result.Rows() == frame.Rows() {
+		return frame, nil
+	}
+	return result, nil
+}
+
+// comparedFields returns the indices of the fields compared in DedupeModeUnchanged.
+func (p *DedupeFrameProcessor) comparedFields(frame *data.Frame) []int {
+	var indices []int
+	for i, f := range frame.Fields {
+		if len(p.config.FieldNames) > 0 {
+			if stringInSlice(f.Name, p.config.FieldNames) {
+				indices = append(indices, i)
+			}
+		} else if !f.Type().Time() {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+func isNewer(v any, last any) bool {
+	t, ok := toTime(v)
+	if !ok {
+		return false
+	}
+	lastTime, ok := toTime(last)
+	return !ok || t.After(lastTime)
+}
+
+func sameValues(row []any, lastRow []any, indices []int) bool {
+	for _, i := range indices {
+		if !reflect.DeepEqual(row[i], lastRow[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func dedupeTestFrame(seconds []int64, values []float64) *data.Frame {
+	times := make([]time.Time, len(seconds))
+	for i, s := range seconds {
+		times[i] = time.Unix(s, 0)
+	}
+	return data.NewFrame("test",
+		data.NewField("time", nil, times),
+		data.NewField("value", nil, values),
+	)
+}
+
+func TestNewDedupeFrameProcessor(t *testing.T) {
+	_, err := NewDedupeFrameProcessor(NewFrameStorage(), DedupeFrameProcessorConfig{Mode: "unknown"})
+	require.Error(t, err)
+	p, err := NewDedupeFrameProcessor(NewFrameStorage(), DedupeFrameProcessorConfig{})
+	require.NoError(t, err)
+	require.Equal(t, DedupeModeDuplicate, p.config.Mode)
+}
+
+func TestDedupeFrameProcessor_ProcessFrame(t *testing.T) {
+	vars := Vars{OrgID: 1, Channel: "stream/test/dedupe"}
+
+	t.Run("should drop samples that are not newer", func(t *testing.T) {
+		p, err := NewDedupeFrameProcessor(NewFrameStorage(), DedupeFrameProcessorConfig{Mode: DedupeModeDuplicate})
+		require.NoError(t, err)
+
+		frame, err := p.ProcessFrame(context.Background(), vars, dedupeTestFrame([]int64{100, 101}, []float64{1, 1}))
+		require.NoError(t, err)
+		require.Equal(t, 2, frame.Rows())
+
+		frame, err = p.ProcessFrame(context.Background(), vars, dedupeTestFrame([]int64{101}, []float64{1}))
+		require.NoError(t, err)
+		require.Nil(t, frame)
+
+		frame, err = p.ProcessFrame(context.Background(), vars, dedupeTestFrame([]int64{100, 102, 102}, []float64{1, 2, 3}))
+		require.NoError(t, err)
+		require.Equal(t, 1, frame.Rows())
+		require.Equal(t, []any{time.Unix(102, 0), 2.0}, frame.RowCopy(0))
+	})
+
+	t.Run("should drop unchanged samples", func(t *testing.T) {
+		p, err := NewDedupeFrameProcessor(NewFrameStorage(), DedupeFrameProcessorConfig{Mode: DedupeModeUnchanged})
+		require.NoError(t, err)
+
+		frame, err := p.ProcessFrame(context.Background(), vars, dedupeTestFrame([]int64{100, 101, 102}, []float64{1, 1, 2}))
+		require.NoError(t, err)
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, []any{time.Unix(102, 0), 2.0}, frame.RowCopy(1))
+
+		frame, err = p.ProcessFrame(context.Background(), vars, dedupeTestFrame([]int64{103}, []float64{2}))
+		require.NoError(t, err)
+		require.Nil(t, frame)
+
+		frame, err = p.ProcessFrame(context.Background(), vars, dedupeTestFrame([]int64{104}, []float64{1}))
+		require.NoError(t, err)
+		require.Equal(t, 1, frame.Rows())
+	})
+
+	t.Run("should compare configured fields", func(t *testing.T) {
+		p, err := NewDedupeFrameProcessor(NewFrameStorage(), DedupeFrameProcessorConfig{Mode: DedupeModeUnchanged, FieldNames: []string{"state"}})
+		require.NoError(t, err)
+
+		frame, err := p.ProcessFrame(context.Background(), vars, data.NewFrame("test",
+			data.NewField("state", nil, []string{"on", "on", "off"}),
+			data.NewField("value", nil, []float64{1, 2, 3}),
+		))
+		require.NoError(t, err)
+		require.Equal(t, []string{"on", "off"}, []string{frame.Fields[0].At(0).(string), frame.Fields[0].At(1).(string)})
+		require.Equal(t, []float64{1, 3}, []float64{frame.Fields[1].At(0).(float64), frame.Fields[1].At(1).(float64)})
+	})
+
+	t.Run("should keep state by channel", func(t *testing.T) {
+		p, err := NewDedupeFrameProcessor(NewFrameStorage(), DedupeFrameProcessorConfig{})
+		require.NoError(t, err)
+
+		_, err = p.ProcessFrame(context.Background(), vars, dedupeTestFrame([]int64{100}, []float64{1}))
+		require.NoError(t, err)
+		frame, err := p.ProcessFrame(context.Background(), Vars{OrgID: 1, Channel: "stream/test/other"}, dedupeTestFrame([]int64{100}, []float64{1}))
+		require.NoError(t, err)
+		require.Equal(t, 1, frame.Rows())
+	})
+
+	t.Run("should require time field in duplicate mode", func(t *testing.T) {
+		p, err := NewDedupeFrameProcessor(NewFrameStorage(), DedupeFrameProcessorConfig{})
+		require.NoError(t, err)
+		_, err = p.ProcessFrame(context.Background(), vars, data.NewFrame("test", data.NewField("value", nil, []float64{1})))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,92 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// DeriveFieldFrameProcessor can add a field computed with a math expression over the
+// other fields of a data.Frame. The expression is evaluated for each row, the fields
+// are referenced by name like the queries of server side expressions, for example
+// "$temperature * 1.8 + 32" or "${total power} / 1000". The computed value is null
+// if a referenced field is missing or null in the row.
+type DeriveFieldFrameProcessor struct {
+	config DeriveFieldFrameProcessorConfig
+	expr   *mathexp.Expr
+	tracer tracing.Tracer
+}
+
+func NewDeriveFieldFrameProcessor(config DeriveFieldFrameProcessorConfig) (*DeriveFieldFrameProcessor, error) {
+	if config.FieldName == "" {
+		return nil, errors.New("field name required")
+	}
+	expr, err := mathexp.New(config.Expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+	return &DeriveFieldFrameProcessor{config: config, expr: expr, tracer: tracing.NewNoopTracerService()}, nil
+}
+
+const FrameProcessorTypeDeriveField = "deriveField"
+
+func (p *DeriveFieldFrameProcessor) Type() string {
+	return FrameProcessorTypeDeriveField
+}
+
+func (p *DeriveFieldFrameProcessor) ProcessFrame(_ context.Context, _ Vars, frame *data.Frame) (*data.Frame, error) {
+	rowLen, err := frame.RowLen()
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]*data.Field, len(p.expr.VarNames))
+	for _, name := range p.expr.VarNames {
+		if _, i := frame.FieldByName(name); i >= 0 {
+			fields[name] = frame.Fields[i]
+		}
+	}
+
+	values := make([]*float64, rowLen)
+	for row := 0; row < rowLen; row++ {
+		vars := make(mathexp.Vars, len(p.expr.VarNames))
+		for _, name := range p.expr.VarNames {
+			var value *float64
+			if field, ok := fields[name]; ok {
+				value, err = field.NullableFloatAt(row)
+				if err != nil {
+					return nil, fmt.Errorf("field %s is not numeric: %w", name, err)
+				}
+			}
+			vars[name] = mathexp.NewScalarResults(name, value)
+		}
+		results, err := p.expr.Execute("", vars, p.tracer)
+		if err != nil {
+			return nil, fmt.Errorf("error evaluating expression: %w", err)
+		}
+		if len(results.Values) != 1 {
+			return nil, fmt.Errorf("expression returned %d values, expected 1", len(results.Values))
+		}
+		switch v := results.Values[0].(type) {
+		case mathexp.Scalar:
+			values[row] = v.GetFloat64Value()
+		case mathexp.Number:
+			values[row] = v.GetFloat64Value()
+		default:
+			return nil, fmt.Errorf("expression returned a %s, expected a number", v.Type())
+		}
+	}
+
+	derived := data.NewField(p.config.FieldName, nil, values)
+	if _, i := frame.FieldByName(p.config.FieldName); i >= 0 {
+		frame.Fields[i] = derived
+	} else {
+		frame.Fields = append(frame.Fields, derived)
+	}
+	return frame, nil
+}
@@ -0,0 +1,49 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestDeriveFieldFrameProcessor_ProcessFrame(t *testing.T) {
+	_, err := NewDeriveFieldFrameProcessor(DeriveFieldFrameProcessorConfig{Expression: "$a * 2"})
+	require.Error(t, err)
+	_, err = NewDeriveFieldFrameProcessor(DeriveFieldFrameProcessorConfig{FieldName: "b", Expression: "$a *"})
+	require.Error(t, err)
+
+	t.Run("should add computed field", func(t *testing.T) {
+		p, err := NewDeriveFieldFrameProcessor(DeriveFieldFrameProcessorConfig{FieldName: "power", Expression: "$voltage * ${current A}"})
+		require.NoError(t, err)
+		frame, err := p.ProcessFrame(context.Background(), Vars{}, data.NewFrame("test",
+			data.NewField("voltage", nil, []float64{230, 230}),
+			data.NewField("current A", nil, []*float64{util.Pointer(2.0), nil}),
+		))
+		require.NoError(t, err)
+		require.Len(t, frame.Fields, 3)
+		require.Equal(t, data.NewField("power", nil, []*float64{util.Pointer(460.0), nil}), frame.Fields[2])
+	})
+
+	t.Run("should replace field", func(t *testing.T) {
+		p, err := NewDeriveFieldFrameProcessor(DeriveFieldFrameProcessorConfig{FieldName: "value", Expression: "$value / 10"})
+		require.NoError(t, err)
+		frame, err := p.ProcessFrame(context.Background(), Vars{}, data.NewFrame("test",
+			data.NewField("value", nil, []int64{15}),
+		))
+		require.NoError(t, err)
+		require.Equal(t, data.NewFrame("test", data.NewField("value", nil, []*float64{util.Pointer(1.5)})), frame)
+	})
+
+	t.Run("should fail for non numeric fields", func(t *testing.T) {
+		p, err := NewDeriveFieldFrameProcessor(DeriveFieldFrameProcessorConfig{FieldName: "value", Expression: "$state + 1"})
+		require.NoError(t, err)
+		_, err = p.ProcessFrame(context.Background(), Vars{}, data.NewFrame("test",
+			data.NewField("state", nil, []string{"on"}),
+		))
+		require.Error(t, err)
+	})
+}
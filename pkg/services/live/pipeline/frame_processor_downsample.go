@@ -0,0 +1,245 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// DownsampleAggregator is a function used to aggregate the values of a time window.
+type DownsampleAggregator string
+
+// Known DownsampleAggregator types.
+const (
+	DownsampleAggregatorLast  DownsampleAggregator = "last"
+	DownsampleAggregatorFirst DownsampleAggregator = "first"
+	DownsampleAggregatorMean  DownsampleAggregator = "mean"
+	DownsampleAggregatorMin   DownsampleAggregator = "min"
+	DownsampleAggregatorMax   DownsampleAggregator = "max"
+	DownsampleAggregatorSum   DownsampleAggregator = "sum"
+	DownsampleAggregatorCount DownsampleAggregator = "count"
+)
+
+// DownsampleFrameProcessor can aggregate the rows of a data.Frame by time window. It
+// keeps the rows of the latest window until a row of a later window arrives, then
+// outputs one row per complete window: the start of the window as time, the aggregated
+// values of numeric fields, and the last values of other fields. Frames without complete
+// windows are dropped, as well as the rows of windows that were already output.
+// The rows of the latest window are kept in memory, so each Grafana instance
+// downsamples the data pushed to it.
+type DownsampleFrameProcessor struct {
+	frameStorage FrameGetSetter
+	config       DownsampleFrameProcessorConfig
+	interval     time.Duration
+}
+
+func NewDownsampleFrameProcessor(frameStorage FrameGetSetter, config DownsampleFrameProcessorConfig) (*DownsampleFrameProcessor, error) {
+	if config.IntervalMilliseconds <= 0 {
+		return nil, errors.New("interval must be positive")
+	}
+	switch config.Aggregator {
+	case "":
+		config.Aggregator = DownsampleAggregatorLast
+	case DownsampleAggregatorLast, DownsampleAggregatorFirst, DownsampleAggregatorMean, DownsampleAggregatorMin,
+		DownsampleAggregatorMax, DownsampleAggregatorSum, DownsampleAggregatorCount:
+	default:
+		return nil, fmt.Errorf("unknown aggregator: %s", config.Aggregator)
+	}
+	return &DownsampleFrameProcessor{
+		frameStorage: frameStorage,
+		config:       config,
+		interval:     time.Duration(config.IntervalMilliseconds) * time.Millisecond,
+	}, nil
+}
+
+const FrameProcessorTypeDownsample = "downsample"
+
+func (p *DownsampleFrameProcessor) Type() string {
+	return FrameProcessorTypeDownsample
+}
+
+func (p *DownsampleFrameProcessor) ProcessFrame(_ context.Context, vars Vars, frame *data.Frame) (*data.Frame, error) {
+	timeIndex, err := timeFieldIndex(frame, p.config.TimeFieldName)
+	if err != nil {
+		return nil, err
+	}
+	key := processorStateKey(FrameProcessorTypeDownsample, vars.Channel)
+	pending, ok, err := p.frameStorage.Get(vars.OrgID, key)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := frame.EmptyCopy()
+	var latestWindow time.Time
+	// The rows of the latest window are dropped if the schema changes.
+	if ok && pending.Rows() > 0 && sameSchema(pending, frame) {
+		for i := 0; i < pending.Rows(); i++ {
+			rows.AppendRow(pending.RowCopy(i)...)
+		}
+		t, _ := toTime(pending.Fields[timeIndex].At(0))
+		latestWindow = t.Truncate(p.interval)
+	}
+	for i := 0; i < frame.Rows(); i++ {
+		t, ok := toTime(frame.Fields[timeIndex].At(i))
+		if !ok || t.Before(latestWindow) {
+			continue
+		}
+		rows.AppendRow(frame.RowCopy(i)...)
+	}
+
+	windows := map[time.Time][]int{}
+	var starts []time.Time
+	for i := 0; i < rows.Rows(); i++ {
+		t, _ := toTime(rows.Fields[timeIndex].At(i))
+		start := t.Truncate(p.interval)
+		if _, ok := windows[start]; !ok {
+			starts = append(starts, start)
+		}
+		windows[start] = append(windows[start], i)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+
+	var result *data.Frame
+	newPending := rows.EmptyCopy()
+	for i, start := range starts {
+		if i == len(starts)-1 {
+			for _, row := range windows[start] {
+				newPending.AppendRow(rows.RowCopy(row)...)
+			}
+			break
+		}
+		if result == nil {
+			result = newDownsampledFrame(frame, timeIndex)
+		}
+		p.appendWindow(result, rows, timeIndex, start, windows[start])
+	}
+
+	if err := p.frameStorage.Set(vars.OrgID, key, newPending); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func newDownsampledFrame(frame *data.Frame, timeIndex int) *data.Frame {
+	fields := make([]*data.Field, len(frame.Fields))
+	for i, f := range frame.Fields {
+		fieldType := f.Type()
+		if i != timeIndex && fieldType.Numeric() {
+			fieldType = data.FieldTypeNullableFloat64
+		}
+		fields[i] = data.NewFieldFromFieldType(fieldType, 0)
+		fields[i].Name = f.Name
+		fields[i].Labels = f.Labels
+		fields[i].Config = f.Config
+	}
+	return data.NewFrame(frame.Name, fields...).SetMeta(frame.Meta)
+}
+
+func (p *DownsampleFrameProcessor) appendWindow(result *data.Frame, frame *data.Frame, timeIndex int, start time.Time, rows []int) {
+	for i, f := range frame.Fields {
+		switch {
+		case i == timeIndex:
+			if f.Nullable() {
+				result.Fields[i].Append(&start)
+			} else {
+				result.Fields[i].Append(start)
+			}
+		case f.Type().Numeric():
+			result.Fields[i].Append(p.aggregate(f, rows))
+		default:
+			result.Fields[i].Append(f.CopyAt(rows[len(rows)-1]))
+		}
+	}
+}
+
+// aggregate returns the aggregated non-null values of the rows of a numeric field.
+func (p *DownsampleFrameProcessor) aggregate(f *data.Field, rows []int) *float64 {
+	var values []float64
+	for _, row := range rows {
+		if v, err := f.NullableFloatAt(row); err == nil && v != nil {
+			values = append(values, *v)
+		}
+	}
+	if p.config.Aggregator == DownsampleAggregatorCount {
+		count := float64(len(values))
+		return &count
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	var result float64
+	switch p.config.Aggregator {
+	case DownsampleAggregatorFirst:
+		result = values[0]
+	case DownsampleAggregatorLast:
+		result = values[len(values)-1]
+	case DownsampleAggregatorMin:
+		result = values[0]
+		for _, v := range values[1:] {
+			if v < result {
+				result = v
+			}
+		}
+	case DownsampleAggregatorMax:
+		result = values[0]
+		for _, v := range values[1:] {
+			if v > result {
+				result = v
+			}
+		}
+	case DownsampleAggregatorSum, DownsampleAggregatorMean:
+		for _, v := range values {
+			result += v
+		}
+		if p.config.Aggregator == DownsampleAggregatorMean {
+			result /= float64(len(values))
+		}
+	}
+	return &result
+}
+
+// processorStateKey returns the FrameStorage key of the state a processor keeps for a channel.
+func processorStateKey(processorType string, channel string) string {
+	return processorType + "/" + channel
+}
+
+// timeFieldIndex returns the index of the time field with the given name, or of the first time field if name is empty.
+func timeFieldIndex(frame *data.Frame, name string) (int, error) {
+	for i, f := range frame.Fields {
+		if f.Type().Time() && (name == "" || f.Name == name) {
+			return i, nil
+		}
+	}
+	if name == "" {
+		return -1, errors.New("frame has no time field")
+	}
+	return -1, fmt.Errorf("frame has no time field %s", name)
+}
+
+func toTime(v any) (time.Time, bool) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, true
+	case *time.Time:
+		if t != nil {
+			return *t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func sameSchema(a, b *data.Frame) bool {
+	if len(a.Fields) != len(b.Fields) {
+		return false
+	}
+	for i := range a.Fields {
+		if a.Fields[i].Name != b.Fields[i].Name || a.Fields[i].Type() != b.Fields[i].Type() {
+			return false
+		}
+	}
+	return true
+}
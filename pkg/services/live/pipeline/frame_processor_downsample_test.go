@@ -0,0 +1,118 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func downsampleTestFrame(seconds []int64, values []*float64, states []string) *data.Frame {
+	times := make([]time.Time, len(seconds))
+	for i, s := range seconds {
+		times[i] = time.Unix(s, 0)
+	}
+	return data.NewFrame("test",
+		data.NewField("time", nil, times),
+		data.NewField("value", nil, values),
+		data.NewField("state", nil, states),
+	)
+}
+
+func TestNewDownsampleFrameProcessor(t *testing.T) {
+	_, err := NewDownsampleFrameProcessor(NewFrameStorage(), DownsampleFrameProcessorConfig{Aggregator: DownsampleAggregatorMean})
+	require.Error(t, err)
+	_, err = NewDownsampleFrameProcessor(NewFrameStorage(), DownsampleFrameProcessorConfig{IntervalMilliseconds: 1000, Aggregator: "median"})
+	require.Error(t, err)
+	p, err := NewDownsampleFrameProcessor(NewFrameStorage(), DownsampleFrameProcessorConfig{IntervalMilliseconds: 1000})
+	require.NoError(t, err)
+	require.Equal(t, DownsampleAggregatorLast, p.config.Aggregator)
+}
+
+func TestDownsampleFrameProcessor_ProcessFrame(t *testing.T) {
+	vars := Vars{OrgID: 1, Channel: "stream/test/downsample"}
+
+	t.Run("should output complete windows", func(t *testing.T) {
+		p, err := NewDownsampleFrameProcessor(NewFrameStorage(), DownsampleFrameProcessorConfig{IntervalMilliseconds: 10000, Aggregator: DownsampleAggregatorMean})
+		require.NoError(t, err)
+
+		frame, err := p.ProcessFrame(context.Background(), vars, downsampleTestFrame(
+			[]int64{100, 105}, []*float64{util.Pointer(1.0), util.Pointer(2.0)}, []string{"a", "b"},
+		))
+		require.NoError(t, err)
+		require.Nil(t, frame, "the window is not complete")
+
+		frame, err = p.ProcessFrame(context.Background(), vars, downsampleTestFrame(
+			[]int64{109, 112, 125}, []*float64{nil, util.Pointer(4.0), util.Pointer(5.0)}, []string{"c", "d", "e"},
+		))
+		require.NoError(t, err)
+		require.Equal(t, data.NewFrame("test",
+			data.NewField("time", nil, []time.Time{time.Unix(100, 0), time.Unix(110, 0)}),
+			data.NewField("value", nil, []*float64{util.Pointer(1.5), util.Pointer(4.0)}),
+			data.NewField("state", nil, []string{"c", "d"}),
+		), frame)
+
+		frame, err = p.ProcessFrame(context.Background(), vars, downsampleTestFrame(
+			[]int64{115, 130}, []*float64{util.Pointer(6.0), util.Pointer(7.0)}, []string{"f", "g"},
+		))
+		require.NoError(t, err)
+		require.Equal(t, data.NewFrame("test",
+			data.NewField("time", nil, []time.Time{time.Unix(120, 0)}),
+			data.NewField("value", nil, []*float64{util.Pointer(5.0)}),
+			data.NewField("state", nil, []string{"e"}),
+		), frame, "late rows should be dropped")
+	})
+
+	t.Run("should aggregate values", func(t *testing.T) {
+		testCases := []struct {
+			aggregator DownsampleAggregator
+			want       *float64
+		}{
+			{aggregator: DownsampleAggregatorFirst, want: util.Pointer(3.0)},
+			{aggregator: DownsampleAggregatorLast, want: util.Pointer(2.0)},
+			{aggregator: DownsampleAggregatorMin, want: util.Pointer(1.0)},
+			{aggregator: DownsampleAggregatorMax, want: util.Pointer(3.0)},
+			{aggregator: DownsampleAggregatorSum, want: util.Pointer(6.0)},
+			{aggregator: DownsampleAggregatorCount, want: util.Pointer(3.0)},
+		}
+		for _, tc := range testCases {
+			t.Run(string(tc.aggregator), func(t *testing.T) {
+				p, err := NewDownsampleFrameProcessor(NewFrameStorage(), DownsampleFrameProcessorConfig{IntervalMilliseconds: 10000, Aggregator: tc.aggregator})
+				require.NoError(t, err)
+				frame, err := p.ProcessFrame(context.Background(), vars, downsampleTestFrame(
+					[]int64{100, 101, 102, 103, 110}, []*float64{util.Pointer(3.0), nil, util.Pointer(1.0), util.Pointer(2.0), util.Pointer(10.0)}, []string{"a", "b", "c", "d", "e"},
+				))
+				require.NoError(t, err)
+				require.Equal(t, tc.want, frame.Fields[1].At(0))
+			})
+		}
+	})
+
+	t.Run("should drop pending rows when schema changes", func(t *testing.T) {
+		p, err := NewDownsampleFrameProcessor(NewFrameStorage(), DownsampleFrameProcessorConfig{IntervalMilliseconds: 10000})
+		require.NoError(t, err)
+		_, err = p.ProcessFrame(context.Background(), vars, downsampleTestFrame([]int64{100}, []*float64{util.Pointer(1.0)}, []string{"a"}))
+		require.NoError(t, err)
+
+		frame, err := p.ProcessFrame(context.Background(), vars, data.NewFrame("test",
+			data.NewField("time", nil, []time.Time{time.Unix(105, 0), time.Unix(110, 0)}),
+			data.NewField("value", nil, []float64{2, 3}),
+		))
+		require.NoError(t, err)
+		require.Equal(t, data.NewFrame("test",
+			data.NewField("time", nil, []time.Time{time.Unix(100, 0)}),
+			data.NewField("value", nil, []*float64{util.Pointer(2.0)}),
+		), frame)
+	})
+
+	t.Run("should require time field", func(t *testing.T) {
+		p, err := NewDownsampleFrameProcessor(NewFrameStorage(), DownsampleFrameProcessorConfig{IntervalMilliseconds: 10000})
+		require.NoError(t, err)
+		_, err = p.ProcessFrame(context.Background(), vars, data.NewFrame("test", data.NewField("value", nil, []float64{1})))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,28 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultipleFrameProcessor_ProcessFrame(t *testing.T) {
+	vars := Vars{OrgID: 1, Channel: "stream/test/multiple"}
+
+	t.Run("should stop when a processor drops the frame", func(t *testing.T) {
+		dedupe, err := NewDedupeFrameProcessor(NewFrameStorage(), DedupeFrameProcessorConfig{Mode: DedupeModeDuplicate})
+		require.NoError(t, err)
+		p := NewMultipleFrameProcessor(dedupe, NewRenameFieldsFrameProcessor(RenameFieldsFrameProcessorConfig{
+			Renames: map[string]string{"value": "renamed"},
+		}))
+
+		frame, err := p.ProcessFrame(context.Background(), vars, dedupeTestFrame([]int64{100}, []float64{1}))
+		require.NoError(t, err)
+		require.Equal(t, "renamed", frame.Fields[1].Name)
+
+		frame, err = p.ProcessFrame(context.Background(), vars, dedupeTestFrame([]int64{100}, []float64{1}))
+		require.NoError(t, err)
+		require.Nil(t, frame)
+	})
+}
@@ -59,6 +59,7 @@ func WriteConfigToDto(b WriteConfig) WriteConfigDto {
 		UID:          b.UID,
 		Settings:     b.Settings,
 		SecureFields: secureFields,
+		Version:      b.Version,
 	}
 }
 
@@ -66,6 +67,7 @@ type WriteConfigDto struct {
 	UID          string          `json:"uid"`
 	Settings     WriteSettings   `json:"settings"`
 	SecureFields map[string]bool `json:"secureFields"`
+	Version      int64           `json:"version"`
 }
 
 type WriteConfigGetCmd struct {
@@ -78,11 +80,13 @@ type WriteConfigCreateCmd struct {
 	SecureSettings map[string]string `json:"secureSettings"`
 }
 
-// TODO: add version field later.
 type WriteConfigUpdateCmd struct {
 	UID            string            `json:"uid"`
 	Settings       WriteSettings     `json:"settings"`
 	SecureSettings map[string]string `json:"secureSettings"`
+	// Version of the write config the update is based on. If set, the update fails
+	// with ErrVersionConflict when the stored write config has another version.
+	Version int64 `json:"version,omitempty"`
 }
 
 type WriteConfigDeleteCmd struct {
@@ -94,6 +98,7 @@ type WriteConfig struct {
 	UID            string            `json:"uid"`
 	Settings       WriteSettings     `json:"settings"`
 	SecureSettings map[string][]byte `json:"secureSettings,omitempty"`
+	Version        int64             `json:"version,omitempty"`
 }
 
 func (r WriteConfig) Valid() (bool, string) {
@@ -154,6 +159,9 @@ type ChannelRuleCreateCmd struct {
 type ChannelRuleUpdateCmd struct {
 	Pattern  string              `json:"pattern"`
 	Settings ChannelRuleSettings `json:"settings"`
+	// Version of the channel rule the update is based on. If set, the update fails
+	// with ErrVersionConflict when the stored channel rule has another version.
+	Version int64 `json:"version,omitempty"`
 }
 
 type ChannelRuleDeleteCmd struct {
@@ -48,6 +48,23 @@ var FrameOutputsRegistry = []EntityInfo{
 		Type:        FrameOutputTypeLoki,
 		Description: "output frame as JSON to Loki",
 	},
+	{
+		Type:        FrameOutputTypeWebhook,
+		Description: "output frames as JSON to an HTTP endpoint in batches",
+		Example: WebhookOutputConfig{
+			UID: "webhook",
+		},
+	},
+	{
+		Type:        FrameOutputTypeSQL,
+		Description: "insert frame rows into a table of a PostgreSQL or MySQL data source in batches",
+		Example: SQLOutputConfig{
+			DatasourceUID: "postgres",
+			Table:         "telemetry",
+			Columns:       map[string]string{"time": "ts", "value": "value"},
+			ChannelColumn: "channel",
+		},
+	},
 }
 
 var ConvertersRegistry = []EntityInfo{
@@ -79,6 +96,45 @@ var FrameProcessorsRegistry = []EntityInfo{
 		Description: "list the fields that should be removed",
 		Example:     DropFieldsFrameProcessorConfig{},
 	},
+	{
+		Type:        FrameProcessorTypeRenameFields,
+		Description: "rename fields",
+		Example: RenameFieldsFrameProcessorConfig{
+			Renames: map[string]string{"temp": "temperature"},
+		},
+	},
+	{
+		Type:        FrameProcessorTypeDeriveField,
+		Description: "add a field computed with a math expression over the other fields",
+		Example: DeriveFieldFrameProcessorConfig{
+			FieldName:  "power",
+			Expression: "$voltage * $current",
+		},
+	},
+	{
+		Type:        FrameProcessorTypeConvertUnits,
+		Description: "convert the values of a field to another unit",
+		Example: ConvertUnitsFrameProcessorConfig{
+			FieldName: "temperature",
+			From:      "fahrenheit",
+			To:        "celsius",
+		},
+	},
+	{
+		Type:        FrameProcessorTypeDownsample,
+		Description: "aggregate the rows of each time window",
+		Example: DownsampleFrameProcessorConfig{
+			IntervalMilliseconds: 10000,
+			Aggregator:           DownsampleAggregatorMean,
+		},
+	},
+	{
+		Type:        FrameProcessorTypeDedupe,
+		Description: "drop duplicate or unchanged samples",
+		Example: DedupeFrameProcessorConfig{
+			Mode: DedupeModeUnchanged,
+		},
+	},
 }
 
 var DataOutputsRegistry = []EntityInfo{
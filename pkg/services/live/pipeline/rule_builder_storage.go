@@ -2,10 +2,13 @@ package pipeline
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/centrifugal/centrifuge"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 
+	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/live/managedstream"
 	"github.com/grafana/grafana/pkg/services/secrets"
 )
@@ -17,6 +20,12 @@ type StorageRuleBuilder struct {
 	Storage              Storage
 	ChannelHandlerGetter ChannelHandlerGetter
 	SecretsService       secrets.Service
+	DataSourceService    datasources.DataSourceService
+	// DryRun builds rules that never output frames to external systems, without
+	// connecting to them.
+	DryRun bool
+
+	frameOutputs frameOutputCache
 }
 
 func (f *StorageRuleBuilder) extractSubscriber(config *SubscriberConfig) (Subscriber, error) {
@@ -90,6 +99,31 @@ func (f *StorageRuleBuilder) extractFrameProcessor(config *FrameProcessorConfig)
 			return nil, missingConfiguration
 		}
 		return NewKeepFieldsFrameProcessor(*config.KeepFieldsProcessorConfig), nil
+	case FrameProcessorTypeRenameFields:
+		if config.RenameFieldsProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewRenameFieldsFrameProcessor(*config.RenameFieldsProcessorConfig), nil
+	case FrameProcessorTypeDeriveField:
+		if config.DeriveFieldProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewDeriveFieldFrameProcessor(*config.DeriveFieldProcessorConfig)
+	case FrameProcessorTypeConvertUnits:
+		if config.ConvertUnitsProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewConvertUnitsFrameProcessor(*config.ConvertUnitsProcessorConfig)
+	case FrameProcessorTypeDownsample:
+		if config.DownsampleProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewDownsampleFrameProcessor(f.FrameStorage, *config.DownsampleProcessorConfig)
+	case FrameProcessorTypeDedupe:
+		if config.DedupeProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewDedupeFrameProcessor(f.FrameStorage, *config.DedupeProcessorConfig)
 	case FrameProcessorTypeMultiple:
 		if config.MultipleProcessorConfig == nil {
 			return nil, missingConfiguration
@@ -164,7 +198,7 @@ func (f *StorageRuleBuilder) constructBasicAuth(writeConfig WriteConfig) (*Basic
 	}, nil
 }
 
-func (f *StorageRuleBuilder) extractFrameOutputter(config *FrameOutputterConfig, writeConfigs []WriteConfig) (FrameOutputter, error) {
+func (f *StorageRuleBuilder) extractFrameOutputter(ctx context.Context, build *frameOutputBuild, config *FrameOutputterConfig, writeConfigs []WriteConfig) (FrameOutputter, error) {
 	if config == nil {
 		return nil, nil
 	}
@@ -182,7 +216,7 @@ func (f *StorageRuleBuilder) extractFrameOutputter(config *FrameOutputterConfig,
 		var outputters []FrameOutputter
 		for _, outConf := range config.MultipleOutputterConfig.Outputters {
 			out := outConf
-			outputter, err := f.extractFrameOutputter(&out, writeConfigs)
+			outputter, err := f.extractFrameOutputter(ctx, build, &out, writeConfigs)
 			if err != nil {
 				return nil, err
 			}
@@ -201,7 +235,7 @@ func (f *StorageRuleBuilder) extractFrameOutputter(config *FrameOutputterConfig,
 		if err != nil {
 			return nil, err
 		}
-		outputter, err := f.extractFrameOutputter(config.ConditionalOutputConfig.Outputter, writeConfigs)
+		outputter, err := f.extractFrameOutputter(ctx, build, config.ConditionalOutputConfig.Outputter, writeConfigs)
 		if err != nil {
 			return nil, err
 		}
@@ -249,6 +283,67 @@ func (f *StorageRuleBuilder) extractFrameOutputter(config *FrameOutputterConfig,
 			return nil, missingConfiguration
 		}
 		return NewChangeLogFrameOutput(f.FrameStorage, *config.ChangeLogOutputConfig), nil
+	case FrameOutputTypeWebhook:
+		if config.WebhookOutputConfig == nil {
+			return nil, missingConfiguration
+		}
+		writeConfig, ok := f.getWriteConfig(config.WebhookOutputConfig.UID, writeConfigs)
+		if !ok {
+			return nil, fmt.Errorf("unknown webhook uid: %s", config.WebhookOutputConfig.UID)
+		}
+		if f.DryRun {
+			return dryRunFrameOutput{outputType: config.Type}, nil
+		}
+		key, err := frameOutputKey(build.orgID, config.Type, config.WebhookOutputConfig)
+		if err != nil {
+			return nil, err
+		}
+		version, err := json.Marshal(writeConfig)
+		if err != nil {
+			return nil, err
+		}
+		return f.frameOutputs.getOrCreate(build, key, string(version), func() (stoppableFrameOutput, error) {
+			basicAuth, err := f.constructBasicAuth(writeConfig)
+			if err != nil {
+				return nil, fmt.Errorf("error getting password: %w", err)
+			}
+			return NewWebhookFrameOutput(writeConfig.Settings.Endpoint, basicAuth, config.WebhookOutputConfig.BatchConfig), nil
+		})
+	case FrameOutputTypeSQL:
+		if config.SQLOutputConfig == nil {
+			return nil, missingConfiguration
+		}
+		ds, err := f.DataSourceService.GetDataSource(ctx, &datasources.GetDataSourceQuery{
+			UID:   config.SQLOutputConfig.DatasourceUID,
+			OrgID: build.orgID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error getting data source %s: %w", config.SQLOutputConfig.DatasourceUID, err)
+		}
+		if f.DryRun {
+			return dryRunFrameOutput{outputType: config.Type}, nil
+		}
+		key, err := frameOutputKey(build.orgID, config.Type, config.SQLOutputConfig)
+		if err != nil {
+			return nil, err
+		}
+		version := fmt.Sprintf("%d/%d", ds.Version, ds.Updated.UnixNano())
+		return f.frameOutputs.getOrCreate(build, key, version, func() (stoppableFrameOutput, error) {
+			secureJSONData, err := f.DataSourceService.DecryptedValues(ctx, ds)
+			if err != nil {
+				return nil, fmt.Errorf("error decrypting data source secure settings: %w", err)
+			}
+			driverName, db, err := openSQLDataSource(ds, secureJSONData)
+			if err != nil {
+				return nil, err
+			}
+			out, err := NewSQLFrameOutput(driverName, db, *config.SQLOutputConfig)
+			if err != nil {
+				_ = db.Close()
+				return nil, err
+			}
+			return out, nil
+		})
 	default:
 		return nil, fmt.Errorf("unknown output type: %s", config.Type)
 	}
@@ -290,6 +385,30 @@ func (f *StorageRuleBuilder) extractDataOutputter(config *DataOutputterConfig, w
 	}
 }
 
+// frameOutputKey identifies an outputter with a batch writer, outputters with the same
+// configuration in an org share the same writer.
+func frameOutputKey(orgID int64, outputType string, config any) (string, error) {
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d/%s/%s", orgID, outputType, configJSON), nil
+}
+
+// dryRunFrameOutput replaces the outputters that write to external systems in
+// rules built for a dry run.
+type dryRunFrameOutput struct {
+	outputType string
+}
+
+func (out dryRunFrameOutput) Type() string {
+	return out.outputType
+}
+
+func (out dryRunFrameOutput) OutputFrame(_ context.Context, _ Vars, _ *data.Frame) ([]*ChannelFrame, error) {
+	return nil, nil
+}
+
 func (f *StorageRuleBuilder) getWriteConfig(uid string, writeConfigs []WriteConfig) (WriteConfig, bool) {
 	for _, rwb := range writeConfigs {
 		if rwb.UID == uid {
@@ -311,6 +430,7 @@ func (f *StorageRuleBuilder) BuildRules(ctx context.Context, orgID int64) ([]*Li
 	}
 
 	rules := make([]*LiveChannelRule, 0, len(channelRules))
+	build := newFrameOutputBuild(orgID)
 
 	for _, ruleConfig := range channelRules {
 		rule := &LiveChannelRule{
@@ -355,7 +475,7 @@ func (f *StorageRuleBuilder) BuildRules(ctx context.Context, orgID int64) ([]*Li
 
 		var outputters []FrameOutputter
 		for _, outConfig := range ruleConfig.Settings.FrameOutputters {
-			out, err := f.extractFrameOutputter(outConfig, writeConfigs)
+			out, err := f.extractFrameOutputter(ctx, build, outConfig, writeConfigs)
 			if err != nil {
 				return nil, fmt.Errorf("error building frame outputter for %s: %w", rule.Pattern, err)
 			}
@@ -376,5 +496,6 @@ func (f *StorageRuleBuilder) BuildRules(ctx context.Context, orgID int64) ([]*Li
 		rules = append(rules, rule)
 	}
 
+	f.frameOutputs.stopUnused(build)
 	return rules, nil
 }
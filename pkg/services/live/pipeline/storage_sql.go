@@ -0,0 +1,421 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type channelRuleRow struct {
+	ID       int64     `xorm:"pk autoincr 'id'"`
+	OrgID    int64     `xorm:"'org_id'"`
+	Pattern  string    `xorm:"'pattern'"`
+	Settings string    `xorm:"'settings'"`
+	Version  int64     `xorm:"'version'"`
+	Created  time.Time `xorm:"'created'"`
+	Updated  time.Time `xorm:"'updated'"`
+}
+
+func (r channelRuleRow) TableName() string {
+	return "live_channel_rule"
+}
+
+type writeConfigRow struct {
+	ID             int64     `xorm:"pk autoincr 'id'"`
+	OrgID          int64     `xorm:"'org_id'"`
+	UID            string    `xorm:"'uid'"`
+	Settings       string    `xorm:"'settings'"`
+	SecureSettings string    `xorm:"'secure_settings'"`
+	Version        int64     `xorm:"'version'"`
+	Created        time.Time `xorm:"'created'"`
+	Updated        time.Time `xorm:"'updated'"`
+}
+
+func (r writeConfigRow) TableName() string {
+	return "live_write_config"
+}
+
+// SQLStorage keeps channel rules and write configs in the database, so that all Grafana
+// instances of an HA deployment share them. Every change increments the version of the
+// channel rule or write config, updates with a version are only applied if it is still
+// the stored version.
+type SQLStorage struct {
+	store          db.DB
+	secretsService secrets.Service
+}
+
+func NewSQLStorage(store db.DB, secretsService secrets.Service) *SQLStorage {
+	return &SQLStorage{store: store, secretsService: secretsService}
+}
+
+func (s *SQLStorage) ListWriteConfigs(ctx context.Context, orgID int64) ([]WriteConfig, error) {
+	var result []WriteConfig
+	err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		var rows []writeConfigRow
+		if err := sess.Where("org_id = ?", orgID).Asc("uid").Find(&rows); err != nil {
+			return fmt.Errorf("can't read write configs: %w", err)
+		}
+		result = make([]WriteConfig, 0, len(rows))
+		for _, row := range rows {
+			writeConfig, err := writeConfigFromRow(row)
+			if err != nil {
+				return err
+			}
+			result = append(result, writeConfig)
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (s *SQLStorage) GetWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigGetCmd) (WriteConfig, bool, error) {
+	var result WriteConfig
+	var found bool
+	err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		row, has, err := getWriteConfigRow(sess, orgID, cmd.UID)
+		if err != nil || !has {
+			return err
+		}
+		result, err = writeConfigFromRow(row)
+		found = err == nil
+		return err
+	})
+	return result, found, err
+}
+
+func (s *SQLStorage) CreateWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigCreateCmd) (WriteConfig, error) {
+	if cmd.UID == "" {
+		cmd.UID = util.GenerateShortUID()
+	}
+	writeConfig, err := s.newWriteConfig(ctx, orgID, cmd.UID, cmd.Settings, cmd.SecureSettings)
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	err = s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		_, has, err := getWriteConfigRow(sess, orgID, writeConfig.UID)
+		if err != nil {
+			return err
+		}
+		if has {
+			return fmt.Errorf("backend already exists in org: %s", writeConfig.UID)
+		}
+		return insertWriteConfig(sess, &writeConfig)
+	})
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	return writeConfig, nil
+}
+
+// UpdateWriteConfig replaces the settings and secure settings of a write config, or creates
+// it if it does not exist and cmd has no version.
+func (s *SQLStorage) UpdateWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigUpdateCmd) (WriteConfig, error) {
+	writeConfig, err := s.newWriteConfig(ctx, orgID, cmd.UID, cmd.Settings, cmd.SecureSettings)
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	err = s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		existing, has, err := getWriteConfigRow(sess, orgID, writeConfig.UID)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(cmd.Version, existing.Version, has); err != nil {
+			return fmt.Errorf("%w: write config %s was changed since version %d", err, writeConfig.UID, cmd.Version)
+		}
+		if !has {
+			return insertWriteConfig(sess, &writeConfig)
+		}
+		writeConfig.Version = existing.Version + 1
+		row, err := writeConfigToRow(writeConfig)
+		if err != nil {
+			return err
+		}
+		affected, err := sess.Where("org_id = ? AND uid = ? AND version = ?", orgID, writeConfig.UID, existing.Version).
+			Cols("settings", "secure_settings", "version", "updated").
+			Update(&row)
+		if err != nil {
+			return fmt.Errorf("can't update write config: %w", err)
+		}
+		if affected == 0 {
+			return fmt.Errorf("%w: write config %s was changed concurrently", ErrVersionConflict, writeConfig.UID)
+		}
+		return nil
+	})
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	return writeConfig, nil
+}
+
+func (s *SQLStorage) DeleteWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigDeleteCmd) error {
+	return s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.Where("org_id = ? AND uid = ?", orgID, cmd.UID).Delete(&writeConfigRow{})
+		if err != nil {
+			return fmt.Errorf("can't delete write config: %w", err)
+		}
+		if affected == 0 {
+			return ErrWriteConfigNotFound
+		}
+		return nil
+	})
+}
+
+func (s *SQLStorage) ListChannelRules(ctx context.Context, orgID int64) ([]ChannelRule, error) {
+	var result []ChannelRule
+	err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		result, err = listChannelRules(sess, orgID)
+		return err
+	})
+	return result, err
+}
+
+func (s *SQLStorage) CreateChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleCreateCmd) (ChannelRule, error) {
+	rule, err := newChannelRule(orgID, cmd.Pattern, cmd.Settings)
+	if err != nil {
+		return rule, err
+	}
+	err = s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		rules, err := listChannelRules(sess, orgID)
+		if err != nil {
+			return err
+		}
+		for _, existingRule := range rules {
+			if existingRule.Pattern == rule.Pattern {
+				return fmt.Errorf("pattern already exists in org: %s", rule.Pattern)
+			}
+		}
+		if ok, reason := checkRulesValid(orgID, append(rules, rule)); !ok {
+			return errors.New(reason)
+		}
+		return insertChannelRule(sess, &rule)
+	})
+	if err != nil {
+		return ChannelRule{}, err
+	}
+	return rule, nil
+}
+
+// UpdateChannelRule replaces the settings of a channel rule, or creates it if it does not
+// exist and cmd has no version.
+func (s *SQLStorage) UpdateChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleUpdateCmd) (ChannelRule, error) {
+	rule, err := newChannelRule(orgID, cmd.Pattern, cmd.Settings)
+	if err != nil {
+		return rule, err
+	}
+	err = s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		rules, err := listChannelRules(sess, orgID)
+		if err != nil {
+			return err
+		}
+		index := -1
+		for i, existingRule := range rules {
+			if existingRule.Pattern == rule.Pattern {
+				index = i
+				break
+			}
+		}
+		var version int64
+		if index > -1 {
+			version = rules[index].Version
+		}
+		if err := checkVersion(cmd.Version, version, index > -1); err != nil {
+			return fmt.Errorf("%w: channel rule %s was changed since version %d", err, rule.Pattern, cmd.Version)
+		}
+		if index == -1 {
+			if ok, reason := checkRulesValid(orgID, append(rules, rule)); !ok {
+				return errors.New(reason)
+			}
+			return insertChannelRule(sess, &rule)
+		}
+		rule.Version = version + 1
+		row, err := channelRuleToRow(rule)
+		if err != nil {
+			return err
+		}
+		affected, err := sess.Where("org_id = ? AND pattern = ? AND version = ?", orgID, rule.Pattern, version).
+			Cols("settings", "version", "updated").
+			Update(&row)
+		if err != nil {
+			return fmt.Errorf("can't update channel rule: %w", err)
+		}
+		if affected == 0 {
+			return fmt.Errorf("%w: channel rule %s was changed concurrently", ErrVersionConflict, rule.Pattern)
+		}
+		return nil
+	})
+	if err != nil {
+		return ChannelRule{}, err
+	}
+	return rule, nil
+}
+
+func (s *SQLStorage) DeleteChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleDeleteCmd) error {
+	return s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.Where("org_id = ? AND pattern = ?", orgID, cmd.Pattern).Delete(&channelRuleRow{})
+		if err != nil {
+			return fmt.Errorf("can't delete channel rule: %w", err)
+		}
+		if affected == 0 {
+			return ErrChannelRuleNotFound
+		}
+		return nil
+	})
+}
+
+func (s *SQLStorage) newWriteConfig(ctx context.Context, orgID int64, uid string, settings WriteSettings, secureSettings map[string]string) (WriteConfig, error) {
+	encrypted, err := s.secretsService.EncryptJsonData(ctx, secureSettings, secrets.WithoutScope())
+	if err != nil {
+		return WriteConfig{}, fmt.Errorf("error encrypting data: %w", err)
+	}
+	writeConfig := WriteConfig{
+		OrgId:          orgID,
+		UID:            uid,
+		Settings:       settings,
+		SecureSettings: encrypted,
+	}
+	if ok, reason := writeConfig.Valid(); !ok {
+		return WriteConfig{}, fmt.Errorf("invalid write config: %s", reason)
+	}
+	return writeConfig, nil
+}
+
+func newChannelRule(orgID int64, pattern string, settings ChannelRuleSettings) (ChannelRule, error) {
+	rule := ChannelRule{
+		OrgId:    orgID,
+		Pattern:  pattern,
+		Settings: settings,
+	}
+	if ok, reason := rule.Valid(); !ok {
+		return rule, fmt.Errorf("invalid channel rule: %s", reason)
+	}
+	return rule, nil
+}
+
+// checkVersion returns ErrVersionConflict if an update based on version must not be applied to the stored
+// version. Updates without a version are always applied.
+func checkVersion(version int64, storedVersion int64, stored bool) error {
+	if version != 0 && (!stored || version != storedVersion) {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+func getWriteConfigRow(sess *db.Session, orgID int64, uid string) (writeConfigRow, bool, error) {
+	var row writeConfigRow
+	has, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(&row)
+	if err != nil {
+		return writeConfigRow{}, false, fmt.Errorf("can't read write config: %w", err)
+	}
+	return row, has, nil
+}
+
+func insertWriteConfig(sess *db.Session, writeConfig *WriteConfig) error {
+	writeConfig.Version = 1
+	row, err := writeConfigToRow(*writeConfig)
+	if err != nil {
+		return err
+	}
+	row.Created = row.Updated
+	if _, err := sess.Insert(&row); err != nil {
+		return fmt.Errorf("can't insert write config: %w", err)
+	}
+	return nil
+}
+
+func listChannelRules(sess *db.Session, orgID int64) ([]ChannelRule, error) {
+	var rows []channelRuleRow
+	if err := sess.Where("org_id = ?", orgID).Asc("pattern").Find(&rows); err != nil {
+		return nil, fmt.Errorf("can't read channel rules: %w", err)
+	}
+	rules := make([]ChannelRule, 0, len(rows))
+	for _, row := range rows {
+		rule, err := channelRuleFromRow(row)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func insertChannelRule(sess *db.Session, rule *ChannelRule) error {
+	rule.Version = 1
+	row, err := channelRuleToRow(*rule)
+	if err != nil {
+		return err
+	}
+	row.Created = row.Updated
+	if _, err := sess.Insert(&row); err != nil {
+		return fmt.Errorf("can't insert channel rule: %w", err)
+	}
+	return nil
+}
+
+func channelRuleToRow(rule ChannelRule) (channelRuleRow, error) {
+	settings, err := json.Marshal(rule.Settings)
+	if err != nil {
+		return channelRuleRow{}, fmt.Errorf("can't marshal channel rule settings: %w", err)
+	}
+	return channelRuleRow{
+		OrgID:    rule.OrgId,
+		Pattern:  rule.Pattern,
+		Settings: string(settings),
+		Version:  rule.Version,
+		Updated:  time.Now().UTC(),
+	}, nil
+}
+
+func channelRuleFromRow(row channelRuleRow) (ChannelRule, error) {
+	rule := ChannelRule{
+		OrgId:   row.OrgID,
+		Pattern: row.Pattern,
+		Version: row.Version,
+	}
+	if err := json.Unmarshal([]byte(row.Settings), &rule.Settings); err != nil {
+		return ChannelRule{}, fmt.Errorf("can't unmarshal settings of channel rule %s: %w", row.Pattern, err)
+	}
+	return rule, nil
+}
+
+func writeConfigToRow(writeConfig WriteConfig) (writeConfigRow, error) {
+	settings, err := json.Marshal(writeConfig.Settings)
+	if err != nil {
+		return writeConfigRow{}, fmt.Errorf("can't marshal write config settings: %w", err)
+	}
+	secureSettings, err := json.Marshal(writeConfig.SecureSettings)
+	if err != nil {
+		return writeConfigRow{}, fmt.Errorf("can't marshal write config secure settings: %w", err)
+	}
+	return writeConfigRow{
+		OrgID:          writeConfig.OrgId,
+		UID:            writeConfig.UID,
+		Settings:       string(settings),
+		SecureSettings: string(secureSettings),
+		Version:        writeConfig.Version,
+		Updated:        time.Now().UTC(),
+	}, nil
+}
+
+func writeConfigFromRow(row writeConfigRow) (WriteConfig, error) {
+	writeConfig := WriteConfig{
+		OrgId:   row.OrgID,
+		UID:     row.UID,
+		Version: row.Version,
+	}
+	if err := json.Unmarshal([]byte(row.Settings), &writeConfig.Settings); err != nil {
+		return WriteConfig{}, fmt.Errorf("can't unmarshal settings of write config %s: %w", row.UID, err)
+	}
+	if row.SecureSettings != "" {
+		if err := json.Unmarshal([]byte(row.SecureSettings), &writeConfig.SecureSettings); err != nil {
+			return WriteConfig{}, fmt.Errorf("can't unmarshal secure settings of write config %s: %w", row.UID, err)
+		}
+	}
+	return writeConfig, nil
+}
@@ -0,0 +1,172 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func setupSQLStorage(t *testing.T) *SQLStorage {
+	t.Helper()
+	sqlStore := db.InitTestDB(t)
+	secretsService := secretsManager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	return NewSQLStorage(sqlStore, secretsService)
+}
+
+func TestIntegrationSQLStorageChannelRules(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	storage := setupSQLStorage(t)
+
+	settings := ChannelRuleSettings{
+		Converter: &ConverterConfig{Type: ConverterTypeJsonAuto},
+	}
+	rule, err := storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/test/x", Settings: settings})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, rule.Version)
+
+	t.Run("should list rules of the org", func(t *testing.T) {
+		rules, err := storage.ListChannelRules(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, []ChannelRule{rule}, rules)
+
+		rules, err = storage.ListChannelRules(ctx, 2)
+		require.NoError(t, err)
+		require.Empty(t, rules)
+	})
+
+	t.Run("should reject duplicate and invalid rules", func(t *testing.T) {
+		_, err := storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/test/x", Settings: settings})
+		require.Error(t, err)
+		_, err = storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "/stream/test/y"})
+		require.Error(t, err)
+		_, err = storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/test/y", Settings: ChannelRuleSettings{
+			Converter: &ConverterConfig{Type: "unknown"},
+		}})
+		require.Error(t, err)
+		_, err = storage.CreateChannelRule(ctx, 2, ChannelRuleCreateCmd{Pattern: "stream/:a/x", Settings: settings})
+		require.NoError(t, err)
+		_, err = storage.CreateChannelRule(ctx, 2, ChannelRuleCreateCmd{Pattern: "stream/:b/y", Settings: settings})
+		require.Error(t, err, "conflicting wildcards should be rejected")
+	})
+
+	t.Run("should update rule with current version", func(t *testing.T) {
+		settings := ChannelRuleSettings{
+			Converter: &ConverterConfig{Type: ConverterTypeJsonFrame},
+		}
+		updated, err := storage.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{Pattern: "stream/test/x", Settings: settings, Version: 1})
+		require.NoError(t, err)
+		require.EqualValues(t, 2, updated.Version)
+
+		_, err = storage.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{Pattern: "stream/test/x", Settings: settings, Version: 1})
+		require.ErrorIs(t, err, ErrVersionConflict)
+		_, err = storage.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{Pattern: "stream/test/z", Settings: settings, Version: 1})
+		require.ErrorIs(t, err, ErrVersionConflict)
+
+		rules, err := storage.ListChannelRules(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, []ChannelRule{updated}, rules)
+	})
+
+	t.Run("should update rule without version", func(t *testing.T) {
+		settings := ChannelRuleSettings{
+			Converter: &ConverterConfig{Type: ConverterTypeJsonFrame},
+		}
+		updated, err := storage.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{Pattern: "stream/test/x", Settings: settings})
+		require.NoError(t, err)
+		require.EqualValues(t, 3, updated.Version)
+
+		created, err := storage.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{Pattern: "stream/test/z", Settings: settings})
+		require.NoError(t, err)
+		require.EqualValues(t, 1, created.Version)
+	})
+
+	t.Run("should delete rule", func(t *testing.T) {
+		require.NoError(t, storage.DeleteChannelRule(ctx, 1, ChannelRuleDeleteCmd{Pattern: "stream/test/z"}))
+		require.ErrorIs(t, storage.DeleteChannelRule(ctx, 1, ChannelRuleDeleteCmd{Pattern: "stream/test/z"}), ErrChannelRuleNotFound)
+
+		rules, err := storage.ListChannelRules(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		require.Equal(t, "stream/test/x", rules[0].Pattern)
+	})
+}
+
+func TestIntegrationSQLStorageWriteConfigs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	storage := setupSQLStorage(t)
+
+	writeConfig, err := storage.CreateWriteConfig(ctx, 1, WriteConfigCreateCmd{
+		UID:            "remote",
+		Settings:       WriteSettings{Endpoint: "http://localhost:9090/api/v1/write", BasicAuth: &BasicAuth{User: "admin"}},
+		SecureSettings: map[string]string{"basicAuthPassword": "secret"},
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, writeConfig.Version)
+
+	t.Run("should encrypt secure settings", func(t *testing.T) {
+		stored, ok, err := storage.GetWriteConfig(ctx, 1, WriteConfigGetCmd{UID: "remote"})
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, writeConfig, stored)
+		require.NotEqual(t, "secret", string(stored.SecureSettings["basicAuthPassword"]))
+
+		decrypted, err := storage.secretsService.DecryptJsonData(ctx, stored.SecureSettings)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"basicAuthPassword": "secret"}, decrypted)
+	})
+
+	t.Run("should scope write configs by org", func(t *testing.T) {
+		_, ok, err := storage.GetWriteConfig(ctx, 2, WriteConfigGetCmd{UID: "remote"})
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		configs, err := storage.ListWriteConfigs(ctx, 2)
+		require.NoError(t, err)
+		require.Empty(t, configs)
+	})
+
+	t.Run("should reject duplicate and invalid write configs", func(t *testing.T) {
+		_, err := storage.CreateWriteConfig(ctx, 1, WriteConfigCreateCmd{UID: "remote", Settings: writeConfig.Settings})
+		require.Error(t, err)
+		_, err = storage.CreateWriteConfig(ctx, 1, WriteConfigCreateCmd{UID: "other"})
+		require.Error(t, err)
+	})
+
+	t.Run("should update write config with current version", func(t *testing.T) {
+		settings := WriteSettings{Endpoint: "http://localhost:9091/api/v1/write"}
+		updated, err := storage.UpdateWriteConfig(ctx, 1, WriteConfigUpdateCmd{UID: "remote", Settings: settings, Version: 1})
+		require.NoError(t, err)
+		require.EqualValues(t, 2, updated.Version)
+		require.Empty(t, updated.SecureSettings)
+
+		_, err = storage.UpdateWriteConfig(ctx, 1, WriteConfigUpdateCmd{UID: "remote", Settings: settings, Version: 1})
+		require.ErrorIs(t, err, ErrVersionConflict)
+
+		configs, err := storage.ListWriteConfigs(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, configs, 1)
+		require.Equal(t, settings, configs[0].Settings)
+		require.EqualValues(t, 2, configs[0].Version)
+	})
+
+	t.Run("should delete write config", func(t *testing.T) {
+		require.NoError(t, storage.DeleteWriteConfig(ctx, 1, WriteConfigDeleteCmd{UID: "remote"}))
+		require.ErrorIs(t, storage.DeleteWriteConfig(ctx, 1, WriteConfigDeleteCmd{UID: "remote"}), ErrWriteConfigNotFound)
+	})
+}
@@ -0,0 +1,248 @@
+package pushmqtt
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MQTT 3.1.1 control packet types, see
+// https://docs.oasis-open.org/mqtt/mqtt/v3.1.1/os/mqtt-v3.1.1-os.html#_Toc398718021
+const (
+	packetConnect     byte = 1
+	packetConnack     byte = 2
+	packetPublish     byte = 3
+	packetPuback      byte = 4
+	packetSubscribe   byte = 8
+	packetSuback      byte = 9
+	packetUnsubscribe byte = 10
+	packetUnsuback    byte = 11
+	packetPingreq     byte = 12
+	packetPingresp    byte = 13
+	packetDisconnect  byte = 14
+)
+
+// CONNACK return codes.
+const (
+	connackAccepted                    byte = 0
+	connackUnacceptableProtocolVersion byte = 1
+	connackBadUsernameOrPassword       byte = 4
+	connackNotAuthorized               byte = 5
+)
+
+// subackFailure is the SUBACK return code of a subscription that is not accepted.
+const subackFailure byte = 0x80
+
+var (
+	errMalformedPacket            = errors.New("malformed packet")
+	errPacketTooLarge             = errors.New("packet too large")
+	errUnsupportedProtocolVersion = errors.New("unsupported protocol version")
+)
+
+type packet struct {
+	kind  byte
+	flags byte
+	body  []byte
+}
+
+type connectPacket struct {
+	clientID    string
+	username    string
+	password    string
+	hasPassword bool
+	keepAlive   uint16
+}
+
+type publishPacket struct {
+	topic    string
+	qos      byte
+	packetID uint16
+	payload  []byte
+}
+
+// readPacket reads a control packet. Packets with a body larger than maxSize are rejected without reading them.
+func readPacket(r *bufio.Reader, maxSize int) (packet, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return packet{}, err
+	}
+	length, err := readRemainingLength(r)
+	if err != nil {
+		return packet{}, err
+	}
+	if length > maxSize {
+		return packet{}, fmt.Errorf("%w: %d bytes", errPacketTooLarge, length)
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return packet{}, err
+	}
+	return packet{kind: header >> 4, flags: header & 0x0f, body: body}, nil
+}
+
+func readRemainingLength(r *bufio.Reader) (int, error) {
+	length := 0
+	for i := 0; i < 4; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		length |= int(b&0x7f) << (7 * i)
+		if b&0x80 == 0 {
+			return length, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: invalid remaining length", errMalformedPacket)
+}
+
+func writePacket(w io.Writer, kind byte, flags byte, body []byte) error {
+	buf := make([]byte, 0, len(body)+5)
+	buf = append(buf, kind<<4|flags)
+	length := len(body)
+	for {
+		b := byte(length & 0x7f)
+		length >>= 7
+		if length > 0 {
+			b |= 0x80
+		}
+		buf = append(buf, b)
+		if length == 0 {
+			break
+		}
+	}
+	buf = append(buf, body...)
+	_, err := w.Write(buf)
+	return err
+}
+
+func writeConnack(w io.Writer, code byte) error {
+	return writePacket(w, packetConnack, 0, []byte{0, code})
+}
+
+func writePacketID(w io.Writer, kind byte, packetID uint16) error {
+	return writePacket(w, kind, 0, binary.BigEndian.AppendUint16(nil, packetID))
+}
+
+// packetReader decodes the fields of a packet body.
+type packetReader struct {
+	body []byte
+	err  error
+}
+
+func (r *packetReader) uint16() uint16 {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.body) < 2 {
+		r.err = errMalformedPacket
+		return 0
+	}
+	v := binary.BigEndian.Uint16(r.body)
+	r.body = r.body[2:]
+	return v
+}
+
+func (r *packetReader) byte() byte {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.body) < 1 {
+		r.err = errMalformedPacket
+		return 0
+	}
+	v := r.body[0]
+	r.body = r.body[1:]
+	return v
+}
+
+func (r *packetReader) bytes() []byte {
+	n := int(r.uint16())
+	if r.err != nil {
+		return nil
+	}
+	if len(r.body) < n {
+		r.err = errMalformedPacket
+		return nil
+	}
+	v := r.body[:n]
+	r.body = r.body[n:]
+	return v
+}
+
+func (r *packetReader) string() string {
+	return string(r.bytes())
+}
+
+func parseConnect(body []byte) (connectPacket, error) {
+	r := &packetReader{body: body}
+	protocol := r.string()
+	level := r.byte()
+	flags := r.byte()
+	keepAlive := r.uint16()
+	if r.err != nil {
+		return connectPacket{}, r.err
+	}
+	// MQTT 3.1 uses protocol name MQIsdp and level 3, MQTT 3.1.1 uses MQTT and level 4.
+	if !(protocol == "MQTT" && level == 4) && !(protocol == "MQIsdp" && level == 3) {
+		return connectPacket{}, fmt.Errorf("%w: %s %d", errUnsupportedProtocolVersion, protocol, level)
+	}
+	c := connectPacket{
+		clientID:  r.string(),
+		keepAlive: keepAlive,
+	}
+	if flags&0x04 != 0 {
+		// Will topic and message. The will is not published, since clients can only publish to channels.
+		r.bytes()
+		r.bytes()
+	}
+	if flags&0x80 != 0 {
+		c.username = r.string()
+	}
+	if flags&0x40 != 0 {
+		c.password = r.string()
+		c.hasPassword = true
+	}
+	if r.err != nil {
+		return connectPacket{}, r.err
+	}
+	return c, nil
+}
+
+func parsePublish(p packet) (publishPacket, error) {
+	r := &packetReader{body: p.body}
+	pub := publishPacket{
+		topic: r.string(),
+		qos:   (p.flags >> 1) & 0x03,
+	}
+	if pub.qos > 0 {
+		pub.packetID = r.uint16()
+	}
+	if r.err != nil {
+		return publishPacket{}, r.err
+	}
+	if pub.qos > 2 {
+		return publishPacket{}, fmt.Errorf("%w: invalid QoS %d", errMalformedPacket, pub.qos)
+	}
+	pub.payload = r.body
+	return pub, nil
+}
+
+// parseSubscribe returns the packet identifier and the number of topic filters of a SUBSCRIBE or UNSUBSCRIBE packet.
+func parseSubscribe(p packet) (uint16, int, error) {
+	r := &packetReader{body: p.body}
+	packetID := r.uint16()
+	filters := 0
+	for r.err == nil && len(r.body) > 0 {
+		r.bytes()
+		if p.kind == packetSubscribe {
+			r.byte()
+		}
+		filters++
+	}
+	if r.err != nil {
+		return 0, 0, r.err
+	}
+	return packetID, filters, nil
+}
@@ -0,0 +1,293 @@
+package pushmqtt
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	liveDto "github.com/grafana/grafana-plugin-sdk-go/live"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/live"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+var (
+	logger = log.New("live.push_mqtt")
+)
+
+const (
+	// connectTimeout is the time a client has to send CONNECT after opening the connection.
+	connectTimeout = 10 * time.Second
+	// maxHeaderSize is the maximum size of a PUBLISH packet without its payload.
+	maxHeaderSize = 4 * 1024
+)
+
+// Publisher pushes the data published to a channel to Live pipeline.
+type Publisher interface {
+	PublishToPipeline(ctx context.Context, user identity.Requester, channel string, data []byte) (bool, error)
+}
+
+// Authenticator authenticates MQTT clients.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (identity.Requester, error)
+}
+
+func ProvideService(cfg *setting.Cfg, live *live.GrafanaLive, authnService authn.Service) *Service {
+	return NewService(cfg.LiveMQTT, live, NewTokenAuthenticator(authnService))
+}
+
+func NewService(cfg setting.LiveMQTTSettings, publisher Publisher, authenticator Authenticator) *Service {
+	return &Service{
+		cfg:           cfg,
+		publisher:     publisher,
+		authenticator: authenticator,
+	}
+}
+
+// Service is an MQTT listener that pushes the messages published by clients to Live pipeline channels.
+// The topic of a message is the path of the channel in the stream scope, for example the messages
+// published to the topic "sensors/room1" are pushed to the channel "stream/sensors/room1". Payloads
+// are converted and processed according to the channel rules, exactly as the data pushed over HTTP
+// or WebSocket.
+//
+// Clients authenticate with a service account token as password and can only publish, subscriptions are
+// rejected. QoS 0 and 1 are supported.
+type Service struct {
+	cfg           setting.LiveMQTTSettings
+	publisher     Publisher
+	authenticator Authenticator
+}
+
+func (s *Service) IsDisabled() bool {
+	return !s.cfg.Enabled
+}
+
+// Run the MQTT listener until the context is cancelled.
+func (s *Service) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.cfg.ListenAddress)
+	if err != nil {
+		return fmt.Errorf("failed to start MQTT listener: %w", err)
+	}
+	if s.cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(s.cfg.CertFile, s.cfg.KeyFile)
+		if err != nil {
+			_ = listener.Close()
+			return fmt.Errorf("failed to load MQTT listener certificate: %w", err)
+		}
+		listener = tls.NewListener(listener, &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		})
+	}
+	logger.Info("MQTT listener started", "address", listener.Addr().String(), "tls", s.cfg.CertFile != "")
+	return s.Serve(ctx, listener)
+}
+
+// Serve accepts the client connections on the listener until the context is cancelled. It closes the listener and
+// all the connections before returning.
+func (s *Service) Serve(ctx context.Context, listener net.Listener) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		_ = listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to accept MQTT connection: %w", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.handleConn(ctx, conn)
+		}()
+	}
+}
+
+func (s *Service) handleConn(ctx context.Context, conn net.Conn) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+
+	l := logger.New("remoteAddr", conn.RemoteAddr().String())
+	r := bufio.NewReader(conn)
+	maxSize := s.cfg.MaxPayloadSize + maxHeaderSize
+
+	_ = conn.SetReadDeadline(time.Now().Add(connectTimeout))
+	p, err := readPacket(r, maxSize)
+	if err != nil {
+		l.Debug("Error reading CONNECT packet", "error", err)
+		return
+	}
+	if p.kind != packetConnect {
+		l.Debug("Expected CONNECT packet", "packetType", p.kind)
+		return
+	}
+	c, err := parseConnect(p.body)
+	if err != nil {
+		l.Debug("Invalid CONNECT packet", "error", err)
+		if errors.Is(err, errUnsupportedProtocolVersion) {
+			_ = writeConnack(conn, connackUnacceptableProtocolVersion)
+		}
+		return
+	}
+	if !c.hasPassword {
+		l.Debug("Client connected without a token", "clientId", c.clientID)
+		_ = writeConnack(conn, connackNotAuthorized)
+		return
+	}
+	user, err := s.authenticator.Authenticate(ctx, c.password)
+	if err != nil {
+		l.Info("Failed to authenticate MQTT client", "clientId", c.clientID, "error", err)
+		_ = writeConnack(conn, connackBadUsernameOrPassword)
+		return
+	}
+	if err := writeConnack(conn, connackAccepted); err != nil {
+		return
+	}
+	l = l.New("clientId", c.clientID, "orgId", user.GetOrgID(), "user", user.GetLogin())
+	l.Debug("MQTT client connected")
+
+	for {
+		// The client must send a packet within one and a half times the keep alive period.
+		deadline := time.Time{}
+		if c.keepAlive > 0 {
+			deadline = time.Now().Add(time.Duration(c.keepAlive) * time.Second * 3 / 2)
+		}
+		_ = conn.SetReadDeadline(deadline)
+
+		p, err := readPacket(r, maxSize)
+		if err != nil {
+			l.Debug("MQTT client disconnected", "error", err)
+			return
+		}
+		switch p.kind {
+		case packetPublish:
+			if err := s.handlePublish(ctx, l, conn, user, p); err != nil {
+				l.Warn("Closing MQTT connection", "error", err)
+				return
+			}
+		case packetSubscribe, packetUnsubscribe:
+			packetID, filters, err := parseSubscribe(p)
+			if err != nil {
+				l.Debug("Invalid packet", "packetType", p.kind, "error", err)
+				return
+			}
+			if p.kind == packetUnsubscribe {
+				err = writePacketID(conn, packetUnsuback, packetID)
+			} else {
+				// Subscriptions are not supported, clients can subscribe to channels over WebSocket.
+				body := binary.BigEndian.AppendUint16(nil, packetID)
+				err = writePacket(conn, packetSuback, 0, append(body, bytes.Repeat([]byte{subackFailure}, filters)...))
+			}
+			if err != nil {
+				return
+			}
+		case packetPingreq:
+			if err := writePacket(conn, packetPingresp, 0, nil); err != nil {
+				return
+			}
+		case packetDisconnect:
+			l.Debug("MQTT client disconnected")
+			return
+		default:
+			l.Debug("Unexpected packet", "packetType", p.kind)
+			return
+		}
+	}
+}
+
+// handlePublish pushes the payload of a PUBLISH packet to the channel of its topic. It returns an error if the
+// connection must be closed, which is how MQTT 3.1.1 tells clients that a publish is rejected.
+func (s *Service) handlePublish(ctx context.Context, l log.Logger, conn net.Conn, user identity.Requester, p packet) error {
+	pub, err := parsePublish(p)
+	if err != nil {
+		return err
+	}
+	if pub.qos == 2 {
+		return errors.New("QoS 2 is not supported")
+	}
+	if len(pub.payload) > s.cfg.MaxPayloadSize {
+		return fmt.Errorf("%w: payload of %d bytes", errPacketTooLarge, len(pub.payload))
+	}
+	channel, err := topicToChannel(pub.topic)
+	if err != nil {
+		return err
+	}
+	l.Debug("Live channel push request",
+		"protocol", "mqtt",
+		"channel", channel,
+		"bodyLength", len(pub.payload),
+	)
+	ruleFound, err := s.publisher.PublishToPipeline(ctx, user, channel, pub.payload)
+	if err != nil {
+		return fmt.Errorf("failed to push to channel %s: %w", channel, err)
+	}
+	if !ruleFound {
+		return fmt.Errorf("no channel rule for channel %s", channel)
+	}
+	if pub.qos == 1 {
+		return writePacketID(conn, packetPuback, pub.packetID)
+	}
+	return nil
+}
+
+// topicToChannel returns the stream scope channel of a topic. The topic can be the path of the channel or the
+// channel itself.
+func topicToChannel(topic string) (string, error) {
+	channel := liveDto.ScopeStream + "/" + strings.TrimPrefix(topic, liveDto.ScopeStream+"/")
+	addr, err := liveDto.ParseChannel(channel)
+	if err != nil || !addr.IsValid() {
+		return "", fmt.Errorf("invalid topic %q: %w", topic, liveDto.ErrInvalidChannelID)
+	}
+	return channel, nil
+}
+
+// NewTokenAuthenticator returns an Authenticator that accepts service account tokens.
+func NewTokenAuthenticator(authenticator authn.Authenticator) Authenticator {
+	return &tokenAuthenticator{authenticator: authenticator}
+}
+
+type tokenAuthenticator struct {
+	authenticator authn.Authenticator
+}
+
+func (a *tokenAuthenticator) Authenticate(ctx context.Context, token string) (identity.Requester, error) {
+	// Service account tokens are authenticated from the Authorization header of HTTP requests.
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	user, err := a.authenticator.Authenticate(ctx, &authn.Request{HTTPRequest: req})
+	if err != nil {
+		return nil, err
+	}
+	if kind, _ := user.GetTypedID(); kind != identity.TypeServiceAccount {
+		return nil, fmt.Errorf("expected a service account token, got %s", kind)
+	}
+	return user, nil
+}
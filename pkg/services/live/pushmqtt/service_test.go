@@ -0,0 +1,256 @@
+package pushmqtt
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/authn/authntest"
+	"github.com/grafana/grafana/pkg/services/live"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type publication struct {
+	orgID   int64
+	channel string
+	data    string
+}
+
+type fakePublisher struct {
+	mu           sync.Mutex
+	publications []publication
+	channels     map[string]error
+}
+
+func (f *fakePublisher) PublishToPipeline(_ context.Context, user identity.Requester, channel string, data []byte) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	err, ok := f.channels[channel]
+	if !ok {
+		return false, nil
+	}
+	if err != nil {
+		return true, err
+	}
+	f.publications = append(f.publications, publication{orgID: user.GetOrgID(), channel: channel, data: string(data)})
+	return true, nil
+}
+
+func (f *fakePublisher) Publications() []publication {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.publications
+}
+
+type fakeAuthenticator struct{}
+
+func (f *fakeAuthenticator) Authenticate(_ context.Context, token string) (identity.Requester, error) {
+	if token != "glsa_token" {
+		return nil, errors.New("invalid token")
+	}
+	return &user.SignedInUser{OrgID: 2, Login: "sa-device"}, nil
+}
+
+type testClient struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func appendString(b []byte, s string) []byte {
+	return append(binary.BigEndian.AppendUint16(b, uint16(len(s))), s...)
+}
+
+func (c *testClient) connect(protocol string, level byte, password *string) packet {
+	body := appendString(nil, protocol)
+	flags := byte(0x02)
+	if password != nil {
+		flags |= 0x80 | 0x40
+	}
+	body = append(body, level, flags, 0, 60)
+	body = appendString(body, "device-1")
+	if password != nil {
+		body = appendString(body, "api_key")
+		body = appendString(body, *password)
+	}
+	c.write(packetConnect, 0, body)
+	return c.read()
+}
+
+func (c *testClient) publish(topic string, qos byte, packetID uint16, payload string) {
+	body := appendString(nil, topic)
+	if qos > 0 {
+		body = binary.BigEndian.AppendUint16(body, packetID)
+	}
+	c.write(packetPublish, qos<<1, append(body, payload...))
+}
+
+func (c *testClient) write(kind byte, flags byte, body []byte) {
+	require.NoError(c.t, writePacket(c.conn, kind, flags, body))
+}
+
+func (c *testClient) read() packet {
+	_ = c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	p, err := readPacket(c.r, 1024)
+	require.NoError(c.t, err)
+	return p
+}
+
+func (c *testClient) requireClosed() {
+	_ = c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err := c.r.ReadByte()
+	require.Error(c.t, err)
+	var netErr net.Error
+	require.False(c.t, errors.As(err, &netErr) && netErr.Timeout(), "expected the connection to be closed")
+}
+
+func setupService(t *testing.T) (*fakePublisher, func() *testClient) {
+	t.Helper()
+	publisher := &fakePublisher{channels: map[string]error{
+		"stream/sensors/room1":     nil,
+		"stream/sensors/forbidden": live.ErrPipelinePublishForbidden,
+	}}
+	svc := NewService(setting.LiveMQTTSettings{Enabled: true, MaxPayloadSize: 16}, publisher, &fakeAuthenticator{})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- svc.Serve(ctx, listener)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		require.ErrorIs(t, <-done, context.Canceled)
+	})
+
+	return publisher, func() *testClient {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = conn.Close() })
+		return &testClient{t: t, conn: conn, r: bufio.NewReader(conn)}
+	}
+}
+
+func TestService(t *testing.T) {
+	token := "glsa_token"
+
+	t.Run("should push published messages to the channels of their topics", func(t *testing.T) {
+		publisher, dial := setupService(t)
+		c := dial()
+		require.Equal(t, packet{kind: packetConnack, body: []byte{0, connackAccepted}}, c.connect("MQTT", 4, &token))
+
+		c.publish("sensors/room1", 0, 0, `{"value":1}`)
+		c.publish("stream/sensors/room1", 1, 7, `{"value":2}`)
+		require.Equal(t, packet{kind: packetPuback, body: []byte{0, 7}}, c.read())
+
+		c.write(packetPingreq, 0, nil)
+		require.Equal(t, packet{kind: packetPingresp, body: []byte{}}, c.read())
+
+		require.Equal(t, []publication{
+			{orgID: 2, channel: "stream/sensors/room1", data: `{"value":1}`},
+			{orgID: 2, channel: "stream/sensors/room1", data: `{"value":2}`},
+		}, publisher.Publications())
+
+		c.write(packetDisconnect, 0, nil)
+		c.requireClosed()
+	})
+
+	t.Run("should reject subscriptions", func(t *testing.T) {
+		_, dial := setupService(t)
+		c := dial()
+		c.connect("MQTT", 4, &token)
+
+		body := binary.BigEndian.AppendUint16(nil, 3)
+		body = append(appendString(body, "sensors/#"), 0)
+		body = append(appendString(body, "other"), 1)
+		c.write(packetSubscribe, 0x02, body)
+		require.Equal(t, packet{kind: packetSuback, body: []byte{0, 3, subackFailure, subackFailure}}, c.read())
+	})
+
+	t.Run("should accept MQTT 3.1 clients", func(t *testing.T) {
+		_, dial := setupService(t)
+		require.Equal(t, packet{kind: packetConnack, body: []byte{0, connackAccepted}}, dial().connect("MQIsdp", 3, &token))
+	})
+
+	t.Run("should refuse connection", func(t *testing.T) {
+		invalid := "invalid"
+		testCases := []struct {
+			name     string
+			protocol string
+			level    byte
+			password *string
+			code     byte
+		}{
+			{name: "without token", protocol: "MQTT", level: 4, code: connackNotAuthorized},
+			{name: "with invalid token", protocol: "MQTT", level: 4, password: &invalid, code: connackBadUsernameOrPassword},
+			{name: "with MQTT 5", protocol: "MQTT", level: 5, password: &token, code: connackUnacceptableProtocolVersion},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				_, dial := setupService(t)
+				c := dial()
+				require.Equal(t, packet{kind: packetConnack, body: []byte{0, tc.code}}, c.connect(tc.protocol, tc.level, tc.password))
+				c.requireClosed()
+			})
+		}
+	})
+
+	t.Run("should close connection if publish is rejected", func(t *testing.T) {
+		testCases := []struct {
+			name    string
+			topic   string
+			qos     byte
+			payload string
+		}{
+			{name: "no channel rule", topic: "sensors/unknown", payload: "{}"},
+			{name: "forbidden", topic: "sensors/forbidden", payload: "{}"},
+			{name: "invalid topic", topic: "sensors/room 1", payload: "{}"},
+			{name: "payload too large", topic: "sensors/room1", payload: `{"value":"too large"}`},
+			{name: "QoS 2", topic: "sensors/room1", qos: 2, payload: "{}"},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				publisher, dial := setupService(t)
+				c := dial()
+				c.connect("MQTT", 4, &token)
+				c.publish(tc.topic, tc.qos, 1, tc.payload)
+				c.requireClosed()
+				require.Empty(t, publisher.Publications())
+			})
+		}
+	})
+}
+
+func TestTokenAuthenticator(t *testing.T) {
+	t.Run("should authenticate service account tokens", func(t *testing.T) {
+		id := &authn.Identity{ID: identity.NewTypedID(identity.TypeServiceAccount, 1), OrgID: 2}
+		authenticator := NewTokenAuthenticator(&authntest.FakeService{ExpectedIdentity: id})
+		user, err := authenticator.Authenticate(context.Background(), "glsa_token")
+		require.NoError(t, err)
+		require.Equal(t, id, user)
+	})
+
+	t.Run("should not authenticate other identities", func(t *testing.T) {
+		id := &authn.Identity{ID: identity.NewTypedID(identity.TypeUser, 1), OrgID: 2}
+		authenticator := NewTokenAuthenticator(&authntest.FakeService{ExpectedIdentity: id})
+		_, err := authenticator.Authenticate(context.Background(), "glsa_token")
+		require.Error(t, err)
+	})
+
+	t.Run("should return authentication error", func(t *testing.T) {
+		authenticator := NewTokenAuthenticator(&authntest.FakeService{ExpectedErr: errors.New("invalid API key")})
+		_, err := authenticator.Authenticate(context.Background(), "glsa_token")
+		require.Error(t, err)
+	})
+}
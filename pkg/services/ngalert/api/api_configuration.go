@@ -14,6 +14,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/cost"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/org"
@@ -26,6 +27,13 @@ type ConfigSrv struct {
 	store                store.AdminConfigurationStore
 	log                  log.Logger
 	featureManager       featuremgmt.FeatureToggles
+	costs                EvaluationCostReader
+}
+
+// EvaluationCostReader provides the evaluation cost of alert rules.
+type EvaluationCostReader interface {
+	OrgCost(orgID int64) cost.OrgCost
+	RuleCosts(orgID int64) []cost.RuleCost
 }
 
 func (srv ConfigSrv) RouteGetAlertmanagers(c *contextmodel.ReqContext) response.Response {
@@ -45,6 +53,36 @@ func (srv ConfigSrv) RouteGetAlertmanagers(c *contextmodel.ReqContext) response.
 	})
 }
 
+func (srv ConfigSrv) RouteGetEvaluationCost(c *contextmodel.ReqContext) response.Response {
+	orgID := c.SignedInUser.GetOrgID()
+	orgCost := srv.costs.OrgCost(orgID)
+	result := apimodels.GettableEvaluationCost{
+		CostSecondsPerMinute:   orgCost.PerMinute.Seconds(),
+		BudgetSecondsPerMinute: orgCost.Budget.Seconds(),
+		IntervalMultiplier:     orgCost.IntervalMultiplier,
+		Rules:                  []apimodels.RuleEvaluationCost{},
+	}
+	for _, rc := range srv.costs.RuleCosts(orgID) {
+		result.Rules = append(result.Rules, apimodels.RuleEvaluationCost{
+			UID:                         rc.UID,
+			Title:                       rc.Title,
+			FolderUID:                   rc.NamespaceUID,
+			RuleGroup:                   rc.RuleGroup,
+			IntervalSeconds:             rc.IntervalSeconds,
+			CostSecondsPerMinute:        rc.PerMinute().Seconds(),
+			Evaluations:                 rc.Evaluations,
+			LastEvaluation:              rc.LastEvaluation,
+			LastDurationSeconds:         rc.Last.Duration.Seconds(),
+			LastQueryDurationSeconds:    rc.Last.QueryDuration.Seconds(),
+			LastSeries:                  rc.Last.Series,
+			AverageDurationSeconds:      rc.AvgDuration.Seconds(),
+			AverageQueryDurationSeconds: rc.AvgQueryDuration.Seconds(),
+			AverageSeries:               rc.AvgSeries,
+		})
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
 func (srv ConfigSrv) RouteGetNGalertConfig(c *contextmodel.ReqContext) response.Response {
 	if c.SignedInUser.GetOrgRole() != org.RoleAdmin {
 		return accessForbiddenResp()
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -12,6 +13,9 @@ import (
 	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/cost"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/org"
 )
@@ -133,3 +137,57 @@ func createAPIAdminSut(t *testing.T,
 		featureManager: features,
 	}
 }
+
+type fakeEvaluationCosts struct {
+	orgs  map[int64]cost.OrgCost
+	rules map[int64][]cost.RuleCost
+}
+
+func (f fakeEvaluationCosts) OrgCost(orgID int64) cost.OrgCost {
+	return f.orgs[orgID]
+}
+
+func (f fakeEvaluationCosts) RuleCosts(orgID int64) []cost.RuleCost {
+	return f.rules[orgID]
+}
+
+func TestRouteGetEvaluationCost(t *testing.T) {
+	costs := fakeEvaluationCosts{
+		orgs: map[int64]cost.OrgCost{1: {PerMinute: 3 * time.Second, Budget: time.Second, IntervalMultiplier: 3}},
+		rules: map[int64][]cost.RuleCost{1: {{
+			AlertRuleKey:    models.AlertRuleKey{OrgID: 1, UID: "rule"},
+			Title:           "Rule",
+			NamespaceUID:    "folder",
+			RuleGroup:       "group",
+			IntervalSeconds: 30,
+			Evaluations:     2,
+			Last:            eval.EvaluationCost{Duration: 2 * time.Second, QueryDuration: time.Second, Series: 5},
+			AvgDuration:     1500 * time.Millisecond,
+			AvgSeries:       4,
+		}}},
+	}
+	srv := ConfigSrv{costs: costs}
+
+	t.Run("returns the cost of the organization and its rules", func(t *testing.T) {
+		resp := srv.RouteGetEvaluationCost(createRequestCtxInOrg(1))
+		require.Equal(t, http.StatusOK, resp.Status())
+
+		var result definitions.GettableEvaluationCost
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.Equal(t, 3.0, result.CostSecondsPerMinute)
+		require.Equal(t, 1.0, result.BudgetSecondsPerMinute)
+		require.EqualValues(t, 3, result.IntervalMultiplier)
+		require.Len(t, result.Rules, 1)
+		require.Equal(t, "rule", result.Rules[0].UID)
+		require.Equal(t, "folder", result.Rules[0].FolderUID)
+		require.Equal(t, 3.0, result.Rules[0].CostSecondsPerMinute)
+		require.Equal(t, 1.0, result.Rules[0].LastQueryDurationSeconds)
+		require.Equal(t, 5, result.Rules[0].LastSeries)
+	})
+
+	t.Run("returns empty list of rules if the organization has no rules", func(t *testing.T) {
+		resp := srv.RouteGetEvaluationCost(createRequestCtxInOrg(2))
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.JSONEq(t, `{"costSecondsPerMinute":0,"budgetSecondsPerMinute":0,"intervalMultiplier":0,"rules":[]}`, string(resp.Body()))
+	})
+}
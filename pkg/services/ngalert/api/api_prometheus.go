@@ -25,10 +25,11 @@ import (
 )
 
 type PrometheusSrv struct {
-	log     log.Logger
-	manager state.AlertInstanceManager
-	store   RuleStore
-	authz   RuleAccessControlService
+	log          log.Logger
+	manager      state.AlertInstanceManager
+	acknowledger AlertInstanceAcknowledger
+	store        RuleStore
+	authz        RuleAccessControlService
 }
 
 const queryIncludeInternalLabels = "includeInternalLabels"
@@ -102,15 +103,30 @@ func PrepareAlertStatuses(manager state.AlertInstanceManager, opts AlertStatuses
 
 			// TODO: or should we make this two fields? Using one field lets the
 			// frontend use the same logic for parsing text on annotations and this.
-			State:    state.FormatStateAndReason(alertState.State, alertState.StateReason),
-			ActiveAt: &startsAt,
-			Value:    valString,
+			State:           state.FormatStateAndReason(alertState.State, alertState.StateReason),
+			ActiveAt:        &startsAt,
+			Value:           valString,
+			InhibitedBy:     alertState.InhibitedBy,
+			Fingerprint:     alertState.CacheID.String(),
+			Acknowledgement: toAlertAcknowledgement(alertState.Acknowledgement),
 		})
 	}
 
 	return alertResponse
 }
 
+func toAlertAcknowledgement(ack *ngmodels.Acknowledgement) *apimodels.AlertAcknowledgement {
+	if ack == nil {
+		return nil
+	}
+	return &apimodels.AlertAcknowledgement{
+		By:        ack.By,
+		Comment:   ack.Comment,
+		At:        ack.At,
+		ExpiresAt: ack.ExpiresAt,
+	}
+}
+
 func formatValues(alertState *state.State) string {
 	var fv string
 	values := alertState.GetLastEvaluationValuesForCondition()
@@ -174,6 +190,8 @@ func getStatesFromQuery(v url.Values) ([]eval.State, error) {
 			states = append(states, eval.Pending)
 		case "nodata":
 			states = append(states, eval.NoData)
+		case "inhibited":
+			states = append(states, eval.Inhibited)
 		// nolint:goconst
 		case "error":
 			states = append(states, eval.Error)
@@ -449,6 +467,7 @@ func toRuleGroup(log log.Logger, manager state.AlertInstanceManager, groupKey ng
 			Query:       ruleToQuery(log, rule),
 			Duration:    rule.For.Seconds(),
 			Annotations: apimodels.LabelsFromMap(rule.Annotations),
+			DependsOn:   rule.DependsOn,
 		}
 
 		newRule := apimodels.Rule{
@@ -480,9 +499,12 @@ func toRuleGroup(log log.Logger, manager state.AlertInstanceManager, groupKey ng
 
 				// TODO: or should we make this two fields? Using one field lets the
 				// frontend use the same logic for parsing text on annotations and this.
-				State:    state.FormatStateAndReason(alertState.State, alertState.StateReason),
-				ActiveAt: &activeAt,
-				Value:    valString,
+				State:           state.FormatStateAndReason(alertState.State, alertState.StateReason),
+				ActiveAt:        &activeAt,
+				Value:           valString,
+				InhibitedBy:     alertState.InhibitedBy,
+				Fingerprint:     alertState.CacheID.String(),
+				Acknowledgement: toAlertAcknowledgement(alertState.Acknowledgement),
 			}
 
 			if alertState.LastEvaluationTime.After(newRule.LastEvaluation) {
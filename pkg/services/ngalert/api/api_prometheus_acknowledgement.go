@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// AlertInstanceAcknowledger sets and removes the acknowledgements of firing alert instances.
+type AlertInstanceAcknowledger interface {
+	Acknowledge(ctx context.Context, rule *ngmodels.AlertRule, cacheID data.Fingerprint, ack *ngmodels.Acknowledgement) (*state.State, error)
+}
+
+// RoutePostAlertAcknowledgement acknowledges the firing alert instance of the rule on behalf of the signed in user.
+func (srv PrometheusSrv) RoutePostAlertAcknowledgement(c *contextmodel.ReqContext, body apimodels.PostableAlertAcknowledgement, ruleUID string, fingerprint string) response.Response {
+	return srv.acknowledge(c, ruleUID, fingerprint, &ngmodels.Acknowledgement{
+		By:        c.SignedInUser.GetLogin(),
+		Comment:   body.Comment,
+		ExpiresAt: body.ExpiresAt,
+	})
+}
+
+// RouteDeleteAlertAcknowledgement removes the acknowledgement of the firing alert instance of the rule.
+func (srv PrometheusSrv) RouteDeleteAlertAcknowledgement(c *contextmodel.ReqContext, ruleUID string, fingerprint string) response.Response {
+	return srv.acknowledge(c, ruleUID, fingerprint, nil)
+}
+
+func (srv PrometheusSrv) acknowledge(c *contextmodel.ReqContext, ruleUID string, fingerprint string, ack *ngmodels.Acknowledgement) response.Response {
+	cacheID, err := strconv.ParseUint(fingerprint, 16, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid fingerprint")
+	}
+	rule, err := srv.store.GetAlertRuleByUID(c.Req.Context(), &ngmodels.GetAlertRuleByUIDQuery{
+		UID:   ruleUID,
+		OrgID: c.SignedInUser.GetOrgID(),
+	})
+	if err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rule")
+	}
+	if err := srv.authz.AuthorizeAccessInFolder(c.Req.Context(), c.SignedInUser, rule); err != nil {
+		return errorToResponse(err)
+	}
+
+	s, err := srv.acknowledger.Acknowledge(c.Req.Context(), rule, data.Fingerprint(cacheID), ack)
+	if err != nil {
+		if errors.Is(err, ngmodels.ErrInvalidAcknowledgement) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return errorToResponse(err)
+	}
+
+	valString := ""
+	if s.State == eval.Alerting {
+		valString = formatValues(s)
+	}
+	activeAt := s.StartsAt
+	return response.JSON(http.StatusOK, apimodels.Alert{
+		Labels:          apimodels.LabelsFromMap(s.GetLabels(ngmodels.WithoutInternalLabels())),
+		Annotations:     apimodels.LabelsFromMap(s.Annotations),
+		State:           state.FormatStateAndReason(s.State, s.StateReason),
+		ActiveAt:        &activeAt,
+		Value:           valString,
+		Fingerprint:     s.CacheID.String(),
+		Acknowledgement: toAlertAcknowledgement(s.Acknowledgement),
+	})
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+type fakeAcknowledger struct {
+	state *state.State
+	err   error
+	calls []*ngmodels.Acknowledgement
+}
+
+func (f *fakeAcknowledger) Acknowledge(_ context.Context, rule *ngmodels.AlertRule, cacheID data.Fingerprint, ack *ngmodels.Acknowledgement) (*state.State, error) {
+	f.calls = append(f.calls, ack)
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.state.AlertRuleUID != rule.UID || f.state.CacheID != cacheID {
+		return nil, ngmodels.ErrAlertInstanceNotFound.Errorf("")
+	}
+	f.state.Acknowledgement = ack
+	return f.state, nil
+}
+
+func TestRoutePostAlertAcknowledgement(t *testing.T) {
+	orgID := int64(1)
+	gen := ngmodels.RuleGen
+	rule := gen.With(gen.WithOrgID(orgID)).GenerateRef()
+	ruleStore := fakes.NewRuleStore(t)
+	ruleStore.PutRule(context.Background(), rule)
+
+	setup := func(err error) (*fakeAcknowledger, PrometheusSrv) {
+		acknowledger := &fakeAcknowledger{
+			state: &state.State{
+				OrgID:        orgID,
+				AlertRuleUID: rule.UID,
+				CacheID:      data.Fingerprint(0xabc),
+				State:        eval.Alerting,
+				StateReason:  ngmodels.StateReasonAcknowledged,
+				Labels:       data.Labels{"job": "api"},
+			},
+			err: err,
+		}
+		return acknowledger, PrometheusSrv{
+			log:          log.NewNopLogger(),
+			acknowledger: acknowledger,
+			store:        ruleStore,
+			authz:        &fakeRuleAccessControlService{},
+		}
+	}
+	ctx := func() *contextmodel.ReqContext {
+		req, err := http.NewRequest(http.MethodPost, "https://grafana.net", nil)
+		require.NoError(t, err)
+		return &contextmodel.ReqContext{Context: &web.Context{Req: req}, SignedInUser: &user.SignedInUser{OrgID: orgID, Login: "admin"}}
+	}
+	fingerprint := data.Fingerprint(0xabc).String()
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	t.Run("should acknowledge alert instance on behalf of the user", func(t *testing.T) {
+		acknowledger, srv := setup(nil)
+		response := srv.RoutePostAlertAcknowledgement(ctx(), apimodels.PostableAlertAcknowledgement{Comment: "on it", ExpiresAt: expiresAt}, rule.UID, fingerprint)
+		require.Equalf(t, http.StatusOK, response.Status(), string(response.Body()))
+
+		require.Len(t, acknowledger.calls, 1)
+		require.Equal(t, &ngmodels.Acknowledgement{By: "admin", Comment: "on it", ExpiresAt: expiresAt}, acknowledger.calls[0])
+
+		result := apimodels.Alert{}
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Equal(t, fingerprint, result.Fingerprint)
+		require.Equal(t, "Alerting (Acknowledged)", result.State)
+		require.Equal(t, &apimodels.AlertAcknowledgement{By: "admin", Comment: "on it", ExpiresAt: expiresAt}, result.Acknowledgement)
+	})
+
+	t.Run("should remove acknowledgement", func(t *testing.T) {
+		acknowledger, srv := setup(nil)
+		response := srv.RouteDeleteAlertAcknowledgement(ctx(), rule.UID, fingerprint)
+		require.Equalf(t, http.StatusOK, response.Status(), string(response.Body()))
+		require.Equal(t, []*ngmodels.Acknowledgement{nil}, acknowledger.calls)
+	})
+
+	testCases := []struct {
+		name        string
+		ruleUID     string
+		fingerprint string
+		err         error
+		status      int
+	}{
+		{name: "invalid fingerprint", ruleUID: rule.UID, fingerprint: "not-a-fingerprint", status: http.StatusBadRequest},
+		{name: "rule does not exist", ruleUID: "unknown", fingerprint: fingerprint, status: http.StatusNotFound},
+		{name: "instance does not exist", ruleUID: rule.UID, fingerprint: data.Fingerprint(1).String(), status: http.StatusNotFound},
+		{name: "instance is not firing", ruleUID: rule.UID, fingerprint: fingerprint, err: ngmodels.ErrAlertInstanceNotFiring.Errorf(""), status: http.StatusBadRequest},
+		{name: "invalid acknowledgement", ruleUID: rule.UID, fingerprint: fingerprint, err: fmt.Errorf("%w: expiry must be in the future", ngmodels.ErrInvalidAcknowledgement), status: http.StatusBadRequest},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("should return %d if %s", tc.status, tc.name), func(t *testing.T) {
+			_, srv := setup(tc.err)
+			response := srv.RoutePostAlertAcknowledgement(ctx(), apimodels.PostableAlertAcknowledgement{ExpiresAt: expiresAt}, tc.ruleUID, tc.fingerprint)
+			require.Equalf(t, tc.status, response.Status(), string(response.Body()))
+		})
+	}
+}
@@ -130,6 +130,7 @@ func TestRouteGetAlertStatuses(t *testing.T) {
 			},
 			"state": "Normal",
 			"activeAt": "0001-01-01T00:00:00Z",
+			"fingerprint": "0000000000000000",
 			"value": ""
 		}, {
 			"labels": {
@@ -142,6 +143,7 @@ func TestRouteGetAlertStatuses(t *testing.T) {
 			},
 			"state": "Normal",
 			"activeAt": "0001-01-01T00:00:00Z",
+			"fingerprint": "0000000000000000",
 			"value": ""
 		}]
 	}
@@ -172,6 +174,7 @@ func TestRouteGetAlertStatuses(t *testing.T) {
 			},
 			"state": "Alerting",
 			"activeAt": "0001-01-01T00:00:00Z",
+			"fingerprint": "0000000000000000",
 			"value": "1.1e+00"
 		}, {
 			"labels": {
@@ -184,6 +187,7 @@ func TestRouteGetAlertStatuses(t *testing.T) {
 			},
 			"state": "Alerting",
 			"activeAt": "0001-01-01T00:00:00Z",
+			"fingerprint": "0000000000000000",
 			"value": "1.1e+00"
 		}]
 	}
@@ -216,6 +220,7 @@ func TestRouteGetAlertStatuses(t *testing.T) {
 			},
 			"state": "Normal",
 			"activeAt": "0001-01-01T00:00:00Z",
+			"fingerprint": "0000000000000000",
 			"value": ""
 		}, {
 			"labels": {
@@ -230,6 +235,7 @@ func TestRouteGetAlertStatuses(t *testing.T) {
 			},
 			"state": "Normal",
 			"activeAt": "0001-01-01T00:00:00Z",
+			"fingerprint": "0000000000000000",
 			"value": ""
 		}]
 	}
@@ -328,6 +334,7 @@ func TestRouteGetRuleStatuses(t *testing.T) {
 					},
 					"state": "Normal",
 					"activeAt": "0001-01-01T00:00:00Z",
+					"fingerprint": "0000000000000000",
 					"value": ""
 				}],
 				"totals": {
@@ -393,6 +400,7 @@ func TestRouteGetRuleStatuses(t *testing.T) {
 					},
 					"state": "Normal",
 					"activeAt": "0001-01-01T00:00:00Z",
+					"fingerprint": "0000000000000000",
 					"value": ""
 				}],
 				"totals": {
@@ -453,6 +461,7 @@ func TestRouteGetRuleStatuses(t *testing.T) {
 					},
 					"state": "Normal",
 					"activeAt": "0001-01-01T00:00:00Z",
+					"fingerprint": "0000000000000000",
 					"value": ""
 				}],
 				"totals": {
@@ -30,6 +30,7 @@ type ProvisioningSrv struct {
 	contactPointService ContactPointService
 	templates           TemplateService
 	muteTimings         MuteTimingService
+	maintenanceWindows  MaintenanceWindowService
 	alertRules          AlertRuleService
 	folderSvc           folder.Service
 
@@ -64,6 +65,14 @@ type MuteTimingService interface {
 	DeleteMuteTiming(ctx context.Context, name string, orgID int64, provenance definitions.Provenance, version string) error
 }
 
+type MaintenanceWindowService interface {
+	GetMaintenanceWindows(ctx context.Context, orgID int64) ([]definitions.MaintenanceWindow, error)
+	GetMaintenanceWindow(ctx context.Context, uid string, orgID int64) (definitions.MaintenanceWindow, error)
+	CreateMaintenanceWindow(ctx context.Context, mw definitions.MaintenanceWindow, orgID int64) (definitions.MaintenanceWindow, error)
+	UpdateMaintenanceWindow(ctx context.Context, mw definitions.MaintenanceWindow, orgID int64) (definitions.MaintenanceWindow, error)
+	DeleteMaintenanceWindow(ctx context.Context, uid string, orgID int64, provenance definitions.Provenance, version int64) error
+}
+
 type AlertRuleService interface {
 	GetAlertRules(ctx context.Context, user identity.Requester) ([]*alerting_models.AlertRule, map[string]alerting_models.Provenance, error)
 	GetAlertRule(ctx context.Context, user identity.Requester, ruleUID string) (alerting_models.AlertRule, alerting_models.Provenance, error)
@@ -318,6 +327,66 @@ func (srv *ProvisioningSrv) RouteDeleteMuteTiming(c *contextmodel.ReqContext, na
 	return response.JSON(http.StatusNoContent, nil)
 }
 
+func (srv *ProvisioningSrv) RouteGetMaintenanceWindows(c *contextmodel.ReqContext) response.Response {
+	windows, err := srv.maintenanceWindows.GetMaintenanceWindows(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get maintenance windows", err)
+	}
+	return response.JSON(http.StatusOK, windows)
+}
+
+func (srv *ProvisioningSrv) RouteGetMaintenanceWindow(c *contextmodel.ReqContext, UID string) response.Response {
+	window, err := srv.maintenanceWindows.GetMaintenanceWindow(c.Req.Context(), UID, c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get maintenance window", err)
+	}
+	return response.JSON(http.StatusOK, window)
+}
+
+func (srv *ProvisioningSrv) RouteGetMaintenanceWindowsExport(c *contextmodel.ReqContext) response.Response {
+	windows, err := srv.maintenanceWindows.GetMaintenanceWindows(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get maintenance windows", err)
+	}
+	return exportMaintenanceWindowsResponse(c, windows)
+}
+
+func (srv *ProvisioningSrv) RouteGetMaintenanceWindowExport(c *contextmodel.ReqContext, UID string) response.Response {
+	window, err := srv.maintenanceWindows.GetMaintenanceWindow(c.Req.Context(), UID, c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get maintenance window", err)
+	}
+	return exportMaintenanceWindowsResponse(c, []definitions.MaintenanceWindow{window})
+}
+
+func (srv *ProvisioningSrv) RoutePostMaintenanceWindow(c *contextmodel.ReqContext, mw definitions.MaintenanceWindow) response.Response {
+	mw.Provenance = determineProvenance(c)
+	created, err := srv.maintenanceWindows.CreateMaintenanceWindow(c.Req.Context(), mw, c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to create maintenance window", err)
+	}
+	return response.JSON(http.StatusCreated, created)
+}
+
+func (srv *ProvisioningSrv) RoutePutMaintenanceWindow(c *contextmodel.ReqContext, mw definitions.MaintenanceWindow, UID string) response.Response {
+	mw.UID = UID
+	mw.Provenance = determineProvenance(c)
+	updated, err := srv.maintenanceWindows.UpdateMaintenanceWindow(c.Req.Context(), mw, c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to update maintenance window", err)
+	}
+	return response.JSON(http.StatusAccepted, updated)
+}
+
+func (srv *ProvisioningSrv) RouteDeleteMaintenanceWindow(c *contextmodel.ReqContext, UID string) response.Response {
+	version := c.QueryInt64("version")
+	err := srv.maintenanceWindows.DeleteMaintenanceWindow(c.Req.Context(), UID, c.SignedInUser.GetOrgID(), determineProvenance(c), version)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to delete maintenance window", err)
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
+
 func (srv *ProvisioningSrv) RouteGetAlertRules(c *contextmodel.ReqContext) response.Response {
 	rules, provenances, err := srv.alertRules.GetAlertRules(c.Req.Context(), c.SignedInUser)
 	if err != nil {
@@ -415,6 +484,9 @@ func (srv *ProvisioningSrv) RoutePutAlertRule(c *contextmodel.ReqContext, ar def
 func (srv *ProvisioningSrv) RouteDeleteAlertRule(c *contextmodel.ReqContext, UID string) response.Response {
 	provenance := determineProvenance(c)
 	err := srv.alertRules.DeleteAlertRule(c.Req.Context(), c.SignedInUser, UID, alerting_models.Provenance(provenance))
+	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
 	if err != nil {
 		return response.ErrOrFallback(http.StatusInternalServerError, "", err)
 	}
@@ -528,6 +600,9 @@ func (srv *ProvisioningSrv) RoutePutAlertRuleGroup(c *contextmodel.ReqContext, a
 func (srv *ProvisioningSrv) RouteDeleteAlertRuleGroup(c *contextmodel.ReqContext, folderUID string, group string) response.Response {
 	provenance := determineProvenance(c)
 	err := srv.alertRules.DeleteRuleGroup(c.Req.Context(), c.SignedInUser, folderUID, group, alerting_models.Provenance(provenance))
+	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
 	if err != nil {
 		return response.ErrOrFallback(http.StatusInternalServerError, "", err)
 	}
@@ -587,6 +662,15 @@ func exportResponse(c *contextmodel.ReqContext, body definitions.AlertingFileExp
 	return r(http.StatusOK, body)
 }
 
+// exportMaintenanceWindowsResponse exports maintenance windows in the provisioning file format.
+// Maintenance windows cannot be managed by Terraform, so HCL is not supported.
+func exportMaintenanceWindowsResponse(c *contextmodel.ReqContext, windows []definitions.MaintenanceWindow) response.Response {
+	if extractExportRequest(c).Format == "hcl" {
+		return ErrResp(http.StatusBadRequest, errors.New("maintenance windows cannot be exported in HCL format"), "")
+	}
+	return exportResponse(c, AlertingFileExportFromMaintenanceWindows(c.SignedInUser.GetOrgID(), windows))
+}
+
 func exportHcl(download bool, body definitions.AlertingFileExport) response.Response {
 	resources := make([]hcl.Resource, 0, len(body.Groups)+len(body.ContactPoints)+len(body.Policies)+len(body.MuteTimings))
 	convertToResources := func() error {
@@ -0,0 +1,170 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/prom"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// maxRuleFileSize is the maximum size of a Prometheus rule file that can be imported.
+const maxRuleFileSize = 10 << 20
+
+type importedGroup struct {
+	key     ngmodels.AlertRuleGroupKey
+	rules   []*ngmodels.AlertRuleWithOptionals
+	skipped []prom.SkippedRule
+}
+
+// ImportPrometheusRules converts the rule groups of the Prometheus rule file in the request body to Grafana-managed rules
+// that query the data source `ds`, and saves them in the folder `namespaceUID`. Every group of the file replaces the rule
+// group with the same name in the folder. Existing rules are matched by title, so importing the same file again updates
+// the rules instead of creating new ones. If the query parameter `dryRun` is true, the changes are calculated and
+// authorized but not saved. All groups are saved in a single transaction.
+func (srv RulerSrv) ImportPrometheusRules(c *contextmodel.ReqContext, ds *datasources.DataSource, namespaceUID string) response.Response {
+	namespace, err := srv.store.GetNamespaceByUID(c.Req.Context(), namespaceUID, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+	dryRun := c.QueryBool("dryRun")
+
+	content, err := io.ReadAll(io.LimitReader(c.Req.Body, maxRuleFileSize+1))
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to read the rule file")
+	}
+	if len(content) > maxRuleFileSize {
+		return ErrResp(http.StatusRequestEntityTooLarge, fmt.Errorf("rule file is larger than %d bytes", maxRuleFileSize), "")
+	}
+	file, err := prom.ParseRuleFile(content)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if len(file.Groups) == 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("rule file does not contain rule groups"), "")
+	}
+
+	limits := RuleLimitsFromConfig(srv.cfg, srv.featureManager)
+	converter, err := prom.NewConverter(prom.Config{
+		DatasourceUID:  ds.UID,
+		DatasourceType: ds.Type,
+		RecordingRules: limits.RecordingRulesAllowed,
+	})
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	groups := make([]importedGroup, 0, len(file.Groups))
+	for _, converted := range converter.ConvertGroups(file.Groups) {
+		ruleGroupConfig := converted.Group
+		if err := srv.checkGroupLimits(ruleGroupConfig); err != nil {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		rules, err := ValidateRuleGroup(&ruleGroupConfig, c.SignedInUser.GetOrgID(), namespace.UID, limits)
+		if err != nil {
+			return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid rule group %s: %w", ruleGroupConfig.Name, err), "")
+		}
+		groups = append(groups, importedGroup{
+			key: ngmodels.AlertRuleGroupKey{
+				OrgID:        c.SignedInUser.GetOrgID(),
+				NamespaceUID: namespace.UID,
+				RuleGroup:    ruleGroupConfig.Name,
+			},
+			rules:   rules,
+			skipped: converted.Skipped,
+		})
+	}
+
+	result := apimodels.ImportPrometheusRulesResponse{
+		DryRun: dryRun,
+		Groups: make([]apimodels.ImportedRuleGroupResponse, 0, len(groups)),
+	}
+	var dbConfig *ngmodels.AlertConfiguration
+	err = srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
+		uids, err := srv.ruleUIDsByTitle(tranCtx, c.SignedInUser.GetOrgID(), namespace.UID)
+		if err != nil {
+			return err
+		}
+		for _, g := range groups {
+			for _, rule := range g.rules {
+				if uid, ok := uids[rule.Title]; ok {
+					rule.UID = uid
+				}
+			}
+			changes, config, err := srv.applyGroupChanges(tranCtx, c, g.key, g.rules, dryRun)
+			if err != nil {
+				return fmt.Errorf("failed to import rule group %s: %w", g.key.RuleGroup, err)
+			}
+			if config != nil {
+				dbConfig = config
+			}
+			result.Groups = append(result.Groups, importedGroupResponse(g, changes))
+		}
+		return nil
+	})
+	if err != nil {
+		return updateGroupErrorResponse(err)
+	}
+
+	srv.refreshAlertmanagerConfig(c, c.SignedInUser.GetOrgID(), dbConfig)
+
+	return response.JSON(http.StatusAccepted, result)
+}
+
+// ruleUIDsByTitle returns the UIDs of the rules in the folder by title. Titles are unique in a folder.
+func (srv RulerSrv) ruleUIDsByTitle(ctx context.Context, orgID int64, namespaceUID string) (map[string]string, error) {
+	rules, err := srv.store.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{
+		OrgID:         orgID,
+		NamespaceUIDs: []string{namespaceUID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch rules of the folder: %w", err)
+	}
+	result := make(map[string]string, len(rules))
+	for _, rule := range rules {
+		result[rule.Title] = rule.UID
+	}
+	return result, nil
+}
+
+func importedGroupResponse(g importedGroup, changes *store.GroupDelta) apimodels.ImportedRuleGroupResponse {
+	result := apimodels.ImportedRuleGroupResponse{
+		Name:    g.key.RuleGroup,
+		Created: make([]string, 0, len(changes.New)),
+		Updated: make([]apimodels.ImportedRuleUpdate, 0, len(changes.Update)),
+		Deleted: make([]string, 0, len(changes.Delete)),
+	}
+	for _, r := range changes.New {
+		result.Created = append(result.Created, r.Title)
+	}
+	for _, u := range changes.Update {
+		if len(u.Diff) == 0 {
+			continue
+		}
+		diff := make([]string, 0, len(u.Diff))
+		for i := range u.Diff {
+			diff = append(diff, strings.TrimSpace(u.Diff[i].String()))
+		}
+		result.Updated = append(result.Updated, apimodels.ImportedRuleUpdate{
+			UID:   u.Existing.UID,
+			Title: u.New.Title,
+			Diff:  diff,
+		})
+	}
+	for _, r := range changes.Delete {
+		result.Deleted = append(result.Deleted, r.Title)
+	}
+	for _, s := range g.skipped {
+		result.Skipped = append(result.Skipped, apimodels.SkippedPrometheusRule{Name: s.Name, Reason: s.Reason})
+	}
+	return result
+}
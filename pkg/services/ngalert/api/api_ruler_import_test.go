@@ -0,0 +1,202 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+)
+
+const importRuleFile = `
+groups:
+  - name: api
+    interval: 1m
+    rules:
+      - alert: HighErrorRate
+        expr: sum by (job) (rate(http_errors_total[5m])) > 0.5
+        for: 5m
+        labels:
+          severity: critical
+      - alert: InstanceDown
+        expr: up == 0
+      - record: job:up:sum
+        expr: sum by (job) (up)
+`
+
+func TestImportPrometheusRules(t *testing.T) {
+	gen := models.RuleGen
+	ds := &datasources.DataSource{UID: "prom", Type: datasources.DS_PROMETHEUS}
+
+	setup := func(t *testing.T) (*fakes.RuleStore, *RulerSrv, int64, string, map[string]*models.AlertRule) {
+		orgID := rand.Int63()
+		folder := randFolder()
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+		groupKey := models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: folder.UID, RuleGroup: "api"}
+		existing := map[string]*models.AlertRule{}
+		for _, title := range []string{"InstanceDown", "Stale"} {
+			rule := gen.With(gen.WithGroupKey(groupKey), gen.WithTitle(title), gen.WithNoNotificationSettings()).GenerateRef()
+			existing[title] = rule
+			ruleStore.PutRule(context.Background(), rule)
+		}
+		svc := createService(ruleStore)
+		svc.conditionValidator = &recordingConditionValidator{}
+		svc.QuotaService = quotatest.New(false, nil)
+		return ruleStore, svc, orgID, folder.UID, existing
+	}
+
+	t.Run("should report changes without saving them in dry run", func(t *testing.T) {
+		ruleStore, svc, orgID, folderUID, existing := setup(t)
+		req := createImportRequest(orgID, folderUID, importRuleFile, true)
+
+		response := svc.ImportPrometheusRules(req, ds, folderUID)
+		require.Equalf(t, http.StatusAccepted, response.Status(), string(response.Body()))
+		result := apimodels.ImportPrometheusRulesResponse{}
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+
+		require.True(t, result.DryRun)
+		require.Len(t, result.Groups, 1)
+		group := result.Groups[0]
+		require.Equal(t, "api", group.Name)
+		require.Equal(t, []string{"HighErrorRate"}, group.Created)
+		require.Len(t, group.Updated, 1)
+		require.Equal(t, existing["InstanceDown"].UID, group.Updated[0].UID)
+		require.NotEmpty(t, group.Updated[0].Diff)
+		require.Equal(t, []string{"Stale"}, group.Deleted)
+		require.Equal(t, []apimodels.SkippedPrometheusRule{{Name: "job:up:sum", Reason: "recording rules are not enabled on this instance"}}, group.Skipped)
+
+		rules, err := ruleStore.ListAlertRules(context.Background(), &models.ListAlertRulesQuery{OrgID: orgID})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []*models.AlertRule{existing["InstanceDown"], existing["Stale"]}, []*models.AlertRule(rules))
+	})
+
+	t.Run("should replace the rule group and keep UIDs of rules with the same title", func(t *testing.T) {
+		ruleStore, svc, orgID, folderUID, existing := setup(t)
+		req := createImportRequest(orgID, folderUID, importRuleFile, false)
+
+		response := svc.ImportPrometheusRules(req, ds, folderUID)
+		require.Equalf(t, http.StatusAccepted, response.Status(), string(response.Body()))
+
+		result := apimodels.ImportPrometheusRulesResponse{}
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.False(t, result.DryRun)
+		require.Equal(t, []string{"HighErrorRate"}, result.Groups[0].Created)
+
+		var updates []models.UpdateRule
+		var inserts []models.AlertRule
+		for _, op := range ruleStore.RecordedOps {
+			switch q := op.(type) {
+			case []models.UpdateRule:
+				updates = append(updates, q...)
+			case []models.AlertRule:
+				inserts = append(inserts, q...)
+			}
+		}
+		require.Len(t, updates, 1)
+		require.Equal(t, existing["InstanceDown"].UID, updates[0].New.UID)
+		require.Equal(t, "C", updates[0].New.Condition)
+		require.Equal(t, int64(60), updates[0].New.IntervalSeconds)
+		require.Len(t, inserts, 1)
+		require.Equal(t, "HighErrorRate", inserts[0].Title)
+		require.Equal(t, map[string]string{"severity": "critical"}, inserts[0].Labels)
+
+		rules, err := ruleStore.ListAlertRules(context.Background(), &models.ListAlertRulesQuery{OrgID: orgID})
+		require.NoError(t, err)
+		require.Len(t, rules, 1, "rules that are not in the file should be deleted")
+	})
+
+	t.Run("should deduplicate titles across groups", func(t *testing.T) {
+		ruleStore, svc, orgID, folderUID, existing := setup(t)
+		file := importRuleFile + `
+  - name: db
+    interval: 1m
+    rules:
+      - alert: InstanceDown
+        expr: up{job="db"} == 0
+`
+		recordedRules := func() map[string]models.AlertRule {
+			result := map[string]models.AlertRule{}
+			for _, op := range ruleStore.RecordedOps {
+				switch q := op.(type) {
+				case []models.UpdateRule:
+					for _, u := range q {
+						result[u.New.Title] = u.New
+					}
+				case []models.AlertRule:
+					for _, r := range q {
+						result[r.Title] = r
+					}
+				}
+			}
+			return result
+		}
+
+		response := svc.ImportPrometheusRules(createImportRequest(orgID, folderUID, file, false), ds, folderUID)
+		require.Equalf(t, http.StatusAccepted, response.Status(), string(response.Body()))
+		saved := recordedRules()
+		require.Len(t, saved, 3)
+		require.Equal(t, "api", saved["HighErrorRate"].RuleGroup)
+		require.Equal(t, "api", saved["InstanceDown"].RuleGroup)
+		require.Equal(t, existing["InstanceDown"].UID, saved["InstanceDown"].UID)
+		require.Equal(t, "db", saved["InstanceDown (2)"].RuleGroup)
+
+		// importing the file again must match every rule of the file with the rule of its own group
+		dbRule := gen.With(
+			gen.WithGroupKey(models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: folderUID, RuleGroup: "db"}),
+			gen.WithTitle("InstanceDown (2)"),
+			gen.WithNoNotificationSettings(),
+		).GenerateRef()
+		ruleStore.PutRule(context.Background(), dbRule)
+		ruleStore.RecordedOps = nil
+
+		response = svc.ImportPrometheusRules(createImportRequest(orgID, folderUID, file, false), ds, folderUID)
+		require.Equalf(t, http.StatusAccepted, response.Status(), string(response.Body()))
+		saved = recordedRules()
+		require.Equal(t, "api", saved["InstanceDown"].RuleGroup)
+		require.Equal(t, existing["InstanceDown"].UID, saved["InstanceDown"].UID)
+		require.Equal(t, "db", saved["InstanceDown (2)"].RuleGroup)
+		require.Equal(t, dbRule.UID, saved["InstanceDown (2)"].UID)
+	})
+
+	t.Run("should return 400 if the file is invalid", func(t *testing.T) {
+		_, svc, orgID, folderUID, _ := setup(t)
+		req := createImportRequest(orgID, folderUID, "groups:\n  - name: api\n    rules:\n      - alert: A\n", false)
+
+		response := svc.ImportPrometheusRules(req, ds, folderUID)
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+}
+
+func createImportRequest(orgID int64, folderUID string, file string, dryRun bool) *contextmodel.ReqContext {
+	scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(folderUID)
+	perms := map[int64]map[string][]string{orgID: {
+		dashboards.ActionFoldersRead: {scope},
+		ac.ActionAlertingRuleRead:    {scope},
+		ac.ActionAlertingRuleCreate:  {scope},
+		ac.ActionAlertingRuleUpdate:  {scope},
+		ac.ActionAlertingRuleDelete:  {scope},
+		datasources.ActionQuery:      {datasources.ScopeAll},
+	}}
+	req := createRequestContextWithPerms(orgID, perms, nil)
+	req.Req.Method = http.MethodPost
+	req.Req.Form = nil
+	req.Req.URL.RawQuery = url.Values{"dryRun": []string{strconv.FormatBool(dryRun)}}.Encode()
+	req.Req.Body = io.NopCloser(strings.NewReader(file))
+	return req
+}
@@ -190,6 +190,23 @@ func TestRouteDeleteAlertRules(t *testing.T) {
 				deleteCommands := getRecordedCommand(ruleStore)
 				require.Empty(t, deleteCommands)
 			})
+			t.Run("return 400 if rules of other groups depend on the group", func(t *testing.T) {
+				ruleStore := initFakeRuleStore(t)
+
+				rulesInGroup := gen.With(gen.WithNamespace(folder), gen.WithSameGroup()).GenerateManyRef(1, 5)
+				ruleStore.PutRule(context.Background(), rulesInGroup...)
+				dependent := gen.With(gen.WithGroupName("dependent"), gen.WithDependsOn(rulesInGroup[0].UID)).GenerateRef()
+				ruleStore.PutRule(context.Background(), dependent)
+
+				permissions := createPermissionsForRules(rulesInGroup, orgID)
+				requestCtx := createRequestContextWithPerms(orgID, permissions, nil)
+
+				response := createService(ruleStore).RouteDeleteAlertRules(requestCtx, folder.UID, rulesInGroup[0].RuleGroup)
+
+				require.Equalf(t, 400, response.Status(), "Expected 400 but got %d: %v", response.Status(), string(response.Body()))
+				require.Contains(t, string(response.Body()), dependent.Title)
+				require.Empty(t, getRecordedCommand(ruleStore))
+			})
 		})
 	})
 }
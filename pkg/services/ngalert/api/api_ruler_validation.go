@@ -149,6 +149,16 @@ func validateAlertingRuleFields(in *apimodels.PostableExtendedRuleNode, newRule
 		return ngmodels.AlertRule{}, err
 	}
 
+	newRule.KeepFiringFor, err = validateKeepFiringFor(in)
+	if err != nil {
+		return ngmodels.AlertRule{}, err
+	}
+
+	newRule.DependsOn, err = validateDependsOn(in)
+	if err != nil {
+		return ngmodels.AlertRule{}, err
+	}
+
 	return newRule, nil
 }
 
@@ -177,7 +187,9 @@ func validateRecordingRuleFields(in *apimodels.PostableExtendedRuleNode, newRule
 	newRule.ExecErrState = ""
 	newRule.Condition = ""
 	newRule.For = 0
+	newRule.KeepFiringFor = 0
 	newRule.NotificationSettings = nil
+	newRule.DependsOn = nil
 
 	return newRule, nil
 }
@@ -264,6 +276,42 @@ func validateForInterval(ruleNode *apimodels.PostableExtendedRuleNode) (time.Dur
 	return duration, nil
 }
 
+// validateKeepFiringFor validates ApiRuleNode.KeepFiringFor and converts it to time.Duration. If the field is not specified returns 0 if GrafanaManagedAlert.UID is empty and -1 if it is not.
+func validateKeepFiringFor(ruleNode *apimodels.PostableExtendedRuleNode) (time.Duration, error) {
+	if ruleNode.ApiRuleNode == nil || ruleNode.ApiRuleNode.KeepFiringFor == nil {
+		if ruleNode.GrafanaManagedAlert.UID != "" {
+			return -1, nil // will be patched later with the real value of the current version of the rule
+		}
+		return 0, nil
+	}
+	duration := time.Duration(*ruleNode.ApiRuleNode.KeepFiringFor)
+	if duration < 0 {
+		return 0, fmt.Errorf("field `keep_firing_for` cannot be negative [%v]. 0 or any positive duration are allowed", *ruleNode.ApiRuleNode.KeepFiringFor)
+	}
+	return duration, nil
+}
+
+func validateDependsOn(ruleNode *apimodels.PostableExtendedRuleNode) ([]string, error) {
+	dependsOn := ruleNode.GrafanaManagedAlert.DependsOn
+	if len(dependsOn) == 0 {
+		return nil, nil
+	}
+	seen := make(map[string]struct{}, len(dependsOn))
+	for _, uid := range dependsOn {
+		if uid == "" {
+			return nil, errors.New("field `depends_on` cannot contain empty rule UIDs")
+		}
+		if uid == ruleNode.GrafanaManagedAlert.UID {
+			return nil, errors.New("alert rule cannot depend on itself")
+		}
+		if _, ok := seen[uid]; ok {
+			return nil, fmt.Errorf("field `depends_on` contains rule UID %s more than once", uid)
+		}
+		seen[uid] = struct{}{}
+	}
+	return dependsOn, nil
+}
+
 // ValidateRuleGroup validates API model (definitions.PostableRuleGroupConfig) and converts it to a collection of models.AlertRule.
 // Returns a slice that contains all rules described by API model or error if either group specification or an alert definition is not valid.
 // It also returns a map containing current existing alerts that don't contain the is_paused field in the body of the call.
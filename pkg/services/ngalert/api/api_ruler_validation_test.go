@@ -370,6 +370,17 @@ func TestValidateRuleNode_NoUID(t *testing.T) {
 				require.Nil(t, alert.Labels)
 			},
 		},
+		{
+			name: "converts keep_firing_for",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.ApiRuleNode.KeepFiringFor = func() *model.Duration { d := model.Duration(time.Minute); return &d }()
+				return &r
+			},
+			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
+				require.Equal(t, time.Minute, alert.KeepFiringFor)
+			},
+		},
 		{
 			name: "defaults to NoData if NoDataState is empty",
 			rule: func() *apimodels.PostableExtendedRuleNode {
@@ -560,6 +571,14 @@ func TestValidateRuleNodeFailures_NoUID(t *testing.T) {
 				return &r
 			},
 		},
+		{
+			name: "fail if keep_firing_for is negative",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.ApiRuleNode.KeepFiringFor = func() *model.Duration { d := model.Duration(-time.Minute); return &d }()
+				return &r
+			},
+		},
 		{
 			name: "fail if there are not data (empty)",
 			rule: func() *apimodels.PostableExtendedRuleNode {
@@ -1062,3 +1081,49 @@ func TestValidateRuleNodeReservedLabels(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateRuleNodeDependsOn(t *testing.T) {
+	cfg := config(t)
+	limits := makeLimits(cfg)
+
+	testCases := []struct {
+		name      string
+		dependsOn []string
+		expErr    string
+	}{
+		{
+			name:      "accepts rule UIDs",
+			dependsOn: []string{"upstream-1", "upstream-2"},
+		},
+		{
+			name:      "rejects empty rule UIDs",
+			dependsOn: []string{""},
+			expErr:    "empty rule UIDs",
+		},
+		{
+			name:      "rejects dependency on itself",
+			dependsOn: []string{"self"},
+			expErr:    "cannot depend on itself",
+		},
+		{
+			name:      "rejects duplicate rule UIDs",
+			dependsOn: []string{"upstream-1", "upstream-1"},
+			expErr:    "more than once",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := validRule()
+			r.GrafanaManagedAlert.UID = "self"
+			r.GrafanaManagedAlert.DependsOn = tc.dependsOn
+			alert, err := validateRuleNode(&r, util.GenerateShortUID(), cfg.BaseInterval, rand.Int63(), randFolder().UID, limits)
+			if tc.expErr != "" {
+				require.ErrorContains(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.dependsOn, alert.DependsOn)
+		})
+	}
+}
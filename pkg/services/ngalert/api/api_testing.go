@@ -286,3 +286,69 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 	}
 	return response.JSON(http.StatusOK, body)
 }
+
+// BacktestWhatIf replays a rule or a rule group over a historical time range and returns the timeline of states
+// together with the notifications that would have been sent to the receivers of the provided notification policy tree.
+func (srv TestingApiSrv) BacktestWhatIf(c *contextmodel.ReqContext, cmd apimodels.BacktestWhatIfConfig) response.Response {
+	if !srv.featureManager.IsEnabled(c.Req.Context(), featuremgmt.FlagAlertingBacktesting) {
+		return ErrResp(http.StatusNotFound, nil, "Backgtesting API is not enabled")
+	}
+
+	if (cmd.Rule == nil) == (cmd.RuleGroup == nil) {
+		return ErrResp(http.StatusBadRequest, nil, "Either rule or rule_group must be specified")
+	}
+	if cmd.Route == nil {
+		return ErrResp(http.StatusBadRequest, nil, "Notification policy tree must be specified")
+	}
+
+	var namespaceUID, folderTitle string
+	if cmd.NamespaceUID != "" {
+		folder, err := srv.folderService.GetNamespaceByUID(c.Req.Context(), cmd.NamespaceUID, c.SignedInUser.GetOrgID(), c.SignedInUser)
+		if err != nil {
+			return toNamespaceErrorResponse(dashboards.ErrFolderAccessDenied)
+		}
+		namespaceUID = folder.UID
+		folderTitle = folder.Fullpath
+	}
+
+	group := cmd.RuleGroup
+	if cmd.Rule != nil {
+		group = &apimodels.PostableRuleGroupConfig{
+			Name:     "backtesting",
+			Interval: cmd.Interval,
+			Rules:    []apimodels.PostableExtendedRuleNode{*cmd.Rule},
+		}
+	}
+	groupRules, err := ValidateRuleGroup(group, c.SignedInUser.GetOrgID(), namespaceUID, RuleLimitsFromConfig(srv.cfg, srv.featureManager))
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	includeFolder := namespaceUID != "" && !srv.cfg.ReservedLabels.IsReservedLabelDisabled(models.FolderTitleLabel)
+	rules := make([]*ngmodels.AlertRule, 0, len(groupRules))
+	extraLabels := make(map[string]data.Labels, len(groupRules))
+	for _, r := range groupRules {
+		rule := &r.AlertRule
+		if rule.Type() == ngmodels.RuleTypeRecording {
+			return ErrResp(http.StatusBadRequest, nil, "Recording rules cannot be backtested")
+		}
+		if rule.UID == "" {
+			// prefix backtesting- is to distinguish between executions of regular rule and backtesting in logs
+			rule.UID = "backtesting-" + util.GenerateShortUID()
+		}
+		if err := srv.authz.AuthorizeDatasourceAccessForRule(c.Req.Context(), c.SignedInUser, rule); err != nil {
+			return errorToResponse(err)
+		}
+		extraLabels[rule.UID] = state.GetRuleExtraLabels(srv.log, rule, folderTitle, includeFolder)
+		rules = append(rules, rule)
+	}
+
+	result, err := srv.backtesting.WhatIf(c.Req.Context(), c.SignedInUser, rules, extraLabels, cmd.Route, cmd.TimeIntervals, cmd.From, cmd.To)
+	if err != nil {
+		if errors.Is(err, backtesting.ErrInvalidInputData) {
+			return ErrResp(http.StatusBadRequest, err, "Failed to evaluate")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "Failed to evaluate")
+	}
+	return response.JSON(http.StatusOK, BacktestWhatIfResultFromWhatIfResult(result))
+}
@@ -9,9 +9,11 @@ import (
 	"github.com/google/uuid"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	acMock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
@@ -23,11 +25,13 @@ import (
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	fakes2 "github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
 	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -391,6 +395,152 @@ func TestRouteEvalQueries(t *testing.T) {
 	})
 }
 
+func TestBacktestWhatIf(t *testing.T) {
+	rc := &contextmodel.ReqContext{
+		Context: &web.Context{
+			Req: &http.Request{},
+		},
+		SignedInUser: &user.SignedInUser{
+			OrgID: 1,
+		},
+	}
+
+	from := time.Unix(0, 0).UTC()
+	to := from.Add(200 * time.Second)
+
+	// The series is above zero in [30s,100s).
+	times := make([]time.Time, 0, 20)
+	values := make([]float64, 0, 20)
+	for ts := from; ts.Before(to); ts = ts.Add(10 * time.Second) {
+		v := 0.0
+		if elapsed := ts.Sub(from); elapsed >= 30*time.Second && elapsed < 100*time.Second {
+			v = 1
+		}
+		times = append(times, ts)
+		values = append(values, v)
+	}
+	frame := data.NewFrame("", data.NewField("time", nil, times), data.NewField("value", data.Labels{"series": "a"}, values))
+	frameJSON, err := json.Marshal(frame)
+	require.NoError(t, err)
+	queryModel, err := json.Marshal(map[string]json.RawMessage{"data": frameJSON})
+	require.NoError(t, err)
+
+	rule := func() *definitions.PostableExtendedRuleNode {
+		return &definitions.PostableExtendedRuleNode{
+			ApiRuleNode: &definitions.ApiRuleNode{
+				Labels: map[string]string{"team": "ops"},
+			},
+			GrafanaManagedAlert: &definitions.PostableGrafanaRule{
+				Title:     "test",
+				Condition: "A",
+				Data: []definitions.AlertQuery{
+					{
+						RefID:         "A",
+						DatasourceUID: "__data__",
+						Model:         queryModel,
+					},
+				},
+				NoDataState:  definitions.NoDataState(models.NoData),
+				ExecErrState: definitions.ExecutionErrorState(models.ErrorErrState),
+			},
+		}
+	}
+	groupInterval := model.Duration(time.Minute)
+	route := func() *definitions.Route {
+		return &definitions.Route{
+			Receiver:      "default",
+			GroupInterval: &groupInterval,
+			Routes: []*definitions.Route{
+				{
+					Receiver: "ops",
+					Match:    map[string]string{"team": "ops"},
+				},
+			},
+		}
+	}
+
+	createSrv := func(t *testing.T, features featuremgmt.FeatureToggles) *TestingApiSrv {
+		srv := createTestingApiSrv(t, nil, acMock.New().WithPermissions([]ac.Permission{
+			{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID("__data__")},
+		}), nil, features, fakes2.NewRuleStore(t))
+		srv.cfg = &setting.UnifiedAlertingSettings{
+			BaseInterval:                  10 * time.Second,
+			DefaultRuleEvaluationInterval: time.Minute,
+		}
+		srv.log = log.NewNopLogger()
+		srv.backtesting = backtesting.NewEngine(nil, nil, srv.tracer)
+		return srv
+	}
+
+	t.Run("should return 404 if backtesting is not enabled", func(t *testing.T) {
+		srv := createSrv(t, featuremgmt.WithFeatures())
+		response := srv.BacktestWhatIf(rc, definitions.BacktestWhatIfConfig{From: from, To: to, Rule: rule(), Route: route()})
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should return 400", func(t *testing.T) {
+		srv := createSrv(t, featuremgmt.WithFeatures(featuremgmt.FlagAlertingBacktesting))
+
+		t.Run("when neither rule nor rule group are specified", func(t *testing.T) {
+			response := srv.BacktestWhatIf(rc, definitions.BacktestWhatIfConfig{From: from, To: to, Route: route()})
+			require.Equal(t, http.StatusBadRequest, response.Status())
+		})
+		t.Run("when both rule and rule group are specified", func(t *testing.T) {
+			response := srv.BacktestWhatIf(rc, definitions.BacktestWhatIfConfig{
+				From:      from,
+				To:        to,
+				Rule:      rule(),
+				RuleGroup: &definitions.PostableRuleGroupConfig{Name: "group", Rules: []definitions.PostableExtendedRuleNode{*rule()}},
+				Route:     route(),
+			})
+			require.Equal(t, http.StatusBadRequest, response.Status())
+		})
+		t.Run("when policy tree is not specified", func(t *testing.T) {
+			response := srv.BacktestWhatIf(rc, definitions.BacktestWhatIfConfig{From: from, To: to, Rule: rule()})
+			require.Equal(t, http.StatusBadRequest, response.Status())
+		})
+		t.Run("when policy tree is invalid", func(t *testing.T) {
+			r := route()
+			r.Receiver = ""
+			response := srv.BacktestWhatIf(rc, definitions.BacktestWhatIfConfig{From: from, To: to, Rule: rule(), Route: r})
+			require.Equal(t, http.StatusBadRequest, response.Status())
+		})
+	})
+
+	t.Run("should return timeline and notifications routed to receivers", func(t *testing.T) {
+		srv := createSrv(t, featuremgmt.WithFeatures(featuremgmt.FlagAlertingBacktesting))
+		response := srv.BacktestWhatIf(rc, definitions.BacktestWhatIfConfig{
+			From:     from,
+			To:       to,
+			Rule:     rule(),
+			Interval: model.Duration(10 * time.Second),
+			Route:    route(),
+		})
+		require.Equalf(t, http.StatusOK, response.Status(), string(response.Body()))
+
+		var result definitions.BacktestWhatIfResult
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+
+		var states []string
+		for _, c := range result.Timeline {
+			require.Equal(t, "a", c.Labels["series"])
+			require.Equal(t, "test", c.Labels["alertname"])
+			states = append(states, c.State)
+		}
+		require.Equal(t, []string{"Normal", "Alerting", "Normal"}, states)
+
+		require.Len(t, result.Notifications, 2)
+		require.Equal(t, from.Add(time.Minute), result.Notifications[0].Time)
+		require.Equal(t, "ops", result.Notifications[0].Receiver)
+		require.Len(t, result.Notifications[0].Alerts, 1)
+		require.Equal(t, "firing", result.Notifications[0].Alerts[0].Status)
+		require.Equal(t, from.Add(2*time.Minute), result.Notifications[1].Time)
+		require.Equal(t, "ops", result.Notifications[1].Receiver)
+		require.Len(t, result.Notifications[1].Alerts, 1)
+		require.Equal(t, "resolved", result.Notifications[1].Alerts[0].Status)
+	})
+}
+
 func createTestingApiSrv(t *testing.T, ds *fakes.FakeCacheService, ac *acMock.Mock, evaluator eval.EvaluatorFactory, featureManager featuremgmt.FeatureToggles, ruleStore RuleStore) *TestingApiSrv {
 	if ac == nil {
 		ac = acMock.New()
@@ -9,6 +9,7 @@ import (
 	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -28,11 +29,13 @@ func AlertRuleFromProvisionedAlertRule(a definitions.ProvisionedAlertRule) (mode
 		NoDataState:          models.NoDataState(a.NoDataState),          // TODO there must be a validation
 		ExecErrState:         models.ExecutionErrorState(a.ExecErrState), // TODO there must be a validation
 		For:                  time.Duration(a.For),
+		KeepFiringFor:        time.Duration(a.KeepFiringFor),
 		Annotations:          a.Annotations,
 		Labels:               a.Labels,
 		IsPaused:             a.IsPaused,
 		NotificationSettings: NotificationSettingsFromAlertRuleNotificationSettings(a.NotificationSettings),
 		Record:               ModelRecordFromApiRecord(a.Record),
+		DependsOn:            a.DependsOn,
 	}, nil
 }
 
@@ -46,6 +49,7 @@ func ProvisionedAlertRuleFromAlertRule(rule models.AlertRule, provenance models.
 		RuleGroup:            rule.RuleGroup,
 		Title:                rule.Title,
 		For:                  model.Duration(rule.For),
+		KeepFiringFor:        model.Duration(rule.KeepFiringFor),
 		Condition:            rule.Condition,
 		Data:                 ApiAlertQueriesFromAlertQueries(rule.Data),
 		Updated:              rule.Updated,
@@ -57,6 +61,7 @@ func ProvisionedAlertRuleFromAlertRule(rule models.AlertRule, provenance models.
 		IsPaused:             rule.IsPaused,
 		NotificationSettings: AlertRuleNotificationSettingsFromNotificationSettings(rule.NotificationSettings),
 		Record:               ApiRecordFromModelRecord(rule.Record),
+		DependsOn:            rule.DependsOn,
 	}
 }
 
@@ -183,6 +188,7 @@ func AlertRuleExportFromAlertRule(rule models.AlertRule) (definitions.AlertRuleE
 		UID:                  rule.UID,
 		Title:                rule.Title,
 		For:                  model.Duration(rule.For),
+		KeepFiringFor:        model.Duration(rule.KeepFiringFor),
 		Condition:            rule.Condition,
 		Data:                 data,
 		DashboardUID:         rule.DashboardUID,
@@ -196,12 +202,18 @@ func AlertRuleExportFromAlertRule(rule models.AlertRule) (definitions.AlertRuleE
 	if rule.For.Seconds() > 0 {
 		result.ForString = util.Pointer(model.Duration(rule.For).String())
 	}
+	if rule.KeepFiringFor.Seconds() > 0 {
+		result.KeepFiringForString = util.Pointer(model.Duration(rule.KeepFiringFor).String())
+	}
 	if rule.Annotations != nil {
 		result.Annotations = &rule.Annotations
 	}
 	if rule.Labels != nil {
 		result.Labels = &rule.Labels
 	}
+	if len(rule.DependsOn) > 0 {
+		result.DependsOn = &rule.DependsOn
+	}
 	return result, nil
 }
 
@@ -386,6 +398,29 @@ func MuteTimeIntervalExportFromMuteTiming(orgID int64, m definitions.MuteTimeInt
 	}
 }
 
+func AlertingFileExportFromMaintenanceWindows(orgID int64, m []definitions.MaintenanceWindow) definitions.AlertingFileExport {
+	f := definitions.AlertingFileExport{
+		APIVersion:         1,
+		MaintenanceWindows: make([]definitions.MaintenanceWindowExport, 0, len(m)),
+	}
+	for _, mw := range m {
+		f.MaintenanceWindows = append(f.MaintenanceWindows, MaintenanceWindowExportFromMaintenanceWindow(orgID, mw))
+	}
+	return f
+}
+
+func MaintenanceWindowExportFromMaintenanceWindow(orgID int64, mw definitions.MaintenanceWindow) definitions.MaintenanceWindowExport {
+	return definitions.MaintenanceWindowExport{
+		OrgID:          orgID,
+		UID:            mw.UID,
+		Title:          mw.Title,
+		Mode:           mw.Mode,
+		TimeIntervals:  mw.TimeIntervals,
+		ObjectMatchers: mw.ObjectMatchers,
+		FolderUIDs:     mw.FolderUIDs,
+	}
+}
+
 // Converts definitions.MuteTimeIntervalExport to definitions.MuteTimeIntervalExportHcl using JSON marshalling. Returns error if structure could not be marshalled\unmarshalled
 func MuteTimingIntervalToMuteTimeIntervalHclExport(m definitions.MuteTimeIntervalExport) (definitions.MuteTimeIntervalExportHcl, error) {
 	result := definitions.MuteTimeIntervalExportHcl{}
@@ -485,3 +520,51 @@ func ApiRecordFromModelRecord(r *models.Record) *definitions.Record {
 		From:   r.From,
 	}
 }
+
+// BacktestWhatIfResultFromWhatIfResult converts backtesting.WhatIfResult to definitions.BacktestWhatIfResult
+func BacktestWhatIfResultFromWhatIfResult(r *backtesting.WhatIfResult) definitions.BacktestWhatIfResult {
+	result := definitions.BacktestWhatIfResult{
+		Timeline:      make([]definitions.BacktestStateChange, 0, len(r.Timeline)),
+		Notifications: make([]definitions.BacktestNotification, 0, len(r.Notifications)),
+	}
+	for _, c := range r.Timeline {
+		result.Timeline = append(result.Timeline, definitions.BacktestStateChange{
+			Time:          c.Time,
+			RuleUID:       c.RuleUID,
+			Labels:        c.Labels,
+			PreviousState: c.PreviousState,
+			State:         c.State,
+		})
+	}
+	for _, n := range r.Notifications {
+		notification := definitions.BacktestNotification{
+			Time:        n.Time,
+			Receiver:    n.Receiver,
+			GroupKey:    n.GroupKey,
+			GroupLabels: labelSetToMap(n.GroupLabels),
+			Alerts:      make([]definitions.BacktestNotificationAlert, 0, len(n.Alerts)),
+		}
+		for _, a := range n.Alerts {
+			status := model.AlertFiring
+			if a.ResolvedAt(n.Time) {
+				status = model.AlertResolved
+			}
+			notification.Alerts = append(notification.Alerts, definitions.BacktestNotificationAlert{
+				Status:   string(status),
+				Labels:   labelSetToMap(a.Labels),
+				StartsAt: a.StartsAt,
+				EndsAt:   a.EndsAt,
+			})
+		}
+		result.Notifications = append(result.Notifications, notification)
+	}
+	return result
+}
+
+func labelSetToMap(ls model.LabelSet) map[string]string {
+	m := make(map[string]string, len(ls))
+	for k, v := range ls {
+		m[string(k)] = string(v)
+	}
+	return m
+}
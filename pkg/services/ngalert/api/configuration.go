@@ -21,6 +21,10 @@ func (f *ConfigurationApiHandler) handleRouteGetAlertmanagers(c *contextmodel.Re
 	return f.grafana.RouteGetAlertmanagers(c)
 }
 
+func (f *ConfigurationApiHandler) handleRouteGetEvaluationCost(c *contextmodel.ReqContext) response.Response {
+	return f.grafana.RouteGetEvaluationCost(c)
+}
+
 func (f *ConfigurationApiHandler) handleRouteGetNGalertConfig(c *contextmodel.ReqContext) response.Response {
 	return f.grafana.RouteGetNGalertConfig(c)
 }
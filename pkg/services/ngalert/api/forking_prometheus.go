@@ -46,6 +46,14 @@ func (f *PrometheusApiHandler) handleRouteGetGrafanaRuleStatuses(ctx *contextmod
 	return f.GrafanaSvc.RouteGetRuleStatuses(ctx)
 }
 
+func (f *PrometheusApiHandler) handleRoutePostGrafanaAlertAcknowledgement(ctx *contextmodel.ReqContext, body apimodels.PostableAlertAcknowledgement, ruleUID string, fingerprint string) response.Response {
+	return f.GrafanaSvc.RoutePostAlertAcknowledgement(ctx, body, ruleUID, fingerprint)
+}
+
+func (f *PrometheusApiHandler) handleRouteDeleteGrafanaAlertAcknowledgement(ctx *contextmodel.ReqContext, ruleUID string, fingerprint string) response.Response {
+	return f.GrafanaSvc.RouteDeleteAlertAcknowledgement(ctx, ruleUID, fingerprint)
+}
+
 func (f *PrometheusApiHandler) getService(ctx *contextmodel.ReqContext) (*LotexProm, error) {
 	_, err := getDatasourceByUID(ctx, f.DatasourceCache, apimodels.LoTexRulerBackend)
 	if err != nil {
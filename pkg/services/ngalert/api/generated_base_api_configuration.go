@@ -22,6 +22,7 @@ import (
 type ConfigurationApi interface {
 	RouteDeleteNGalertConfig(*contextmodel.ReqContext) response.Response
 	RouteGetAlertmanagers(*contextmodel.ReqContext) response.Response
+	RouteGetEvaluationCost(*contextmodel.ReqContext) response.Response
 	RouteGetNGalertConfig(*contextmodel.ReqContext) response.Response
 	RouteGetStatus(*contextmodel.ReqContext) response.Response
 	RoutePostNGalertConfig(*contextmodel.ReqContext) response.Response
@@ -33,6 +34,9 @@ func (f *ConfigurationApiHandler) RouteDeleteNGalertConfig(ctx *contextmodel.Req
 func (f *ConfigurationApiHandler) RouteGetAlertmanagers(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetAlertmanagers(ctx)
 }
+func (f *ConfigurationApiHandler) RouteGetEvaluationCost(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetEvaluationCost(ctx)
+}
 func (f *ConfigurationApiHandler) RouteGetNGalertConfig(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetNGalertConfig(ctx)
 }
@@ -74,6 +78,18 @@ func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApi, m *metri
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/evaluation-cost"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/ngalert/evaluation-cost"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/evaluation-cost",
+				api.Hooks.Wrap(srv.RouteGetEvaluationCost),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/ngalert/admin_config"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -14,17 +14,26 @@ import (
 	"github.com/grafana/grafana/pkg/middleware"
 	"github.com/grafana/grafana/pkg/middleware/requestmeta"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/web"
 )
 
 type PrometheusApi interface {
+	RouteDeleteGrafanaAlertAcknowledgement(*contextmodel.ReqContext) response.Response
 	RouteGetAlertStatuses(*contextmodel.ReqContext) response.Response
 	RouteGetGrafanaAlertStatuses(*contextmodel.ReqContext) response.Response
 	RouteGetGrafanaRuleStatuses(*contextmodel.ReqContext) response.Response
 	RouteGetRuleStatuses(*contextmodel.ReqContext) response.Response
+	RoutePostGrafanaAlertAcknowledgement(*contextmodel.ReqContext) response.Response
 }
 
+func (f *PrometheusApiHandler) RouteDeleteGrafanaAlertAcknowledgement(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	ruleUIDParam := web.Params(ctx.Req)[":RuleUID"]
+	fingerprintParam := web.Params(ctx.Req)[":Fingerprint"]
+	return f.handleRouteDeleteGrafanaAlertAcknowledgement(ctx, ruleUIDParam, fingerprintParam)
+}
 func (f *PrometheusApiHandler) RouteGetAlertStatuses(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	datasourceUIDParam := web.Params(ctx.Req)[":DatasourceUID"]
@@ -41,9 +50,32 @@ func (f *PrometheusApiHandler) RouteGetRuleStatuses(ctx *contextmodel.ReqContext
 	datasourceUIDParam := web.Params(ctx.Req)[":DatasourceUID"]
 	return f.handleRouteGetRuleStatuses(ctx, datasourceUIDParam)
 }
+func (f *PrometheusApiHandler) RoutePostGrafanaAlertAcknowledgement(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	ruleUIDParam := web.Params(ctx.Req)[":RuleUID"]
+	fingerprintParam := web.Params(ctx.Req)[":Fingerprint"]
+	// Parse Request Body
+	conf := apimodels.PostableAlertAcknowledgement{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostGrafanaAlertAcknowledgement(ctx, conf, ruleUIDParam, fingerprintParam)
+}
 
 func (api *API) RegisterPrometheusApiEndpoints(srv PrometheusApi, m *metrics.API) {
 	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Delete(
+			toMacaronPath("/api/prometheus/grafana/api/v1/rules/{RuleUID}/alerts/{Fingerprint}/acknowledgement"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodDelete, "/api/prometheus/grafana/api/v1/rules/{RuleUID}/alerts/{Fingerprint}/acknowledgement"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/prometheus/grafana/api/v1/rules/{RuleUID}/alerts/{Fingerprint}/acknowledgement",
+				api.Hooks.Wrap(srv.RouteDeleteGrafanaAlertAcknowledgement),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/prometheus/{DatasourceUID}/api/v1/alerts"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -92,5 +124,17 @@ func (api *API) RegisterPrometheusApiEndpoints(srv PrometheusApi, m *metrics.API
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/prometheus/grafana/api/v1/rules/{RuleUID}/alerts/{Fingerprint}/acknowledgement"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/prometheus/grafana/api/v1/rules/{RuleUID}/alerts/{Fingerprint}/acknowledgement"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/prometheus/grafana/api/v1/rules/{RuleUID}/alerts/{Fingerprint}/acknowledgement",
+				api.Hooks.Wrap(srv.RoutePostGrafanaAlertAcknowledgement),
+				m,
+			),
+		)
 	}, middleware.ReqSignedIn)
 }
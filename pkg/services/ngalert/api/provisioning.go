@@ -136,6 +136,34 @@ func (f *ProvisioningApiHandler) handleRouteExportMuteTimings(ctx *contextmodel.
 	return f.svc.RouteGetMuteTimingsExport(ctx)
 }
 
+func (f *ProvisioningApiHandler) handleRouteGetMaintenanceWindows(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetMaintenanceWindows(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetMaintenanceWindow(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RouteGetMaintenanceWindow(ctx, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRouteExportMaintenanceWindows(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetMaintenanceWindowsExport(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteExportMaintenanceWindow(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RouteGetMaintenanceWindowExport(ctx, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostMaintenanceWindow(ctx *contextmodel.ReqContext, mw apimodels.MaintenanceWindow) response.Response {
+	return f.svc.RoutePostMaintenanceWindow(ctx, mw)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutMaintenanceWindow(ctx *contextmodel.ReqContext, mw apimodels.MaintenanceWindow, UID string) response.Response {
+	return f.svc.RoutePutMaintenanceWindow(ctx, mw, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeleteMaintenanceWindow(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RouteDeleteMaintenanceWindow(ctx, UID)
+}
+
 func (f *ProvisioningApiHandler) handleRouteDeleteAlertRuleGroup(ctx *contextmodel.ReqContext, folderUID, group string) response.Response {
 	return f.svc.RouteDeleteAlertRuleGroup(ctx, folderUID, group)
 }
@@ -1,6 +1,8 @@
 package definitions
 
 import (
+	"time"
+
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 )
 
@@ -24,6 +26,16 @@ import (
 //     Responses:
 //		 200: GettableAlertmanagers
 
+// swagger:route GET /v1/ngalert/evaluation-cost configuration RouteGetEvaluationCost
+//
+//  Get the evaluation cost of the alert rules of the user's organization, the evaluation budget of the organization and how much the evaluation intervals are stretched because the budget is exceeded.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//		 200: GettableEvaluationCost
+
 // swagger:route GET /v1/ngalert/admin_config configuration RouteGetNGalertConfig
 //
 //  Get the NGalert configuration of the user's organization, returns 404 if no configuration is present.
@@ -95,3 +107,35 @@ type AlertingStatus struct {
 	AlertmanagersChoice      AlertmanagersChoice `json:"alertmanagersChoice"`
 	NumExternalAlertmanagers int                 `json:"numExternalAlertmanagers"`
 }
+
+// swagger:model
+type GettableEvaluationCost struct {
+	// CostSecondsPerMinute is the time spent evaluating the alert rules of the organization per minute.
+	CostSecondsPerMinute float64 `json:"costSecondsPerMinute"`
+	// BudgetSecondsPerMinute is the evaluation budget of the organization. Zero means that there is no budget.
+	BudgetSecondsPerMinute float64 `json:"budgetSecondsPerMinute"`
+	// IntervalMultiplier is the factor the evaluation intervals of the alert rules are stretched by.
+	IntervalMultiplier int64 `json:"intervalMultiplier"`
+	// Rules are the evaluated alert rules, the most expensive first.
+	Rules []RuleEvaluationCost `json:"rules"`
+}
+
+// swagger:model
+type RuleEvaluationCost struct {
+	UID             string `json:"uid"`
+	Title           string `json:"title"`
+	FolderUID       string `json:"folderUid"`
+	RuleGroup       string `json:"ruleGroup"`
+	IntervalSeconds int64  `json:"intervalSeconds"`
+	// CostSecondsPerMinute is the average evaluation duration scaled to the evaluation interval of the rule.
+	CostSecondsPerMinute float64 `json:"costSecondsPerMinute"`
+	Evaluations          int64   `json:"evaluations"`
+	// swagger:strfmt date-time
+	LastEvaluation              time.Time `json:"lastEvaluation"`
+	LastDurationSeconds         float64   `json:"lastDurationSeconds"`
+	LastQueryDurationSeconds    float64   `json:"lastQueryDurationSeconds"`
+	LastSeries                  int       `json:"lastSeries"`
+	AverageDurationSeconds      float64   `json:"averageDurationSeconds"`
+	AverageQueryDurationSeconds float64   `json:"averageQueryDurationSeconds"`
+	AverageSeries               float64   `json:"averageSeries"`
+}
@@ -92,6 +92,24 @@ import (
 //       403: ForbiddenError
 //       404: description: Not found.
 
+// swagger:route POST /ruler/grafana/api/v1/rules/{Namespace}/import/prometheus ruler RoutePostPrometheusRulesForImport
+//
+// Imports rule groups from a Prometheus rule file. The request body is the rule file in YAML format. Every rule group
+// of the file replaces the rule group with the same name in the folder.
+//
+//     Consumes:
+//     - application/yaml
+//     - text/yaml
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       202: ImportPrometheusRulesResponse
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: NotFound
+
 // swagger:route POST /ruler/{DatasourceUID}/api/v1/rules/{Namespace} ruler RoutePostNameRulesConfig
 //
 // Creates or updates a rule group
@@ -194,6 +212,22 @@ type NamespaceConfig struct {
 	Body PostableRuleGroupConfig
 }
 
+// swagger:parameters RoutePostPrometheusRulesForImport
+type ImportPrometheusRulesParams struct {
+	// The UID of the rule folder
+	// in: path
+	Namespace string
+	// The UID of the Prometheus data source the queries of the rules are executed against
+	// in: query
+	// required: true
+	DatasourceUID string `json:"datasourceUid"`
+	// Calculate the changes without saving them
+	// in: query
+	// required: false
+	// default: false
+	DryRun bool `json:"dryRun"`
+}
+
 // swagger:parameters RouteGetNamespaceRulesConfig RouteDeleteNamespaceRulesConfig RouteGetNamespaceGrafanaRulesConfig RouteDeleteNamespaceGrafanaRulesConfig
 type PathNamespaceConfig struct {
 	// The UID of the rule folder
@@ -500,6 +534,7 @@ type PostableGrafanaRule struct {
 	IsPaused             *bool                          `json:"is_paused" yaml:"is_paused"`
 	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings" yaml:"notification_settings"`
 	Record               *Record                        `json:"record" yaml:"record"`
+	DependsOn            []string                       `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
 }
 
 // swagger:model
@@ -521,6 +556,7 @@ type GettableGrafanaRule struct {
 	IsPaused             bool                           `json:"is_paused" yaml:"is_paused"`
 	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty"`
 	Record               *Record                        `json:"record,omitempty" yaml:"record,omitempty"`
+	DependsOn            []string                       `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
 }
 
 // AlertQuery represents a single query associated with an alert definition.
@@ -597,3 +633,34 @@ type UpdateRuleGroupResponse struct {
 	Updated []string `json:"updated,omitempty"`
 	Deleted []string `json:"deleted,omitempty"`
 }
+
+// swagger:model
+type ImportPrometheusRulesResponse struct {
+	// DryRun is true if the changes were not saved.
+	DryRun bool                        `json:"dryRun"`
+	Groups []ImportedRuleGroupResponse `json:"groups"`
+}
+
+type ImportedRuleGroupResponse struct {
+	Name string `json:"name"`
+	// Created contains the titles of the rules that are created.
+	Created []string `json:"created"`
+	// Updated contains the rules that are changed by the import.
+	Updated []ImportedRuleUpdate `json:"updated"`
+	// Deleted contains the titles of the rules of the group that are not in the file.
+	Deleted []string `json:"deleted"`
+	// Skipped contains the rules of the file that cannot be imported.
+	Skipped []SkippedPrometheusRule `json:"skipped,omitempty"`
+}
+
+type ImportedRuleUpdate struct {
+	UID   string `json:"uid"`
+	Title string `json:"title"`
+	// Diff contains the changed fields of the rule.
+	Diff []string `json:"diff"`
+}
+
+type SkippedPrometheusRule struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
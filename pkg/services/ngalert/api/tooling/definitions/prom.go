@@ -158,6 +158,8 @@ type AlertingRule struct {
 	Alerts         []Alert          `json:"alerts,omitempty"`
 	Totals         map[string]int64 `json:"totals,omitempty"`
 	TotalsFiltered map[string]int64 `json:"totalsFiltered,omitempty"`
+	// DependsOn are the UIDs of the rules the rule depends on.
+	DependsOn []string `json:"dependsOn,omitempty"`
 	Rule
 }
 
@@ -190,6 +192,24 @@ type Alert struct {
 	ActiveAt *time.Time `json:"activeAt"`
 	// required: true
 	Value string `json:"value"`
+	// InhibitedBy are the UIDs of the firing rules that inhibit the alert.
+	InhibitedBy []string `json:"inhibitedBy,omitempty"`
+	// Fingerprint identifies the alert instance among the instances of its rule.
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Acknowledgement is set if a user acknowledged the firing alert instance.
+	Acknowledgement *AlertAcknowledgement `json:"acknowledgement,omitempty"`
+}
+
+// AlertAcknowledgement describes who acknowledged a firing alert instance, when, and until when.
+// swagger:model
+type AlertAcknowledgement struct {
+	// required: true
+	By      string `json:"by"`
+	Comment string `json:"comment,omitempty"`
+	// required: true
+	At time.Time `json:"at"`
+	// required: true
+	ExpiresAt time.Time `json:"expiresAt"`
 }
 
 type StateByImportance int
@@ -199,6 +219,7 @@ const (
 	StatePending
 	StateError
 	StateNoData
+	StateInhibited
 	StateNormal
 )
 
@@ -212,6 +233,8 @@ func stateByImportanceFromString(s string) (StateByImportance, error) {
 		return StateError, nil
 	case "nodata":
 		return StateNoData, nil
+	case "inhibited":
+		return StateInhibited, nil
 	case "normal":
 		return StateNormal, nil
 	default:
@@ -0,0 +1,53 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route POST /prometheus/grafana/api/v1/rules/{RuleUID}/alerts/{Fingerprint}/acknowledgement prometheus RoutePostGrafanaAlertAcknowledgement
+//
+// Acknowledge a firing alert instance. The acknowledgement is removed when it expires or the alert instance stops firing.
+// While the alert instance is acknowledged, repeated notifications do not include it.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: Alert
+//       400: ValidationError
+//       404: NotFound
+
+// swagger:route DELETE /prometheus/grafana/api/v1/rules/{RuleUID}/alerts/{Fingerprint}/acknowledgement prometheus RouteDeleteGrafanaAlertAcknowledgement
+//
+// Remove the acknowledgement of a firing alert instance.
+//
+//     Responses:
+//       200: Alert
+//       400: ValidationError
+//       404: NotFound
+
+// swagger:parameters RoutePostGrafanaAlertAcknowledgement RouteDeleteGrafanaAlertAcknowledgement
+type AlertAcknowledgementParams struct {
+	// UID of the alert rule.
+	// in: path
+	RuleUID string
+	// Fingerprint of the alert instance, as returned by the alerts API.
+	// in: path
+	Fingerprint string
+}
+
+// swagger:parameters RoutePostGrafanaAlertAcknowledgement
+type PostableAlertAcknowledgementParams struct {
+	// in:body
+	Body PostableAlertAcknowledgement
+}
+
+// PostableAlertAcknowledgement is the request to acknowledge a firing alert instance.
+// swagger:model
+type PostableAlertAcknowledgement struct {
+	// Comment shown to other users and available to notification templates.
+	Comment string `json:"comment,omitempty"`
+	// Time the acknowledgement expires. It must be in the future.
+	// required: true
+	ExpiresAt time.Time `json:"expiresAt"`
+}
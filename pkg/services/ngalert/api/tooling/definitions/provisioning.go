@@ -3,11 +3,12 @@ package definitions
 // AlertingFileExport is the full provisioned file export.
 // swagger:model
 type AlertingFileExport struct {
-	APIVersion    int64                      `json:"apiVersion" yaml:"apiVersion"`
-	Groups        []AlertRuleGroupExport     `json:"groups,omitempty" yaml:"groups,omitempty"`
-	ContactPoints []ContactPointExport       `json:"contactPoints,omitempty" yaml:"contactPoints,omitempty"`
-	Policies      []NotificationPolicyExport `json:"policies,omitempty" yaml:"policies,omitempty"`
-	MuteTimings   []MuteTimeIntervalExport   `json:"muteTimes,omitempty" yaml:"muteTimes,omitempty"`
+	APIVersion         int64                      `json:"apiVersion" yaml:"apiVersion"`
+	Groups             []AlertRuleGroupExport     `json:"groups,omitempty" yaml:"groups,omitempty"`
+	ContactPoints      []ContactPointExport       `json:"contactPoints,omitempty" yaml:"contactPoints,omitempty"`
+	Policies           []NotificationPolicyExport `json:"policies,omitempty" yaml:"policies,omitempty"`
+	MuteTimings        []MuteTimeIntervalExport   `json:"muteTimes,omitempty" yaml:"muteTimes,omitempty"`
+	MaintenanceWindows []MaintenanceWindowExport  `json:"maintenanceWindows,omitempty" yaml:"maintenanceWindows,omitempty"`
 }
 
 // swagger:parameters RouteGetAlertRuleGroupExport RouteGetAlertRuleExport RouteGetContactpointsExport RouteGetContactpointExport RoutePostRulesGroupForExport RouteExportMuteTimings RouteExportMuteTiming
@@ -0,0 +1,165 @@
+package definitions
+
+import (
+	"github.com/prometheus/alertmanager/timeinterval"
+)
+
+// swagger:route GET /v1/provisioning/maintenance-windows provisioning stable RouteGetMaintenanceWindows
+//
+// Get all the maintenance windows.
+//
+//     Responses:
+//       200: MaintenanceWindows
+
+// swagger:route GET /v1/provisioning/maintenance-windows/export provisioning stable RouteExportMaintenanceWindows
+//
+// Export all maintenance windows in provisioning format.
+//
+//     Produces:
+//     - application/json
+//     - application/yaml
+//     - text/yaml
+//
+//     Responses:
+//       200: AlertingFileExport
+//       400: ValidationError
+//       403: PermissionDenied
+
+// swagger:route GET /v1/provisioning/maintenance-windows/{UID} provisioning stable RouteGetMaintenanceWindow
+//
+// Get a maintenance window.
+//
+//     Responses:
+//       200: MaintenanceWindow
+//       404: description: Not found.
+
+// swagger:route GET /v1/provisioning/maintenance-windows/{UID}/export provisioning stable RouteExportMaintenanceWindow
+//
+// Export a maintenance window in provisioning format.
+//
+//     Produces:
+//     - application/json
+//     - application/yaml
+//     - text/yaml
+//
+//     Responses:
+//       200: AlertingFileExport
+//       400: ValidationError
+//       403: PermissionDenied
+//       404: description: Not found.
+
+// swagger:route POST /v1/provisioning/maintenance-windows provisioning stable RoutePostMaintenanceWindow
+//
+// Create a new maintenance window.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: MaintenanceWindow
+//       400: ValidationError
+
+// swagger:route PUT /v1/provisioning/maintenance-windows/{UID} provisioning stable RoutePutMaintenanceWindow
+//
+// Replace an existing maintenance window.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: MaintenanceWindow
+//       400: ValidationError
+//       404: description: Not found.
+//       409: GenericPublicError
+
+// swagger:route DELETE /v1/provisioning/maintenance-windows/{UID} provisioning stable RouteDeleteMaintenanceWindow
+//
+// Delete a maintenance window.
+//
+//     Responses:
+//       204: description: The maintenance window was deleted successfully.
+//       409: GenericPublicError
+
+// swagger:model
+type MaintenanceWindows []MaintenanceWindow
+
+// swagger:parameters RouteGetMaintenanceWindow RoutePutMaintenanceWindow RouteExportMaintenanceWindow
+type MaintenanceWindowUIDParam struct {
+	// Maintenance window UID
+	// in:path
+	UID string
+}
+
+// swagger:parameters RouteDeleteMaintenanceWindow
+type RouteDeleteMaintenanceWindowParam struct {
+	// Maintenance window UID
+	// in:path
+	UID string
+
+	// Version of the maintenance window to use for optimistic concurrency. Leave empty to disable validation
+	// in:query
+	Version int64 `json:"version"`
+}
+
+// swagger:parameters RoutePostMaintenanceWindow RoutePutMaintenanceWindow
+type MaintenanceWindowPayload struct {
+	// in:body
+	Body MaintenanceWindow
+}
+
+// swagger:parameters RoutePostMaintenanceWindow RoutePutMaintenanceWindow RouteDeleteMaintenanceWindow
+type MaintenanceWindowHeaders struct {
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+}
+
+// swagger:parameters RouteExportMaintenanceWindows RouteExportMaintenanceWindow
+type MaintenanceWindowExportQueryParams struct {
+	// Whether to initiate a download of the file or not.
+	// in: query
+	// required: false
+	// default: false
+	Download bool `json:"download"`
+
+	// Format of the downloaded file. Supported yaml or json. Accept header can also be used, but the query parameter will take precedence.
+	// in: query
+	// required: false
+	// default: yaml
+	// enum: yaml,json
+	Format string `json:"format"`
+}
+
+// MaintenanceWindow is a recurring period during which the matched alert rules are either muted or not evaluated.
+// swagger:model
+type MaintenanceWindow struct {
+	// example: maintenance-db
+	UID string `json:"uid,omitempty" yaml:"uid,omitempty"`
+	// required: true
+	// example: Database maintenance
+	Title string `json:"title" yaml:"title"`
+	// What happens to the matched rules while the window is active. Rules of a muted window are evaluated but their
+	// notifications are silenced. Rules of a paused window are not evaluated, and their state history is not recorded.
+	// required: true
+	// enum: mute,pause
+	Mode string `json:"mode" yaml:"mode"`
+	// The recurrence of the window. The window is active when any of the intervals contains the current time.
+	// required: true
+	TimeIntervals []timeinterval.TimeInterval `json:"time_intervals" yaml:"time_intervals"`
+	// Matchers of the rule labels. All matchers must match for the rule to be selected. A window requires at least one matcher or folder.
+	ObjectMatchers ObjectMatchers `json:"object_matchers,omitempty" yaml:"object_matchers,omitempty"`
+	// The window applies only to the rules in these folders. If empty, rules in all folders are selected.
+	FolderUIDs []string   `json:"folder_uids,omitempty" yaml:"folder_uids,omitempty"`
+	Version    int64      `json:"version,omitempty" yaml:"-"`
+	Provenance Provenance `json:"provenance,omitempty" yaml:"-"`
+}
+
+// MaintenanceWindowExport is the provisioned file export of the maintenance window.
+type MaintenanceWindowExport struct {
+	OrgID          int64                       `json:"orgId" yaml:"orgId"`
+	UID            string                      `json:"uid" yaml:"uid"`
+	Title          string                      `json:"title" yaml:"title"`
+	Mode           string                      `json:"mode" yaml:"mode"`
+	TimeIntervals  []timeinterval.TimeInterval `json:"time_intervals" yaml:"time_intervals"`
+	ObjectMatchers ObjectMatchers              `json:"object_matchers,omitempty" yaml:"object_matchers,omitempty"`
+	FolderUIDs     []string                    `json:"folder_uids,omitempty" yaml:"folder_uids,omitempty"`
+}
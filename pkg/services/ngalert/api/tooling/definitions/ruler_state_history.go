@@ -41,6 +41,15 @@ type StateHistoryParams struct {
 	// in:query
 	// required: false
 	Limit int `json:"limit"`
+	// Number of most recent records to skip. Used together with limit to page through the history.
+	// in:query
+	// required: false
+	Offset int `json:"offset"`
+	// Filter by the labels of alert instances. The value is a JSON encoded matcher, for example {"name": "team", "value": "ops.*", "isRegex": true}.
+	// Not supported when the state history is configured to use annotations.
+	// in:query
+	// required: false
+	Matcher []string `json:"matcher"`
 	// Filter by rule UID. Required the state history is configured to use annotations for storage.
 	// in:query
 	// required: false
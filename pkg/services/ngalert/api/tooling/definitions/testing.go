@@ -68,6 +68,20 @@ import (
 //     Responses:
 //       200: BacktestResult
 
+// swagger:route Post /v1/rule/backtest/whatif testing BacktestWhatIf
+//
+// Replay a rule or a rule group over a historical time range and route the resulting notifications through a notification policy tree
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: BacktestWhatIfResult
+//       400: ValidationError
+
 // swagger:parameters RouteTestReceiverConfig
 type TestReceiverRequest struct {
 	// in:body
@@ -234,3 +248,68 @@ type BacktestConfig struct {
 
 // swagger:model
 type BacktestResult data.Frame
+
+// swagger:parameters BacktestWhatIf
+type BacktestWhatIfRequest struct {
+	// in:body
+	Body BacktestWhatIfConfig
+}
+
+// swagger:model
+type BacktestWhatIfConfig struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	// UID of the folder the rules belong to. Optional. It is used to populate the grafana_folder label.
+	NamespaceUID string `json:"namespace_uid,omitempty"`
+
+	// A single rule to replay. Either rule or rule_group must be specified.
+	Rule *PostableExtendedRuleNode `json:"rule,omitempty"`
+	// Evaluation interval of the single rule. Defaults to the default evaluation interval.
+	Interval model.Duration `json:"interval,omitempty"`
+
+	// A rule group to replay. Rules are evaluated in order, so that dependencies between rules of the group are honored.
+	RuleGroup *PostableRuleGroupConfig `json:"rule_group,omitempty"`
+
+	// The notification policy tree the notifications are routed through.
+	// required: true
+	Route *Route `json:"route"`
+
+	// The time intervals referenced by the mute time intervals of the notification policy tree.
+	TimeIntervals []config.TimeInterval `json:"time_intervals,omitempty"`
+}
+
+// swagger:model
+type BacktestWhatIfResult struct {
+	// The initial state of every alert instance and every change of state afterward.
+	Timeline []BacktestStateChange `json:"timeline"`
+	// Every notification that would have been sent, in order of time.
+	Notifications []BacktestNotification `json:"notifications"`
+}
+
+// swagger:model
+type BacktestStateChange struct {
+	Time          time.Time         `json:"time"`
+	RuleUID       string            `json:"rule_uid"`
+	Labels        map[string]string `json:"labels"`
+	PreviousState string            `json:"previous_state"`
+	State         string            `json:"state"`
+}
+
+// swagger:model
+type BacktestNotification struct {
+	Time        time.Time                   `json:"time"`
+	Receiver    string                      `json:"receiver"`
+	GroupKey    string                      `json:"group_key"`
+	GroupLabels map[string]string           `json:"group_labels"`
+	Alerts      []BacktestNotificationAlert `json:"alerts"`
+}
+
+// swagger:model
+type BacktestNotificationAlert struct {
+	// firing or resolved
+	Status   string            `json:"status"`
+	Labels   map[string]string `json:"labels"`
+	StartsAt time.Time         `json:"startsAt"`
+	EndsAt   time.Time         `json:"endsAt"`
+}
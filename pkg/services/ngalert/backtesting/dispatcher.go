@@ -0,0 +1,209 @@
+package backtesting
+
+import (
+	"sort"
+	"time"
+
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+)
+
+// Notification is a notification that the simulated Alertmanager would have sent to a receiver.
+type Notification struct {
+	Time        time.Time
+	Receiver    string
+	GroupKey    string
+	GroupLabels model.LabelSet
+	Alerts      []*model.Alert
+}
+
+// dispatcher replays alerts through a notification policy tree, mimicking grouping and the timing of notifications
+// (group_wait, group_interval and repeat_interval) of the Alertmanager dispatcher on a simulated clock.
+// Like in the Alertmanager notification pipeline, notifications of a route are not sent while the route is muted by
+// its mute time intervals or outside its active time intervals.
+type dispatcher struct {
+	route         *dispatch.Route
+	intervener    *timeinterval.Intervener
+	groups        map[string]*aggregationGroup
+	notifications []Notification
+}
+
+// newDispatcher returns a dispatcher for route. timeIntervals must contain every time interval referenced by route.
+func newDispatcher(route *dispatch.Route, timeIntervals map[string][]timeinterval.TimeInterval) *dispatcher {
+	return &dispatcher{
+		route:      route,
+		intervener: timeinterval.NewIntervener(timeIntervals),
+		groups:     make(map[string]*aggregationGroup),
+	}
+}
+
+type aggregationGroup struct {
+	route  *dispatch.Route
+	key    string
+	labels model.LabelSet
+	alerts map[model.Fingerprint]*model.Alert
+	next   time.Time
+
+	hasFlushed   bool
+	lastSentAt   time.Time
+	lastFiring   map[model.Fingerprint]struct{}
+	lastResolved map[model.Fingerprint]struct{}
+}
+
+// add flushes all groups that are due before now and then routes the alerts to their aggregation groups.
+func (d *dispatcher) add(now time.Time, alerts []*model.Alert) {
+	d.advance(now)
+	for _, alert := range alerts {
+		for _, r := range d.route.Match(alert.Labels) {
+			labels := groupLabels(alert, r)
+			key := r.Key() + ":" + labels.String()
+			ag, ok := d.groups[key]
+			if !ok {
+				ag = &aggregationGroup{
+					route:  r,
+					key:    key,
+					labels: labels,
+					alerts: make(map[model.Fingerprint]*model.Alert),
+					next:   now.Add(r.RouteOpts.GroupWait),
+				}
+				d.groups[key] = ag
+			}
+			// Alertmanager flushes immediately if the alert is older than the group wait.
+			if !ag.hasFlushed && alert.StartsAt.Add(r.RouteOpts.GroupWait).Before(now) {
+				ag.next = now
+			}
+			ag.alerts[alert.Fingerprint()] = alert
+		}
+	}
+}
+
+// advance flushes, in order of time, all groups that are due at or before until.
+func (d *dispatcher) advance(until time.Time) {
+	for {
+		var due *aggregationGroup
+		for _, ag := range d.groups {
+			if ag.next.After(until) {
+				continue
+			}
+			if due == nil || ag.next.Before(due.next) || (ag.next.Equal(due.next) && ag.key < due.key) {
+				due = ag
+			}
+		}
+		if due == nil {
+			return
+		}
+		d.flush(due)
+	}
+}
+
+func (d *dispatcher) flush(ag *aggregationGroup) {
+	now := ag.next
+	alerts := make([]*model.Alert, 0, len(ag.alerts))
+	firing := make(map[model.Fingerprint]struct{})
+	resolved := make(map[model.Fingerprint]struct{})
+	for fp, a := range ag.alerts {
+		alerts = append(alerts, a)
+		if a.ResolvedAt(now) {
+			resolved[fp] = struct{}{}
+		} else {
+			firing[fp] = struct{}{}
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].Labels.Before(alerts[j].Labels)
+	})
+
+	if ag.needsUpdate(now, firing, resolved) && !d.muted(ag.route, now) {
+		snapshot := make([]*model.Alert, 0, len(alerts))
+		for _, a := range alerts {
+			c := *a
+			if c.ResolvedAt(now) {
+				c.EndsAt = minTime(c.EndsAt, now)
+			}
+			snapshot = append(snapshot, &c)
+		}
+		d.notifications = append(d.notifications, Notification{
+			Time:        now,
+			Receiver:    ag.route.RouteOpts.Receiver,
+			GroupKey:    ag.key,
+			GroupLabels: ag.labels,
+			Alerts:      snapshot,
+		})
+		ag.lastSentAt = now
+		ag.lastFiring = firing
+		ag.lastResolved = resolved
+	}
+	ag.hasFlushed = true
+
+	for fp := range resolved {
+		delete(ag.alerts, fp)
+	}
+	if len(ag.alerts) == 0 {
+		delete(d.groups, ag.key)
+		return
+	}
+	ag.next = now.Add(ag.route.RouteOpts.GroupInterval)
+}
+
+// muted returns true if the notifications of route are suppressed at now by its mute or active time intervals.
+// Suppressed notifications are not recorded as sent, as in the Alertmanager notification pipeline.
+func (d *dispatcher) muted(route *dispatch.Route, now time.Time) bool {
+	if len(route.RouteOpts.ActiveTimeIntervals) > 0 {
+		active, err := d.intervener.Mutes(route.RouteOpts.ActiveTimeIntervals, now)
+		if err != nil || !active {
+			return true
+		}
+	}
+	if len(route.RouteOpts.MuteTimeIntervals) > 0 {
+		muted, err := d.intervener.Mutes(route.RouteOpts.MuteTimeIntervals, now)
+		if err != nil || muted {
+			return true
+		}
+	}
+	return false
+}
+
+// needsUpdate follows the deduplication logic of the Alertmanager notification pipeline.
+func (ag *aggregationGroup) needsUpdate(now time.Time, firing, resolved map[model.Fingerprint]struct{}) bool {
+	if ag.lastSentAt.IsZero() {
+		return len(firing) > 0
+	}
+	if !isSubset(firing, ag.lastFiring) {
+		return true
+	}
+	if len(firing) == 0 {
+		// Notify about all alerts being resolved, unless it was already done.
+		return !isSubset(resolved, ag.lastResolved)
+	}
+	if !isSubset(resolved, ag.lastResolved) {
+		return true
+	}
+	return !ag.lastSentAt.Add(ag.route.RouteOpts.RepeatInterval).After(now)
+}
+
+func groupLabels(alert *model.Alert, route *dispatch.Route) model.LabelSet {
+	groupLabels := model.LabelSet{}
+	for ln, lv := range alert.Labels {
+		if _, ok := route.RouteOpts.GroupBy[ln]; ok || route.RouteOpts.GroupByAll {
+			groupLabels[ln] = lv
+		}
+	}
+	return groupLabels
+}
+
+func isSubset(set, superset map[model.Fingerprint]struct{}) bool {
+	for fp := range set {
+		if _, ok := superset[fp]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
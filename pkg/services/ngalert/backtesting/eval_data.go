@@ -41,8 +41,10 @@ func (d *dataEvaluator) Eval(_ context.Context, from time.Time, interval time.Du
 	var resampled = make([]mathexp.Series, 0, len(d.data))
 	to := from.Add(time.Duration(evaluations) * interval)
 	for _, s := range d.data {
-		// making sure the input data frame is aligned with the interval
-		r, err := s.Resample(d.refID, interval, d.downsampleFunction, d.upsampleFunction, from, to.Add(-interval)) // we want to query [from,to)
+		// making sure the input data frame is aligned with the interval.
+		// We want to query [from,to) but resample [from,to] because a series cannot be resampled to a single point.
+		// The last point is never evaluated.
+		r, err := s.Resample(d.refID, interval, d.downsampleFunction, d.upsampleFunction, from, to)
 		if err != nil {
 			return err
 		}
@@ -158,6 +158,24 @@ func TestDataEvaluator_Eval(t *testing.T) {
 			}
 		})
 	})
+	t.Run("should evaluate a single point", func(t *testing.T) {
+		// What-if testing evaluates the rules of a group one evaluation at a time.
+		start := frame.At(0, 10).(time.Time)
+		var r []results
+		err = evaluator.Eval(context.Background(), start, time.Second, 1, func(idx int, now time.Time, res eval.Results) error {
+			r = append(r, results{now, res})
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, r, 1)
+		require.Equal(t, start, r[0].time)
+		for idx, result := range r[0].results {
+			expected, err := frame.Fields[idx+1].FloatAt(10)
+			require.NoError(t, err)
+			require.EqualValues(t, expected, *result.Values[refID].Value)
+		}
+	})
+
 	t.Run("when frame resolution does not match evaluation interval", func(t *testing.T) {
 		t.Run("should closest timestamp if interval is smaller than frame resolution", func(t *testing.T) {
 			interval := 300 * time.Millisecond
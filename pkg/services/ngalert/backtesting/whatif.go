@@ -0,0 +1,176 @@
+package backtesting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// WhatIfResult is the result of replaying a rule group over a historical time range.
+type WhatIfResult struct {
+	// Timeline contains the initial state of every series and every change of state afterward.
+	Timeline []StateChange
+	// Notifications contains every notification that would have been sent, in order of time.
+	Notifications []Notification
+}
+
+// StateChange describes a change of state of an alert instance at a specific evaluation.
+type StateChange struct {
+	Time          time.Time
+	RuleUID       string
+	Labels        data.Labels
+	PreviousState string
+	State         string
+}
+
+// WhatIf evaluates the rules of a group over the time range [from,to) using the same state manager as the scheduler,
+// and routes the alerts that the scheduler would send through the notification policy tree.
+// The rules must have the same evaluation interval. Paused rules are not evaluated.
+// extraLabels contains the labels that are added to the alert instances of a rule, keyed by the rule UID.
+// timeIntervals must contain the time intervals referenced by the mute time intervals of the route.
+func (e *Engine) WhatIf(ctx context.Context, user identity.Requester, rules []*models.AlertRule, extraLabels map[string]data.Labels, route *apimodels.Route, timeIntervals []config.TimeInterval, from, to time.Time) (*WhatIfResult, error) {
+	logger := logger.FromContext(ctx)
+
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("%w: at least one rule is required", ErrInvalidInputData)
+	}
+	if route == nil {
+		return nil, fmt.Errorf("%w: notification policy tree is required", ErrInvalidInputData)
+	}
+	if err := route.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: invalid notification policy tree: %s", ErrInvalidInputData, err)
+	}
+	intervals := make(map[string][]timeinterval.TimeInterval, len(timeIntervals))
+	for _, ti := range timeIntervals {
+		intervals[ti.Name] = ti.TimeIntervals
+	}
+	if err := checkTimeIntervals(route, intervals); err != nil {
+		return nil, fmt.Errorf("%w: invalid notification policy tree: %s", ErrInvalidInputData, err)
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: invalid interval of the backtesting [%d,%d]", ErrInvalidInputData, from.Unix(), to.Unix())
+	}
+	intervalSeconds := rules[0].IntervalSeconds
+	for _, rule := range rules {
+		if rule.IntervalSeconds != intervalSeconds {
+			return nil, fmt.Errorf("%w: all rules must have the same evaluation interval", ErrInvalidInputData)
+		}
+	}
+	if to.Sub(from).Seconds() < float64(intervalSeconds) {
+		return nil, fmt.Errorf("%w: interval of the backtesting [%d,%d] is less than evaluation interval [%ds]", ErrInvalidInputData, from.Unix(), to.Unix(), intervalSeconds)
+	}
+	interval := time.Duration(intervalSeconds) * time.Second
+	length := int(to.Sub(from) / interval)
+
+	stateManager := e.createStateManager()
+
+	evaluators := make([]backtestingEvaluator, len(rules))
+	for i, rule := range rules {
+		if rule.IsPaused {
+			continue
+		}
+		evaluator, err := backtestingEvaluatorFactory(models.WithRuleKey(ctx, rule.GetKey()), e.evalFactory, user, rule.GetEvalCondition().WithSource("backtesting"), &schedule.AlertingResultsFromRuleState{
+			Manager: stateManager,
+			Rule:    rule,
+		})
+		if err != nil {
+			return nil, errors.Join(ErrInvalidInputData, fmt.Errorf("rule %s: %w", rule.UID, err))
+		}
+		evaluators[i] = evaluator
+	}
+
+	logger.Info("Start what-if testing of alert rules", "from", from, "to", to, "interval", intervalSeconds, "evaluations", length, "rules", len(rules))
+	start := time.Now()
+
+	result := &WhatIfResult{}
+	d := newDispatcher(dispatch.NewRoute(route.AsAMRoute(), nil), intervals)
+	for idx := 0; idx < length; idx++ {
+		now := from.Add(time.Duration(idx) * interval)
+		var alerts []*model.Alert
+		send := func(_ context.Context, transitions state.StateTransitions) {
+			for _, t := range transitions {
+				alerts = append(alerts, postableToModelAlert(state.StateToPostableAlert(t, nil)))
+			}
+		}
+		// Rules of a group are evaluated sequentially, so rules that depend on other rules of the group see their latest state.
+		for i, rule := range rules {
+			if evaluators[i] == nil {
+				continue
+			}
+			ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
+			err := evaluators[i].Eval(ruleCtx, now, interval, 1, func(_ int, evaluatedAt time.Time, results eval.Results) error {
+				transitions := stateManager.ProcessEvalResults(ruleCtx, evaluatedAt, rule, results, extraLabels[rule.UID], send)
+				for _, t := range transitions {
+					if idx > 0 && !t.Changed() {
+						continue
+					}
+					result.Timeline = append(result.Timeline, StateChange{
+						Time:          evaluatedAt,
+						RuleUID:       rule.UID,
+						Labels:        t.Labels,
+						PreviousState: t.PreviousFormatted(),
+						State:         t.Formatted(),
+					})
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to evaluate rule %s at %s: %w", rule.UID, now, err)
+			}
+		}
+		d.add(now, alerts)
+	}
+	d.advance(to)
+	result.Notifications = d.notifications
+
+	logger.Info("What-if testing finished successfully", "duration", time.Since(start), "notifications", len(result.Notifications))
+	return result, nil
+}
+
+// checkTimeIntervals returns an error if a route of the tree references a time interval that is not defined.
+func checkTimeIntervals(route *apimodels.Route, intervals map[string][]timeinterval.TimeInterval) error {
+	for _, name := range route.MuteTimeIntervals {
+		if _, ok := intervals[name]; !ok {
+			return fmt.Errorf("undefined time interval %q used in route", name)
+		}
+	}
+	for _, r := range route.Routes {
+		if err := checkTimeIntervals(r, intervals); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func postableToModelAlert(alert *amv2.PostableAlert) *model.Alert {
+	labels := make(model.LabelSet, len(alert.Labels))
+	for k, v := range alert.Labels {
+		labels[model.LabelName(k)] = model.LabelValue(v)
+	}
+	annotations := make(model.LabelSet, len(alert.Annotations))
+	for k, v := range alert.Annotations {
+		annotations[model.LabelName(k)] = model.LabelValue(v)
+	}
+	return &model.Alert{
+		Labels:       labels,
+		Annotations:  annotations,
+		StartsAt:     time.Time(alert.StartsAt),
+		EndsAt:       time.Time(alert.EndsAt),
+		GeneratorURL: string(alert.GeneratorURL),
+	}
+}
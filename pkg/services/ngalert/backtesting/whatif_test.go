@@ -0,0 +1,210 @@
+package backtesting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestWhatIf(t *testing.T) {
+	from := time.Unix(0, 0).UTC()
+	to := from.Add(200 * time.Second)
+
+	// The condition of the rule with UID "upstream" is met in [30s,100s), the one of "downstream" is always met.
+	resultsByRule := map[string]func(now time.Time) eval.Results{
+		"upstream": func(now time.Time) eval.Results {
+			s := eval.Normal
+			if elapsed := now.Sub(from); elapsed >= 30*time.Second && elapsed < 100*time.Second {
+				s = eval.Alerting
+			}
+			return eval.Results{{Instance: data.Labels{"series": "a"}, State: s, EvaluatedAt: now}}
+		},
+		"downstream": func(now time.Time) eval.Results {
+			return eval.Results{{Instance: data.Labels{"series": "b"}, State: eval.Alerting, EvaluatedAt: now}}
+		},
+	}
+	backtestingEvaluatorFactory = func(ctx context.Context, evalFactory eval.EvaluatorFactory, user identity.Requester, condition models.Condition, r eval.AlertingResultsReader) (backtestingEvaluator, error) {
+		key, _ := models.RuleKeyFromContext(ctx)
+		return &fakeBacktestingEvaluator{
+			evalCallback: func(now time.Time) (eval.Results, error) {
+				return resultsByRule[key.UID](now), nil
+			},
+		}, nil
+	}
+	t.Cleanup(func() {
+		backtestingEvaluatorFactory = newBacktestingEvaluator
+	})
+
+	engine := NewEngine(nil, nil, tracing.InitializeTracerForTest())
+
+	gen := models.RuleGen
+	upstream := gen.With(
+		gen.WithUID("upstream"),
+		gen.WithOrgID(1),
+		gen.WithInterval(10*time.Second),
+		gen.WithFor(20*time.Second),
+		gen.WithKeepFiringFor(0),
+		gen.WithLabels(data.Labels{"team": "ops"}),
+		gen.WithNoNotificationSettings(),
+		gen.WithIsPaused(false),
+	).GenerateRef()
+
+	extraLabels := map[string]data.Labels{
+		upstream.UID: state.GetRuleExtraLabels(log.NewNopLogger(), upstream, "folder", true),
+	}
+
+	opsMatcher, err := labels.NewMatcher(labels.MatchEqual, "team", "ops")
+	require.NoError(t, err)
+	groupWait := model.Duration(30 * time.Second)
+	groupInterval := model.Duration(time.Minute)
+	route := func() *apimodels.Route {
+		return &apimodels.Route{
+			Receiver:      "default",
+			GroupByStr:    []string{"alertname"},
+			GroupWait:     &groupWait,
+			GroupInterval: &groupInterval,
+			Routes: []*apimodels.Route{
+				{
+					Receiver:       "ops",
+					ObjectMatchers: apimodels.ObjectMatchers{opsMatcher},
+				},
+			},
+		}
+	}
+
+	t.Run("should return state timeline and notifications", func(t *testing.T) {
+		result, err := engine.WhatIf(context.Background(), nil, []*models.AlertRule{upstream}, extraLabels, route(), nil, from, to)
+		require.NoError(t, err)
+
+		var timeline []string
+		for _, c := range result.Timeline {
+			require.Equal(t, "upstream", c.RuleUID)
+			require.Equal(t, "a", c.Labels["series"])
+			timeline = append(timeline, c.Time.Sub(from).String()+" "+c.PreviousState+" -> "+c.State)
+		}
+		require.Equal(t, []string{
+			"0s Normal -> Normal",
+			"30s Normal -> Pending",
+			"50s Pending -> Alerting",
+			"1m40s Alerting -> Normal",
+		}, timeline)
+
+		require.Len(t, result.Notifications, 2)
+
+		firing := result.Notifications[0]
+		require.Equal(t, from.Add(80*time.Second), firing.Time, "should be sent after group_wait")
+		require.Equal(t, "ops", firing.Receiver)
+		require.Equal(t, model.LabelSet{model.AlertNameLabel: model.LabelValue(upstream.Title)}, firing.GroupLabels)
+		require.Len(t, firing.Alerts, 1)
+		require.False(t, firing.Alerts[0].ResolvedAt(firing.Time))
+		require.Equal(t, model.LabelValue("a"), firing.Alerts[0].Labels["series"])
+
+		resolved := result.Notifications[1]
+		require.Equal(t, from.Add(140*time.Second), resolved.Time, "should be sent after group_interval")
+		require.Equal(t, "ops", resolved.Receiver)
+		require.Len(t, resolved.Alerts, 1)
+		require.True(t, resolved.Alerts[0].ResolvedAt(resolved.Time))
+	})
+
+	t.Run("should not notify about pending alerts", func(t *testing.T) {
+		rule := models.CopyRule(upstream)
+		rule.For = 2 * time.Minute
+		result, err := engine.WhatIf(context.Background(), nil, []*models.AlertRule{rule}, nil, route(), nil, from, to)
+		require.NoError(t, err)
+		require.Empty(t, result.Notifications)
+	})
+
+	t.Run("should not send notifications while the route is muted", func(t *testing.T) {
+		r := route()
+		r.Routes[0].MuteTimeIntervals = []string{"maintenance"}
+		timeIntervals := []config.TimeInterval{{
+			Name:          "maintenance",
+			TimeIntervals: []timeinterval.TimeInterval{{Times: []timeinterval.TimeRange{{StartMinute: 1, EndMinute: 2}}}},
+		}}
+		result, err := engine.WhatIf(context.Background(), nil, []*models.AlertRule{upstream}, extraLabels, r, timeIntervals, from, to)
+		require.NoError(t, err)
+		// The alert fires at 1m20s, when the route is muted, and is resolved before the next flush.
+		require.Empty(t, result.Notifications)
+	})
+
+	t.Run("should inhibit rules that depend on firing rules of the group", func(t *testing.T) {
+		downstream := gen.With(
+			gen.WithUID("downstream"),
+			gen.WithOrgID(1),
+			gen.WithInterval(10*time.Second),
+			gen.WithFor(0),
+			gen.WithKeepFiringFor(0),
+			gen.WithLabels(data.Labels{"team": "db"}),
+			gen.WithNoNotificationSettings(),
+			gen.WithIsPaused(false),
+			gen.WithDependsOn("upstream"),
+		).GenerateRef()
+
+		result, err := engine.WhatIf(context.Background(), nil, []*models.AlertRule{upstream, downstream}, nil, route(), nil, from, to)
+		require.NoError(t, err)
+
+		var timeline []string
+		for _, c := range result.Timeline {
+			if c.RuleUID == "downstream" {
+				timeline = append(timeline, c.Time.Sub(from).String()+" "+c.PreviousState+" -> "+c.State)
+			}
+		}
+		require.Equal(t, []string{
+			"0s Normal -> Alerting",
+			"50s Alerting -> Inhibited",
+			"1m40s Inhibited -> Alerting",
+		}, timeline)
+
+		receivers := map[string]int{}
+		for _, n := range result.Notifications {
+			receivers[n.Receiver]++
+		}
+		require.Equal(t, 2, receivers["ops"])
+		require.Positive(t, receivers["default"])
+	})
+
+	t.Run("should fail", func(t *testing.T) {
+		t.Run("when policy tree is missing", func(t *testing.T) {
+			_, err := engine.WhatIf(context.Background(), nil, []*models.AlertRule{upstream}, nil, nil, nil, from, to)
+			require.ErrorIs(t, err, ErrInvalidInputData)
+		})
+		t.Run("when root policy has no receiver", func(t *testing.T) {
+			r := route()
+			r.Receiver = ""
+			_, err := engine.WhatIf(context.Background(), nil, []*models.AlertRule{upstream}, nil, r, nil, from, to)
+			require.ErrorIs(t, err, ErrInvalidInputData)
+		})
+		t.Run("when route uses an undefined time interval", func(t *testing.T) {
+			r := route()
+			r.Routes[0].MuteTimeIntervals = []string{"maintenance"}
+			_, err := engine.WhatIf(context.Background(), nil, []*models.AlertRule{upstream}, nil, r, nil, from, to)
+			require.ErrorIs(t, err, ErrInvalidInputData)
+		})
+		t.Run("when rules have different intervals", func(t *testing.T) {
+			other := models.CopyRule(upstream)
+			other.UID = "other"
+			other.IntervalSeconds = 20
+			_, err := engine.WhatIf(context.Background(), nil, []*models.AlertRule{upstream, other}, nil, route(), nil, from, to)
+			require.ErrorIs(t, err, ErrInvalidInputData)
+		})
+		t.Run("when range is less than interval", func(t *testing.T) {
+			_, err := engine.WhatIf(context.Background(), nil, []*models.AlertRule{upstream}, nil, route(), nil, from, from.Add(5*time.Second))
+			require.ErrorIs(t, err, ErrInvalidInputData)
+		})
+	})
+}
@@ -0,0 +1,300 @@
+package cost
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	// BudgetRefreshInterval is how often the evaluation budgets of the organizations are reloaded
+	// and the interval multipliers are recalculated.
+	BudgetRefreshInterval = time.Minute
+	// MaxIntervalMultiplier is the maximum factor the evaluation intervals of an organization are stretched by
+	// when the organization exceeds its evaluation budget.
+	MaxIntervalMultiplier = 10
+	// averageWeight is the weight of the latest evaluation in the moving averages of the cost of a rule.
+	averageWeight = 0.2
+)
+
+// RuleCost is the accounted cost of the evaluations of an alert rule.
+type RuleCost struct {
+	models.AlertRuleKey
+	Title        string
+	NamespaceUID string
+	RuleGroup    string
+	// IntervalSeconds is the configured evaluation interval of the rule.
+	IntervalSeconds int64
+	Evaluations     int64
+	LastEvaluation  time.Time
+	// Last is the cost of the latest evaluation.
+	Last eval.EvaluationCost
+	// AvgDuration, AvgQueryDuration and AvgSeries are exponentially weighted moving averages of the cost of the
+	// evaluations, so that a single slow evaluation does not change the cost of the rule much.
+	AvgDuration      time.Duration
+	AvgQueryDuration time.Duration
+	AvgSeries        float64
+}
+
+// PerMinute returns the evaluation time the rule consumes per minute when it is evaluated at its configured interval.
+func (c RuleCost) PerMinute() time.Duration {
+	if c.IntervalSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(float64(c.AvgDuration) * 60 / float64(c.IntervalSeconds))
+}
+
+// OrgCost is the accounted cost of the evaluations of all rules of an organization.
+type OrgCost struct {
+	// PerMinute is the sum of the evaluation time per minute of the rules of the organization.
+	PerMinute time.Duration
+	// Budget is the evaluation time per minute the organization is allowed to consume. Zero means no budget.
+	Budget time.Duration
+	// IntervalMultiplier is the factor the evaluation intervals of the rules of the organization are stretched by.
+	IntervalMultiplier int64
+}
+
+// BudgetProvider provides the evaluation budgets of organizations.
+type BudgetProvider interface {
+	// EvaluationBudget returns the evaluation time per minute the organization is allowed to consume.
+	// Zero means the organization has no budget.
+	EvaluationBudget(ctx context.Context, orgID int64) (time.Duration, error)
+}
+
+// Tracker accounts for the cost of the evaluations of alert rules, and calculates how much the evaluation intervals
+// of the organizations that exceed their evaluation budget should be stretched.
+//
+// The cost is tracked in memory by each Grafana instance for the evaluations it runs, and is not shared between
+// replicas. In a high availability setup every replica evaluates all rules, so the budget of an organization
+// limits the cost of its rules on each replica, not the sum of the costs of all replicas.
+type Tracker struct {
+	budgets  BudgetProvider
+	slowDown bool
+	metrics  *metrics.Scheduler
+	clock    clock.Clock
+	log      log.Logger
+
+	mtx         sync.RWMutex
+	rules       map[models.AlertRuleKey]*RuleCost
+	orgBudgets  map[int64]time.Duration
+	multipliers map[int64]int64
+}
+
+// NewTracker creates a new Tracker. If slowDown is false, the evaluation budgets are only reported and the
+// evaluation intervals are never stretched.
+func NewTracker(budgets BudgetProvider, slowDown bool, m *metrics.Scheduler, clk clock.Clock, logger log.Logger) *Tracker {
+	return &Tracker{
+		budgets:     budgets,
+		slowDown:    slowDown,
+		metrics:     m,
+		clock:       clk,
+		log:         logger,
+		rules:       make(map[models.AlertRuleKey]*RuleCost),
+		orgBudgets:  make(map[int64]time.Duration),
+		multipliers: make(map[int64]int64),
+	}
+}
+
+// Run periodically reloads the evaluation budgets until the context is cancelled.
+func (t *Tracker) Run(ctx context.Context) error {
+	ticker := t.clock.Ticker(BudgetRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			t.refreshBudgets(ctx)
+		}
+	}
+}
+
+// Recorder returns the recorder of the cost of the evaluations of the rule.
+func (t *Tracker) Recorder(rule *models.AlertRule) eval.CostRecorder {
+	return &ruleRecorder{tracker: t, rule: rule}
+}
+
+// Forget removes the cost of the rule. It is called when the rule is deleted.
+func (t *Tracker) Forget(key models.AlertRuleKey) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	delete(t.rules, key)
+}
+
+// IntervalMultiplier returns the factor the evaluation intervals of the rules of the organization are stretched by.
+func (t *Tracker) IntervalMultiplier(orgID int64) int64 {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+	if m, ok := t.multipliers[orgID]; ok {
+		return m
+	}
+	return 1
+}
+
+// RuleCosts returns the cost of the rules of the organization, the most expensive first.
+func (t *Tracker) RuleCosts(orgID int64) []RuleCost {
+	t.mtx.RLock()
+	result := make([]RuleCost, 0)
+	for _, c := range t.rules {
+		if c.OrgID == orgID {
+			result = append(result, *c)
+		}
+	}
+	t.mtx.RUnlock()
+	slices.SortFunc(result, func(a, b RuleCost) int {
+		if c := cmp.Compare(b.PerMinute(), a.PerMinute()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.UID, b.UID)
+	})
+	return result
+}
+
+// OrgCost returns the cost of the rules of the organization, and its budget.
+func (t *Tracker) OrgCost(orgID int64) OrgCost {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+	result := OrgCost{
+		PerMinute:          t.orgUsage(orgID),
+		Budget:             t.orgBudgets[orgID],
+		IntervalMultiplier: 1,
+	}
+	if m, ok := t.multipliers[orgID]; ok {
+		result.IntervalMultiplier = m
+	}
+	return result
+}
+
+// TotalPerMinute returns the evaluation time per minute of the rules of all organizations.
+func (t *Tracker) TotalPerMinute() time.Duration {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+	var total time.Duration
+	for _, c := range t.rules {
+		total += c.PerMinute()
+	}
+	return total
+}
+
+func (t *Tracker) orgUsage(orgID int64) time.Duration {
+	var total time.Duration
+	for _, c := range t.rules {
+		if c.OrgID == orgID {
+			total += c.PerMinute()
+		}
+	}
+	return total
+}
+
+func (t *Tracker) record(rule *models.AlertRule, cost eval.EvaluationCost) {
+	if t.metrics != nil {
+		orgID := fmt.Sprint(rule.OrgID)
+		t.metrics.EvalQueryDuration.WithLabelValues(orgID).Observe(cost.QueryDuration.Seconds())
+		t.metrics.EvalSeries.WithLabelValues(orgID).Observe(float64(cost.Series))
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	key := rule.GetKey()
+	c, ok := t.rules[key]
+	if !ok {
+		c = &RuleCost{
+			AlertRuleKey:     key,
+			AvgDuration:      cost.Duration,
+			AvgQueryDuration: cost.QueryDuration,
+			AvgSeries:        float64(cost.Series),
+		}
+		t.rules[key] = c
+	} else {
+		c.AvgDuration = time.Duration(movingAverage(float64(c.AvgDuration), float64(cost.Duration)))
+		c.AvgQueryDuration = time.Duration(movingAverage(float64(c.AvgQueryDuration), float64(cost.QueryDuration)))
+		c.AvgSeries = movingAverage(c.AvgSeries, float64(cost.Series))
+	}
+	c.Title = rule.Title
+	c.NamespaceUID = rule.NamespaceUID
+	c.RuleGroup = rule.RuleGroup
+	c.IntervalSeconds = rule.IntervalSeconds
+	c.Evaluations++
+	c.LastEvaluation = t.clock.Now()
+	c.Last = cost
+}
+
+// refreshBudgets reloads the budgets of the organizations that have rules, and recalculates their interval
+// multipliers. The usage is calculated from the configured intervals of the rules, so that stretching the intervals
+// does not lower the usage and the multiplier stays stable as long as the cost of the rules does not change.
+func (t *Tracker) refreshBudgets(ctx context.Context) {
+	t.mtx.RLock()
+	usage := make(map[int64]time.Duration)
+	for _, c := range t.rules {
+		usage[c.OrgID] += c.PerMinute()
+	}
+	t.mtx.RUnlock()
+
+	budgets := make(map[int64]time.Duration, len(usage))
+	multipliers := make(map[int64]int64, len(usage))
+	for orgID, used := range usage {
+		budget, err := t.budgets.EvaluationBudget(ctx, orgID)
+		if err != nil {
+			t.log.Warn("Failed to get evaluation budget", "org", orgID, "error", err)
+			continue
+		}
+		budgets[orgID] = budget
+		multiplier := int64(1)
+		if t.slowDown {
+			multiplier = intervalMultiplier(used, budget)
+		}
+		if multiplier > 1 {
+			t.log.Info("Organization exceeds its evaluation budget, slowing down rule evaluation", "org", orgID, "usage", used, "budget", budget, "multiplier", multiplier)
+		}
+		multipliers[orgID] = multiplier
+
+		if t.metrics != nil {
+			org := fmt.Sprint(orgID)
+			t.metrics.EvaluationCost.WithLabelValues(org).Set(used.Seconds())
+			t.metrics.EvaluationBudget.WithLabelValues(org).Set(budget.Seconds())
+			t.metrics.EvaluationIntervalMultiplier.WithLabelValues(org).Set(float64(multiplier))
+		}
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.orgBudgets = budgets
+	t.multipliers = multipliers
+}
+
+// intervalMultiplier returns how many times the intervals must be stretched to bring the usage within the budget.
+func intervalMultiplier(used, budget time.Duration) int64 {
+	if budget <= 0 || used <= budget {
+		return 1
+	}
+	m := int64(math.Ceil(float64(used) / float64(budget)))
+	if m > MaxIntervalMultiplier {
+		return MaxIntervalMultiplier
+	}
+	return m
+}
+
+func movingAverage(avg, value float64) float64 {
+	return avg + averageWeight*(value-avg)
+}
+
+type ruleRecorder struct {
+	tracker *Tracker
+	rule    *models.AlertRule
+}
+
+func (r *ruleRecorder) RecordEvaluationCost(cost eval.EvaluationCost) {
+	r.tracker.record(r.rule, cost)
+}
@@ -0,0 +1,96 @@
+package cost
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeBudgets map[int64]time.Duration
+
+func (f fakeBudgets) EvaluationBudget(_ context.Context, orgID int64) (time.Duration, error) {
+	return f[orgID], nil
+}
+
+func TestTracker(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock()
+	m := metrics.NewSchedulerMetrics(prometheus.NewPedanticRegistry())
+
+	cheap := &models.AlertRule{UID: "cheap", OrgID: 1, Title: "Cheap", IntervalSeconds: 60}
+	expensive := &models.AlertRule{UID: "expensive", OrgID: 1, Title: "Expensive", IntervalSeconds: 10}
+	other := &models.AlertRule{UID: "other", OrgID: 2, Title: "Other", IntervalSeconds: 60}
+
+	t.Run("accounts for the cost of each rule", func(t *testing.T) {
+		tracker := NewTracker(fakeBudgets{}, false, m, clk, log.NewNopLogger())
+		tracker.Recorder(cheap).RecordEvaluationCost(eval.EvaluationCost{Duration: time.Second, QueryDuration: 500 * time.Millisecond, Series: 10})
+		tracker.Recorder(expensive).RecordEvaluationCost(eval.EvaluationCost{Duration: time.Second, Series: 100})
+		tracker.Recorder(other).RecordEvaluationCost(eval.EvaluationCost{Duration: time.Second})
+
+		costs := tracker.RuleCosts(1)
+		require.Len(t, costs, 2)
+		require.Equal(t, "expensive", costs[0].UID)
+		require.Equal(t, 6*time.Second, costs[0].PerMinute())
+		require.Equal(t, "cheap", costs[1].UID)
+		require.Equal(t, time.Second, costs[1].PerMinute())
+		require.Equal(t, 500*time.Millisecond, costs[1].Last.QueryDuration)
+		require.EqualValues(t, 1, costs[1].Evaluations)
+
+		require.Equal(t, 7*time.Second, tracker.OrgCost(1).PerMinute)
+		require.Equal(t, 8*time.Second, tracker.TotalPerMinute())
+
+		tracker.Forget(expensive.GetKey())
+		require.Equal(t, time.Second, tracker.OrgCost(1).PerMinute)
+	})
+
+	t.Run("smooths the cost of the evaluations", func(t *testing.T) {
+		tracker := NewTracker(fakeBudgets{}, false, m, clk, log.NewNopLogger())
+		recorder := tracker.Recorder(cheap)
+		recorder.RecordEvaluationCost(eval.EvaluationCost{Duration: time.Second})
+		recorder.RecordEvaluationCost(eval.EvaluationCost{Duration: 6 * time.Second})
+
+		costs := tracker.RuleCosts(1)
+		require.Len(t, costs, 1)
+		require.Equal(t, 2*time.Second, costs[0].AvgDuration)
+		require.Equal(t, 6*time.Second, costs[0].Last.Duration)
+		require.EqualValues(t, 2, costs[0].Evaluations)
+	})
+
+	t.Run("stretches the intervals of organizations over budget", func(t *testing.T) {
+		tracker := NewTracker(fakeBudgets{1: 2 * time.Second, 2: time.Minute}, true, m, clk, log.NewNopLogger())
+		tracker.Recorder(cheap).RecordEvaluationCost(eval.EvaluationCost{Duration: time.Second})
+		tracker.Recorder(expensive).RecordEvaluationCost(eval.EvaluationCost{Duration: time.Second})
+		tracker.Recorder(other).RecordEvaluationCost(eval.EvaluationCost{Duration: time.Second})
+
+		require.EqualValues(t, 1, tracker.IntervalMultiplier(1))
+		tracker.refreshBudgets(ctx)
+		require.EqualValues(t, 4, tracker.IntervalMultiplier(1))
+		require.EqualValues(t, 1, tracker.IntervalMultiplier(2))
+		require.EqualValues(t, 1, tracker.IntervalMultiplier(3))
+		require.Equal(t, OrgCost{PerMinute: 7 * time.Second, Budget: 2 * time.Second, IntervalMultiplier: 4}, tracker.OrgCost(1))
+	})
+
+	t.Run("does not stretch the intervals if slowing down is disabled", func(t *testing.T) {
+		tracker := NewTracker(fakeBudgets{1: time.Second}, false, m, clk, log.NewNopLogger())
+		tracker.Recorder(expensive).RecordEvaluationCost(eval.EvaluationCost{Duration: time.Second})
+		tracker.refreshBudgets(ctx)
+		require.EqualValues(t, 1, tracker.IntervalMultiplier(1))
+		require.Equal(t, time.Second, tracker.OrgCost(1).Budget)
+	})
+}
+
+func TestIntervalMultiplier(t *testing.T) {
+	require.EqualValues(t, 1, intervalMultiplier(time.Minute, 0))
+	require.EqualValues(t, 1, intervalMultiplier(time.Second, time.Second))
+	require.EqualValues(t, 2, intervalMultiplier(1001*time.Millisecond, time.Second))
+	require.EqualValues(t, MaxIntervalMultiplier, intervalMultiplier(time.Hour, time.Second))
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/expr"
 )
 
 // AlertingResultsReader provides fingerprints of results that are in alerting state.
@@ -14,11 +15,19 @@ type AlertingResultsReader interface {
 	Read() map[data.Fingerprint]struct{}
 }
 
+// HysteresisStateReader provides the state of the results of a multi-level hysteresis condition.
+// It is used during the evaluation of queries if the AlertingResultsReader implements it.
+type HysteresisStateReader interface {
+	ReadHysteresisState() expr.HysteresisState
+}
+
 // EvaluationContext represents the context in which a condition is evaluated.
 type EvaluationContext struct {
 	Ctx                   context.Context
 	User                  identity.Requester
 	AlertingResultsReader AlertingResultsReader
+	// CostRecorder, if set, receives the cost of every evaluation of the condition.
+	CostRecorder CostRecorder
 }
 
 func NewContext(ctx context.Context, user identity.Requester) EvaluationContext {
@@ -0,0 +1,56 @@
+package eval
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/expr"
+)
+
+// EvaluationCost is the cost of a single evaluation of a condition.
+type EvaluationCost struct {
+	// Duration is the time it took to execute the queries and expressions of the condition.
+	Duration time.Duration
+	// QueryDuration is the part of Duration that was spent executing data source queries.
+	QueryDuration time.Duration
+	// Series is the number of series and numbers returned by the data source queries.
+	Series int
+}
+
+// CostRecorder receives the cost of every evaluation of a condition.
+// It is used by the scheduler to account for the cost of alert rules.
+type CostRecorder interface {
+	RecordEvaluationCost(cost EvaluationCost)
+}
+
+type debugExpressionExecutor interface {
+	ExecutePipelineWithDebug(ctx context.Context, now time.Time, pipeline expr.DataPipeline) (*backend.QueryDataResponse, *expr.PipelineDebug, error)
+}
+
+// executeWithCost executes the pipeline and reports the cost of the execution to the recorder.
+// The debug information of the pipeline is used to tell the data source queries apart from the expressions.
+func (r *conditionEvaluator) executeWithCost(ctx context.Context, now time.Time) (*backend.QueryDataResponse, error) {
+	executor, ok := r.expressionService.(debugExpressionExecutor)
+	if !ok {
+		start := time.Now()
+		result, err := r.expressionService.ExecutePipeline(ctx, now, r.pipeline)
+		r.costRecorder.RecordEvaluationCost(EvaluationCost{Duration: time.Since(start)})
+		return result, err
+	}
+
+	start := time.Now()
+	result, debug, err := executor.ExecutePipelineWithDebug(ctx, now, r.pipeline)
+	cost := EvaluationCost{Duration: time.Since(start)}
+	if debug != nil {
+		cost.QueryDuration = debug.DatasourceDuration()
+		for _, n := range debug.Nodes {
+			if n.NodeType == expr.TypeDatasourceNode.String() {
+				cost.Series += n.Values
+			}
+		}
+	}
+	r.costRecorder.RecordEvaluationCost(cost)
+	return result, err
+}
@@ -55,6 +55,7 @@ type conditionEvaluator struct {
 	condition         models.Condition
 	evalTimeout       time.Duration
 	evalResultLimit   int
+	costRecorder      CostRecorder
 }
 
 func (r *conditionEvaluator) EvaluateRaw(ctx context.Context, now time.Time) (resp *backend.QueryDataResponse, err error) {
@@ -77,7 +78,12 @@ func (r *conditionEvaluator) EvaluateRaw(ctx context.Context, now time.Time) (re
 		execCtx = timeoutCtx
 	}
 	logger.FromContext(ctx).Debug("Executing pipeline", "commands", strings.Join(r.pipeline.GetCommandTypes(), ","), "datasources", strings.Join(r.pipeline.GetDatasourceTypes(), ","))
-	result, err := r.expressionService.ExecutePipeline(execCtx, now, r.pipeline)
+	var result *backend.QueryDataResponse
+	if r.costRecorder != nil {
+		result, err = r.executeWithCost(execCtx, now)
+	} else {
+		result, err = r.expressionService.ExecutePipeline(execCtx, now, r.pipeline)
+	}
 
 	// Check if the result of the condition evaluation is too large
 	if err == nil && result != nil && r.evalResultLimit > 0 {
@@ -160,6 +166,9 @@ type ExecutionResults struct {
 	// NoData contains the DatasourceUID for RefIDs that returned no data.
 	NoData map[string]string
 
+	// Hysteresis contains the state of a multi-level hysteresis condition by the fingerprint of the labels of the result.
+	Hysteresis map[data.Fingerprint]expr.HysteresisSeriesState
+
 	Error error
 }
 
@@ -261,6 +270,9 @@ type Result struct {
 	// as EvalMatches (from "classic condition"), and in the future from operations
 	// like SSE "math".
 	EvaluationString string
+
+	// Hysteresis is the state of the result if the condition is a multi-level hysteresis.
+	Hysteresis *expr.HysteresisSeriesState
 }
 
 func NewResultFromError(err error, evaluatedAt time.Time, duration time.Duration) Result {
@@ -296,14 +308,19 @@ const (
 	// Error is the eval state for an alert rule condition
 	// that evaluated to Error.
 	Error
+
+	// Inhibited is the eval state for an alert instance that would be
+	// Pending or Alerting while a rule the alert rule depends on is firing.
+	// It is never the state of an evaluation result.
+	Inhibited
 )
 
 func (s State) IsValid() bool {
-	return s <= Error
+	return s <= Inhibited
 }
 
 func (s State) String() string {
-	return [...]string{"Normal", "Alerting", "Pending", "NoData", "Error"}[s]
+	return [...]string{"Normal", "Alerting", "Pending", "NoData", "Error", "Inhibited"}[s]
 }
 
 func ParseStateString(repr string) (State, error) {
@@ -318,6 +335,8 @@ func ParseStateString(repr string) (State, error) {
 		return NoData, nil
 	case "error":
 		return Error, nil
+	case "inhibited":
+		return Inhibited, nil
 	default:
 		return -1, fmt.Errorf("invalid state: %s", repr)
 	}
@@ -379,11 +398,28 @@ func getExprRequest(ctx EvaluationContext, condition models.Condition, dsCacheSe
 		// if the query is command expression and it's a hysteresis, patch it with the current state
 		// it's important to do this before GetModel
 		if ds.Type == expr.DatasourceType {
+			isMultiLevelHysteresis, err := q.IsMultiLevelHysteresisExpression()
+			if err != nil {
+				return nil, fmt.Errorf("failed to build query '%s': %w", q.RefID, err)
+			}
 			isHysteresis, err := q.IsHysteresisExpression()
 			if err != nil {
 				return nil, fmt.Errorf("failed to build query '%s': %w", q.RefID, err)
 			}
-			if isHysteresis {
+			if isMultiLevelHysteresis {
+				// the state is only kept for the results of the alert condition.
+				if q.RefID != condition.Condition {
+					return nil, fmt.Errorf("multi-level threshold '%s' is only allowed to be the alert condition", q.RefID)
+				}
+				if stateReader, ok := reader.(HysteresisStateReader); ok {
+					state := stateReader.ReadHysteresisState()
+					logger.FromContext(ctx.Ctx).Debug("Detected multi-level hysteresis command. Populating with the state", "items", len(state))
+					err = q.PatchMultiLevelHysteresisExpression(state)
+					if err != nil {
+						return nil, fmt.Errorf("failed to amend hysteresis command '%s': %w", q.RefID, err)
+					}
+				}
+			} else if isHysteresis {
 				// make sure we allow hysteresis expressions to be specified only as the alert condition.
 				// This guarantees us that the AlertResultsReader can be correctly applied to the expression tree.
 				if q.RefID != condition.Condition {
@@ -520,6 +556,15 @@ func queryDataResponseToExecutionResults(c models.Condition, execResp *backend.Q
 			}
 		}
 
+		if frame.Meta != nil {
+			if s, ok := frame.Meta.Custom.(expr.HysteresisSeriesState); ok && len(frame.Fields) == 1 {
+				if result.Hysteresis == nil {
+					result.Hysteresis = make(map[data.Fingerprint]expr.HysteresisSeriesState)
+				}
+				result.Hysteresis[frame.Fields[0].Labels.Fingerprint()] = s
+			}
+		}
+
 		frame.SetMeta(&data.FrameMeta{}) // overwrite metadata
 
 		if len(frame.Fields) == 1 {
@@ -742,6 +787,9 @@ func evaluateExecutionResult(execResults ExecutionResults, ts time.Time) Results
 			EvaluationString:   extractEvalString(f),
 			Values:             extractValues(f),
 		}
+		if s, ok := execResults.Hysteresis[f.Fields[0].Labels.Fingerprint()]; ok {
+			r.Hysteresis = &s
+		}
 
 		switch {
 		case val == nil:
@@ -829,10 +877,10 @@ func (e *evaluatorImpl) Create(ctx EvaluationContext, condition models.Condition
 	if err != nil {
 		return nil, err
 	}
-	return e.create(condition, req)
+	return e.create(condition, req, ctx.CostRecorder)
 }
 
-func (e *evaluatorImpl) create(condition models.Condition, req *expr.Request) (ConditionEvaluator, error) {
+func (e *evaluatorImpl) create(condition models.Condition, req *expr.Request, costRecorder CostRecorder) (ConditionEvaluator, error) {
 	pipeline, err := e.expressionService.BuildPipeline(req)
 	if err != nil {
 		return nil, err
@@ -846,6 +894,7 @@ func (e *evaluatorImpl) create(condition models.Condition, req *expr.Request) (C
 				condition:         condition,
 				evalTimeout:       e.evaluationTimeout,
 				evalResultLimit:   e.evaluationResultLimit,
+				costRecorder:      costRecorder,
 			}, nil
 		}
 		conditions = append(conditions, node.RefID())
@@ -732,6 +732,103 @@ func TestCreate_HysteresisCommand(t *testing.T) {
 	}
 }
 
+func TestCreate_MultiLevelHysteresisCommand(t *testing.T) {
+	createCondition := func(services *fakes.FakeCacheService, store *pluginstore.FakePluginStore, condition string) models.Condition {
+		dsQuery := models.GenerateAlertQuery()
+		ds := &datasources.DataSource{
+			UID:  dsQuery.DatasourceUID,
+			Type: util.GenerateShortUID(),
+		}
+		services.DataSources = append(services.DataSources, ds)
+		store.PluginList = append(store.PluginList, pluginstore.Plugin{
+			JSONData: plugins.JSONData{
+				ID:      ds.Type,
+				Backend: true,
+			},
+		})
+		return models.Condition{
+			Condition: condition,
+			Data: []models.AlertQuery{
+				dsQuery,
+				models.CreateMultiLevelHysteresisExpression(t, "B", dsQuery.RefID, 2, 10, 20),
+				models.CreateClassicConditionExpression("C", dsQuery.RefID, "last", "gt", rand.Int()),
+			},
+		}
+	}
+
+	testCases := []struct {
+		name      string
+		reader    AlertingResultsReader
+		condition string
+		expected  expr.HysteresisState
+		error     bool
+	}{
+		{
+			name:      "fail if multi-level hysteresis command is not the condition",
+			condition: "C",
+			error:     true,
+		},
+		{
+			name:      "populate with the loaded state",
+			condition: "B",
+			reader:    FakeHysteresisStateReader{state: expr.HysteresisState{1: {Level: 2, Unloading: 1}, 2: {Level: 1}}},
+			expected:  expr.HysteresisState{1: {Level: 2, Unloading: 1}, 2: {Level: 1}},
+		},
+		{
+			name:      "do nothing if reader does not provide the state",
+			condition: "B",
+			reader:    FakeLoadedMetricsReader{fingerprints: map[data.Fingerprint]struct{}{1: {}}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			cacheService := &fakes.FakeCacheService{}
+			store := &pluginstore.FakePluginStore{}
+			condition := createCondition(cacheService, store, testCase.condition)
+			evaluator := NewEvaluatorFactory(setting.UnifiedAlertingSettings{}, cacheService, expr.ProvideService(&setting.Cfg{ExpressionsEnabled: true}, nil, nil, featuremgmt.WithFeatures(featuremgmt.FlagRecoveryThreshold), nil, tracing.InitializeTracerForTest()))
+			evalCtx := NewContextWithPreviousResults(context.Background(), &user.SignedInUser{}, testCase.reader)
+
+			eval, err := evaluator.Create(evalCtx, condition)
+			if testCase.error {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.IsType(t, &conditionEvaluator{}, eval)
+			ce := eval.(*conditionEvaluator)
+
+			cmds := expr.GetCommandsFromPipeline[*expr.MultiLevelHysteresisCommand](ce.pipeline)
+			require.Len(t, cmds, 1)
+			if testCase.expected == nil {
+				require.Empty(t, cmds[0].State)
+			} else {
+				require.Equal(t, testCase.expected, cmds[0].State)
+			}
+		})
+	}
+}
+
+func TestMultiLevelHysteresisStateInResults(t *testing.T) {
+	labels := data.Labels{"host": "a"}
+	frame := data.NewFrame("", data.NewField("", labels, []*float64{util.Pointer(2.0)}))
+	frame.SetMeta(&data.FrameMeta{Custom: expr.HysteresisSeriesState{Level: 2, Unloading: 1}})
+	other := data.NewFrame("", data.NewField("", data.Labels{"host": "b"}, []*float64{util.Pointer(0.0)}))
+
+	execResults := queryDataResponseToExecutionResults(models.Condition{Condition: "B"}, &backend.QueryDataResponse{
+		Responses: backend.Responses{
+			"B": {Frames: data.Frames{frame, other}},
+		},
+	})
+	require.Equal(t, map[data.Fingerprint]expr.HysteresisSeriesState{labels.Fingerprint(): {Level: 2, Unloading: 1}}, execResults.Hysteresis)
+
+	results := evaluateExecutionResult(execResults, time.Now())
+	require.Len(t, results, 2)
+	require.Equal(t, &expr.HysteresisSeriesState{Level: 2, Unloading: 1}, results[0].Hysteresis)
+	require.Equal(t, Alerting, results[0].State)
+	require.Nil(t, results[1].Hysteresis)
+}
+
 func TestEvaluate(t *testing.T) {
 	cases := []struct {
 		name     string
@@ -1029,6 +1126,33 @@ func TestEvaluateRaw(t *testing.T) {
 		_, err := e.EvaluateRaw(context.Background(), time.Now())
 		require.ErrorIs(t, err, context.DeadlineExceeded)
 	})
+
+	t.Run("should record the cost of the evaluation", func(t *testing.T) {
+		var costs []EvaluationCost
+		e := conditionEvaluator{
+			expressionService: &fakeExpressionService{
+				hook: func(ctx context.Context, now time.Time, pipeline expr.DataPipeline) (*backend.QueryDataResponse, error) {
+					time.Sleep(time.Millisecond)
+					return &backend.QueryDataResponse{}, nil
+				},
+			},
+			evalTimeout: -1,
+			costRecorder: costRecorderFunc(func(cost EvaluationCost) {
+				costs = append(costs, cost)
+			}),
+		}
+
+		_, err := e.EvaluateRaw(context.Background(), time.Now())
+		require.NoError(t, err)
+		require.Len(t, costs, 1)
+		require.GreaterOrEqual(t, costs[0].Duration, time.Millisecond)
+	})
+}
+
+type costRecorderFunc func(cost EvaluationCost)
+
+func (f costRecorderFunc) RecordEvaluationCost(cost EvaluationCost) {
+	f(cost)
 }
 
 func TestEvaluateRawLimit(t *testing.T) {
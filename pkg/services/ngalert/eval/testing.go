@@ -7,6 +7,7 @@ import (
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
@@ -93,3 +94,12 @@ type FakeLoadedMetricsReader struct {
 func (f FakeLoadedMetricsReader) Read() map[data.Fingerprint]struct{} {
 	return f.fingerprints
 }
+
+type FakeHysteresisStateReader struct {
+	FakeLoadedMetricsReader
+	state expr.HysteresisState
+}
+
+func (f FakeHysteresisStateReader) ReadHysteresisState() expr.HysteresisState {
+	return f.state
+}
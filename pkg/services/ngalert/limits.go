@@ -2,7 +2,10 @@ package ngalert
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/grafana/grafana/pkg/services/ngalert/cost"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/setting"
@@ -12,7 +15,13 @@ type RuleUsageReader interface {
 	Count(ctx context.Context, orgID int64) (int64, error)
 }
 
-func RegisterQuotas(cfg *setting.Cfg, qs quota.Service, rules RuleUsageReader) error {
+// EvaluationCostReader provides the evaluation cost of the alert rules.
+type EvaluationCostReader interface {
+	OrgCost(orgID int64) cost.OrgCost
+	TotalPerMinute() time.Duration
+}
+
+func RegisterQuotas(cfg *setting.Cfg, qs quota.Service, rules RuleUsageReader, costs EvaluationCostReader) error {
 	defaultLimits, err := readQuotaConfig(cfg)
 	if err != nil {
 		return err
@@ -21,11 +30,11 @@ func RegisterQuotas(cfg *setting.Cfg, qs quota.Service, rules RuleUsageReader) e
 	return qs.RegisterQuotaReporter(&quota.NewUsageReporter{
 		TargetSrv:     models.QuotaTargetSrv,
 		DefaultLimits: defaultLimits,
-		Reporter:      UsageReporter(rules),
+		Reporter:      UsageReporter(rules, costs),
 	})
 }
 
-func UsageReporter(rules RuleUsageReader) quota.UsageReporterFunc {
+func UsageReporter(rules RuleUsageReader, costs EvaluationCostReader) quota.UsageReporterFunc {
 	return func(ctx context.Context, scopeParams *quota.ScopeParameters) (*quota.Map, error) {
 		u := &quota.Map{}
 
@@ -54,6 +63,18 @@ func UsageReporter(rules RuleUsageReader) quota.UsageReporterFunc {
 			u.Set(tag, globalUsage)
 		}
 
+		orgCostTag, err := quota.NewTag(models.QuotaTargetSrv, models.QuotaTargetEvaluationCost, quota.OrgScope)
+		if err != nil {
+			return u, err
+		}
+		u.Set(orgCostTag, costs.OrgCost(orgID).PerMinute.Milliseconds())
+
+		globalCostTag, err := quota.NewTag(models.QuotaTargetSrv, models.QuotaTargetEvaluationCost, quota.GlobalScope)
+		if err != nil {
+			return u, err
+		}
+		u.Set(globalCostTag, costs.TotalPerMinute().Milliseconds())
+
 		return u, nil
 	}
 }
@@ -76,5 +97,44 @@ func readQuotaConfig(cfg *setting.Cfg) (*quota.Map, error) {
 
 	limits.Set(globalQuotaTag, cfg.Quota.Global.AlertRule)
 	limits.Set(orgQuotaTag, cfg.Quota.Org.AlertRule)
+
+	globalCostTag, err := quota.NewTag(models.QuotaTargetSrv, models.QuotaTargetEvaluationCost, quota.GlobalScope)
+	if err != nil {
+		return limits, err
+	}
+	orgCostTag, err := quota.NewTag(models.QuotaTargetSrv, models.QuotaTargetEvaluationCost, quota.OrgScope)
+	if err != nil {
+		return limits, err
+	}
+
+	limits.Set(globalCostTag, cfg.Quota.Global.AlertRuleEvaluationCost)
+	limits.Set(orgCostTag, cfg.Quota.Org.AlertRuleEvaluationCost)
 	return limits, nil
 }
+
+// QuotaBudgetProvider reads the evaluation budgets of organizations from their alert rule evaluation cost quota.
+type QuotaBudgetProvider struct {
+	Quotas quota.Service
+}
+
+// EvaluationBudget returns the evaluation cost limit of the organization. It returns zero if quotas are disabled or
+// the organization has no limit.
+func (p QuotaBudgetProvider) EvaluationBudget(ctx context.Context, orgID int64) (time.Duration, error) {
+	quotas, err := p.Quotas.GetQuotasByScope(ctx, quota.OrgScope, orgID)
+	if err != nil {
+		if errors.Is(err, quota.ErrDisabled) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	for _, q := range quotas {
+		if q.Service != string(models.QuotaTargetSrv) || q.Target != string(models.QuotaTargetEvaluationCost) {
+			continue
+		}
+		if q.Limit <= 0 {
+			return 0, nil
+		}
+		return time.Duration(q.Limit) * time.Millisecond, nil
+	}
+	return 0, nil
+}
@@ -3,7 +3,9 @@ package ngalert
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/grafana/grafana/pkg/services/ngalert/cost"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/setting"
@@ -17,7 +19,7 @@ func TestUsageReporter(t *testing.T) {
 			OrgID: 1,
 		}
 
-		res, err := UsageReporter(rules)(context.Background(), &params)
+		res, err := UsageReporter(rules, newFakeCostReader(nil))(context.Background(), &params)
 
 		require.NoError(t, err)
 		rulesOrg, _ := quota.NewTag(models.QuotaTargetSrv, models.QuotaTarget, quota.OrgScope)
@@ -32,7 +34,7 @@ func TestUsageReporter(t *testing.T) {
 			OrgID: 1,
 		}
 
-		res, err := UsageReporter(rules)(context.Background(), &params)
+		res, err := UsageReporter(rules, newFakeCostReader(nil))(context.Background(), &params)
 
 		require.NoError(t, err)
 		rulesGlobal, _ := quota.NewTag(models.QuotaTargetSrv, models.QuotaTarget, quota.GlobalScope)
@@ -44,7 +46,7 @@ func TestUsageReporter(t *testing.T) {
 	t.Run("reports global usage if scope params are nil", func(t *testing.T) {
 		rules := newFakeUsageReader(map[int64]int64{1: 10, 2: 20})
 
-		res, err := UsageReporter(rules)(context.Background(), nil)
+		res, err := UsageReporter(rules, newFakeCostReader(nil))(context.Background(), nil)
 
 		require.NoError(t, err)
 		rulesGlobal, _ := quota.NewTag(models.QuotaTargetSrv, models.QuotaTarget, quota.GlobalScope)
@@ -52,16 +54,72 @@ func TestUsageReporter(t *testing.T) {
 		require.True(t, ok, "reporter did not report on global rules usage")
 		require.Equal(t, int64(30), val)
 	})
+
+	t.Run("reports evaluation cost in milliseconds per minute", func(t *testing.T) {
+		rules := newFakeUsageReader(map[int64]int64{1: 10, 2: 20})
+		costs := newFakeCostReader(map[int64]time.Duration{1: 1500 * time.Millisecond, 2: time.Second})
+		params := quota.ScopeParameters{
+			OrgID: 1,
+		}
+
+		res, err := UsageReporter(rules, costs)(context.Background(), &params)
+
+		require.NoError(t, err)
+		costOrg, _ := quota.NewTag(models.QuotaTargetSrv, models.QuotaTargetEvaluationCost, quota.OrgScope)
+		val, ok := res.Get(costOrg)
+		require.True(t, ok, "reporter did not report on org 1 evaluation cost")
+		require.Equal(t, int64(1500), val)
+		costGlobal, _ := quota.NewTag(models.QuotaTargetSrv, models.QuotaTargetEvaluationCost, quota.GlobalScope)
+		val, ok = res.Get(costGlobal)
+		require.True(t, ok, "reporter did not report on global evaluation cost")
+		require.Equal(t, int64(2500), val)
+	})
+}
+
+func TestQuotaBudgetProvider(t *testing.T) {
+	t.Run("returns the evaluation cost limit of the org", func(t *testing.T) {
+		qs := &fakeQuotaService{quotas: []quota.QuotaDTO{
+			{Service: string(models.QuotaTargetSrv), Target: string(models.QuotaTarget), Limit: 100},
+			{Service: string(models.QuotaTargetSrv), Target: string(models.QuotaTargetEvaluationCost), Limit: 2000},
+		}}
+
+		budget, err := QuotaBudgetProvider{Quotas: qs}.EvaluationBudget(context.Background(), 1)
+
+		require.NoError(t, err)
+		require.Equal(t, 2*time.Second, budget)
+	})
+
+	t.Run("returns no budget if the org is unlimited", func(t *testing.T) {
+		qs := &fakeQuotaService{quotas: []quota.QuotaDTO{
+			{Service: string(models.QuotaTargetSrv), Target: string(models.QuotaTargetEvaluationCost), Limit: -1},
+		}}
+
+		budget, err := QuotaBudgetProvider{Quotas: qs}.EvaluationBudget(context.Background(), 1)
+
+		require.NoError(t, err)
+		require.Zero(t, budget)
+	})
+
+	t.Run("returns no budget if quotas are disabled", func(t *testing.T) {
+		qs := &fakeQuotaService{err: quota.ErrDisabled.Errorf("disabled")}
+
+		budget, err := QuotaBudgetProvider{Quotas: qs}.EvaluationBudget(context.Background(), 1)
+
+		require.NoError(t, err)
+		require.Zero(t, budget)
+	})
 }
 
 func TestReadQuotaConfig(t *testing.T) {
 	cfg := &setting.Cfg{
 		Quota: setting.QuotaSettings{
 			Org: setting.OrgQuota{
-				AlertRule: 30,
+				AlertRule:               30,
+				AlertRuleEvaluationCost: 1000,
 			},
 			Global: setting.GlobalQuota{
-				AlertRule: 50,
+				AlertRule:               50,
+				AlertRuleEvaluationCost: 5000,
 			},
 		},
 	}
@@ -85,6 +143,20 @@ func TestReadQuotaConfig(t *testing.T) {
 		require.True(t, ok, "did not configure global rules quota")
 		require.Equal(t, int64(50), val)
 	})
+
+	t.Run("registers evaluation cost quotas from config", func(t *testing.T) {
+		res, err := readQuotaConfig(cfg)
+
+		require.NoError(t, err)
+		costOrg, _ := quota.NewTag(models.QuotaTargetSrv, models.QuotaTargetEvaluationCost, quota.OrgScope)
+		val, ok := res.Get(costOrg)
+		require.True(t, ok, "did not configure per-org evaluation cost quota")
+		require.Equal(t, int64(1000), val)
+		costGlobal, _ := quota.NewTag(models.QuotaTargetSrv, models.QuotaTargetEvaluationCost, quota.GlobalScope)
+		val, ok = res.Get(costGlobal)
+		require.True(t, ok, "did not configure global evaluation cost quota")
+		require.Equal(t, int64(5000), val)
+	})
 }
 
 type fakeUsageReader struct {
@@ -111,3 +183,35 @@ func (f fakeUsageReader) Count(_ context.Context, orgID int64) (int64, error) {
 	}
 	return 0, nil
 }
+
+type fakeCostReader struct {
+	costs map[int64]time.Duration // orgID -> cost per minute
+}
+
+func newFakeCostReader(costs map[int64]time.Duration) fakeCostReader {
+	return fakeCostReader{
+		costs: costs,
+	}
+}
+
+func (f fakeCostReader) OrgCost(orgID int64) cost.OrgCost {
+	return cost.OrgCost{PerMinute: f.costs[orgID]}
+}
+
+func (f fakeCostReader) TotalPerMinute() time.Duration {
+	total := time.Duration(0)
+	for _, c := range f.costs {
+		total += c
+	}
+	return total
+}
+
+type fakeQuotaService struct {
+	quota.Service
+	quotas []quota.QuotaDTO
+	err    error
+}
+
+func (f *fakeQuotaService) GetQuotasByScope(_ context.Context, _ quota.Scope, _ int64) ([]quota.QuotaDTO, error) {
+	return f.quotas, f.err
+}
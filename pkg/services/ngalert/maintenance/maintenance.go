@@ -0,0 +1,263 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+
+	alertingModels "github.com/grafana/alerting/models"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	// SyncInterval is how often the maintenance windows are reloaded and their silences are synchronized.
+	SyncInterval = 30 * time.Second
+	// silenceDuration is how far the end of a silence of an active window is extended. If Grafana stops, the silences
+	// of the windows expire on their own after at most this duration.
+	silenceDuration = 5 * time.Minute
+	// silenceCreatedByPrefix identifies the silences that are managed by maintenance windows.
+	silenceCreatedByPrefix = "maintenance-window:"
+)
+
+// Store provides the maintenance windows of all organizations.
+type Store interface {
+	ListAllMaintenanceWindows(ctx context.Context) ([]models.MaintenanceWindow, error)
+}
+
+// RuleStore provides the alert rules matched by the maintenance windows.
+type RuleStore interface {
+	ListAlertRules(ctx context.Context, query *models.ListAlertRulesQuery) (models.RulesGroup, error)
+}
+
+// SilenceService manages the silences in the Alertmanager of an organization.
+type SilenceService interface {
+	ListSilences(ctx context.Context, orgID int64, filter []string) ([]*models.Silence, error)
+	CreateSilence(ctx context.Context, orgID int64, ps models.Silence) (string, error)
+	DeleteSilence(ctx context.Context, orgID int64, silenceID string) error
+}
+
+// Peer tells which instance of a high availability setup maintains the silences of the maintenance windows.
+type Peer interface {
+	// IsPrimary returns true if the instance is the primary of the Alertmanager cluster, or if there is no cluster.
+	IsPrimary() bool
+}
+
+// Service applies maintenance windows. It tells the scheduler which rules must not be evaluated, and maintains a
+// silence in the Alertmanager for every active window, so the notifications of the matched rules are muted without
+// editing the rules or the notification policies. In a high availability setup, only the primary instance maintains
+// the silences, which are replicated to the other instances by the Alertmanager cluster.
+type Service struct {
+	store    Store
+	rules    RuleStore
+	silences SilenceService
+	peer     Peer
+	clock    clock.Clock
+	log      log.Logger
+
+	windows atomic.Pointer[[]models.MaintenanceWindow]
+	// silencedOrgs are the organizations that may have silences of maintenance windows. It is used to expire the
+	// silences of windows that were deleted.
+	silencedOrgs map[int64]struct{}
+}
+
+func NewService(store Store, rules RuleStore, silences SilenceService, peer Peer, clk clock.Clock, logger log.Logger) *Service {
+	return &Service{
+		store:        store,
+		rules:        rules,
+		silences:     silences,
+		peer:         peer,
+		clock:        clk,
+		log:          logger,
+		silencedOrgs: map[int64]struct{}{},
+	}
+}
+
+// Run periodically reloads the maintenance windows and synchronizes their silences until the context is cancelled.
+func (s *Service) Run(ctx context.Context) error {
+	s.log.Info("Starting maintenance windows service", "interval", SyncInterval)
+	ticker := s.clock.Ticker(SyncInterval)
+	defer ticker.Stop()
+	for {
+		s.sync(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// IsEvaluationPaused returns true if the rule is matched by a maintenance window in the pause mode that is active at
+// the given time.
+func (s *Service) IsEvaluationPaused(rule *models.AlertRule, now time.Time) bool {
+	windows := s.windows.Load()
+	if windows == nil {
+		return false
+	}
+	for i := range *windows {
+		w := &(*windows)[i]
+		if w.Mode == models.MaintenanceWindowModePause && w.Matches(rule) && w.IsActive(now) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) sync(ctx context.Context) {
+	windows, err := s.store.ListAllMaintenanceWindows(ctx)
+	if err != nil {
+		s.log.Error("Failed to load maintenance windows", "error", err)
+		return
+	}
+	s.windows.Store(&windows)
+
+	now := s.clock.Now()
+	byOrg := make(map[int64][]models.MaintenanceWindow)
+	for _, w := range windows {
+		if w.IsActive(now) {
+			byOrg[w.OrgID] = append(byOrg[w.OrgID], w)
+		}
+		s.silencedOrgs[w.OrgID] = struct{}{}
+	}
+	if !s.peer.IsPrimary() {
+		return
+	}
+	for orgID := range s.silencedOrgs {
+		if err := s.syncSilences(ctx, orgID, byOrg[orgID], now); err != nil {
+			s.log.Warn("Failed to synchronize silences of maintenance windows", "org", orgID, "error", err)
+		}
+	}
+}
+
+// syncSilences makes sure that there is exactly one silence for each of the active windows of the organization, and
+// expires the silences of windows that are no longer active.
+func (s *Service) syncSilences(ctx context.Context, orgID int64, active []models.MaintenanceWindow, now time.Time) error {
+	desired := make(map[string]models.MaintenanceWindow, len(active))
+	matchers := make(map[string]string, len(active))
+	if len(active) > 0 {
+		rules, err := s.rules.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: orgID})
+		if err != nil {
+			return fmt.Errorf("failed to list alert rules: %w", err)
+		}
+		for _, w := range active {
+			var uids []string
+			for _, rule := range rules {
+				if w.Matches(rule) {
+					uids = append(uids, regexp.QuoteMeta(rule.UID))
+				}
+			}
+			if len(uids) == 0 {
+				continue
+			}
+			slices.Sort(uids)
+			desired[w.UID] = w
+			matchers[w.UID] = strings.Join(uids, "|")
+		}
+	}
+
+	silences, err := s.silences.ListSilences(ctx, orgID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list silences: %w", err)
+	}
+	// Instances that are briefly primary at the same time can create a silence for the same window. Keeping the
+	// silence with the lowest ID makes them agree on which one to keep.
+	slices.SortFunc(silences, func(a, b *models.Silence) int {
+		return strings.Compare(silenceID(a), silenceID(b))
+	})
+	existing := make(map[string]*models.Silence)
+	for _, silence := range silences {
+		windowUID, ok := windowUIDFromSilence(silence)
+		if !ok {
+			continue
+		}
+		_, isDesired := desired[windowUID]
+		if _, dup := existing[windowUID]; !dup && isDesired {
+			existing[windowUID] = silence
+			continue
+		}
+		// The window is not active anymore, or there is another silence of the same window created concurrently.
+		if err := s.silences.DeleteSilence(ctx, orgID, *silence.ID); err != nil {
+			s.log.Warn("Failed to expire silence of maintenance window", "org", orgID, "window", windowUID, "silence", *silence.ID, "error", err)
+		}
+	}
+
+	if len(desired) == 0 && len(existing) == 0 {
+		delete(s.silencedOrgs, orgID)
+		return nil
+	}
+
+	for uid, w := range desired {
+		current := existing[uid]
+		if current != nil && silenceMatcherValue(current) == matchers[uid] && time.Time(*current.EndsAt).Sub(now) > silenceDuration/2 {
+			continue
+		}
+		silence := newSilence(w, matchers[uid], now)
+		if current != nil {
+			silence.ID = current.ID
+			silence.StartsAt = current.StartsAt
+		}
+		if _, err := s.silences.CreateSilence(ctx, orgID, silence); err != nil {
+			s.log.Warn("Failed to create silence of maintenance window", "org", orgID, "window", uid, "error", err)
+			continue
+		}
+		s.log.Debug("Updated silence of maintenance window", "org", orgID, "window", uid)
+	}
+	return nil
+}
+
+func newSilence(w models.MaintenanceWindow, rulesRegex string, now time.Time) models.Silence {
+	startsAt := strfmt.DateTime(now)
+	endsAt := strfmt.DateTime(now.Add(silenceDuration))
+	return models.Silence{
+		Silence: amv2.Silence{
+			Comment:   util.Pointer(fmt.Sprintf("Maintenance window %q", w.Title)),
+			CreatedBy: util.Pointer(silenceCreatedByPrefix + w.UID),
+			StartsAt:  &startsAt,
+			EndsAt:    &endsAt,
+			Matchers: amv2.Matchers{{
+				Name:    util.Pointer(alertingModels.RuleUIDLabel),
+				Value:   util.Pointer(rulesRegex),
+				IsRegex: util.Pointer(true),
+				IsEqual: util.Pointer(true),
+			}},
+		},
+	}
+}
+
+// windowUIDFromSilence returns the UID of the maintenance window that manages the silence if the silence is active.
+func windowUIDFromSilence(silence *models.Silence) (string, bool) {
+	if silence == nil || silence.ID == nil || silence.CreatedBy == nil || silence.EndsAt == nil {
+		return "", false
+	}
+	if silence.Status != nil && silence.Status.State != nil && *silence.Status.State == amv2.SilenceStatusStateExpired {
+		return "", false
+	}
+	return strings.CutPrefix(*silence.CreatedBy, silenceCreatedByPrefix)
+}
+
+func silenceID(silence *models.Silence) string {
+	if silence == nil || silence.ID == nil {
+		return ""
+	}
+	return *silence.ID
+}
+
+func silenceMatcherValue(silence *models.Silence) string {
+	for _, m := range silence.Matchers {
+		if m != nil && m.Name != nil && *m.Name == alertingModels.RuleUIDLabel && m.Value != nil {
+			return *m.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,199 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type fakeStore struct {
+	windows []models.MaintenanceWindow
+}
+
+func (f *fakeStore) ListAllMaintenanceWindows(context.Context) ([]models.MaintenanceWindow, error) {
+	return f.windows, nil
+}
+
+type fakeRuleStore struct {
+	rules models.RulesGroup
+}
+
+func (f *fakeRuleStore) ListAlertRules(_ context.Context, q *models.ListAlertRulesQuery) (models.RulesGroup, error) {
+	var result models.RulesGroup
+	for _, r := range f.rules {
+		if r.OrgID == q.OrgID {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+type fakeSilences struct {
+	silences map[string]*models.Silence
+	creates  int
+	clock    clock.Clock
+}
+
+func (f *fakeSilences) ListSilences(_ context.Context, _ int64, _ []string) ([]*models.Silence, error) {
+	result := make([]*models.Silence, 0, len(f.silences))
+	for _, s := range f.silences {
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+func (f *fakeSilences) CreateSilence(_ context.Context, _ int64, ps models.Silence) (string, error) {
+	f.creates++
+	if ps.ID == nil {
+		ps.ID = util.Pointer(fmt.Sprintf("silence-%d", len(f.silences)+1))
+	}
+	ps.Status = &amv2.SilenceStatus{State: util.Pointer(amv2.SilenceStatusStateActive)}
+	f.silences[*ps.ID] = &ps
+	return *ps.ID, nil
+}
+
+func (f *fakeSilences) DeleteSilence(_ context.Context, _ int64, id string) error {
+	s := f.silences[id]
+	s.Status = &amv2.SilenceStatus{State: util.Pointer(amv2.SilenceStatusStateExpired)}
+	endsAt := strfmt.DateTime(f.clock.Now())
+	s.EndsAt = &endsAt
+	return nil
+}
+
+func (f *fakeSilences) active() []*models.Silence {
+	var result []*models.Silence
+	for _, s := range f.silences {
+		if *s.Status.State != amv2.SilenceStatusStateExpired {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+type fakePeer struct {
+	primary bool
+}
+
+func (f *fakePeer) IsPrimary() bool {
+	return f.primary
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock()
+	clk.Set(time.Date(2024, 1, 1, 0, 30, 0, 0, time.UTC))
+
+	matcher, err := labels.NewMatcher(labels.MatchEqual, "team", "db")
+	require.NoError(t, err)
+	window := models.MaintenanceWindow{
+		UID:   "window",
+		OrgID: 1,
+		Title: "Database maintenance",
+		Mode:  models.MaintenanceWindowModePause,
+		TimeIntervals: []timeinterval.TimeInterval{{
+			Times: []timeinterval.TimeRange{{StartMinute: 60, EndMinute: 120}},
+		}},
+		Matchers: labels.Matchers{matcher},
+	}
+	db1 := &models.AlertRule{UID: "db.1", OrgID: 1, Labels: map[string]string{"team": "db"}}
+	db2 := &models.AlertRule{UID: "db-2", OrgID: 1, Labels: map[string]string{"team": "db"}}
+	ops := &models.AlertRule{UID: "ops", OrgID: 1, Labels: map[string]string{"team": "ops"}}
+
+	store := &fakeStore{windows: []models.MaintenanceWindow{window}}
+	rules := &fakeRuleStore{rules: models.RulesGroup{db1, db2, ops}}
+	silences := &fakeSilences{silences: map[string]*models.Silence{}, clock: clk}
+	svc := NewService(store, rules, silences, &fakePeer{primary: true}, clk, log.NewNopLogger())
+
+	t.Run("does nothing before the window starts", func(t *testing.T) {
+		svc.sync(ctx)
+		require.Empty(t, silences.silences)
+		require.False(t, svc.IsEvaluationPaused(db1, clk.Now()))
+	})
+
+	t.Run("pauses evaluation and silences matched rules while the window is active", func(t *testing.T) {
+		clk.Add(30 * time.Minute)
+		svc.sync(ctx)
+
+		require.True(t, svc.IsEvaluationPaused(db1, clk.Now()))
+		require.True(t, svc.IsEvaluationPaused(db2, clk.Now()))
+		require.False(t, svc.IsEvaluationPaused(ops, clk.Now()))
+
+		active := silences.active()
+		require.Len(t, active, 1)
+		require.Equal(t, `db-2|db\.1`, silenceMatcherValue(active[0]))
+		require.Equal(t, silenceCreatedByPrefix+"window", *active[0].CreatedBy)
+		require.Equal(t, clk.Now().Add(silenceDuration), time.Time(*active[0].EndsAt))
+	})
+
+	t.Run("extends the silence only when it is about to expire", func(t *testing.T) {
+		clk.Add(SyncInterval)
+		svc.sync(ctx)
+		require.Equal(t, 1, silences.creates)
+
+		clk.Add(silenceDuration / 2)
+		svc.sync(ctx)
+		require.Equal(t, 2, silences.creates)
+		active := silences.active()
+		require.Len(t, active, 1)
+		require.Equal(t, clk.Now().Add(silenceDuration), time.Time(*active[0].EndsAt))
+	})
+
+	t.Run("removes duplicated silences", func(t *testing.T) {
+		_, err := silences.CreateSilence(ctx, 1, newSilence(window, "db-2", clk.Now()))
+		require.NoError(t, err)
+		require.Len(t, silences.active(), 2)
+		svc.sync(ctx)
+		active := silences.active()
+		require.Len(t, active, 1)
+		require.Equal(t, "silence-1", *active[0].ID, "should keep the silence with the lowest ID")
+	})
+
+	t.Run("expires the silence when the window ends", func(t *testing.T) {
+		clk.Set(time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC))
+		svc.sync(ctx)
+		require.False(t, svc.IsEvaluationPaused(db1, clk.Now()))
+		require.Empty(t, silences.active())
+	})
+
+	t.Run("expires the silence when the window is deleted", func(t *testing.T) {
+		clk.Set(time.Date(2024, 1, 2, 1, 0, 0, 0, time.UTC))
+		svc.sync(ctx)
+		require.Len(t, silences.active(), 1)
+
+		store.windows = nil
+		svc.sync(ctx)
+		require.Empty(t, silences.active())
+		require.Empty(t, svc.silencedOrgs)
+	})
+
+	t.Run("mute windows do not pause evaluation", func(t *testing.T) {
+		mute := window
+		mute.Mode = models.MaintenanceWindowModeMute
+		store.windows = []models.MaintenanceWindow{mute}
+		svc.sync(ctx)
+		require.False(t, svc.IsEvaluationPaused(db1, clk.Now()))
+		require.Len(t, silences.active(), 1)
+	})
+
+	t.Run("only the primary instance maintains the silences", func(t *testing.T) {
+		store.windows = []models.MaintenanceWindow{window}
+		replica := NewService(store, rules, silences, &fakePeer{primary: false}, clk, log.NewNopLogger())
+		creates := silences.creates
+		clk.Add(silenceDuration)
+		replica.sync(ctx)
+		require.True(t, replica.IsEvaluationPaused(db1, clk.Now()))
+		require.Equal(t, creates, silences.creates)
+	})
+}
@@ -8,6 +8,11 @@ import (
 type RemoteWriter struct {
 	WritesTotal   *prometheus.CounterVec
 	WriteDuration *prometheus.HistogramVec
+
+	RetriesTotal             *prometheus.CounterVec
+	DroppedWritesTotal       *prometheus.CounterVec
+	InflightWrites           *prometheus.GaugeVec
+	BackpressureWaitDuration *prometheus.HistogramVec
 }
 
 func NewRemoteWriterMetrics(r prometheus.Registerer) *RemoteWriter {
@@ -26,5 +31,31 @@ func NewRemoteWriterMetrics(r prometheus.Registerer) *RemoteWriter {
 				Help:      "Histogram of remote write durations.",
 				Buckets:   prometheus.DefBuckets,
 			}, []string{"org", "backend"}),
+		RetriesTotal: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "remote_writer_retries_total",
+			Help:      "The total number of retried remote writes.",
+		}, []string{"org", "backend"}),
+		DroppedWritesTotal: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "remote_writer_dropped_writes_total",
+			Help:      "The total number of remote writes dropped while waiting for a free write slot.",
+		}, []string{"org", "backend"}),
+		InflightWrites: promauto.With(r).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "remote_writer_inflight_writes",
+			Help:      "The number of remote writes in progress.",
+		}, []string{"backend"}),
+		BackpressureWaitDuration: promauto.With(r).NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "remote_writer_backpressure_wait_duration_seconds",
+				Help:      "Histogram of the time remote writes waited for a free write slot.",
+				Buckets:   prometheus.DefBuckets,
+			}, []string{"backend"}),
 	}
 }
@@ -31,6 +31,11 @@ type Scheduler struct {
 	UpdateSchedulableAlertRulesDuration prometheus.Histogram
 	Ticker                              *ticker.Metrics
 	EvaluationMissed                    *prometheus.CounterVec
+	EvalQueryDuration                   *prometheus.HistogramVec
+	EvalSeries                          *prometheus.HistogramVec
+	EvaluationCost                      *prometheus.GaugeVec
+	EvaluationBudget                    *prometheus.GaugeVec
+	EvaluationIntervalMultiplier        *prometheus.GaugeVec
 }
 
 func NewSchedulerMetrics(r prometheus.Registerer) *Scheduler {
@@ -183,5 +188,52 @@ func NewSchedulerMetrics(r prometheus.Registerer) *Scheduler {
 			},
 			[]string{"org", "name"},
 		),
+		EvalQueryDuration: promauto.With(r).NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "rule_evaluation_query_duration_seconds",
+				Help:      "The time to execute the data source queries of a rule evaluation.",
+				Buckets:   []float64{.01, .1, .5, 1, 5, 10, 15, 30, 60, 120, 180, 240, 300},
+			},
+			[]string{"org"},
+		),
+		EvalSeries: promauto.With(r).NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "rule_evaluation_series",
+				Help:      "The number of series returned by the data source queries of a rule evaluation.",
+				Buckets:   prometheus.ExponentialBuckets(1, 4, 10),
+			},
+			[]string{"org"},
+		),
+		EvaluationCost: promauto.With(r).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "rule_evaluation_cost_seconds_per_minute",
+				Help:      "The evaluation time per minute the rules of an organization consume at their configured intervals.",
+			},
+			[]string{"org"},
+		),
+		EvaluationBudget: promauto.With(r).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "rule_evaluation_budget_seconds_per_minute",
+				Help:      "The evaluation time per minute the rules of an organization are allowed to consume. Zero means no budget.",
+			},
+			[]string{"org"},
+		),
+		EvaluationIntervalMultiplier: promauto.With(r).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "rule_evaluation_interval_multiplier",
+				Help:      "The factor the evaluation intervals of the rules of an organization are stretched by because the organization exceeds its evaluation budget.",
+			},
+			[]string{"org"},
+		),
 	}
 }
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+)
+
+var (
+	ErrAlertInstanceNotFound  = errutil.NotFound("alerting.acknowledgement.instanceNotFound", errutil.WithPublicMessage("Alert instance not found"))
+	ErrAlertInstanceNotFiring = errutil.BadRequest("alerting.acknowledgement.instanceNotFiring", errutil.WithPublicMessage("Only firing alert instances can be acknowledged"))
+	ErrInvalidAcknowledgement = errors.New("invalid acknowledgement")
+)
+
+// maxAcknowledgementCommentLength is the maximum length of the comment of an acknowledgement.
+const maxAcknowledgementCommentLength = 1024
+
+const (
+	// StateReasonAcknowledged is the reason of a firing alert instance that has an active acknowledgement.
+	StateReasonAcknowledged = "Acknowledged"
+
+	// AcknowledgedByAnnotation is the name of the annotation that contains the login of the user who acknowledged the alert instance.
+	AcknowledgedByAnnotation = GrafanaReservedLabelPrefix + "acknowledged_by"
+	// AcknowledgementCommentAnnotation is the name of the annotation that contains the comment of the acknowledgement.
+	AcknowledgementCommentAnnotation = GrafanaReservedLabelPrefix + "acknowledgement_comment"
+	// AcknowledgedUntilAnnotation is the name of the annotation that contains the time the acknowledgement expires, in RFC3339 format.
+	AcknowledgedUntilAnnotation = GrafanaReservedLabelPrefix + "acknowledged_until"
+)
+
+// Acknowledgement records that a user took ownership of a firing alert instance. It is removed when it expires
+// or when the alert instance stops firing.
+type Acknowledgement struct {
+	By        string    `json:"by"`
+	Comment   string    `json:"comment,omitempty"`
+	At        time.Time `json:"at"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Validate checks that the acknowledgement has an owner, a comment of reasonable length and expires after now.
+func (a *Acknowledgement) Validate(now time.Time) error {
+	if a.By == "" {
+		return fmt.Errorf("%w: owner must be set", ErrInvalidAcknowledgement)
+	}
+	if len(a.Comment) > maxAcknowledgementCommentLength {
+		return fmt.Errorf("%w: comment must not be longer than %d characters", ErrInvalidAcknowledgement, maxAcknowledgementCommentLength)
+	}
+	if !a.ExpiresAt.After(now) {
+		return fmt.Errorf("%w: expiry must be in the future", ErrInvalidAcknowledgement)
+	}
+	return nil
+}
+
+// IsActive returns true if the acknowledgement is set and has not expired at the given time.
+func (a *Acknowledgement) IsActive(now time.Time) bool {
+	return a != nil && now.Before(a.ExpiresAt)
+}
+
+// Annotations returns the annotations that describe the acknowledgement to notification templates.
+func (a *Acknowledgement) Annotations() map[string]string {
+	result := map[string]string{
+		AcknowledgedByAnnotation:    a.By,
+		AcknowledgedUntilAnnotation: a.ExpiresAt.UTC().Format(time.RFC3339),
+	}
+	if a.Comment != "" {
+		result[AcknowledgementCommentAnnotation] = a.Comment
+	}
+	return result
+}
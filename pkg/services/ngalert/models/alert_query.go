@@ -129,6 +129,28 @@ func (aq *AlertQuery) IsHysteresisExpression() (bool, error) {
 	return expr.IsHysteresisExpression(aq.modelProps), nil
 }
 
+// IsMultiLevelHysteresisExpression returns true if the model describes a multi-level hysteresis command expression. Returns error if the Model is not a valid JSON
+func (aq *AlertQuery) IsMultiLevelHysteresisExpression() (bool, error) {
+	if aq.modelProps == nil {
+		err := aq.setModelProps()
+		if err != nil {
+			return false, err
+		}
+	}
+	return expr.IsMultiLevelHysteresisExpression(aq.modelProps), nil
+}
+
+// PatchMultiLevelHysteresisExpression updates the AlertQuery to include the state of the results into the multi-level hysteresis
+func (aq *AlertQuery) PatchMultiLevelHysteresisExpression(state expr.HysteresisState) error {
+	if aq.modelProps == nil {
+		err := aq.setModelProps()
+		if err != nil {
+			return err
+		}
+	}
+	return expr.SetStateToMultiLevelHysteresisCommand(aq.modelProps, state)
+}
+
 // PatchHysteresisExpression updates the AlertQuery to include loaded metrics into hysteresis
 func (aq *AlertQuery) PatchHysteresisExpression(loadedMetrics map[data.Fingerprint]struct{}) error {
 	if aq.modelProps == nil {
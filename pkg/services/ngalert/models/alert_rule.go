@@ -164,6 +164,7 @@ const (
 	StateReasonUpdated       = "Updated"
 	StateReasonRuleDeleted   = "RuleDeleted"
 	StateReasonKeepLast      = "KeepLast"
+	StateReasonKeepFiring    = "KeepFiring"
 )
 
 func ConcatReasons(reasons ...string) string {
@@ -265,10 +266,12 @@ type AlertRule struct {
 	// ideally this field should have been apimodels.ApiDuration
 	// but this is currently not possible because of circular dependencies
 	For                  time.Duration
+	KeepFiringFor        time.Duration
 	Annotations          map[string]string
 	Labels               map[string]string
 	IsPaused             bool
 	NotificationSettings []NotificationSettings `xorm:"notification_settings"` // we use slice to workaround xorm mapping that does not serialize a struct to JSON unless it's a slice
+	DependsOn            []string               `xorm:"depends_on"`
 }
 
 // Namespaced describes a class of resources that are stored in a specific namespace.
@@ -557,6 +560,14 @@ func (alertRule *AlertRule) ValidateAlertRule(cfg setting.UnifiedAlertingSetting
 		return fmt.Errorf("%w: field `for` cannot be negative", ErrAlertRuleFailedValidation)
 	}
 
+	if alertRule.KeepFiringFor < 0 {
+		return fmt.Errorf("%w: field `keep_firing_for` cannot be negative", ErrAlertRuleFailedValidation)
+	}
+
+	if err := validateDependsOn(alertRule); err != nil {
+		return err
+	}
+
 	if len(alertRule.Labels) > 0 {
 		for label := range alertRule.Labels {
 			if _, ok := LabelsUserCannotSpecify[label]; ok {
@@ -599,6 +610,29 @@ func validateRecordingRuleFields(rule *AlertRule) error {
 	return nil
 }
 
+func validateDependsOn(rule *AlertRule) error {
+	if len(rule.DependsOn) == 0 {
+		return nil
+	}
+	if rule.Type() == RuleTypeRecording {
+		return fmt.Errorf("%w: recording rules cannot depend on other rules", ErrAlertRuleFailedValidation)
+	}
+	seen := make(map[string]struct{}, len(rule.DependsOn))
+	for _, uid := range rule.DependsOn {
+		if uid == "" {
+			return fmt.Errorf("%w: field `depends_on` cannot contain empty rule UIDs", ErrAlertRuleFailedValidation)
+		}
+		if uid == rule.UID {
+			return fmt.Errorf("%w: rule cannot depend on itself", ErrAlertRuleFailedValidation)
+		}
+		if _, ok := seen[uid]; ok {
+			return fmt.Errorf("%w: field `depends_on` contains rule UID %s more than once", ErrAlertRuleFailedValidation, uid)
+		}
+		seen[uid] = struct{}{}
+	}
+	return nil
+}
+
 func (alertRule *AlertRule) ResourceType() string {
 	return "alertRule"
 }
@@ -648,10 +682,12 @@ type AlertRuleVersion struct {
 	// ideally this field should have been apimodels.ApiDuration
 	// but this is currently not possible because of circular dependencies
 	For                  time.Duration
+	KeepFiringFor        time.Duration
 	Annotations          map[string]string
 	Labels               map[string]string
 	IsPaused             bool
 	NotificationSettings []NotificationSettings `xorm:"notification_settings"` // we use slice to workaround xorm mapping that does not serialize a struct to JSON unless it's a slice
+	DependsOn            []string               `xorm:"depends_on"`
 }
 
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
@@ -790,6 +826,9 @@ func PatchPartialAlertRule(existingRule *AlertRule, ruleToPatch *AlertRuleWithOp
 	if ruleToPatch.For == -1 {
 		ruleToPatch.For = existingRule.For
 	}
+	if ruleToPatch.KeepFiringFor == -1 {
+		ruleToPatch.KeepFiringFor = existingRule.KeepFiringFor
+	}
 	if !ruleToPatch.HasPause {
 		ruleToPatch.IsPaused = existingRule.IsPaused
 	}
@@ -826,6 +865,8 @@ func SortAlertRulesByGroupIndex(rules []AlertRule) {
 const (
 	QuotaTargetSrv quota.TargetSrv = "ngalert"
 	QuotaTarget    quota.Target    = "alert_rule"
+	// QuotaTargetEvaluationCost limits the milliseconds spent evaluating the alert rules per minute.
+	QuotaTargetEvaluationCost quota.Target = "alert_rule_evaluation_cost"
 )
 
 type ruleKeyContextKey struct{}
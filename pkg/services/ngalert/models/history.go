@@ -3,6 +3,8 @@ package models
 import (
 	"time"
 
+	"github.com/prometheus/alertmanager/pkg/labels"
+
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 )
 
@@ -13,8 +15,13 @@ type HistoryQuery struct {
 	DashboardUID string
 	PanelID      int64
 	Labels       map[string]string
-	From         time.Time
-	To           time.Time
-	Limit        int
+	// Matchers filter the history by the labels of the alert instances. Unlike Labels, they support
+	// the non-equality and regular expression match types. The annotations backend does not support them.
+	Matchers labels.Matchers
+	From     time.Time
+	To       time.Time
+	Limit    int
+	// Offset is the number of most recent entries to skip. Together with Limit, it is used to page through history.
+	Offset       int
 	SignedInUser identity.Requester
 }
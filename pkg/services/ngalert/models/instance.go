@@ -16,7 +16,17 @@ type AlertInstance struct {
 	LastEvalTime      time.Time
 	LastSentAt        *time.Time
 	ResolvedAt        *time.Time
+	KeepFiringSince   *time.Time
 	ResultFingerprint string
+	// HysteresisLevel and HysteresisUnloading are the state of a multi-level hysteresis condition.
+	HysteresisLevel     int64
+	HysteresisUnloading int64
+	// AcknowledgedBy, AcknowledgementComment, AcknowledgedAt and AcknowledgementExpiresAt describe the acknowledgement
+	// of a firing instance. AcknowledgedAt is nil if the instance is not acknowledged.
+	AcknowledgedBy           string
+	AcknowledgementComment   string
+	AcknowledgedAt           *time.Time
+	AcknowledgementExpiresAt *time.Time
 }
 
 type AlertInstanceKey struct {
@@ -39,6 +49,8 @@ const (
 	InstanceStateNoData InstanceStateType = "NoData"
 	// InstanceStateError is for an erroring alert.
 	InstanceStateError InstanceStateType = "Error"
+	// InstanceStateInhibited is for an alert that is firing while a rule it depends on is firing.
+	InstanceStateInhibited InstanceStateType = "Inhibited"
 )
 
 // IsValid checks that the value of InstanceStateType is a valid
@@ -48,7 +60,8 @@ func (i InstanceStateType) IsValid() bool {
 		i == InstanceStateNormal ||
 		i == InstanceStateNoData ||
 		i == InstanceStatePending ||
-		i == InstanceStateError
+		i == InstanceStateError ||
+		i == InstanceStateInhibited
 }
 
 // ListAlertInstancesQuery is the query list alert Instances.
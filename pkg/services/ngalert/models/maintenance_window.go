@@ -0,0 +1,112 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+)
+
+var (
+	ErrMaintenanceWindowNotFound         = errutil.NotFound("alerting.maintenance-windows.notFound", errutil.WithPublicMessage("Maintenance window not found"))
+	ErrMaintenanceWindowExists           = errutil.BadRequest("alerting.maintenance-windows.exists", errutil.WithPublicMessage("Maintenance window with this UID or title already exists."))
+	ErrMaintenanceWindowVersionConflict  = errutil.Conflict("alerting.maintenance-windows.conflict")
+	ErrMaintenanceWindowFailedValidation = errors.New("invalid maintenance window")
+)
+
+// MaintenanceWindowMode defines what happens to the alert rules matched by an active maintenance window.
+type MaintenanceWindowMode string
+
+const (
+	// MaintenanceWindowModeMute keeps evaluating the matched rules but silences their notifications.
+	MaintenanceWindowModeMute MaintenanceWindowMode = "mute"
+	// MaintenanceWindowModePause stops the evaluation of the matched rules, so neither state nor state history
+	// changes while the window is active. Notifications of already firing alerts are silenced as well.
+	MaintenanceWindowModePause MaintenanceWindowMode = "pause"
+)
+
+// MaintenanceWindowMaxTitleLength is the maximum length of the maintenance window title.
+const MaintenanceWindowMaxTitleLength = 190
+
+// MaintenanceWindow is a recurring period of planned maintenance during which the matched alert rules are either
+// muted or not evaluated at all.
+type MaintenanceWindow struct {
+	UID   string
+	OrgID int64
+	Title string
+	Mode  MaintenanceWindowMode
+	// TimeIntervals define when the window is active. The window is active if any of the intervals contains the time.
+	TimeIntervals []timeinterval.TimeInterval
+	// Matchers select the rules by their labels. All matchers must match.
+	Matchers labels.Matchers
+	// FolderUIDs restrict the window to the rules in these folders. If empty, rules in all folders are matched.
+	FolderUIDs []string
+	Version    int64
+	Updated    time.Time
+}
+
+func (w *MaintenanceWindow) ResourceType() string {
+	return "maintenanceWindow"
+}
+
+func (w *MaintenanceWindow) ResourceID() string {
+	return w.UID
+}
+
+// Validate checks that the maintenance window is well-formed.
+func (w *MaintenanceWindow) Validate() error {
+	if w.Title == "" {
+		return fmt.Errorf("%w: title is empty", ErrMaintenanceWindowFailedValidation)
+	}
+	if len(w.Title) > MaintenanceWindowMaxTitleLength {
+		return fmt.Errorf("%w: title is longer than %d characters", ErrMaintenanceWindowFailedValidation, MaintenanceWindowMaxTitleLength)
+	}
+	switch w.Mode {
+	case MaintenanceWindowModeMute, MaintenanceWindowModePause:
+	default:
+		return fmt.Errorf("%w: unknown mode '%s', must be one of [%s, %s]", ErrMaintenanceWindowFailedValidation, w.Mode, MaintenanceWindowModeMute, MaintenanceWindowModePause)
+	}
+	if len(w.TimeIntervals) == 0 {
+		return fmt.Errorf("%w: at least one time interval is required", ErrMaintenanceWindowFailedValidation)
+	}
+	if len(w.Matchers) == 0 && len(w.FolderUIDs) == 0 {
+		return fmt.Errorf("%w: at least one matcher or folder is required", ErrMaintenanceWindowFailedValidation)
+	}
+	for _, m := range w.Matchers {
+		if m == nil {
+			return fmt.Errorf("%w: matcher is empty", ErrMaintenanceWindowFailedValidation)
+		}
+	}
+	return nil
+}
+
+// IsActive returns true if the maintenance window is active at the given time.
+func (w *MaintenanceWindow) IsActive(t time.Time) bool {
+	for _, ti := range w.TimeIntervals {
+		if ti.ContainsTime(t.UTC()) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches returns true if the alert rule is selected by the folders and matchers of the maintenance window.
+func (w *MaintenanceWindow) Matches(rule *AlertRule) bool {
+	if rule == nil || rule.OrgID != w.OrgID {
+		return false
+	}
+	if len(w.FolderUIDs) > 0 && !slices.Contains(w.FolderUIDs, rule.NamespaceUID) {
+		return false
+	}
+	for _, m := range w.Matchers {
+		if !m.Matches(rule.Labels[m.Name]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceWindow(t *testing.T) {
+	matcher, err := labels.NewMatcher(labels.MatchEqual, "team", "db")
+	require.NoError(t, err)
+	w := MaintenanceWindow{
+		OrgID: 1,
+		Mode:  MaintenanceWindowModePause,
+		TimeIntervals: []timeinterval.TimeInterval{{
+			Times: []timeinterval.TimeRange{{StartMinute: 60, EndMinute: 120}},
+		}},
+		Matchers:   labels.Matchers{matcher},
+		FolderUIDs: []string{"folder"},
+	}
+
+	t.Run("is active within its time intervals", func(t *testing.T) {
+		day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		require.False(t, w.IsActive(day.Add(59*time.Minute)))
+		require.True(t, w.IsActive(day.Add(time.Hour)))
+		require.False(t, w.IsActive(day.Add(2*time.Hour)))
+	})
+
+	t.Run("matches rules by folder and labels", func(t *testing.T) {
+		rule := &AlertRule{OrgID: 1, NamespaceUID: "folder", Labels: map[string]string{"team": "db"}}
+		require.True(t, w.Matches(rule))
+
+		other := *rule
+		other.NamespaceUID = "other"
+		require.False(t, w.Matches(&other))
+
+		other = *rule
+		other.Labels = map[string]string{"team": "ops"}
+		require.False(t, w.Matches(&other))
+
+		other = *rule
+		other.OrgID = 2
+		require.False(t, w.Matches(&other))
+	})
+
+	t.Run("requires matchers or folders", func(t *testing.T) {
+		valid := w
+		valid.Title = "maintenance"
+		require.NoError(t, valid.Validate())
+
+		onlyFolders := valid
+		onlyFolders.Matchers = nil
+		require.NoError(t, onlyFolders.Validate())
+
+		onlyMatchers := valid
+		onlyMatchers.FolderUIDs = nil
+		require.NoError(t, onlyMatchers.Validate())
+
+		empty := valid
+		empty.Matchers = nil
+		empty.FolderUIDs = nil
+		require.ErrorIs(t, empty.Validate(), ErrMaintenanceWindowFailedValidation)
+	})
+}
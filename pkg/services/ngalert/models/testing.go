@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/rand"
 	"slices"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -299,12 +300,30 @@ func (a *AlertRuleMutators) WithTitle(title string) AlertRuleMutator {
 	}
 }
 
+func (a *AlertRuleMutators) WithUID(uid string) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.UID = uid
+	}
+}
+
 func (a *AlertRuleMutators) WithFor(duration time.Duration) AlertRuleMutator {
 	return func(rule *AlertRule) {
 		rule.For = duration
 	}
 }
 
+func (a *AlertRuleMutators) WithKeepFiringFor(duration time.Duration) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.KeepFiringFor = duration
+	}
+}
+
+func (a *AlertRuleMutators) WithDependsOn(uids ...string) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.DependsOn = uids
+	}
+}
+
 func (a *AlertRuleMutators) WithForNTimes(timesOfInterval int64) AlertRuleMutator {
 	return func(rule *AlertRule) {
 		rule.For = time.Duration(rule.IntervalSeconds*timesOfInterval) * time.Second
@@ -604,6 +623,7 @@ func CopyRule(r *AlertRule, mutators ...AlertRuleMutator) *AlertRule {
 		NoDataState:     r.NoDataState,
 		ExecErrState:    r.ExecErrState,
 		For:             r.For,
+		KeepFiringFor:   r.KeepFiringFor,
 		Record:          r.Record,
 	}
 
@@ -653,6 +673,11 @@ func CopyRule(r *AlertRule, mutators ...AlertRuleMutator) *AlertRule {
 		result.NotificationSettings = append(result.NotificationSettings, CopyNotificationSettings(s))
 	}
 
+	if r.DependsOn != nil {
+		result.DependsOn = make([]string, len(r.DependsOn))
+		copy(result.DependsOn, r.DependsOn)
+	}
+
 	if len(mutators) > 0 {
 		for _, mutator := range mutators {
 			mutator(&result)
@@ -806,6 +831,36 @@ func CreateHysteresisExpression(t *testing.T, refID string, inputRefID string, t
 	return q
 }
 
+// CreateMultiLevelHysteresisExpression creates a threshold expression with a level for each of the thresholds.
+// A series leaves a level when it is below the threshold of the level for the given number of evaluations.
+func CreateMultiLevelHysteresisExpression(t *testing.T, refID string, inputRefID string, unloadEvaluations int, thresholds ...int) AlertQuery {
+	t.Helper()
+	conditions := make([]string, 0, len(thresholds))
+	for _, threshold := range thresholds {
+		conditions = append(conditions, fmt.Sprintf(`{"evaluator": {"params": [%[1]d], "type": "gt"}, "unloadEvaluator": {"params": [%[1]d], "type": "lt"}, "unloadEvaluations": %[2]d}`, threshold, unloadEvaluations))
+	}
+	q := AlertQuery{
+		RefID:         refID,
+		QueryType:     expr.DatasourceType,
+		DatasourceUID: expr.DatasourceUID,
+		Model: json.RawMessage(fmt.Sprintf(`
+		{
+			"refId": "%[1]s",
+			"type": "threshold",
+			"datasource": {
+				"uid": "%[4]s",
+				"type": "%[5]s"
+			},
+			"expression": "%[2]s",
+			"conditions": [%[3]s]
+		}`, refID, inputRefID, strings.Join(conditions, ","), expr.DatasourceUID, expr.DatasourceType)),
+	}
+	h, err := q.IsMultiLevelHysteresisExpression()
+	require.NoError(t, err)
+	require.Truef(t, h, "test model is expected to be a multi-level hysteresis expression")
+	return q
+}
+
 type AlertInstanceMutator func(*AlertInstance)
 
 // AlertInstanceGen provides a factory function that generates a random AlertInstance.
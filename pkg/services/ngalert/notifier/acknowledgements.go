@@ -0,0 +1,100 @@
+package notifier
+
+import (
+	"context"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// suppressAcknowledgedAlertsSetting is the setting of an integration that enables withoutAcknowledgedAlerts for it.
+const suppressAcknowledgedAlertsSetting = "suppressAcknowledgedAlerts"
+
+// withoutAcknowledgedAlerts wraps the integrations of a receiver that opted in with the suppressAcknowledgedAlerts
+// setting so that they do not notify about firing alerts that are acknowledged. This keeps repeated notifications of a
+// group from including the alerts someone already took ownership of, without having to silence them. Resolved alerts
+// are always included. Other integrations notify about acknowledged alerts like about any other alert.
+func withoutAcknowledgedAlerts(receiver *alertingNotify.APIReceiver, integrations []*alertingNotify.Integration) []*alertingNotify.Integration {
+	suppress := suppressAcknowledgedIntegrations(receiver)
+	if len(suppress) == 0 {
+		return integrations
+	}
+	result := make([]*alertingNotify.Integration, 0, len(integrations))
+	for _, i := range integrations {
+		if !suppress[integrationKey{typ: i.Name(), index: i.Index()}] {
+			result = append(result, i)
+			continue
+		}
+		n := &acknowledgementFilter{upstream: i, now: time.Now}
+		result = append(result, alertingNotify.NewIntegration(n, i, i.Name(), i.Index(), receiver.Name))
+	}
+	return result
+}
+
+// integrationKey identifies an integration of a receiver. Integrations are indexed per type, in the order of their
+// configurations.
+type integrationKey struct {
+	typ   string
+	index int
+}
+
+// suppressAcknowledgedIntegrations returns the integrations of the receiver that have the suppressAcknowledgedAlerts
+// setting enabled.
+func suppressAcknowledgedIntegrations(receiver *alertingNotify.APIReceiver) map[integrationKey]bool {
+	result := map[integrationKey]bool{}
+	indexes := map[string]int{}
+	for _, cfg := range receiver.Integrations {
+		index := indexes[cfg.Type]
+		indexes[cfg.Type]++
+		if len(cfg.Settings) == 0 {
+			continue
+		}
+		settings, err := simplejson.NewJson(cfg.Settings)
+		if err != nil {
+			continue
+		}
+		if settings.Get(suppressAcknowledgedAlertsSetting).MustBool() {
+			result[integrationKey{typ: cfg.Type, index: index}] = true
+		}
+	}
+	return result
+}
+
+type acknowledgementFilter struct {
+	upstream *alertingNotify.Integration
+	now      func() time.Time
+}
+
+// Notify notifies about the alerts that are not acknowledged. If all alerts are acknowledged, the notification is not
+// sent and it is not retried.
+func (f *acknowledgementFilter) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	now := f.now()
+	filtered := make([]*types.Alert, 0, len(alerts))
+	for _, a := range alerts {
+		if !a.ResolvedAt(now) && isAcknowledged(a, now) {
+			continue
+		}
+		filtered = append(filtered, a)
+	}
+	if len(filtered) == 0 {
+		return false, nil
+	}
+	return f.upstream.Notify(ctx, filtered...)
+}
+
+// isAcknowledged returns true if the alert has the annotations of an acknowledgement that has not expired yet.
+func isAcknowledged(a *types.Alert, now time.Time) bool {
+	if a.Annotations[model.LabelName(models.AcknowledgedByAnnotation)] == "" {
+		return false
+	}
+	until, err := time.Parse(time.RFC3339, string(a.Annotations[model.LabelName(models.AcknowledgedUntilAnnotation)]))
+	if err != nil {
+		return false
+	}
+	return now.Before(until)
+}
@@ -0,0 +1,90 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type recordingNotifier struct {
+	notified [][]*types.Alert
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, alerts ...*types.Alert) (bool, error) {
+	n.notified = append(n.notified, alerts)
+	return false, nil
+}
+
+func (n *recordingNotifier) SendResolved() bool {
+	return true
+}
+
+func TestWithoutAcknowledgedAlerts(t *testing.T) {
+	now := time.Now()
+	upstream := &recordingNotifier{}
+	other := &recordingNotifier{}
+	receiver := &alertingNotify.APIReceiver{
+		ConfigReceiver: alertingNotify.ConfigReceiver{Name: "team"},
+		GrafanaIntegrations: alertingNotify.GrafanaIntegrations{Integrations: []*alertingNotify.GrafanaIntegrationConfig{
+			{Type: "webhook", Settings: json.RawMessage(`{"url": "http://localhost/0"}`)},
+			{Type: "webhook", Settings: json.RawMessage(`{"url": "http://localhost/1", "suppressAcknowledgedAlerts": true}`)},
+		}},
+	}
+	integrations := withoutAcknowledgedAlerts(receiver, []*alertingNotify.Integration{
+		alertingNotify.NewIntegration(other, other, "webhook", 0, "team"),
+		alertingNotify.NewIntegration(upstream, upstream, "webhook", 1, "team"),
+	})
+	require.Len(t, integrations, 2)
+	require.Equal(t, "webhook", integrations[1].Name())
+	require.Equal(t, 1, integrations[1].Index())
+
+	alert := func(name string, resolved bool, acknowledgedUntil time.Time) *types.Alert {
+		a := &types.Alert{Alert: model.Alert{
+			Labels:      model.LabelSet{model.AlertNameLabel: model.LabelValue(name)},
+			Annotations: model.LabelSet{},
+			StartsAt:    now.Add(-time.Hour),
+			EndsAt:      now.Add(time.Hour),
+		}}
+		if resolved {
+			a.EndsAt = now.Add(-time.Minute)
+		}
+		if !acknowledgedUntil.IsZero() {
+			a.Annotations[model.LabelName(models.AcknowledgedByAnnotation)] = "admin"
+			a.Annotations[model.LabelName(models.AcknowledgedUntilAnnotation)] = model.LabelValue(acknowledgedUntil.Format(time.RFC3339))
+		}
+		return a
+	}
+
+	firing := alert("firing", false, time.Time{})
+	acknowledged := alert("acknowledged", false, now.Add(time.Hour))
+	expired := alert("expired", false, now.Add(-time.Hour))
+	resolved := alert("resolved", true, now.Add(time.Hour))
+
+	_, err := integrations[1].Notify(context.Background(), firing, acknowledged, expired, resolved)
+	require.NoError(t, err)
+	require.Len(t, upstream.notified, 1)
+	require.Equal(t, []*types.Alert{firing, expired, resolved}, upstream.notified[0])
+
+	t.Run("should notify about acknowledged alerts if not enabled", func(t *testing.T) {
+		_, err := integrations[0].Notify(context.Background(), firing, acknowledged)
+		require.NoError(t, err)
+		require.Len(t, other.notified, 1)
+		require.Equal(t, []*types.Alert{firing, acknowledged}, other.notified[0])
+	})
+
+	t.Run("should not notify if all alerts are acknowledged", func(t *testing.T) {
+		upstream.notified = nil
+		retry, err := integrations[1].Notify(context.Background(), acknowledged)
+		require.NoError(t, err)
+		require.False(t, retry)
+		require.Empty(t, upstream.notified)
+	})
+}
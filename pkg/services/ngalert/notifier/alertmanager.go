@@ -380,7 +380,8 @@ func (am *alertmanager) AppURL() string {
 
 // buildReceiverIntegrations builds a list of integration notifiers off of a receiver config.
 func (am *alertmanager) buildReceiverIntegrations(receiver *alertingNotify.APIReceiver, tmpl *alertingTemplates.Template) ([]*alertingNotify.Integration, error) {
-	receiverCfg, err := alertingNotify.BuildReceiverConfiguration(context.Background(), receiver, am.decryptFn)
+	builtin, custom := splitCustomIntegrations(receiver)
+	receiverCfg, err := alertingNotify.BuildReceiverConfiguration(context.Background(), builtin, am.decryptFn)
 	if err != nil {
 		return nil, err
 	}
@@ -403,7 +404,12 @@ func (am *alertmanager) buildReceiverIntegrations(receiver *alertingNotify.APIRe
 	if err != nil {
 		return nil, err
 	}
-	return integrations, nil
+
+	customIntegrations, err := buildCustomIntegrations(context.Background(), receiver.Name, custom, tmpl, am.decryptFn, am.orgID)
+	if err != nil {
+		return nil, err
+	}
+	return withoutAcknowledgedAlerts(receiver, append(integrations, customIntegrations...)), nil
 }
 
 // PutAlerts receives the alerts and then sends them through the corresponding route based on whenever the alert has a receiver embedded or not
@@ -12,6 +12,11 @@ import (
 
 // GetAvailableNotifiers returns the metadata of all the notification channels that can be configured.
 func GetAvailableNotifiers() []*NotifierPlugin {
+	return append(builtinNotifiers(), customNotifiers()...)
+}
+
+// builtinNotifiers returns the metadata of the notification channels that are provided by the alerting package.
+func builtinNotifiers() []*NotifierPlugin {
 	hostname, _ := os.Hostname()
 
 	pushoverSoundOptions := []SelectOption{
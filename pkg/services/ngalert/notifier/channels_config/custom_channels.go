@@ -0,0 +1,32 @@
+package channels_config
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	registeredMtx       sync.RWMutex
+	registeredNotifiers []*NotifierPlugin
+)
+
+// RegisterCustomNotifier adds the metadata of a contact point type that is not provided by the alerting package
+// to the available notifiers. It returns an error if a notifier of the same type is already available.
+func RegisterCustomNotifier(plugin *NotifierPlugin) error {
+	registeredMtx.Lock()
+	defer registeredMtx.Unlock()
+
+	for _, n := range append(builtinNotifiers(), registeredNotifiers...) {
+		if n.Type == plugin.Type {
+			return fmt.Errorf("notifier of type '%s' already exists", plugin.Type)
+		}
+	}
+	registeredNotifiers = append(registeredNotifiers, plugin)
+	return nil
+}
+
+func customNotifiers() []*NotifierPlugin {
+	registeredMtx.RLock()
+	defer registeredMtx.RUnlock()
+	return append([]*NotifierPlugin(nil), registeredNotifiers...)
+}
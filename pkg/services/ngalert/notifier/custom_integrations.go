@@ -0,0 +1,53 @@
+package notifier
+
+import (
+	"context"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	alertingTemplates "github.com/grafana/alerting/templates"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/integrations"
+	// Register the contact point types that are not provided by the alerting package.
+	_ "github.com/grafana/grafana/pkg/services/ngalert/notifier/integrations/templatedhttp"
+)
+
+// splitCustomIntegrations splits the integrations of a receiver into the integrations that are provided by the alerting
+// package, which are returned as a copy of the receiver, and the integrations of custom contact point types.
+func splitCustomIntegrations(receiver *alertingNotify.APIReceiver) (*alertingNotify.APIReceiver, []*alertingNotify.GrafanaIntegrationConfig) {
+	var builtin, custom []*alertingNotify.GrafanaIntegrationConfig
+	for _, integration := range receiver.Integrations {
+		if _, ok := integrations.Get(integration.Type); ok {
+			custom = append(custom, integration)
+			continue
+		}
+		builtin = append(builtin, integration)
+	}
+	if len(custom) == 0 {
+		return receiver, nil
+	}
+
+	result := *receiver
+	result.GrafanaIntegrations = alertingNotify.GrafanaIntegrations{Integrations: builtin}
+	return &result, custom
+}
+
+// buildCustomIntegrations validates the integrations of custom contact point types and creates their notifiers.
+func buildCustomIntegrations(ctx context.Context, receiverName string, configs []*alertingNotify.GrafanaIntegrationConfig, tmpl *alertingTemplates.Template, decrypt alertingNotify.GetDecryptedValueFn, orgID int64) ([]*alertingNotify.Integration, error) {
+	result := make([]*alertingNotify.Integration, 0, len(configs))
+	// Integrations are indexed per type, like the integrations that are provided by the alerting package.
+	indexes := make(map[string]int, len(configs))
+	for _, integration := range configs {
+		factory, _ := integrations.Get(integration.Type)
+		cfg := integrations.NewConfig(ctx, integration, decrypt, orgID)
+		if err := factory.Validate(cfg); err != nil {
+			return nil, alertingNotify.IntegrationValidationError{Integration: integration, Err: err}
+		}
+		n, err := factory.New(cfg, tmpl, LoggerFactory("ngalert.notifier."+integration.Type, "notifierUID", integration.UID))
+		if err != nil {
+			return nil, alertingNotify.IntegrationValidationError{Integration: integration, Err: err}
+		}
+		result = append(result, alertingNotify.NewIntegration(n, n, integration.Type, indexes[integration.Type], receiverName))
+		indexes[integration.Type]++
+	}
+	return result, nil
+}
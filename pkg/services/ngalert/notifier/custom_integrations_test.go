@@ -0,0 +1,97 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	alertingTemplates "github.com/grafana/alerting/templates"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCustomIntegrations(t *testing.T) {
+	receiver := &alertingNotify.APIReceiver{
+		ConfigReceiver: alertingNotify.ConfigReceiver{Name: "tickets"},
+		GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
+			Integrations: []*alertingNotify.GrafanaIntegrationConfig{
+				{UID: "a", Type: "templatedhttp", Settings: json.RawMessage(`{"url": "http://localhost/a"}`)},
+				{UID: "b", Type: "webhook", Settings: json.RawMessage(`{"url": "http://localhost/b"}`)},
+				{UID: "c", Type: "templatedhttp", Settings: json.RawMessage(`{"url": "http://localhost/c"}`)},
+			},
+		},
+	}
+
+	builtin, custom := splitCustomIntegrations(receiver)
+	require.Len(t, receiver.Integrations, 3, "should not modify the receiver")
+	require.Equal(t, "tickets", builtin.Name)
+	require.Len(t, builtin.Integrations, 1)
+	require.Equal(t, "b", builtin.Integrations[0].UID)
+	require.Len(t, custom, 2)
+
+	integrations, err := buildCustomIntegrations(context.Background(), receiver.Name, custom, alertingTemplates.ForTests(t), alertingNotify.NoopDecrypt, 1)
+	require.NoError(t, err)
+	require.Len(t, integrations, 2)
+	for i, integration := range integrations {
+		require.Equal(t, "templatedhttp", integration.Name())
+		require.Equal(t, i, integration.Index())
+	}
+
+	t.Run("should not copy receivers without custom integrations", func(t *testing.T) {
+		r := &alertingNotify.APIReceiver{GrafanaIntegrations: alertingNotify.GrafanaIntegrations{Integrations: builtin.Integrations}}
+		b, custom := splitCustomIntegrations(r)
+		require.Same(t, r, b)
+		require.Empty(t, custom)
+	})
+
+	t.Run("should fail when templates are invalid", func(t *testing.T) {
+		invalid := &alertingNotify.GrafanaIntegrationConfig{UID: "d", Type: "templatedhttp", Settings: json.RawMessage(`{"url": "http://localhost/{{ .Status "}`)}
+		_, err := buildCustomIntegrations(context.Background(), receiver.Name, []*alertingNotify.GrafanaIntegrationConfig{invalid}, alertingTemplates.ForTests(t), alertingNotify.NoopDecrypt, 1)
+		var validationErr alertingNotify.IntegrationValidationError
+		require.ErrorAs(t, err, &validationErr)
+		require.Equal(t, "d", validationErr.Integration.UID)
+	})
+}
+
+func TestValidateIntegration(t *testing.T) {
+	testCases := []struct {
+		name        string
+		integration *alertingNotify.GrafanaIntegrationConfig
+		expErr      string
+	}{
+		{
+			name:        "valid custom integration",
+			integration: &alertingNotify.GrafanaIntegrationConfig{Type: "templatedhttp", Settings: json.RawMessage(`{"url": "http://localhost", "body": "{{ template \"custom\" . }}"}`)},
+		},
+		{
+			name:        "custom integration with invalid template",
+			integration: &alertingNotify.GrafanaIntegrationConfig{Type: "templatedhttp", Settings: json.RawMessage(`{"url": "http://localhost", "headers": {"X-Team": "{{ .CommonLabels.team"}}`)},
+			expErr:      "invalid template in header 'X-Team'",
+		},
+		{
+			name:        "custom integration with invalid settings",
+			integration: &alertingNotify.GrafanaIntegrationConfig{Type: "templatedhttp", Settings: json.RawMessage(`{}`)},
+			expErr:      "required field 'url' is not specified",
+		},
+		{
+			name:        "valid builtin integration",
+			integration: &alertingNotify.GrafanaIntegrationConfig{Type: "webhook", Settings: json.RawMessage(`{"url": "http://localhost"}`)},
+		},
+		{
+			name:        "unknown integration",
+			integration: &alertingNotify.GrafanaIntegrationConfig{Type: "unknown", Settings: json.RawMessage(`{}`)},
+			expErr:      "notifier unknown is not supported",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateIntegration(context.Background(), tc.integration, alertingNotify.NoopDecrypt)
+			if tc.expErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.expErr)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+// Package integrations is the extension point for contact point types that are not provided by the alerting package.
+//
+// An integration registers a Factory, usually in the init function of its package. The metadata of the
+// integration is then served with the available notifiers, contact points of its type pass validation and
+// the Alertmanager builds its notifiers with the factory.
+package integrations
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	alertingLogging "github.com/grafana/alerting/logging"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/grafana/alerting/receivers"
+	alertingTemplates "github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/notify"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels_config"
+)
+
+// Notifier sends the notifications of an integration.
+type Notifier interface {
+	notify.Notifier
+	notify.ResolvedSender
+}
+
+// Config is the configuration of an integration of a contact point.
+type Config struct {
+	receivers.Metadata
+	// Settings contains the settings of the integration as they are stored in the contact point.
+	Settings json.RawMessage
+	// Decrypt returns the decrypted value of a secure setting, or the fallback if the setting is not set.
+	Decrypt receivers.DecryptFunc
+	OrgID   int64
+}
+
+// Factory creates the notifiers of a contact point type.
+type Factory interface {
+	// Plugin returns the metadata of the contact point type, including its type name and settings.
+	Plugin() *channels_config.NotifierPlugin
+	// Validate checks the configuration of an integration when its contact point is saved.
+	Validate(cfg Config) error
+	// New creates a notifier for the configuration of an integration.
+	New(cfg Config, tmpl *alertingTemplates.Template, logger alertingLogging.Logger) (Notifier, error)
+}
+
+var (
+	mtx       sync.RWMutex
+	factories = map[string]Factory{}
+)
+
+// Register makes a contact point type available. It panics if the type is already registered or provided by
+// the alerting package.
+func Register(f Factory) {
+	plugin := f.Plugin()
+	if err := channels_config.RegisterCustomNotifier(plugin); err != nil {
+		panic(fmt.Sprintf("integrations: failed to register %s: %s", plugin.Type, err))
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	factories[strings.ToLower(plugin.Type)] = f
+}
+
+// Get returns the factory of a contact point type, if the type is registered.
+func Get(integrationType string) (Factory, bool) {
+	mtx.RLock()
+	defer mtx.RUnlock()
+	f, ok := factories[strings.ToLower(integrationType)]
+	return f, ok
+}
+
+// NewConfig decodes the secure settings of an integration and returns its configuration.
+// Secure settings are expected to be base64 encoded and encrypted. Settings that are not base64 encoded are used as is.
+func NewConfig(ctx context.Context, integration *alertingNotify.GrafanaIntegrationConfig, decrypt alertingNotify.GetDecryptedValueFn, orgID int64) Config {
+	secureSettings := make(map[string][]byte, len(integration.SecureSettings))
+	for k, v := range integration.SecureSettings {
+		d, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			// An error means that the secure settings are not base64 encoded.
+			for k, v := range integration.SecureSettings {
+				secureSettings[k] = []byte(v)
+			}
+			break
+		}
+		secureSettings[k] = d
+	}
+
+	return Config{
+		Metadata: receivers.Metadata{
+			UID:                   integration.UID,
+			Name:                  integration.Name,
+			Type:                  integration.Type,
+			DisableResolveMessage: integration.DisableResolveMessage,
+		},
+		Settings: integration.Settings,
+		Decrypt: func(key string, fallback string) string {
+			return decrypt(ctx, secureSettings, key, fallback)
+		},
+		OrgID: orgID,
+	}
+}
@@ -0,0 +1,51 @@
+package templatedhttp
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// requestTimeout is the timeout of a request, including the request of the OAuth2 access token.
+const requestTimeout = 30 * time.Second
+
+// newClient creates the HTTP client of a notifier. The client presents the TLS client certificate, if configured,
+// and authenticates requests with an OAuth2 access token that is requested with the client credentials grant.
+func newClient(cfg Config) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.TLS != nil {
+		tlsCfg, err := cfg.TLS.toTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsCfg
+	}
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   requestTimeout,
+	}
+
+	if cfg.OAuth2 == nil {
+		return client, nil
+	}
+
+	oauth2Cfg := clientcredentials.Config{
+		ClientID:     cfg.OAuth2.ClientID,
+		ClientSecret: cfg.OAuth2.ClientSecret,
+		TokenURL:     cfg.OAuth2.TokenURL,
+		Scopes:       cfg.OAuth2.Scopes,
+	}
+	// The token is requested with the same client, so the token endpoint can require mutual TLS as well.
+	// The context is kept by the token source and only carries the client.
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, client)
+	return &http.Client{
+		Transport: &oauth2.Transport{
+			Source: oauth2.ReuseTokenSource(nil, oauth2Cfg.TokenSource(ctx)),
+			Base:   transport,
+		},
+		Timeout: requestTimeout,
+	}, nil
+}
@@ -0,0 +1,217 @@
+package templatedhttp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/grafana/alerting/receivers"
+	alertingTemplates "github.com/grafana/alerting/templates"
+)
+
+// Config is the configuration of a templated HTTP integration.
+// The URL, the body and the values of the headers are rendered with the notification templates.
+type Config struct {
+	URL         string
+	HTTPMethod  string
+	ContentType string
+	Body        string
+	Headers     map[string]string
+	// HTTP Basic Authentication.
+	User     string
+	Password string
+	// OAuth2 client credentials grant. The access token is sent in the Authorization header.
+	OAuth2 *OAuth2Config
+	// TLS configuration of the connection, including the client certificate for mutual TLS.
+	TLS *TLSConfig
+}
+
+type OAuth2Config struct {
+	ClientID     string
+	ClientSecret string
+	TokenURL     string
+	Scopes       []string
+}
+
+type TLSConfig struct {
+	CACertificate      string
+	ClientCertificate  string
+	ClientKey          string
+	InsecureSkipVerify bool
+}
+
+var allowedMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// NewConfig parses and validates the settings of a templated HTTP integration.
+func NewConfig(jsonData json.RawMessage, decryptFn receivers.DecryptFunc) (Config, error) {
+	settings := Config{}
+	rawSettings := struct {
+		URL                   string            `json:"url,omitempty" yaml:"url,omitempty"`
+		HTTPMethod            string            `json:"httpMethod,omitempty" yaml:"httpMethod,omitempty"`
+		ContentType           string            `json:"contentType,omitempty" yaml:"contentType,omitempty"`
+		Body                  string            `json:"body,omitempty" yaml:"body,omitempty"`
+		Headers               map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+		User                  string            `json:"username,omitempty" yaml:"username,omitempty"`
+		Password              string            `json:"password,omitempty" yaml:"password,omitempty"`
+		OAuth2ClientID        string            `json:"oauth2ClientId,omitempty" yaml:"oauth2ClientId,omitempty"`
+		OAuth2ClientSecret    string            `json:"oauth2ClientSecret,omitempty" yaml:"oauth2ClientSecret,omitempty"`
+		OAuth2TokenURL        string            `json:"oauth2TokenUrl,omitempty" yaml:"oauth2TokenUrl,omitempty"`
+		OAuth2Scopes          string            `json:"oauth2Scopes,omitempty" yaml:"oauth2Scopes,omitempty"`
+		TLSCACertificate      string            `json:"tlsCACertificate,omitempty" yaml:"tlsCACertificate,omitempty"`
+		TLSClientCertificate  string            `json:"tlsClientCertificate,omitempty" yaml:"tlsClientCertificate,omitempty"`
+		TLSClientKey          string            `json:"tlsClientKey,omitempty" yaml:"tlsClientKey,omitempty"`
+		TLSInsecureSkipVerify bool              `json:"tlsInsecureSkipVerify,omitempty" yaml:"tlsInsecureSkipVerify,omitempty"`
+	}{}
+
+	err := json.Unmarshal(jsonData, &rawSettings)
+	if err != nil {
+		return settings, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+
+	if rawSettings.URL == "" {
+		return settings, errors.New("required field 'url' is not specified")
+	}
+	settings.URL = rawSettings.URL
+
+	settings.HTTPMethod = strings.ToUpper(rawSettings.HTTPMethod)
+	if settings.HTTPMethod == "" {
+		settings.HTTPMethod = http.MethodPost
+	}
+	if !slices.Contains(allowedMethods, settings.HTTPMethod) {
+		return settings, fmt.Errorf("invalid HTTP method '%s', allowed methods are %s", rawSettings.HTTPMethod, strings.Join(allowedMethods, ", "))
+	}
+
+	settings.ContentType = rawSettings.ContentType
+	if settings.ContentType == "" {
+		settings.ContentType = "application/json"
+	}
+	settings.Body = rawSettings.Body
+	settings.Headers = rawSettings.Headers
+	for name := range settings.Headers {
+		if !isValidHeaderName(name) {
+			return settings, fmt.Errorf("invalid header name '%s'", name)
+		}
+		if strings.EqualFold(name, "Authorization") && rawSettings.OAuth2ClientID != "" {
+			return settings, errors.New("the Authorization header cannot be set when OAuth2 is configured")
+		}
+	}
+
+	settings.User = decryptFn("username", rawSettings.User)
+	settings.Password = decryptFn("password", rawSettings.Password)
+
+	if rawSettings.OAuth2ClientID != "" {
+		if settings.User != "" {
+			return settings, errors.New("both HTTP Basic Authentication and OAuth2 are set, only 1 is permitted")
+		}
+		if rawSettings.OAuth2TokenURL == "" {
+			return settings, errors.New("required field 'oauth2TokenUrl' is not specified")
+		}
+		if err := validateStaticURL(rawSettings.OAuth2TokenURL); err != nil {
+			return settings, fmt.Errorf("invalid OAuth2 token URL: %w", err)
+		}
+		settings.OAuth2 = &OAuth2Config{
+			ClientID:     rawSettings.OAuth2ClientID,
+			ClientSecret: decryptFn("oauth2ClientSecret", rawSettings.OAuth2ClientSecret),
+			TokenURL:     rawSettings.OAuth2TokenURL,
+			Scopes:       splitScopes(rawSettings.OAuth2Scopes),
+		}
+	}
+
+	tlsCfg := &TLSConfig{
+		CACertificate:      rawSettings.TLSCACertificate,
+		ClientCertificate:  rawSettings.TLSClientCertificate,
+		ClientKey:          decryptFn("tlsClientKey", rawSettings.TLSClientKey),
+		InsecureSkipVerify: rawSettings.TLSInsecureSkipVerify,
+	}
+	if *tlsCfg != (TLSConfig{}) {
+		if _, err := tlsCfg.toTLSConfig(); err != nil {
+			return settings, err
+		}
+		settings.TLS = tlsCfg
+	}
+
+	return settings, nil
+}
+
+// ValidateTemplates checks that the URL, the body and the values of the headers are valid templates.
+// Templates that are referenced by name, such as {{ template "default.message" . }}, are resolved when a notification
+// is sent, because they can be defined in the notification templates of the organization.
+func (c Config) ValidateTemplates() error {
+	var errs []error
+	check := func(field, text string) {
+		if _, err := template.New(field).Funcs(template.FuncMap(alertingTemplates.DefaultFuncs)).Parse(text); err != nil {
+			errs = append(errs, fmt.Errorf("invalid template in %s: %w", field, err))
+		}
+	}
+	check("url", c.URL)
+	check("body", c.Body)
+	for name, value := range c.Headers {
+		check(fmt.Sprintf("header '%s'", name), value)
+	}
+	return errors.Join(errs...)
+}
+
+// toTLSConfig returns the configuration of the TLS client.
+func (c *TLSConfig) toTLSConfig() (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// #nosec G402 -- Skipping verification is an explicit choice of the user.
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+	if c.CACertificate != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(c.CACertificate)) {
+			return nil, errors.New("failed to parse CA certificate")
+		}
+		cfg.RootCAs = pool
+	}
+	if (c.ClientCertificate == "") != (c.ClientKey == "") {
+		return nil, errors.New("both TLS client certificate and key are required for mutual TLS")
+	}
+	if c.ClientCertificate != "" {
+		cert, err := tls.X509KeyPair([]byte(c.ClientCertificate), []byte(c.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse TLS client certificate and key: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+func validateStaticURL(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme '%s'", u.Scheme)
+	}
+	if u.Host == "" {
+		return errors.New("host is required")
+	}
+	return nil
+}
+
+// isValidHeaderName reports whether the name is a valid token as defined by RFC 7230.
+func isValidHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r >= 0x7f || r <= ' ' || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, r) {
+			return false
+		}
+	}
+	return true
+}
+
+func splitScopes(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' })
+}
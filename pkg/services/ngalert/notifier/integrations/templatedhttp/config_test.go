@@ -0,0 +1,123 @@
+package templatedhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConfig(t *testing.T) {
+	cases := []struct {
+		name              string
+		settings          string
+		secureSettings    map[string]string
+		expectedConfig    Config
+		expectedInitError string
+	}{
+		{
+			name:              "Error if URL is missing",
+			settings:          `{}`,
+			expectedInitError: "required field 'url' is not specified",
+		},
+		{
+			name:     "Minimal valid configuration",
+			settings: `{"url": "http://localhost/{{ .CommonLabels.team }}"}`,
+			expectedConfig: Config{
+				URL:         "http://localhost/{{ .CommonLabels.team }}",
+				HTTPMethod:  http.MethodPost,
+				ContentType: "application/json",
+			},
+		},
+		{
+			name:              "Error if HTTP method is not allowed",
+			settings:          `{"url": "http://localhost", "httpMethod": "TRACE"}`,
+			expectedInitError: "invalid HTTP method 'TRACE'",
+		},
+		{
+			name:              "Error if header name is invalid",
+			settings:          `{"url": "http://localhost", "headers": {"X Team": "ops"}}`,
+			expectedInitError: "invalid header name 'X Team'",
+		},
+		{
+			name:              "Error if both basic auth and OAuth2 are set",
+			settings:          `{"url": "http://localhost", "username": "user", "oauth2ClientId": "id", "oauth2TokenUrl": "http://localhost/token"}`,
+			expectedInitError: "both HTTP Basic Authentication and OAuth2 are set",
+		},
+		{
+			name:              "Error if OAuth2 token URL is missing",
+			settings:          `{"url": "http://localhost", "oauth2ClientId": "id"}`,
+			expectedInitError: "required field 'oauth2TokenUrl' is not specified",
+		},
+		{
+			name:              "Error if Authorization header is set with OAuth2",
+			settings:          `{"url": "http://localhost", "headers": {"authorization": "x"}, "oauth2ClientId": "id", "oauth2TokenUrl": "http://localhost/token"}`,
+			expectedInitError: "the Authorization header cannot be set when OAuth2 is configured",
+		},
+		{
+			name:           "OAuth2 with secret from secure settings",
+			settings:       `{"url": "http://localhost", "httpMethod": "patch", "oauth2ClientId": "id", "oauth2TokenUrl": "https://idp/token", "oauth2Scopes": "a, b c"}`,
+			secureSettings: map[string]string{"oauth2ClientSecret": "secret"},
+			expectedConfig: Config{
+				URL:         "http://localhost",
+				HTTPMethod:  http.MethodPatch,
+				ContentType: "application/json",
+				OAuth2: &OAuth2Config{
+					ClientID:     "id",
+					ClientSecret: "secret",
+					TokenURL:     "https://idp/token",
+					Scopes:       []string{"a", "b", "c"},
+				},
+			},
+		},
+		{
+			name:              "Error if TLS client key is missing",
+			settings:          `{"url": "http://localhost", "tlsClientCertificate": "cert"}`,
+			expectedInitError: "both TLS client certificate and key are required for mutual TLS",
+		},
+		{
+			name:              "Error if CA certificate is invalid",
+			settings:          `{"url": "http://localhost", "tlsCACertificate": "invalid"}`,
+			expectedInitError: "failed to parse CA certificate",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			decrypt := func(key, fallback string) string {
+				if v, ok := c.secureSettings[key]; ok {
+					return v
+				}
+				return fallback
+			}
+			actual, err := NewConfig(json.RawMessage(c.settings), decrypt)
+			if c.expectedInitError != "" {
+				require.ErrorContains(t, err, c.expectedInitError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expectedConfig, actual)
+		})
+	}
+}
+
+func TestValidateTemplates(t *testing.T) {
+	cfg, err := NewConfig(json.RawMessage(`{
+		"url": "http://localhost/{{ .CommonLabels.team }}",
+		"body": "{{ template \"custom.body\" . }}",
+		"headers": {"X-Alerts": "{{ len .Alerts | toUpper }}"}
+	}`), noopDecrypt)
+	require.NoError(t, err)
+	require.NoError(t, cfg.ValidateTemplates())
+
+	cfg.Body = "{{ .CommonLabels.team "
+	cfg.Headers["X-Broken"] = "{{ unknownFunc . }}"
+	err = cfg.ValidateTemplates()
+	require.ErrorContains(t, err, "invalid template in body")
+	require.ErrorContains(t, err, "invalid template in header 'X-Broken'")
+}
+
+func noopDecrypt(_ string, fallback string) string {
+	return fallback
+}
@@ -0,0 +1,126 @@
+package templatedhttp
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels_config"
+)
+
+func plugin() *channels_config.NotifierPlugin {
+	methods := make([]channels_config.SelectOption, 0, len(allowedMethods))
+	for _, m := range allowedMethods {
+		methods = append(methods, channels_config.SelectOption{Value: m, Label: m})
+	}
+
+	return &channels_config.NotifierPlugin{
+		Type:        Type,
+		Name:        "Templated HTTP",
+		Description: "Sends HTTP requests with a templated URL, body and headers",
+		Heading:     "Templated HTTP settings",
+		Info:        "The URL, the body and the header values can use notification templates, for example {{ .CommonLabels.alertname }}.",
+		Options: []channels_config.NotifierOption{
+			{
+				Label:        "URL",
+				Description:  "Templated URL of the request.",
+				Element:      channels_config.ElementTypeInput,
+				InputType:    channels_config.InputTypeText,
+				PropertyName: "url",
+				Required:     true,
+			},
+			{
+				Label:         "HTTP Method",
+				Element:       channels_config.ElementTypeSelect,
+				SelectOptions: methods,
+				PropertyName:  "httpMethod",
+				Placeholder:   http.MethodPost,
+			},
+			{
+				Label:        "Content type",
+				Description:  "Content type of the body. Default is application/json.",
+				Element:      channels_config.ElementTypeInput,
+				InputType:    channels_config.InputTypeText,
+				PropertyName: "contentType",
+				Placeholder:  "application/json",
+			},
+			{
+				Label:        "Body",
+				Description:  "Templated body of the request. Requests without a body are sent when empty.",
+				Element:      channels_config.ElementTypeTextArea,
+				PropertyName: "body",
+				Placeholder:  `{"summary": "{{ template "default.title" . }}"}`,
+			},
+			{
+				Label:        "Headers",
+				Description:  "Headers of the request. The values can use templates.",
+				Element:      channels_config.ElementTypeKeyValueMap,
+				InputType:    channels_config.InputTypeText,
+				PropertyName: "headers",
+			},
+			{
+				Label:        "HTTP Basic Authentication - Username",
+				Element:      channels_config.ElementTypeInput,
+				InputType:    channels_config.InputTypeText,
+				PropertyName: "username",
+			},
+			{
+				Label:        "HTTP Basic Authentication - Password",
+				Element:      channels_config.ElementTypeInput,
+				InputType:    channels_config.InputTypePassword,
+				PropertyName: "password",
+				Secure:       true,
+			},
+			{
+				Label:        "OAuth2 - Client ID",
+				Description:  "Client ID of the client credentials grant. Only one of HTTP Basic Authentication or OAuth2 can be set.",
+				Element:      channels_config.ElementTypeInput,
+				InputType:    channels_config.InputTypeText,
+				PropertyName: "oauth2ClientId",
+			},
+			{
+				Label:        "OAuth2 - Client secret",
+				Element:      channels_config.ElementTypeInput,
+				InputType:    channels_config.InputTypePassword,
+				PropertyName: "oauth2ClientSecret",
+				Secure:       true,
+			},
+			{
+				Label:        "OAuth2 - Token URL",
+				Element:      channels_config.ElementTypeInput,
+				InputType:    channels_config.InputTypeText,
+				PropertyName: "oauth2TokenUrl",
+			},
+			{
+				Label:        "OAuth2 - Scopes",
+				Description:  "Comma-separated list of scopes to request.",
+				Element:      channels_config.ElementTypeInput,
+				InputType:    channels_config.InputTypeText,
+				PropertyName: "oauth2Scopes",
+			},
+			{
+				Label:        "TLS - CA certificate",
+				Description:  "PEM encoded certificate of the CA that signed the certificate of the server.",
+				Element:      channels_config.ElementTypeTextArea,
+				PropertyName: "tlsCACertificate",
+			},
+			{
+				Label:        "TLS - Client certificate",
+				Description:  "PEM encoded client certificate for mutual TLS.",
+				Element:      channels_config.ElementTypeTextArea,
+				PropertyName: "tlsClientCertificate",
+			},
+			{
+				Label:        "TLS - Client key",
+				Description:  "PEM encoded private key of the client certificate.",
+				Element:      channels_config.ElementTypeTextArea,
+				PropertyName: "tlsClientKey",
+				Secure:       true,
+			},
+			{
+				Label:        "TLS - Skip verification",
+				Description:  "Do not verify the certificate of the server.",
+				Element:      channels_config.ElementTypeCheckbox,
+				PropertyName: "tlsInsecureSkipVerify",
+			},
+		},
+	}
+}
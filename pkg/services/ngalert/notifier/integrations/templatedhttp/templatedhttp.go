@@ -0,0 +1,135 @@
+// Package templatedhttp provides the templated HTTP contact point. It sends a request with a body, headers and URL
+// that are rendered with the notification templates, so notifications can be sent to systems that expect a
+// specific payload.
+package templatedhttp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	alertingLogging "github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	alertingTemplates "github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels_config"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/integrations"
+)
+
+// Type is the type of the templated HTTP contact point.
+const Type = "templatedhttp"
+
+// maxErrorBodySize is the maximum number of bytes of a response body that is included in an error.
+const maxErrorBodySize = 1024
+
+func init() {
+	integrations.Register(Factory{})
+}
+
+// Factory creates templated HTTP notifiers.
+type Factory struct{}
+
+func (Factory) Plugin() *channels_config.NotifierPlugin {
+	return plugin()
+}
+
+func (Factory) Validate(cfg integrations.Config) error {
+	settings, err := NewConfig(cfg.Settings, cfg.Decrypt)
+	if err != nil {
+		return err
+	}
+	return settings.ValidateTemplates()
+}
+
+func (Factory) New(cfg integrations.Config, tmpl *alertingTemplates.Template, logger alertingLogging.Logger) (integrations.Notifier, error) {
+	settings, err := NewConfig(cfg.Settings, cfg.Decrypt)
+	if err != nil {
+		return nil, err
+	}
+	client, err := newClient(settings)
+	if err != nil {
+		return nil, err
+	}
+	return New(settings, cfg.Metadata, tmpl, client, logger), nil
+}
+
+// Notifier sends notifications as HTTP requests rendered with the notification templates.
+type Notifier struct {
+	*receivers.Base
+	log      alertingLogging.Logger
+	client   *http.Client
+	tmpl     *alertingTemplates.Template
+	settings Config
+}
+
+func New(cfg Config, meta receivers.Metadata, template *alertingTemplates.Template, client *http.Client, logger alertingLogging.Logger) *Notifier {
+	return &Notifier{
+		Base:     receivers.NewBase(meta),
+		log:      logger,
+		client:   client,
+		tmpl:     template,
+		settings: cfg,
+	}
+}
+
+// Notify renders the request and sends it. Requests that fail with a server error or are rate limited are retried.
+func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	var tmplErr error
+	tmpl, _ := alertingTemplates.TmplText(ctx, n.tmpl, as, n.log, &tmplErr)
+
+	rawURL := tmpl(n.settings.URL)
+	body := tmpl(n.settings.Body)
+	headers := make(map[string]string, len(n.settings.Headers))
+	for name, value := range n.settings.Headers {
+		headers[name] = tmpl(value)
+	}
+	if tmplErr != nil {
+		return false, fmt.Errorf("failed to render templates: %w", tmplErr)
+	}
+	if err := validateStaticURL(rawURL); err != nil {
+		return false, fmt.Errorf("invalid URL '%s': %w", rawURL, err)
+	}
+	u, _ := url.Parse(rawURL)
+
+	req, err := http.NewRequestWithContext(ctx, n.settings.HTTPMethod, u.String(), bytes.NewBufferString(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("User-Agent", "Grafana")
+	if body != "" {
+		req.Header.Set("Content-Type", n.settings.ContentType)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	if n.settings.User != "" {
+		req.SetBasicAuth(n.settings.User, n.settings.Password)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			n.log.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return true, nil
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	err = fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, respBody)
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, err
+}
+
+func (n *Notifier) SendResolved() bool {
+	return !n.GetDisableResolveMessage()
+}
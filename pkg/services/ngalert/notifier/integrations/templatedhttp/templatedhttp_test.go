@@ -0,0 +1,260 @@
+package templatedhttp
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	alertingTemplates "github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/integrations"
+)
+
+func TestNotify(t *testing.T) {
+	tmpl := newTestTemplate(t)
+
+	var (
+		lastRequest *http.Request
+		lastBody    string
+		status      = http.StatusOK
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		lastRequest, lastBody = r, string(b)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+
+	alerts := []*types.Alert{
+		{
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "alert1", "team": "ops"},
+				Annotations: model.LabelSet{"summary": "disk is full"},
+			},
+		},
+	}
+	ctx := notify.WithGroupKey(context.Background(), "alertname")
+	ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": "alert1"})
+
+	newNotifier := func(t *testing.T, settings string) *Notifier {
+		t.Helper()
+		cfg, err := NewConfig(json.RawMessage(settings), noopDecrypt)
+		require.NoError(t, err)
+		client, err := newClient(cfg)
+		require.NoError(t, err)
+		return New(cfg, receivers.Metadata{Type: Type}, tmpl, client, &logging.FakeLogger{})
+	}
+
+	t.Run("renders the URL, body and headers", func(t *testing.T) {
+		n := newNotifier(t, `{
+			"url": "`+srv.URL+`/teams/{{ .CommonLabels.team }}",
+			"httpMethod": "put",
+			"body": "{\"title\": \"{{ .CommonLabels.alertname }}\", \"count\": {{ len .Alerts.Firing }}}",
+			"headers": {"X-Team": "{{ .CommonLabels.team }}"},
+			"username": "user",
+			"password": "pass"
+		}`)
+
+		retry, err := n.Notify(ctx, alerts...)
+		require.NoError(t, err)
+		require.True(t, retry)
+
+		require.Equal(t, http.MethodPut, lastRequest.Method)
+		require.Equal(t, "/teams/ops", lastRequest.URL.Path)
+		require.Equal(t, `{"title": "alert1", "count": 1}`, lastBody)
+		require.Equal(t, "ops", lastRequest.Header.Get("X-Team"))
+		require.Equal(t, "application/json", lastRequest.Header.Get("Content-Type"))
+		user, pass, ok := lastRequest.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "user", user)
+		require.Equal(t, "pass", pass)
+	})
+
+	t.Run("uses notification templates", func(t *testing.T) {
+		n := newNotifier(t, `{"url": "`+srv.URL+`", "body": "{{ template \"default.title\" . }}", "contentType": "text/plain"}`)
+
+		_, err := n.Notify(ctx, alerts...)
+		require.NoError(t, err)
+		require.Equal(t, "[FIRING:1] alert1 (ops)", lastBody)
+		require.Equal(t, "text/plain", lastRequest.Header.Get("Content-Type"))
+	})
+
+	t.Run("fails when a template cannot be rendered", func(t *testing.T) {
+		n := newNotifier(t, `{"url": "`+srv.URL+`", "body": "{{ template \"missing\" . }}"}`)
+
+		retry, err := n.Notify(ctx, alerts...)
+		require.ErrorContains(t, err, "failed to render templates")
+		require.False(t, retry)
+	})
+
+	t.Run("fails when the rendered URL is invalid", func(t *testing.T) {
+		n := newNotifier(t, `{"url": "{{ .CommonLabels.team }}"}`)
+
+		_, err := n.Notify(ctx, alerts...)
+		require.ErrorContains(t, err, "invalid URL 'ops'")
+	})
+
+	t.Run("retries on server errors only", func(t *testing.T) {
+		n := newNotifier(t, `{"url": "`+srv.URL+`"}`)
+		t.Cleanup(func() { status = http.StatusOK })
+
+		status = http.StatusServiceUnavailable
+		retry, err := n.Notify(ctx, alerts...)
+		require.ErrorContains(t, err, "unexpected status code 503")
+		require.True(t, retry)
+
+		status = http.StatusBadRequest
+		retry, err = n.Notify(ctx, alerts...)
+		require.ErrorContains(t, err, "unexpected status code 400")
+		require.False(t, retry)
+	})
+}
+
+func TestNotify_OAuth2(t *testing.T) {
+	tokenRequests := 0
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		require.Equal(t, "alerts:write", r.PostForm.Get("scope"))
+		id, secret, _ := r.BasicAuth()
+		require.Equal(t, "grafana", id)
+		require.Equal(t, "secret", secret)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token": "token", "token_type": "Bearer", "expires_in": 3600}`))
+	}))
+	t.Cleanup(tokenSrv.Close)
+
+	var authorization string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	t.Cleanup(srv.Close)
+
+	decrypt := func(key, fallback string) string {
+		if key == "oauth2ClientSecret" {
+			return "secret"
+		}
+		return fallback
+	}
+	f := Factory{}
+	n, err := f.New(integrations.Config{
+		Metadata: receivers.Metadata{Type: Type},
+		Settings: json.RawMessage(`{"url": "` + srv.URL + `", "oauth2ClientId": "grafana", "oauth2TokenUrl": "` + tokenSrv.URL + `", "oauth2Scopes": "alerts:write"}`),
+		Decrypt:  decrypt,
+	}, newTestTemplate(t), &logging.FakeLogger{})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = n.Notify(context.Background(), &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "a"}}})
+		require.NoError(t, err)
+		require.Equal(t, "Bearer token", authorization)
+	}
+	require.Equal(t, 1, tokenRequests, "should reuse the token until it expires")
+}
+
+func TestNotify_MutualTLS(t *testing.T) {
+	certPEM, keyPEM, cert := generateCertificate(t)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Len(t, r.TLS.PeerCertificates, 1)
+		require.Equal(t, "grafana", r.TLS.PeerCertificates[0].Subject.CommonName)
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(cert)
+	srv.TLS = &tls.Config{ClientCAs: clientCAs, ClientAuth: tls.RequireAndVerifyClientCert, MinVersion: tls.VersionTLS12}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	settings, err := json.Marshal(map[string]string{
+		"url":                  srv.URL,
+		"tlsCACertificate":     string(caPEM),
+		"tlsClientCertificate": string(certPEM),
+	})
+	require.NoError(t, err)
+	decrypt := func(key, fallback string) string {
+		if key == "tlsClientKey" {
+			return string(keyPEM)
+		}
+		return fallback
+	}
+
+	n, err := Factory{}.New(integrations.Config{
+		Metadata: receivers.Metadata{Type: Type},
+		Settings: settings,
+		Decrypt:  decrypt,
+	}, newTestTemplate(t), &logging.FakeLogger{})
+	require.NoError(t, err)
+
+	_, err = n.Notify(context.Background(), &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "a"}}})
+	require.NoError(t, err)
+
+	t.Run("fails without the client certificate", func(t *testing.T) {
+		settings, err := json.Marshal(map[string]string{"url": srv.URL, "tlsCACertificate": string(caPEM)})
+		require.NoError(t, err)
+		n, err := Factory{}.New(integrations.Config{Metadata: receivers.Metadata{Type: Type}, Settings: settings, Decrypt: noopDecrypt}, newTestTemplate(t), &logging.FakeLogger{})
+		require.NoError(t, err)
+
+		_, err = n.Notify(context.Background(), &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "a"}}})
+		require.Error(t, err)
+	})
+}
+
+func newTestTemplate(t *testing.T) *alertingTemplates.Template {
+	t.Helper()
+
+	tmpl := alertingTemplates.ForTests(t)
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+	return tmpl
+}
+
+// generateCertificate returns a self-signed client certificate and its key, encoded as PEM.
+func generateCertificate(t *testing.T) ([]byte, []byte, *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "grafana"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		cert
+}
@@ -401,6 +401,11 @@ func (moa *MultiOrgAlertmanager) StopAndWait() {
 	}
 }
 
+// IsPrimary returns true if this instance is the first member of the Alertmanager cluster, or if there is no cluster.
+func (moa *MultiOrgAlertmanager) IsPrimary() bool {
+	return moa.peer.Position() == 0
+}
+
 // AlertmanagerFor returns the Alertmanager instance for the organization provided.
 // When the organization does not have an active Alertmanager, it returns a ErrNoAlertmanagerForOrg.
 // When the Alertmanager of the organization is not ready, it returns a ErrAlertmanagerNotReady.
@@ -6,11 +6,13 @@ import (
 	"fmt"
 	"sync"
 
+	alertingNotify "github.com/grafana/alerting/notify"
 	"github.com/prometheus/alertmanager/config"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/integrations"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 )
 
@@ -154,3 +156,21 @@ func (v *cachedNotificationSettingsValidationService) Validator(ctx context.Cont
 	}
 	return result, nil
 }
+
+// ValidateIntegration checks the configuration of an integration of a contact point before it is saved.
+// Integrations of custom contact point types are validated by their factory, which includes their templates.
+func ValidateIntegration(ctx context.Context, integration *alertingNotify.GrafanaIntegrationConfig, decrypt alertingNotify.GetDecryptedValueFn) error {
+	if factory, ok := integrations.Get(integration.Type); ok {
+		if err := factory.Validate(integrations.NewConfig(ctx, integration, decrypt, 0)); err != nil {
+			return alertingNotify.IntegrationValidationError{Integration: integration, Err: err}
+		}
+		return nil
+	}
+
+	_, err := alertingNotify.BuildReceiverConfiguration(ctx, &alertingNotify.APIReceiver{
+		GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
+			Integrations: []*alertingNotify.GrafanaIntegrationConfig{integration},
+		},
+	}, decrypt)
+	return err
+}
@@ -0,0 +1,357 @@
+// Package prom converts rule groups in the Prometheus rule file format to Grafana-managed rule groups.
+package prom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/grafana/pkg/expr"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	queryRefID     = "A"
+	reduceRefID    = "B"
+	conditionRefID = "C"
+
+	// defaultQueryRange is the time range of the queries. Prometheus evaluates rules with instant queries, so it
+	// only bounds the lookback of the query.
+	defaultQueryRange = 10 * time.Minute
+)
+
+// valueVariable matches the Prometheus template variable $value, but not Grafana's $values.
+var valueVariable = regexp.MustCompile(`\$value\b`)
+
+// Config configures the conversion of Prometheus rules.
+type Config struct {
+	// DatasourceUID is the UID of the Prometheus-compatible data source the queries of the rules are executed against.
+	DatasourceUID string
+	// DatasourceType is the type of the data source, for example prometheus.
+	DatasourceType string
+	// RecordingRules tells whether recording rules are converted. If false, recording rules are skipped.
+	RecordingRules bool
+}
+
+// SkippedRule is a rule of a Prometheus rule group that cannot be converted.
+type SkippedRule struct {
+	Name   string
+	Reason string
+}
+
+// Converter converts Prometheus rule groups to Grafana-managed rule groups.
+type Converter struct {
+	cfg Config
+}
+
+func NewConverter(cfg Config) (*Converter, error) {
+	if cfg.DatasourceUID == "" {
+		return nil, errors.New("data source UID is required")
+	}
+	return &Converter{cfg: cfg}, nil
+}
+
+// ParseRuleFile parses and validates a Prometheus rule file.
+func ParseRuleFile(content []byte) (*rulefmt.RuleGroups, error) {
+	groups, errs := rulefmt.Parse(content)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid Prometheus rule file: %w", errors.Join(errs...))
+	}
+	return groups, nil
+}
+
+// ConvertGroup converts the Prometheus rule group to a Grafana-managed rule group. Rules that cannot be converted are
+// returned as skipped, the rest of the group is converted.
+//
+// An alerting rule is converted to three queries: the Prometheus query, a reduce expression that takes the last value
+// of every series, and the condition. If the Prometheus expression compares a query with a number, for example
+// `rate(errors[5m]) > 0.1`, the query is the left-hand side of the comparison and the condition is a threshold, so
+// that the alert instances of the series that do not match are Normal. Otherwise the query is the whole expression
+// and the condition is true for every series it returns, which matches how Prometheus fires alerts.
+func (c *Converter) ConvertGroup(group rulefmt.RuleGroup) (apimodels.PostableRuleGroupConfig, []SkippedRule) {
+	return c.convertGroup(group, map[string]bool{})
+}
+
+// ConvertedGroup is a rule group converted by ConvertGroups, with the rules that could not be converted.
+type ConvertedGroup struct {
+	Group   apimodels.PostableRuleGroupConfig
+	Skipped []SkippedRule
+}
+
+// ConvertGroups converts the rule groups of a rule file like ConvertGroup. Grafana requires the titles of the rules
+// to be unique in a folder, so titles are deduplicated across all groups, not only within each group.
+func (c *Converter) ConvertGroups(groups []rulefmt.RuleGroup) []ConvertedGroup {
+	titles := map[string]bool{}
+	result := make([]ConvertedGroup, 0, len(groups))
+	for _, group := range groups {
+		converted, skipped := c.convertGroup(group, titles)
+		result = append(result, ConvertedGroup{Group: converted, Skipped: skipped})
+	}
+	return result
+}
+
+// convertGroup converts the rule group. titles are the titles that are already taken, and are updated with the titles
+// of the converted rules.
+func (c *Converter) convertGroup(group rulefmt.RuleGroup, titles map[string]bool) (apimodels.PostableRuleGroupConfig, []SkippedRule) {
+	result := apimodels.PostableRuleGroupConfig{
+		Name:     group.Name,
+		Interval: group.Interval,
+		Rules:    make([]apimodels.PostableExtendedRuleNode, 0, len(group.Rules)),
+	}
+	var skipped []SkippedRule
+	for _, node := range group.Rules {
+		rule := rulefmt.Rule{
+			Record:        node.Record.Value,
+			Alert:         node.Alert.Value,
+			Expr:          node.Expr.Value,
+			For:           node.For,
+			KeepFiringFor: node.KeepFiringFor,
+			Labels:        node.Labels,
+			Annotations:   node.Annotations,
+		}
+		name := rule.Alert
+		if rule.Record != "" {
+			name = rule.Record
+		}
+
+		var converted apimodels.PostableExtendedRuleNode
+		var err error
+		if rule.Record != "" {
+			if !c.cfg.RecordingRules {
+				skipped = append(skipped, SkippedRule{Name: name, Reason: "recording rules are not enabled on this instance"})
+				continue
+			}
+			converted, err = c.convertRecordingRule(rule)
+		} else {
+			converted, err = c.convertAlertingRule(rule)
+		}
+		if err != nil {
+			skipped = append(skipped, SkippedRule{Name: name, Reason: err.Error()})
+			continue
+		}
+
+		// Prometheus allows several rules with the same name, Grafana requires unique titles.
+		title := name
+		for n := 2; titles[title]; n++ {
+			title = fmt.Sprintf("%s (%d)", name, n)
+		}
+		titles[title] = true
+		converted.GrafanaManagedAlert.Title = title
+		result.Rules = append(result.Rules, converted)
+	}
+	return result, skipped
+}
+
+func (c *Converter) convertAlertingRule(rule rulefmt.Rule) (apimodels.PostableExtendedRuleNode, error) {
+	query, condition, err := c.translateExpression(rule.Expr)
+	if err != nil {
+		return apimodels.PostableExtendedRuleNode{}, err
+	}
+	reduce, err := expressionQuery(reduceRefID, map[string]any{
+		"type":       "reduce",
+		"expression": queryRefID,
+		"reducer":    "last",
+	})
+	if err != nil {
+		return apimodels.PostableExtendedRuleNode{}, err
+	}
+
+	forDuration := rule.For
+	keepFiringFor := rule.KeepFiringFor
+	return apimodels.PostableExtendedRuleNode{
+		ApiRuleNode: &apimodels.ApiRuleNode{
+			For:           &forDuration,
+			KeepFiringFor: &keepFiringFor,
+			Labels:        translateTemplates(rule.Labels),
+			Annotations:   translateTemplates(rule.Annotations),
+		},
+		GrafanaManagedAlert: &apimodels.PostableGrafanaRule{
+			Title:     rule.Alert,
+			Condition: conditionRefID,
+			Data:      []apimodels.AlertQuery{query, reduce, condition},
+			// Prometheus does not fire alerts if the query returns no series, and keeps the alerts if the query fails.
+			NoDataState:  apimodels.OK,
+			ExecErrState: apimodels.ExecutionErrorState(ngmodels.KeepLastErrState),
+		},
+	}, nil
+}
+
+func (c *Converter) convertRecordingRule(rule rulefmt.Rule) (apimodels.PostableExtendedRuleNode, error) {
+	if _, err := parser.ParseExpr(rule.Expr); err != nil {
+		return apimodels.PostableExtendedRuleNode{}, fmt.Errorf("invalid query: %w", err)
+	}
+	query, err := c.datasourceQuery(rule.Expr)
+	if err != nil {
+		return apimodels.PostableExtendedRuleNode{}, err
+	}
+	return apimodels.PostableExtendedRuleNode{
+		ApiRuleNode: &apimodels.ApiRuleNode{
+			Labels: rule.Labels,
+		},
+		GrafanaManagedAlert: &apimodels.PostableGrafanaRule{
+			Title: rule.Record,
+			Data:  []apimodels.AlertQuery{query},
+			Record: &apimodels.Record{
+				Metric: rule.Record,
+				From:   queryRefID,
+			},
+		},
+	}, nil
+}
+
+// translateExpression returns the Prometheus query and the condition of the alerting rule.
+func (c *Converter) translateExpression(promQL string) (apimodels.AlertQuery, apimodels.AlertQuery, error) {
+	parsed, err := parser.ParseExpr(promQL)
+	if err != nil {
+		return apimodels.AlertQuery{}, apimodels.AlertQuery{}, fmt.Errorf("invalid query: %w", err)
+	}
+
+	queryExpr := promQL
+	condition := map[string]any{
+		"type":       "math",
+		"expression": fmt.Sprintf("is_number($%[1]s) || is_nan($%[1]s) || is_inf($%[1]s)", reduceRefID),
+	}
+	if series, op, threshold, ok := splitComparison(parsed); ok {
+		queryExpr = series
+		switch op {
+		case parser.GTR, parser.LSS:
+			evaluator := string(expr.ThresholdIsAbove)
+			if op == parser.LSS {
+				evaluator = string(expr.ThresholdIsBelow)
+			}
+			condition = map[string]any{
+				"type":       "threshold",
+				"expression": reduceRefID,
+				"conditions": []any{map[string]any{
+					"evaluator": map[string]any{"type": evaluator, "params": []float64{threshold}},
+				}},
+			}
+		default:
+			condition = map[string]any{
+				"type":       "math",
+				"expression": fmt.Sprintf("$%s %s %s", reduceRefID, op, strconv.FormatFloat(threshold, 'g', -1, 64)),
+			}
+		}
+	}
+
+	query, err := c.datasourceQuery(queryExpr)
+	if err != nil {
+		return apimodels.AlertQuery{}, apimodels.AlertQuery{}, err
+	}
+	cond, err := expressionQuery(conditionRefID, condition)
+	if err != nil {
+		return apimodels.AlertQuery{}, apimodels.AlertQuery{}, err
+	}
+	return query, cond, nil
+}
+
+// splitComparison returns the query and the threshold of an expression that filters the series of a query by
+// comparing them with a number, such as `up == 0` or `0.9 < ratio`. The comparison operator is returned as if the
+// query was on the left-hand side.
+func splitComparison(e parser.Expr) (string, parser.ItemType, float64, bool) {
+	for {
+		paren, ok := e.(*parser.ParenExpr)
+		if !ok {
+			break
+		}
+		e = paren.Expr
+	}
+	bin, ok := e.(*parser.BinaryExpr)
+	if !ok || !bin.Op.IsComparisonOperator() || bin.ReturnBool {
+		return "", 0, 0, false
+	}
+	if n, ok := numberLiteral(bin.RHS); ok && bin.LHS.Type() == parser.ValueTypeVector {
+		return bin.LHS.String(), bin.Op, n, true
+	}
+	if n, ok := numberLiteral(bin.LHS); ok && bin.RHS.Type() == parser.ValueTypeVector {
+		return bin.RHS.String(), invertComparison(bin.Op), n, true
+	}
+	return "", 0, 0, false
+}
+
+func numberLiteral(e parser.Expr) (float64, bool) {
+	switch v := e.(type) {
+	case *parser.NumberLiteral:
+		return v.Val, true
+	case *parser.ParenExpr:
+		return numberLiteral(v.Expr)
+	case *parser.UnaryExpr:
+		if n, ok := numberLiteral(v.Expr); ok && v.Op == parser.SUB {
+			return -n, true
+		}
+	}
+	return 0, false
+}
+
+func invertComparison(op parser.ItemType) parser.ItemType {
+	switch op {
+	case parser.GTR:
+		return parser.LSS
+	case parser.LSS:
+		return parser.GTR
+	case parser.GTE:
+		return parser.LTE
+	case parser.LTE:
+		return parser.GTE
+	}
+	return op
+}
+
+// translateTemplates replaces the Prometheus template variable $value, which is the value of the series that fired
+// the alert, with the value of the reduce expression. $labels has the same meaning in Grafana.
+func translateTemplates(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		result[k] = valueVariable.ReplaceAllLiteralString(v, fmt.Sprintf("$values.%s.Value", reduceRefID))
+	}
+	return result
+}
+
+func (c *Converter) datasourceQuery(promQL string) (apimodels.AlertQuery, error) {
+	model, err := json.Marshal(map[string]any{
+		"refId":   queryRefID,
+		"expr":    promQL,
+		"instant": true,
+		"range":   false,
+		"datasource": map[string]string{
+			"type": c.cfg.DatasourceType,
+			"uid":  c.cfg.DatasourceUID,
+		},
+	})
+	if err != nil {
+		return apimodels.AlertQuery{}, err
+	}
+	return apimodels.AlertQuery{
+		RefID:             queryRefID,
+		DatasourceUID:     c.cfg.DatasourceUID,
+		RelativeTimeRange: apimodels.RelativeTimeRange{From: apimodels.Duration(defaultQueryRange)},
+		Model:             model,
+	}, nil
+}
+
+func expressionQuery(refID string, model map[string]any) (apimodels.AlertQuery, error) {
+	model["refId"] = refID
+	model["datasource"] = map[string]string{
+		"type": expr.DatasourceType,
+		"uid":  expr.DatasourceUID,
+	}
+	raw, err := json.Marshal(model)
+	if err != nil {
+		return apimodels.AlertQuery{}, err
+	}
+	return apimodels.AlertQuery{
+		RefID:         refID,
+		DatasourceUID: expr.DatasourceUID,
+		Model:         raw,
+	}, nil
+}
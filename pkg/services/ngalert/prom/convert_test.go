@@ -0,0 +1,199 @@
+package prom
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	prommodel "github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+const ruleFile = `
+groups:
+  - name: api
+    interval: 1m
+    rules:
+      - alert: HighErrorRate
+        expr: sum by (job) (rate(http_errors_total[5m])) > 0.5
+        for: 5m
+        keep_firing_for: 1m
+        labels:
+          severity: critical
+        annotations:
+          summary: "Error rate of {{ $labels.job }} is {{ $value | humanize }}"
+      - alert: InstanceDown
+        expr: 1 > up
+      - alert: InstanceDown
+        expr: up == 0
+      - alert: Absent
+        expr: absent(up{job="api"})
+      - record: job:http_errors:rate5m
+        expr: sum by (job) (rate(http_errors_total[5m]))
+`
+
+func TestConvertGroup(t *testing.T) {
+	groups, err := ParseRuleFile([]byte(ruleFile))
+	require.NoError(t, err)
+	require.Len(t, groups.Groups, 1)
+
+	c, err := NewConverter(Config{DatasourceUID: "prom", DatasourceType: "prometheus"})
+	require.NoError(t, err)
+	group, skipped := c.ConvertGroup(groups.Groups[0])
+
+	require.Equal(t, "api", group.Name)
+	require.Equal(t, prommodel.Duration(time.Minute), group.Interval)
+	require.Equal(t, []SkippedRule{{Name: "job:http_errors:rate5m", Reason: "recording rules are not enabled on this instance"}}, skipped)
+	require.Len(t, group.Rules, 4)
+
+	t.Run("should split comparison into query and threshold", func(t *testing.T) {
+		rule := group.Rules[0]
+		require.Equal(t, "HighErrorRate", rule.GrafanaManagedAlert.Title)
+		require.Equal(t, "C", rule.GrafanaManagedAlert.Condition)
+		require.Equal(t, prommodel.Duration(5*time.Minute), *rule.ApiRuleNode.For)
+		require.Equal(t, prommodel.Duration(time.Minute), *rule.ApiRuleNode.KeepFiringFor)
+		require.Equal(t, apimodels.OK, rule.GrafanaManagedAlert.NoDataState)
+		require.Equal(t, apimodels.ExecutionErrorState("KeepLast"), rule.GrafanaManagedAlert.ExecErrState)
+		require.Equal(t, map[string]string{"severity": "critical"}, rule.ApiRuleNode.Labels)
+		require.Equal(t, "Error rate of {{ $labels.job }} is {{ $values.B.Value | humanize }}", rule.ApiRuleNode.Annotations["summary"])
+
+		data := rule.GrafanaManagedAlert.Data
+		require.Len(t, data, 3)
+		require.Equal(t, "prom", data[0].DatasourceUID)
+		require.Equal(t, apimodels.Duration(10*time.Minute), data[0].RelativeTimeRange.From)
+		require.Equal(t, `sum by (job) (rate(http_errors_total[5m]))`, model(t, data[0])["expr"])
+		require.Equal(t, true, model(t, data[0])["instant"])
+		require.Equal(t, "reduce", model(t, data[1])["type"])
+		require.Equal(t, "A", model(t, data[1])["expression"])
+		cond := model(t, data[2])
+		require.Equal(t, "threshold", cond["type"])
+		require.Equal(t, "B", cond["expression"])
+		require.JSONEq(t, `[{"evaluator":{"type":"gt","params":[0.5]}}]`, toJSON(t, cond["conditions"]))
+	})
+
+	t.Run("should invert comparison with number on the left", func(t *testing.T) {
+		rule := group.Rules[1]
+		require.Equal(t, "InstanceDown", rule.GrafanaManagedAlert.Title)
+		require.Equal(t, "up", model(t, rule.GrafanaManagedAlert.Data[0])["expr"])
+		require.JSONEq(t, `[{"evaluator":{"type":"lt","params":[1]}}]`, toJSON(t, model(t, rule.GrafanaManagedAlert.Data[2])["conditions"]))
+	})
+
+	t.Run("should use math for comparisons not supported by threshold and deduplicate titles", func(t *testing.T) {
+		rule := group.Rules[2]
+		require.Equal(t, "InstanceDown (2)", rule.GrafanaManagedAlert.Title)
+		require.Equal(t, "up", model(t, rule.GrafanaManagedAlert.Data[0])["expr"])
+		cond := model(t, rule.GrafanaManagedAlert.Data[2])
+		require.Equal(t, "math", cond["type"])
+		require.Equal(t, "$B == 0", cond["expression"])
+	})
+
+	t.Run("should fire for every series of other expressions", func(t *testing.T) {
+		rule := group.Rules[3]
+		require.Equal(t, `absent(up{job="api"})`, model(t, rule.GrafanaManagedAlert.Data[0])["expr"])
+		cond := model(t, rule.GrafanaManagedAlert.Data[2])
+		require.Equal(t, "math", cond["type"])
+		require.Equal(t, "is_number($B) || is_nan($B) || is_inf($B)", cond["expression"])
+	})
+
+	t.Run("should convert recording rules if enabled", func(t *testing.T) {
+		c, err := NewConverter(Config{DatasourceUID: "prom", RecordingRules: true})
+		require.NoError(t, err)
+		group, skipped := c.ConvertGroup(groups.Groups[0])
+		require.Empty(t, skipped)
+		require.Len(t, group.Rules, 5)
+		rule := group.Rules[4]
+		require.Equal(t, &apimodels.Record{Metric: "job:http_errors:rate5m", From: "A"}, rule.GrafanaManagedAlert.Record)
+		require.Len(t, rule.GrafanaManagedAlert.Data, 1)
+	})
+}
+
+func TestSplitComparison(t *testing.T) {
+	c, err := NewConverter(Config{DatasourceUID: "prom"})
+	require.NoError(t, err)
+	testCases := []struct {
+		expr      string
+		query     string
+		condition string
+	}{
+		{expr: `(up < 1)`, query: `up`, condition: `threshold`},
+		{expr: `up >= -1`, query: `up`, condition: `$B >= -1`},
+		{expr: `2 >= up`, query: `up`, condition: `$B <= 2`},
+		{expr: `up > bool 0`, query: `up > bool 0`, condition: `is_number($B) || is_nan($B) || is_inf($B)`},
+		{expr: `up > on(job) down`, query: `up > on(job) down`, condition: `is_number($B) || is_nan($B) || is_inf($B)`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+			query, cond, err := c.translateExpression(tc.expr)
+			require.NoError(t, err)
+			require.Equal(t, tc.query, model(t, query)["expr"])
+			m := model(t, cond)
+			if m["type"] == "threshold" {
+				require.Equal(t, tc.condition, m["type"])
+			} else {
+				require.Equal(t, tc.condition, m["expression"])
+			}
+		})
+	}
+
+	t.Run("should skip rules with invalid queries", func(t *testing.T) {
+		_, _, err := c.translateExpression(`rate(up`)
+		require.ErrorContains(t, err, "invalid query")
+	})
+}
+
+func TestConvertGroups(t *testing.T) {
+	groups, err := ParseRuleFile([]byte(`
+groups:
+  - name: api
+    rules:
+      - alert: InstanceDown
+        expr: up == 0
+      - alert: InstanceDown (2)
+        expr: up == 0
+  - name: db
+    rules:
+      - alert: InstanceDown
+        expr: up == 0
+`))
+	require.NoError(t, err)
+	c, err := NewConverter(Config{DatasourceUID: "prom"})
+	require.NoError(t, err)
+
+	converted := c.ConvertGroups(groups.Groups)
+	require.Len(t, converted, 2)
+	titles := func(group apimodels.PostableRuleGroupConfig) []string {
+		var result []string
+		for _, rule := range group.Rules {
+			result = append(result, rule.GrafanaManagedAlert.Title)
+		}
+		return result
+	}
+	require.Equal(t, "api", converted[0].Group.Name)
+	require.Equal(t, []string{"InstanceDown", "InstanceDown (2)"}, titles(converted[0].Group))
+	require.Equal(t, "db", converted[1].Group.Name)
+	require.Equal(t, []string{"InstanceDown (3)"}, titles(converted[1].Group))
+}
+
+func TestParseRuleFile(t *testing.T) {
+	_, err := ParseRuleFile([]byte("groups:\n  - name: a\n    rules:\n      - alert: A\n")) // missing expr
+	require.ErrorContains(t, err, "invalid Prometheus rule file")
+
+	_, err = NewConverter(Config{})
+	require.Error(t, err)
+}
+
+func model(t *testing.T, q apimodels.AlertQuery) map[string]any {
+	t.Helper()
+	m := map[string]any{}
+	require.NoError(t, json.Unmarshal(q.Model, &m))
+	return m
+}
+
+func toJSON(t *testing.T, v any) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(b)
+}
@@ -231,6 +231,9 @@ func (service *AlertRuleService) CreateAlertRule(ctx context.Context, user ident
 			}
 		}
 	}
+	if err := store.ValidateRuleDependencies(ctx, service.ruleStore, &store.GroupDelta{GroupKey: rule.GetGroupKey(), New: []*models.AlertRule{&rule}}); err != nil {
+		return models.AlertRule{}, err
+	}
 	err = service.xact.InTransaction(ctx, func(ctx context.Context) error {
 		ids, err := service.ruleStore.InsertAlertRules(ctx, []models.AlertRule{
 			rule,
@@ -402,6 +405,10 @@ func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, user iden
 		}
 	}
 
+	if err := store.ValidateRuleDependencies(ctx, service.ruleStore, delta); err != nil {
+		return err
+	}
+
 	return service.persistDelta(ctx, user, delta, provenance)
 }
 
@@ -426,6 +433,10 @@ func (service *AlertRuleService) DeleteRuleGroup(ctx context.Context, user ident
 		}
 	}
 
+	if err := store.ValidateRuleDependencies(ctx, service.ruleStore, delta); err != nil {
+		return err
+	}
+
 	return service.persistDelta(ctx, user, delta, provenance)
 }
 
@@ -602,6 +613,9 @@ func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, user ident
 	if err != nil {
 		return models.AlertRule{}, err
 	}
+	if err := store.ValidateRuleDependencies(ctx, service.ruleStore, &store.GroupDelta{GroupKey: rule.GetGroupKey(), Update: []store.RuleDelta{{Existing: storedRule, New: &rule}}}); err != nil {
+		return models.AlertRule{}, err
+	}
 	err = service.xact.InTransaction(ctx, func(ctx context.Context) error {
 		err := service.ruleStore.UpdateAlertRules(ctx, []models.UpdateRule{
 			{
@@ -652,6 +666,12 @@ func (service *AlertRuleService) DeleteAlertRule(ctx context.Context, user ident
 	// This is different from deleting groups. We delete the rules directly rather than persisting a delta here to keep the semantics the same.
 	// TODO: Either persist a delta here as a breaking change, or deprecate this endpoint in favor of the group endpoint.
 	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := store.ValidateRuleDependencies(ctx, service.ruleStore, &store.GroupDelta{
+			GroupKey: models.AlertRuleGroupKey{OrgID: user.GetOrgID()},
+			Delete:   []*models.AlertRule{rule},
+		}); err != nil {
+			return err
+		}
 		return service.deleteRules(ctx, user.GetOrgID(), rule)
 	})
 }
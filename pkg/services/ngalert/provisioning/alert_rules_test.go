@@ -573,6 +573,24 @@ func TestCreateAlertRule(t *testing.T) {
 		return service, ruleStore, provenanceStore, ac
 	}
 
+	t.Run("should reject dependencies on rules that do not exist", func(t *testing.T) {
+		rule := gen.With(gen.WithOrgID(orgID), gen.WithDependsOn("unknown")).Generate()
+		service, ruleStore, _, ac := initServiceWithData(t)
+		ac.CanWriteAllRulesFunc = func(ctx context.Context, user identity.Requester) (bool, error) {
+			return true, nil
+		}
+
+		_, err := service.CreateAlertRule(context.Background(), u, rule, models.ProvenanceFile)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "does not exist")
+
+		inserts := ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			a, ok := cmd.([]models.AlertRule)
+			return a, ok
+		})
+		require.Empty(t, inserts)
+	})
+
 	t.Run("when user can write all rules", func(t *testing.T) {
 		t.Run("and a new rule creates a new group", func(t *testing.T) {
 			rule := gen.With(gen.WithOrgID(orgID)).Generate()
@@ -940,6 +958,19 @@ func TestDeleteAlertRule(t *testing.T) {
 		deletes := getDeleteQueries(ruleStore)
 		require.Len(t, deletes, 1)
 	})
+	t.Run("should not delete rules that other rules depend on", func(t *testing.T) {
+		service, ruleStore, _, ac := initServiceWithData(t)
+		ac.CanWriteAllRulesFunc = func(ctx context.Context, user identity.Requester) (bool, error) {
+			return true, nil
+		}
+		dependent := gen.With(gen.WithOrgID(orgID), gen.WithDependsOn(rules[0].UID)).GenerateRef()
+		ruleStore.PutRule(context.Background(), dependent)
+
+		err := service.DeleteAlertRule(context.Background(), u, rules[0].UID, groupProvenance)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, dependent.Title)
+		require.Empty(t, getDeleteQueries(ruleStore))
+	})
 	t.Run("when user cannot write all rules", func(t *testing.T) {
 		rule := models.CopyRule(rules[0])
 		rule.Title = rule.Title + "_new"
@@ -1380,6 +1411,34 @@ func TestReplaceGroup(t *testing.T) {
 			require.Len(t, updates, 1)
 		})
 	})
+
+	t.Run("should reject rule dependencies that form a cycle", func(t *testing.T) {
+		group := models.AlertRuleGroup{
+			Title:      groupKey.RuleGroup,
+			FolderUID:  groupKey.NamespaceUID,
+			Interval:   groupIntervalSeconds,
+			Provenance: groupProvenance,
+		}
+		for i, rule := range rules {
+			r := models.CopyRule(rule, gen.WithDependsOn(rules[(i+1)%len(rules)].UID))
+			group.Rules = append(group.Rules, *r)
+		}
+
+		service, ruleStore, _, ac := initServiceWithData(t)
+		ac.CanWriteAllRulesFunc = func(ctx context.Context, user identity.Requester) (bool, error) {
+			return true, nil
+		}
+
+		err := service.ReplaceRuleGroup(context.Background(), u, group, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "cycles")
+
+		updates := ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			a, ok := cmd.([]models.UpdateRule)
+			return a, ok
+		})
+		require.Empty(t, updates)
+	})
 }
 
 func TestDeleteRuleGroup(t *testing.T) {
@@ -1418,6 +1477,19 @@ func TestDeleteRuleGroup(t *testing.T) {
 		deletes := getDeleteQueries(ruleStore)
 		require.Len(t, deletes, 1)
 	})
+	t.Run("should not delete the group if rules of other groups depend on it", func(t *testing.T) {
+		service, ruleStore, _, ac := initServiceWithData(t)
+		ac.CanWriteAllRulesFunc = func(ctx context.Context, user identity.Requester) (bool, error) {
+			return true, nil
+		}
+		dependent := gen.With(gen.WithOrgID(orgID), gen.WithDependsOn(rules[1].UID)).GenerateRef()
+		ruleStore.PutRule(context.Background(), dependent)
+
+		err := service.DeleteRuleGroup(context.Background(), u, groupKey.NamespaceUID, groupKey.RuleGroup, groupProvenance)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, dependent.Title)
+		require.Empty(t, getDeleteQueries(ruleStore))
+	})
 	t.Run("when user cannot write all rules", func(t *testing.T) {
 		t.Run("it should not update if not authorized", func(t *testing.T) {
 			service, ruleStore, _, ac := initServiceWithData(t)
@@ -133,6 +133,41 @@ func TestContactPointService(t *testing.T) {
 		require.ErrorIs(t, err, ErrValidation)
 	})
 
+	t.Run("create accepts templated HTTP contact points", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		newCp := createTestContactPoint()
+		newCp.Type = "templatedhttp"
+		newCp.Settings = simplejson.NewFromAny(map[string]any{
+			"url":      "https://tickets.local/{{ .CommonLabels.team }}",
+			"body":     `{"summary": "{{ template "default.title" . }}"}`,
+			"password": "secret",
+			"username": "grafana",
+		})
+
+		created, err := sut.CreateContactPoint(context.Background(), 1, newCp, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		cps, err := sut.GetContactPoints(context.Background(), cpsQueryWithName(1, newCp.Name), redactedUser)
+		require.NoError(t, err)
+		require.Len(t, cps, 1)
+		require.Equal(t, created.UID, cps[0].UID)
+		require.Equal(t, "[REDACTED]", cps[0].Settings.Get("password").MustString())
+	})
+
+	t.Run("create rejects templated HTTP contact points with invalid templates", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		newCp := createTestContactPoint()
+		newCp.Type = "templatedhttp"
+		newCp.Settings = simplejson.NewFromAny(map[string]any{
+			"url":  "https://tickets.local",
+			"body": "{{ .CommonLabels.team ",
+		})
+
+		_, err := sut.CreateContactPoint(context.Background(), 1, newCp, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+		require.ErrorContains(t, err, "invalid template in body")
+	})
+
 	t.Run("update rejects contact points with no settings", func(t *testing.T) {
 		sut := createContactPointServiceSut(t, secretsService)
 		newCp := createTestContactPoint()
@@ -18,6 +18,8 @@ var (
 	ErrTimeIntervalInvalid  = errutil.BadRequest("alerting.notifications.time-intervals.invalidFormat").MustTemplate("Invalid format of the submitted time interval", errutil.WithPublic("Time interval is in invalid format. Correct the payload and try again."))
 	ErrTimeIntervalInUse    = errutil.Conflict("alerting.notifications.time-intervals.used").MustTemplate("Time interval is used")
 
+	ErrMaintenanceWindowInvalid = errutil.BadRequest("alerting.maintenance-windows.invalidFormat").MustTemplate("Invalid format of the submitted maintenance window", errutil.WithPublic("Maintenance window is in invalid format: {{ .Public.Error }}"))
+
 	ErrContactPointReferenced = errutil.Conflict("alerting.notifications.contact-points.referenced", errutil.WithPublicMessage("Contact point is currently referenced by a notification policy."))
 	ErrContactPointUsedInRule = errutil.Conflict("alerting.notifications.contact-points.used-by-rule", errutil.WithPublicMessage("Contact point is currently used in the notification settings of one or many alert rules."))
 )
@@ -34,6 +36,16 @@ func MakeErrTimeIntervalInvalid(err error) error {
 	return ErrTimeIntervalInvalid.Build(data)
 }
 
+// MakeErrMaintenanceWindowInvalid creates an error with the ErrMaintenanceWindowInvalid template
+func MakeErrMaintenanceWindowInvalid(err error) error {
+	return ErrMaintenanceWindowInvalid.Build(errutil.TemplateData{
+		Public: map[string]interface{}{
+			"Error": err.Error(),
+		},
+		Error: err,
+	})
+}
+
 func MakeErrTimeIntervalInUse(usedByRoutes bool, rules []models.AlertRuleKey) error {
 	uids := make([]string, 0, len(rules))
 	for _, key := range rules {
@@ -0,0 +1,205 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning/validation"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// MaintenanceWindowStore represents the ability to persist and query maintenance windows.
+type MaintenanceWindowStore interface {
+	ListMaintenanceWindows(ctx context.Context, orgID int64) ([]models.MaintenanceWindow, error)
+	GetMaintenanceWindow(ctx context.Context, orgID int64, uid string) (models.MaintenanceWindow, error)
+	InsertMaintenanceWindow(ctx context.Context, w models.MaintenanceWindow) (models.MaintenanceWindow, error)
+	UpdateMaintenanceWindow(ctx context.Context, w models.MaintenanceWindow) (models.MaintenanceWindow, error)
+	DeleteMaintenanceWindow(ctx context.Context, orgID int64, uid string) error
+}
+
+type MaintenanceWindowService struct {
+	store           MaintenanceWindowStore
+	provenanceStore ProvisioningStore
+	xact            TransactionManager
+	log             log.Logger
+	validator       validation.ProvenanceStatusTransitionValidator
+}
+
+func NewMaintenanceWindowService(store MaintenanceWindowStore, prov ProvisioningStore, xact TransactionManager, log log.Logger) *MaintenanceWindowService {
+	return &MaintenanceWindowService{
+		store:           store,
+		provenanceStore: prov,
+		xact:            xact,
+		log:             log,
+		validator:       validation.ValidateProvenanceRelaxed,
+	}
+}
+
+// GetMaintenanceWindows returns all maintenance windows within the specified org.
+func (svc *MaintenanceWindowService) GetMaintenanceWindows(ctx context.Context, orgID int64) ([]definitions.MaintenanceWindow, error) {
+	windows, err := svc.store.ListMaintenanceWindows(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	provenances, err := svc.provenanceStore.GetProvenances(ctx, orgID, (&models.MaintenanceWindow{}).ResourceType())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]definitions.MaintenanceWindow, 0, len(windows))
+	for _, w := range windows {
+		result = append(result, MaintenanceWindowFromModel(w, provenances[w.ResourceID()]))
+	}
+	return result, nil
+}
+
+// GetMaintenanceWindow returns the maintenance window with the given UID.
+func (svc *MaintenanceWindowService) GetMaintenanceWindow(ctx context.Context, uid string, orgID int64) (definitions.MaintenanceWindow, error) {
+	w, err := svc.store.GetMaintenanceWindow(ctx, orgID, uid)
+	if err != nil {
+		return definitions.MaintenanceWindow{}, err
+	}
+	prov, err := svc.provenanceStore.GetProvenance(ctx, &w, orgID)
+	if err != nil {
+		return definitions.MaintenanceWindow{}, err
+	}
+	return MaintenanceWindowFromModel(w, prov), nil
+}
+
+// CreateMaintenanceWindow adds a new maintenance window within the specified org. If the UID is empty, a new one is generated.
+func (svc *MaintenanceWindowService) CreateMaintenanceWindow(ctx context.Context, mw definitions.MaintenanceWindow, orgID int64) (definitions.MaintenanceWindow, error) {
+	if mw.UID == "" {
+		mw.UID = util.GenerateShortUID()
+	} else if err := util.ValidateUID(mw.UID); err != nil {
+		return definitions.MaintenanceWindow{}, MakeErrMaintenanceWindowInvalid(fmt.Errorf("cannot create maintenance window with UID '%s': %w", mw.UID, err))
+	}
+	w := MaintenanceWindowToModel(mw, orgID)
+	if err := w.Validate(); err != nil {
+		return definitions.MaintenanceWindow{}, MakeErrMaintenanceWindowInvalid(err)
+	}
+
+	var created models.MaintenanceWindow
+	err := svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = svc.store.InsertMaintenanceWindow(ctx, w)
+		if err != nil {
+			return err
+		}
+		return svc.provenanceStore.SetProvenance(ctx, &created, orgID, models.Provenance(mw.Provenance))
+	})
+	if err != nil {
+		return definitions.MaintenanceWindow{}, err
+	}
+	return MaintenanceWindowFromModel(created, models.Provenance(mw.Provenance)), nil
+}
+
+// UpdateMaintenanceWindow replaces an existing maintenance window within the specified org. If the version of the
+// maintenance window is not specified, the optimistic concurrency check is skipped.
+func (svc *MaintenanceWindowService) UpdateMaintenanceWindow(ctx context.Context, mw definitions.MaintenanceWindow, orgID int64) (definitions.MaintenanceWindow, error) {
+	existing, err := svc.store.GetMaintenanceWindow(ctx, orgID, mw.UID)
+	if err != nil {
+		return definitions.MaintenanceWindow{}, err
+	}
+
+	w := MaintenanceWindowToModel(mw, orgID)
+	if err := w.Validate(); err != nil {
+		return definitions.MaintenanceWindow{}, MakeErrMaintenanceWindowInvalid(err)
+	}
+	if w.Version == 0 {
+		if models.Provenance(mw.Provenance) != models.ProvenanceFile {
+			svc.log.Debug("Ignoring optimistic concurrency check because version was not provided", "maintenanceWindow", mw.UID, "operation", "update")
+		}
+		w.Version = existing.Version
+	}
+
+	// check that provenance is not changed in an invalid way
+	storedProvenance, err := svc.provenanceStore.GetProvenance(ctx, &existing, orgID)
+	if err != nil {
+		return definitions.MaintenanceWindow{}, err
+	}
+	if err := svc.validator(storedProvenance, models.Provenance(mw.Provenance)); err != nil {
+		return definitions.MaintenanceWindow{}, err
+	}
+
+	var updated models.MaintenanceWindow
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		updated, err = svc.store.UpdateMaintenanceWindow(ctx, w)
+		if err != nil {
+			return err
+		}
+		return svc.provenanceStore.SetProvenance(ctx, &updated, orgID, models.Provenance(mw.Provenance))
+	})
+	if err != nil {
+		return definitions.MaintenanceWindow{}, err
+	}
+	return MaintenanceWindowFromModel(updated, models.Provenance(mw.Provenance)), nil
+}
+
+// DeleteMaintenanceWindow deletes the maintenance window with the given UID in the given org. If the maintenance
+// window does not exist, no error is returned. If version is 0, the optimistic concurrency check is skipped.
+func (svc *MaintenanceWindowService) DeleteMaintenanceWindow(ctx context.Context, uid string, orgID int64, provenance definitions.Provenance, version int64) error {
+	existing, err := svc.store.GetMaintenanceWindow(ctx, orgID, uid)
+	if err != nil {
+		if errors.Is(err, models.ErrMaintenanceWindowNotFound) {
+			svc.log.FromContext(ctx).Debug("Maintenance window was not found. Skip deleting", "uid", uid)
+			return nil
+		}
+		return err
+	}
+
+	// check that provenance is not changed in an invalid way
+	storedProvenance, err := svc.provenanceStore.GetProvenance(ctx, &existing, orgID)
+	if err != nil {
+		return err
+	}
+	if err := svc.validator(storedProvenance, models.Provenance(provenance)); err != nil {
+		return err
+	}
+
+	if version != 0 && version != existing.Version {
+		return ErrVersionConflict.Errorf("provided version %d of maintenance window %s does not match current version %d", version, uid, existing.Version)
+	}
+
+	return svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.store.DeleteMaintenanceWindow(ctx, orgID, uid); err != nil {
+			return err
+		}
+		return svc.provenanceStore.DeleteProvenance(ctx, &existing, orgID)
+	})
+}
+
+// MaintenanceWindowToModel converts the API representation of the maintenance window to the model.
+func MaintenanceWindowToModel(mw definitions.MaintenanceWindow, orgID int64) models.MaintenanceWindow {
+	return models.MaintenanceWindow{
+		UID:           mw.UID,
+		OrgID:         orgID,
+		Title:         mw.Title,
+		Mode:          models.MaintenanceWindowMode(mw.Mode),
+		TimeIntervals: mw.TimeIntervals,
+		Matchers:      labels.Matchers(mw.ObjectMatchers),
+		FolderUIDs:    mw.FolderUIDs,
+		Version:       mw.Version,
+	}
+}
+
+// MaintenanceWindowFromModel converts the maintenance window model to its API representation.
+func MaintenanceWindowFromModel(w models.MaintenanceWindow, provenance models.Provenance) definitions.MaintenanceWindow {
+	return definitions.MaintenanceWindow{
+		UID:            w.UID,
+		Title:          w.Title,
+		Mode:           string(w.Mode),
+		TimeIntervals:  w.TimeIntervals,
+		ObjectMatchers: definitions.ObjectMatchers(w.Matchers),
+		FolderUIDs:     w.FolderUIDs,
+		Version:        w.Version,
+		Provenance:     definitions.Provenance(provenance),
+	}
+}
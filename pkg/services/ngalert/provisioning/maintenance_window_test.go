@@ -0,0 +1,157 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning/validation"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+)
+
+type fakeMaintenanceWindowStore struct {
+	windows map[string]models.MaintenanceWindow
+}
+
+func (f *fakeMaintenanceWindowStore) ListMaintenanceWindows(_ context.Context, orgID int64) ([]models.MaintenanceWindow, error) {
+	var result []models.MaintenanceWindow
+	for _, w := range f.windows {
+		if w.OrgID == orgID {
+			result = append(result, w)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeMaintenanceWindowStore) GetMaintenanceWindow(_ context.Context, orgID int64, uid string) (models.MaintenanceWindow, error) {
+	w, ok := f.windows[uid]
+	if !ok || w.OrgID != orgID {
+		return models.MaintenanceWindow{}, models.ErrMaintenanceWindowNotFound.Errorf("")
+	}
+	return w, nil
+}
+
+func (f *fakeMaintenanceWindowStore) InsertMaintenanceWindow(_ context.Context, w models.MaintenanceWindow) (models.MaintenanceWindow, error) {
+	w.Version = 1
+	f.windows[w.UID] = w
+	return w, nil
+}
+
+func (f *fakeMaintenanceWindowStore) UpdateMaintenanceWindow(_ context.Context, w models.MaintenanceWindow) (models.MaintenanceWindow, error) {
+	if f.windows[w.UID].Version != w.Version {
+		return models.MaintenanceWindow{}, models.ErrMaintenanceWindowVersionConflict.Errorf("")
+	}
+	w.Version++
+	f.windows[w.UID] = w
+	return w, nil
+}
+
+func (f *fakeMaintenanceWindowStore) DeleteMaintenanceWindow(_ context.Context, _ int64, uid string) error {
+	delete(f.windows, uid)
+	return nil
+}
+
+func TestMaintenanceWindowService(t *testing.T) {
+	ctx := context.Background()
+	orgID := int64(1)
+	window := definitions.MaintenanceWindow{
+		Title: "Database maintenance",
+		Mode:  string(models.MaintenanceWindowModeMute),
+		TimeIntervals: []timeinterval.TimeInterval{{
+			Times: []timeinterval.TimeRange{{StartMinute: 60, EndMinute: 120}},
+		}},
+		FolderUIDs: []string{"folder"},
+	}
+
+	createSut := func() (*MaintenanceWindowService, *fakeMaintenanceWindowStore, *fakes.FakeProvisioningStore) {
+		store := &fakeMaintenanceWindowStore{windows: map[string]models.MaintenanceWindow{}}
+		prov := fakes.NewFakeProvisioningStore()
+		return NewMaintenanceWindowService(store, prov, newNopTransactionManager(), log.NewNopLogger()), store, prov
+	}
+
+	t.Run("create generates UID and stores provenance", func(t *testing.T) {
+		sut, store, _ := createSut()
+		mw := window
+		mw.Provenance = definitions.Provenance(models.ProvenanceFile)
+
+		created, err := sut.CreateMaintenanceWindow(ctx, mw, orgID)
+		require.NoError(t, err)
+		require.NotEmpty(t, created.UID)
+		require.EqualValues(t, 1, created.Version)
+		require.Contains(t, store.windows, created.UID)
+
+		stored, err := sut.GetMaintenanceWindow(ctx, created.UID, orgID)
+		require.NoError(t, err)
+		require.Equal(t, definitions.Provenance(models.ProvenanceFile), stored.Provenance)
+	})
+
+	t.Run("create rejects invalid windows", func(t *testing.T) {
+		sut, _, _ := createSut()
+		mw := window
+		mw.Mode = "snooze"
+		_, err := sut.CreateMaintenanceWindow(ctx, mw, orgID)
+		require.ErrorIs(t, err, ErrMaintenanceWindowInvalid)
+
+		mw = window
+		mw.UID = "invalid uid!"
+		_, err = sut.CreateMaintenanceWindow(ctx, mw, orgID)
+		require.ErrorIs(t, err, ErrMaintenanceWindowInvalid)
+
+		mw = window
+		mw.FolderUIDs = nil
+		_, err = sut.CreateMaintenanceWindow(ctx, mw, orgID)
+		require.ErrorIs(t, err, ErrMaintenanceWindowInvalid)
+	})
+
+	t.Run("update without version skips the concurrency check", func(t *testing.T) {
+		sut, _, _ := createSut()
+		created, err := sut.CreateMaintenanceWindow(ctx, window, orgID)
+		require.NoError(t, err)
+
+		upd := created
+		upd.Version = 0
+		upd.Mode = string(models.MaintenanceWindowModePause)
+		updated, err := sut.UpdateMaintenanceWindow(ctx, upd, orgID)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, updated.Version)
+		require.Equal(t, upd.Mode, updated.Mode)
+
+		upd.Version = 1
+		_, err = sut.UpdateMaintenanceWindow(ctx, upd, orgID)
+		require.ErrorIs(t, err, models.ErrMaintenanceWindowVersionConflict)
+	})
+
+	t.Run("update rejects provisioned windows", func(t *testing.T) {
+		sut, _, _ := createSut()
+		mw := window
+		mw.Provenance = definitions.Provenance(models.ProvenanceFile)
+		created, err := sut.CreateMaintenanceWindow(ctx, mw, orgID)
+		require.NoError(t, err)
+
+		created.Provenance = definitions.Provenance(models.ProvenanceNone)
+		_, err = sut.UpdateMaintenanceWindow(ctx, created, orgID)
+		require.ErrorIs(t, err, validation.ErrProvenanceChangeNotAllowed)
+	})
+
+	t.Run("delete checks version and removes provenance", func(t *testing.T) {
+		sut, store, prov := createSut()
+		created, err := sut.CreateMaintenanceWindow(ctx, window, orgID)
+		require.NoError(t, err)
+
+		err = sut.DeleteMaintenanceWindow(ctx, created.UID, orgID, definitions.Provenance(models.ProvenanceNone), 2)
+		require.ErrorIs(t, err, ErrVersionConflict)
+
+		require.NoError(t, sut.DeleteMaintenanceWindow(ctx, created.UID, orgID, definitions.Provenance(models.ProvenanceNone), 1))
+		require.Empty(t, store.windows)
+		provenances, err := prov.GetProvenances(ctx, orgID, (&models.MaintenanceWindow{}).ResourceType())
+		require.NoError(t, err)
+		require.Empty(t, provenances)
+
+		require.NoError(t, sut.DeleteMaintenanceWindow(ctx, "missing", orgID, definitions.Provenance(models.ProvenanceNone), 0))
+	})
+}
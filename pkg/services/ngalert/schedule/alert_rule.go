@@ -60,6 +60,7 @@ func newRuleFactory(
 	logger log.Logger,
 	tracer tracing.Tracer,
 	recordingWriter RecordingWriter,
+	costs EvaluationCosts,
 	evalAppliedHook evalAppliedFunc,
 	stopAppliedHook stopAppliedFunc,
 ) ruleFactoryFunc {
@@ -76,6 +77,7 @@ func newRuleFactory(
 				met,
 				tracer,
 				recordingWriter,
+				costs,
 			)
 		}
 		return newAlertRule(
@@ -92,6 +94,7 @@ func newRuleFactory(
 			met,
 			logger,
 			tracer,
+			costs,
 			evalAppliedHook,
 			stopAppliedHook,
 		)
@@ -122,6 +125,7 @@ type alertRule struct {
 	stateManager *state.Manager
 	evalFactory  eval.EvaluatorFactory
 	ruleProvider ruleProvider
+	costs        EvaluationCosts
 
 	// Event hooks that are only used in tests.
 	evalAppliedHook evalAppliedFunc
@@ -146,6 +150,7 @@ func newAlertRule(
 	met *metrics.Scheduler,
 	logger log.Logger,
 	tracer tracing.Tracer,
+	costs EvaluationCosts,
 	evalAppliedHook func(ngmodels.AlertRuleKey, time.Time),
 	stopAppliedHook func(ngmodels.AlertRuleKey),
 ) *alertRule {
@@ -164,6 +169,7 @@ func newAlertRule(
 		stateManager:         stateManager,
 		evalFactory:          evalFactory,
 		ruleProvider:         ruleProvider,
+		costs:                costs,
 		evalAppliedHook:      evalAppliedHook,
 		stopAppliedHook:      stopAppliedHook,
 		metrics:              met,
@@ -358,6 +364,9 @@ func (a *alertRule) evaluate(ctx context.Context, e *Evaluation, span trace.Span
 	start := a.clock.Now()
 
 	evalCtx := eval.NewContextWithPreviousResults(ctx, SchedulerUserFor(e.rule.OrgID), a.newLoadedMetricsReader(e.rule))
+	if a.costs != nil {
+		evalCtx.CostRecorder = a.costs.Recorder(e.rule)
+	}
 	ruleEval, err := a.evalFactory.Create(evalCtx, e.rule.GetEvalCondition().WithSource("scheduler").WithFolder(e.folderTitle))
 	var results eval.Results
 	var dur time.Duration
@@ -422,11 +431,19 @@ func (a *alertRule) evaluate(ctx context.Context, e *Evaluation, span trace.Span
 			attribute.Int64("results", int64(len(results))),
 		))
 	}
+	// The state manager calculates when alerts end from the interval of the rule, so that they do not end
+	// before the next evaluation. If the interval is stretched, it must use the stretched interval.
+	stateRule := e.rule
+	if e.intervalMultiplier > 1 {
+		stretched := *e.rule
+		stretched.IntervalSeconds *= e.intervalMultiplier
+		stateRule = &stretched
+	}
 	start = a.clock.Now()
 	_ = a.stateManager.ProcessEvalResults(
 		ctx,
 		e.scheduledAt,
-		e.rule,
+		stateRule,
 		results,
 		state.GetRuleExtraLabels(logger, e.rule, e.folderTitle, !a.disableGrafanaFolder),
 		func(ctx context.Context, statesToSend state.StateTransitions) {
@@ -267,7 +267,7 @@ func TestAlertRule(t *testing.T) {
 }
 
 func blankRuleForTests(ctx context.Context, key models.AlertRuleKey) *alertRule {
-	return newAlertRule(ctx, key, nil, false, 0, nil, nil, nil, nil, nil, nil, log.NewNopLogger(), nil, nil, nil)
+	return newAlertRule(ctx, key, nil, false, 0, nil, nil, nil, nil, nil, nil, log.NewNopLogger(), nil, nil, nil, nil)
 }
 
 func TestRuleRoutine(t *testing.T) {
@@ -736,6 +736,42 @@ func TestRuleRoutine(t *testing.T) {
 
 			require.Len(t, args.PostableAlerts, 1)
 		})
+
+		t.Run("alerts should not end before the next evaluation if the interval is stretched", func(t *testing.T) {
+			rule := gen.With(withQueryForState(t, eval.Alerting), models.RuleMuts.WithIntervalSeconds(60)).GenerateRef()
+
+			evalAppliedChan := make(chan time.Time)
+
+			sender := NewSyncAlertsSenderMock()
+			sender.EXPECT().Send(mock.Anything, rule.GetKey(), mock.Anything).Return()
+
+			sch, ruleStore, _, _ := createSchedule(evalAppliedChan, sender)
+			ruleStore.PutRule(context.Background(), rule)
+			factory := ruleFactoryFromScheduler(sch)
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+			ruleInfo := factory.new(ctx, rule)
+
+			go func() {
+				_ = ruleInfo.Run()
+			}()
+
+			scheduledAt := sch.clock.Now()
+			ruleInfo.Eval(&Evaluation{
+				scheduledAt:        scheduledAt,
+				rule:               rule,
+				intervalMultiplier: 5,
+			})
+
+			waitForTimeChannel(t, evalAppliedChan)
+
+			sender.AssertNumberOfCalls(t, "Send", 1)
+			args, ok := sender.Calls()[0].Arguments[2].(definitions.PostableAlerts)
+			require.True(t, ok)
+			require.Len(t, args.PostableAlerts, 1)
+			// alerts end after 4 intervals, the interval of the rule is stretched to 5 minutes
+			require.Equal(t, scheduledAt.Add(20*time.Minute).UTC(), time.Time(args.PostableAlerts[0].EndsAt).UTC())
+		})
 	})
 
 	t.Run("when there are no alerts to send it should not call notifiers", func(t *testing.T) {
@@ -827,7 +863,7 @@ func TestRuleRoutine(t *testing.T) {
 }
 
 func ruleFactoryFromScheduler(sch *schedule) ruleFactory {
-	return newRuleFactory(sch.appURL, sch.disableGrafanaFolder, sch.maxAttempts, sch.alertsSender, sch.stateManager, sch.evaluatorFactory, &sch.schedulableAlertRules, sch.clock, sch.featureToggles, sch.metrics, sch.log, sch.tracer, sch.recordingWriter, sch.evalAppliedFunc, sch.stopAppliedFunc)
+	return newRuleFactory(sch.appURL, sch.disableGrafanaFolder, sch.maxAttempts, sch.alertsSender, sch.stateManager, sch.evaluatorFactory, &sch.schedulableAlertRules, sch.clock, sch.featureToggles, sch.metrics, sch.log, sch.tracer, sch.recordingWriter, sch.evaluationCosts, sch.evalAppliedFunc, sch.stopAppliedFunc)
 }
 
 func stateForRule(rule *models.AlertRule, ts time.Time, evalState eval.State) *state.State {
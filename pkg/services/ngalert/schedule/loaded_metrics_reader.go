@@ -3,12 +3,14 @@ package schedule
 import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 )
 
 var _ eval.AlertingResultsReader = AlertingResultsFromRuleState{}
+var _ eval.HysteresisStateReader = AlertingResultsFromRuleState{}
 
 func (a *alertRule) newLoadedMetricsReader(rule *ngmodels.AlertRule) eval.AlertingResultsReader {
 	return &AlertingResultsFromRuleState{
@@ -42,3 +44,16 @@ func (n AlertingResultsFromRuleState) Read() map[data.Fingerprint]struct{} {
 	}
 	return active
 }
+
+// ReadHysteresisState returns the state of the multi-level hysteresis condition for results that are at a level.
+func (n AlertingResultsFromRuleState) ReadHysteresisState() expr.HysteresisState {
+	states := n.Manager.GetStatesForRuleUID(n.Rule.OrgID, n.Rule.UID)
+
+	result := expr.HysteresisState{}
+	for _, st := range states {
+		if st.Hysteresis.Level > 0 {
+			result[st.ResultFingerprint] = st.Hysteresis
+		}
+	}
+	return result
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
@@ -53,6 +54,29 @@ func TestLoadedResultsFromRuleState(t *testing.T) {
 	})
 }
 
+func TestHysteresisStateFromRuleState(t *testing.T) {
+	rule := ngmodels.RuleGen.GenerateRef()
+	p := &FakeRuleStateProvider{
+		map[ngmodels.AlertRuleKey][]*state.State{
+			rule.GetKey(): {
+				{State: eval.Alerting, ResultFingerprint: data.Fingerprint(1), Hysteresis: expr.HysteresisSeriesState{Level: 2, Unloading: 1}},
+				{State: eval.Alerting, ResultFingerprint: data.Fingerprint(2), Hysteresis: expr.HysteresisSeriesState{Level: 1}},
+				{State: eval.Normal, ResultFingerprint: data.Fingerprint(3)},
+			},
+		},
+	}
+
+	reader := AlertingResultsFromRuleState{
+		Manager: p,
+		Rule:    rule,
+	}
+
+	require.Equal(t, expr.HysteresisState{
+		1: {Level: 2, Unloading: 1},
+		2: {Level: 1},
+	}, reader.ReadHysteresisState())
+}
+
 type FakeRuleStateProvider struct {
 	states map[ngmodels.AlertRuleKey][]*state.State
 }
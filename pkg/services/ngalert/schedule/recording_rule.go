@@ -47,6 +47,7 @@ type recordingRule struct {
 	evalFactory    eval.EvaluatorFactory
 	featureToggles featuremgmt.FeatureToggles
 	writer         RecordingWriter
+	costs          EvaluationCosts
 
 	// Event hooks that are only used in tests.
 	evalAppliedHook evalAppliedFunc
@@ -56,7 +57,7 @@ type recordingRule struct {
 	tracer  tracing.Tracer
 }
 
-func newRecordingRule(parent context.Context, key ngmodels.AlertRuleKey, maxAttempts int64, clock clock.Clock, evalFactory eval.EvaluatorFactory, ft featuremgmt.FeatureToggles, logger log.Logger, metrics *metrics.Scheduler, tracer tracing.Tracer, writer RecordingWriter) *recordingRule {
+func newRecordingRule(parent context.Context, key ngmodels.AlertRuleKey, maxAttempts int64, clock clock.Clock, evalFactory eval.EvaluatorFactory, ft featuremgmt.FeatureToggles, logger log.Logger, metrics *metrics.Scheduler, tracer tracing.Tracer, writer RecordingWriter, costs EvaluationCosts) *recordingRule {
 	ctx, stop := util.WithCancelCause(ngmodels.WithRuleKey(parent, key))
 	return &recordingRule{
 		key:                 key,
@@ -75,6 +76,7 @@ func newRecordingRule(parent context.Context, key ngmodels.AlertRuleKey, maxAtte
 		metrics:             metrics,
 		tracer:              tracer,
 		writer:              writer,
+		costs:               costs,
 	}
 }
 
@@ -228,6 +230,9 @@ func (r *recordingRule) doEvaluate(ctx context.Context, ev *Evaluation) {
 func (r *recordingRule) tryEvaluation(ctx context.Context, ev *Evaluation, logger log.Logger) error {
 	evalStart := r.clock.Now()
 	evalCtx := eval.NewContext(ctx, SchedulerUserFor(ev.rule.OrgID))
+	if r.costs != nil {
+		evalCtx.CostRecorder = r.costs.Recorder(ev.rule)
+	}
 	result, err := r.buildAndExecutePipeline(ctx, evalCtx, ev, logger)
 	evalDur := r.clock.Now().Sub(evalStart)
 	if err != nil {
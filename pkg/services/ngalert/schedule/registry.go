@@ -85,6 +85,9 @@ type Evaluation struct {
 	scheduledAt time.Time
 	rule        *models.AlertRule
 	folderTitle string
+	// intervalMultiplier is the factor the interval of the rule is stretched by because its organization exceeds
+	// its evaluation budget. Zero means that the interval is not stretched.
+	intervalMultiplier int64
 }
 
 func (e *Evaluation) Fingerprint() fingerprint {
@@ -306,6 +309,7 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 	writeInt(rule.ID)
 	writeInt(rule.OrgID)
 	writeInt(int64(rule.For))
+	writeInt(int64(rule.KeepFiringFor))
 	if rule.DashboardUID != nil {
 		writeString(*rule.DashboardUID)
 	}
@@ -320,6 +324,9 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 		binary.LittleEndian.PutUint64(tmp, uint64(rule.Record.Fingerprint()))
 		writeBytes(tmp)
 	}
+	for _, uid := range rule.DependsOn {
+		writeString(uid)
+	}
 
 	return fingerprint(sum.Sum64())
 }
@@ -194,6 +194,7 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 			ExecErrState:    "test-err",
 			Record:          &models.Record{Metric: "my_metric", From: "A"},
 			For:             12,
+			KeepFiringFor:   34,
 			Annotations: map[string]string{
 				"key-annotation": "value-annotation",
 			},
@@ -204,6 +205,7 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 			NotificationSettings: []models.NotificationSettings{
 				models.NotificationSettingsGen()(),
 			},
+			DependsOn: []string{"test-uid1"},
 		}
 		r2 := &models.AlertRule{
 			ID:        2,
@@ -233,6 +235,7 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 			ExecErrState:    "test-err2",
 			Record:          &models.Record{Metric: "my_metric2", From: "B"},
 			For:             1141,
+			KeepFiringFor:   4331,
 			Annotations: map[string]string{
 				"key-annotation2": "value-annotation",
 			},
@@ -243,6 +246,7 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 			NotificationSettings: []models.NotificationSettings{
 				models.NotificationSettingsGen()(),
 			},
+			DependsOn: []string{"test-uid2"},
 		}
 
 		excludedFields := map[string]struct{}{
@@ -52,6 +52,19 @@ type RecordingWriter interface {
 	Write(ctx context.Context, name string, t time.Time, frames data.Frames, extraLabels map[string]string) error
 }
 
+// MaintenanceWindows tells whether the evaluation of a rule is paused by an active maintenance window.
+type MaintenanceWindows interface {
+	IsEvaluationPaused(rule *ngmodels.AlertRule, now time.Time) bool
+}
+
+// EvaluationCosts accounts for the cost of rule evaluations and tells how much the evaluation intervals of an
+// organization that exceeds its evaluation budget should be stretched.
+type EvaluationCosts interface {
+	Recorder(rule *ngmodels.AlertRule) eval.CostRecorder
+	IntervalMultiplier(orgID int64) int64
+	Forget(key ngmodels.AlertRuleKey)
+}
+
 type schedule struct {
 	// base tick rate (fastest possible configured check)
 	baseInterval time.Duration
@@ -100,6 +113,10 @@ type schedule struct {
 	tracer tracing.Tracer
 
 	recordingWriter RecordingWriter
+
+	maintenanceWindows MaintenanceWindows
+
+	evaluationCosts EvaluationCosts
 }
 
 // SchedulerCfg is the scheduler configuration.
@@ -119,6 +136,11 @@ type SchedulerCfg struct {
 	Tracer               tracing.Tracer
 	Log                  log.Logger
 	RecordingWriter      RecordingWriter
+	// MaintenanceWindows is optional. If set, rules paused by a maintenance window are not evaluated.
+	MaintenanceWindows MaintenanceWindows
+	// EvaluationCosts is optional. If set, the cost of every evaluation is recorded, and the evaluation intervals of
+	// organizations that exceed their budget are stretched.
+	EvaluationCosts EvaluationCosts
 }
 
 // NewScheduler returns a new scheduler.
@@ -148,6 +170,8 @@ func NewScheduler(cfg SchedulerCfg, stateManager *state.Manager) *schedule {
 		alertsSender:          cfg.AlertSender,
 		tracer:                cfg.Tracer,
 		recordingWriter:       cfg.RecordingWriter,
+		maintenanceWindows:    cfg.MaintenanceWindows,
+		evaluationCosts:       cfg.EvaluationCosts,
 	}
 
 	return &sch
@@ -188,6 +212,9 @@ func (sch *schedule) deleteAlertRule(keys ...ngmodels.AlertRuleKey) {
 		}
 		// stop rule evaluation
 		ruleRoutine.Stop(errRuleDeleted)
+		if sch.evaluationCosts != nil {
+			sch.evaluationCosts.Forget(key)
+		}
 	}
 	// Our best bet at this point is that we update the metrics with what we hope to schedule in the next tick.
 	alertRules, _ := sch.schedulableAlertRules.all()
@@ -265,6 +292,7 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		sch.log,
 		sch.tracer,
 		sch.recordingWriter,
+		sch.evaluationCosts,
 		sch.evalAppliedFunc,
 		sch.stopAppliedFunc,
 	)
@@ -295,8 +323,18 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		}
 
 		itemFrequency := item.IntervalSeconds / int64(sch.baseInterval.Seconds())
+		intervalMultiplier := int64(1)
+		if sch.evaluationCosts != nil {
+			// stretch the interval if the organization exceeds its evaluation budget
+			intervalMultiplier = sch.evaluationCosts.IntervalMultiplier(item.OrgID)
+			itemFrequency *= intervalMultiplier
+		}
 		offset := jitterOffsetInTicks(item, sch.baseInterval, sch.jitterEvaluations)
 		isReadyToRun := item.IntervalSeconds != 0 && (tickNum%itemFrequency)-offset == 0
+		if isReadyToRun && sch.maintenanceWindows != nil && sch.maintenanceWindows.IsEvaluationPaused(item, tick) {
+			logger.Debug("Rule evaluation is paused by a maintenance window", "tick", tick)
+			isReadyToRun = false
+		}
 
 		var folderTitle string
 		if !sch.disableGrafanaFolder {
@@ -311,9 +349,10 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		if isReadyToRun {
 			logger.Debug("Rule is ready to run on the current tick", "tick", tick, "frequency", itemFrequency, "offset", offset)
 			readyToRun = append(readyToRun, readyToRunItem{ruleRoutine: ruleRoutine, Evaluation: Evaluation{
-				scheduledAt: tick,
-				rule:        item,
-				folderTitle: folderTitle,
+				scheduledAt:        tick,
+				rule:               item,
+				folderTitle:        folderTitle,
+				intervalMultiplier: intervalMultiplier,
 			}})
 		}
 		if _, isUpdated := updated[key]; isUpdated && !isReadyToRun {
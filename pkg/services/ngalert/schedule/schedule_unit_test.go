@@ -420,6 +420,94 @@ func TestSchedule_deleteAlertRule(t *testing.T) {
 	})
 }
 
+type fakeMaintenanceWindows func(rule *models.AlertRule, now time.Time) bool
+
+func (f fakeMaintenanceWindows) IsEvaluationPaused(rule *models.AlertRule, now time.Time) bool {
+	return f(rule, now)
+}
+
+func TestProcessTicksMaintenanceWindows(t *testing.T) {
+	ctx := context.Background()
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+	ruleStore := newFakeRulesStore()
+	sch := setupScheduler(t, ruleStore, nil, nil, nil, nil)
+
+	gen := models.RuleGen
+	paused := gen.With(gen.WithInterval(time.Second), gen.WithTitle("paused")).GenerateRef()
+	active := gen.With(gen.WithInterval(time.Second), gen.WithTitle("active")).GenerateRef()
+	ruleStore.PutRule(ctx, paused, active)
+
+	pausedUntil := time.Time{}.Add(2 * time.Second)
+	sch.maintenanceWindows = fakeMaintenanceWindows(func(rule *models.AlertRule, now time.Time) bool {
+		return rule.UID == paused.UID && now.Before(pausedUntil)
+	})
+
+	tick := time.Time{}.Add(time.Second)
+	scheduled, _, _ := sch.processTick(ctx, dispatcherGroup, tick)
+	require.Len(t, scheduled, 1)
+	require.Equal(t, active.UID, scheduled[0].rule.UID)
+	require.True(t, sch.registry.exists(paused.GetKey()), "paused rule should stay registered")
+	require.False(t, paused.IsPaused)
+
+	tick = tick.Add(time.Second)
+	scheduled, _, _ = sch.processTick(ctx, dispatcherGroup, tick)
+	require.Len(t, scheduled, 2)
+}
+
+type fakeEvaluationCosts struct {
+	multipliers map[int64]int64
+	forgotten   []models.AlertRuleKey
+}
+
+func (f *fakeEvaluationCosts) Recorder(*models.AlertRule) eval.CostRecorder {
+	return nil
+}
+
+func (f *fakeEvaluationCosts) IntervalMultiplier(orgID int64) int64 {
+	if m, ok := f.multipliers[orgID]; ok {
+		return m
+	}
+	return 1
+}
+
+func (f *fakeEvaluationCosts) Forget(key models.AlertRuleKey) {
+	f.forgotten = append(f.forgotten, key)
+}
+
+func TestProcessTicksEvaluationCosts(t *testing.T) {
+	ctx := context.Background()
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+	ruleStore := newFakeRulesStore()
+	sch := setupScheduler(t, ruleStore, nil, nil, nil, nil)
+
+	gen := models.RuleGen
+	overBudget := gen.With(gen.WithInterval(time.Second), gen.WithOrgID(1)).GenerateRef()
+	withinBudget := gen.With(gen.WithInterval(time.Second), gen.WithOrgID(2)).GenerateRef()
+	ruleStore.PutRule(ctx, overBudget, withinBudget)
+
+	costs := &fakeEvaluationCosts{multipliers: map[int64]int64{1: 2}}
+	sch.evaluationCosts = costs
+
+	t.Run("should stretch the interval of organizations over budget", func(t *testing.T) {
+		tick := time.Time{}
+		counts := map[string]int{}
+		for i := 0; i < 4; i++ {
+			tick = tick.Add(time.Second)
+			scheduled, _, _ := sch.processTick(ctx, dispatcherGroup, tick)
+			for _, item := range scheduled {
+				counts[item.rule.UID]++
+			}
+		}
+		require.Equal(t, 2, counts[overBudget.UID])
+		require.Equal(t, 4, counts[withinBudget.UID])
+	})
+
+	t.Run("should forget the cost of deleted rules", func(t *testing.T) {
+		sch.deleteAlertRule(overBudget.GetKey())
+		require.Equal(t, []models.AlertRuleKey{overBudget.GetKey()}, costs.forgotten)
+	})
+}
+
 func setupScheduler(t *testing.T, rs *fakeRulesStore, is *state.FakeInstanceStore, registry *prometheus.Registry, senderMock *SyncAlertsSenderMock, evalMock eval.EvaluatorFactory) *schedule {
 	t.Helper()
 	testTracer := tracing.InitializeTracerForTest()
@@ -0,0 +1,36 @@
+package state
+
+import (
+	"strings"
+
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// acknowledgementFromInstance returns the acknowledgement saved with the alert instance, if any.
+func acknowledgementFromInstance(instance *ngModels.AlertInstance) *ngModels.Acknowledgement {
+	if instance.AcknowledgedAt == nil || instance.AcknowledgementExpiresAt == nil {
+		return nil
+	}
+	return &ngModels.Acknowledgement{
+		By:        instance.AcknowledgedBy,
+		Comment:   instance.AcknowledgementComment,
+		At:        *instance.AcknowledgedAt,
+		ExpiresAt: *instance.AcknowledgementExpiresAt,
+	}
+}
+
+// withAcknowledgedReason adds the Acknowledged reason to the reason of a firing state.
+func withAcknowledgedReason(reason string) string {
+	if reason == "" {
+		return ngModels.StateReasonAcknowledged
+	}
+	return ngModels.ConcatReasons(reason, ngModels.StateReasonAcknowledged)
+}
+
+// withoutAcknowledgedReason removes the Acknowledged reason from the reason of a state.
+func withoutAcknowledgedReason(reason string) string {
+	if reason == ngModels.StateReasonAcknowledged {
+		return ""
+	}
+	return strings.TrimSuffix(reason, ngModels.ConcatReasons("", ngModels.StateReasonAcknowledged))
+}
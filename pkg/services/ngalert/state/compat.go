@@ -29,6 +29,7 @@ const (
 // StateToPostableAlert converts a state to a model that is accepted by Alertmanager. Annotations and Labels are copied from the state.
 // - if state has at least one result, a new label '__value_string__' is added to the label set
 // - the alert's GeneratorURL is constructed to point to the alert detail view
+// - if the state is acknowledged, annotations with the owner, comment and expiry of the acknowledgement are added
 // - if evaluation state is either NoData or Error, the resulting set of labels is changed:
 //   - original alert name (label: model.AlertNameLabel) is backed up to OriginalAlertName
 //   - label model.AlertNameLabel is overwritten to either NoDataAlertName or ErrorAlertName
@@ -56,6 +57,12 @@ func StateToPostableAlert(transition StateTransition, appURL *url.URL) *models.P
 		nA[alertingModels.StateReasonAnnotation] = alertState.StateReason
 	}
 
+	if alertState.Acknowledgement != nil {
+		for k, v := range alertState.Acknowledgement.Annotations() {
+			nA[k] = v
+		}
+	}
+
 	if alertState.OrgID != 0 {
 		nA[alertingModels.OrgIDAnnotation] = strconv.FormatInt(alertState.OrgID, 10)
 	}
@@ -144,7 +151,7 @@ func FromAlertsStateToStoppedAlert(firingStates []StateTransition, appURL *url.U
 	alerts := apimodels.PostableAlerts{PostableAlerts: make([]models.PostableAlert, 0, len(firingStates))}
 	ts := clock.Now()
 	for _, transition := range firingStates {
-		if transition.PreviousState == eval.Normal || transition.PreviousState == eval.Pending {
+		if transition.PreviousState == eval.Normal || transition.PreviousState == eval.Pending || transition.PreviousState == eval.Inhibited {
 			continue
 		}
 		postableAlert := StateToPostableAlert(transition, appURL)
@@ -159,6 +159,19 @@ func Test_StateToPostableAlert(t *testing.T) {
 				require.Equal(t, alertState.StateReason, result.Annotations[ngModels.StateReasonAnnotation])
 			})
 
+			t.Run("should add acknowledgement annotations if acknowledged", func(t *testing.T) {
+				alertState := randomTransition(eval.Normal, tc.state)
+				alertState.Acknowledgement = &ngModels.Acknowledgement{
+					By:        "TEST_USER",
+					Comment:   "TEST_COMMENT",
+					ExpiresAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+				}
+				result := StateToPostableAlert(alertState, appURL)
+				require.Equal(t, "TEST_USER", result.Annotations[ngModels.AcknowledgedByAnnotation])
+				require.Equal(t, "TEST_COMMENT", result.Annotations[ngModels.AcknowledgementCommentAnnotation])
+				require.Equal(t, "2024-01-01T12:00:00Z", result.Annotations[ngModels.AcknowledgedUntilAnnotation])
+			})
+
 			switch tc.state {
 			case eval.NoData:
 				t.Run("should keep existing labels and change name", func(t *testing.T) {
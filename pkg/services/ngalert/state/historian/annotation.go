@@ -25,6 +25,9 @@ import (
 	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
 )
 
+// defaultAnnotationsLimit is the number of annotations found when the query has no limit.
+const defaultAnnotationsLimit = 100
+
 type AccessControl interface {
 	CanReadAllRules(ctx context.Context, user identity.Requester) (bool, error)
 	AuthorizeAccessInFolder(ctx context.Context, user identity.Requester, rule ngmodels.Namespaced) error
@@ -118,6 +121,9 @@ func (h *AnnotationBackend) Query(ctx context.Context, query ngmodels.HistoryQue
 	if query.Labels != nil {
 		logger.Warn("Annotation state history backend does not support label queries, ignoring that filter")
 	}
+	if len(query.Matchers) > 0 {
+		return nil, newErrUnsupportedHistoryQuery("Filtering by label matchers is not supported when the state history is stored in annotations")
+	}
 
 	rq := ngmodels.GetAlertRuleByUIDQuery{
 		UID:   query.RuleUID,
@@ -142,10 +148,21 @@ func (h *AnnotationBackend) Query(ctx context.Context, query ngmodels.HistoryQue
 		To:           query.To.UnixMilli(),
 		SignedInUser: query.SignedInUser,
 	}
+	if query.Limit > 0 || query.Offset > 0 {
+		// Annotations are found most recent first, so the skipped ones are fetched and dropped.
+		limit := query.Limit
+		if limit < 1 {
+			limit = defaultAnnotationsLimit
+		}
+		q.Limit = int64(limit + query.Offset)
+	}
 	items, err := h.store.Find(ctx, &q)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query annotations for state history: %w", err)
 	}
+	if query.Offset > 0 {
+		items = items[min(query.Offset, len(items)):]
+	}
 
 	frame := data.NewFrame("states")
 
@@ -252,6 +269,10 @@ func BuildAnnotationTextAndData(rule history_model.RuleMeta, currentState *state
 		value = strings.Join(values, ", ")
 	}
 
+	if ack := currentState.Acknowledgement; ack != nil {
+		jsonData.Set("acknowledgement", ack)
+	}
+
 	labels := removePrivateLabels(currentState.Labels)
 	return fmt.Sprintf("%s {%s} - %s", rule.Title, labels.String(), value), jsonData
 }
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
@@ -97,6 +98,41 @@ func TestAnnotationHistorian(t *testing.T) {
 		require.Equal(t, now.Add(-10*time.Second).UnixMilli(), query.From)
 	})
 
+	t.Run("annotation queries skip the most recent items by offset", func(t *testing.T) {
+		store := &interceptingAnnotationStore{
+			items: []*annotations.ItemDTO{{ID: 3, Time: 3}, {ID: 2, Time: 2}, {ID: 1, Time: 1}},
+		}
+		anns := createTestAnnotationSutWithStore(t, store)
+
+		q := models.HistoryQuery{
+			RuleUID: "my-rule",
+			OrgID:   1,
+			Limit:   1,
+			Offset:  1,
+		}
+		frame, err := anns.Query(context.Background(), q)
+
+		require.NoError(t, err)
+		require.Equal(t, int64(2), store.lastQuery.Limit)
+		require.Equal(t, 1, frame.Rows())
+		require.Equal(t, time.Unix(2, 0), frame.Fields[0].At(0))
+	})
+
+	t.Run("annotation queries reject label matchers", func(t *testing.T) {
+		anns := createTestAnnotationBackendSut(t)
+		matcher, err := labels.NewMatcher(labels.MatchRegexp, "a", "b.*")
+		require.NoError(t, err)
+
+		q := models.HistoryQuery{
+			RuleUID:  "my-rule",
+			OrgID:    1,
+			Matchers: labels.Matchers{matcher},
+		}
+		_, err = anns.Query(context.Background(), q)
+
+		require.ErrorIs(t, err, ErrUnsupportedHistoryQuery)
+	})
+
 	t.Run("writing state transitions as annotations succeeds", func(t *testing.T) {
 		anns := createTestAnnotationBackendSut(t)
 		rule := createTestRule()
@@ -276,11 +312,16 @@ func assertValidJSON(t *testing.T, j *simplejson.Json) string {
 
 type interceptingAnnotationStore struct {
 	lastQuery *annotations.ItemQuery
+	items     []*annotations.ItemDTO
 }
 
 func (i *interceptingAnnotationStore) Find(ctx context.Context, query *annotations.ItemQuery) ([]*annotations.ItemDTO, error) {
 	i.lastQuery = query
-	return []*annotations.ItemDTO{}, nil
+	items := i.items
+	if query.Limit > 0 && int(query.Limit) < len(items) {
+		items = items[:query.Limit]
+	}
+	return append([]*annotations.ItemDTO{}, items...), nil
 }
 
 func (i *interceptingAnnotationStore) Save(ctx context.Context, panel *PanelKey, annotations []annotations.Item, orgID int64, logger log.Logger) error {
@@ -19,7 +19,6 @@ import (
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
-	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/ngalert/client"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
@@ -161,18 +160,48 @@ func (h *RemoteLokiBackend) Query(ctx context.Context, query models.HistoryQuery
 	if query.From.IsZero() {
 		query.From = now.Add(-defaultQueryRange)
 	}
+	limit := int64(query.Limit)
+	if query.Offset > 0 {
+		// Loki has no offset, so the skipped entries are fetched and dropped after the results are merged.
+		if query.Limit < 1 {
+			query.Limit = defaultPageSize
+		}
+		limit = int64(query.Limit + query.Offset)
+		if limit > maximumPageSize {
+			return nil, newErrUnsupportedHistoryQuery(fmt.Sprintf("The sum of limit and offset must not exceed %d when the state history is stored in Loki", maximumPageSize))
+		}
+	}
 	var res []Stream
 	for _, logQL := range queries {
 		// Timestamps are expected in RFC3339Nano.
 		// Apply user-defined limit to every request. Multiple batches is a very rare case, and therefore we can tolerate getting more data than needed.
 		// The limit can be applied after all results are merged
-		r, err := h.client.RangeQuery(ctx, logQL, query.From.UnixNano(), query.To.UnixNano(), int64(query.Limit))
+		r, err := h.client.RangeQuery(ctx, logQL, query.From.UnixNano(), query.To.UnixNano(), limit)
 		if err != nil {
 			return nil, err
 		}
 		res = append(res, r.Data.Result...)
 	}
-	return merge(res, uids)
+	frame, err := merge(res, uids)
+	if err != nil {
+		return nil, err
+	}
+	if query.Offset > 0 {
+		return pageFrame(frame, query.Limit, query.Offset), nil
+	}
+	return frame, nil
+}
+
+// pageFrame returns the rows of a frame sorted by ascending time that remain after skipping the offset most recent
+// ones, up to limit rows.
+func pageFrame(frame *data.Frame, limit, offset int) *data.Frame {
+	end := frame.Rows() - offset
+	start := max(end-limit, 0)
+	paged := frame.EmptyCopy()
+	for i := start; i < end; i++ {
+		paged.AppendRow(frame.RowCopy(i)...)
+	}
+	return paged
 }
 
 // merge will put all the results in one array sorted by timestamp.
@@ -285,18 +314,19 @@ func StatesToStream(rule history_model.RuleMeta, states []state.StateTransition,
 
 		sanitizedLabels := removePrivateLabels(state.Labels)
 		entry := LokiEntry{
-			SchemaVersion:  1,
-			Previous:       state.PreviousFormatted(),
-			Current:        state.Formatted(),
-			Values:         valuesAsDataBlob(state.State),
-			Condition:      rule.Condition,
-			DashboardUID:   rule.DashboardUID,
-			PanelID:        rule.PanelID,
-			Fingerprint:    labelFingerprint(sanitizedLabels),
-			RuleTitle:      rule.Title,
-			RuleID:         rule.ID,
-			RuleUID:        rule.UID,
-			InstanceLabels: sanitizedLabels,
+			SchemaVersion:   1,
+			Previous:        state.PreviousFormatted(),
+			Current:         state.Formatted(),
+			Values:          valuesAsDataBlob(state.State),
+			Condition:       rule.Condition,
+			DashboardUID:    rule.DashboardUID,
+			PanelID:         rule.PanelID,
+			Fingerprint:     labelFingerprint(sanitizedLabels),
+			RuleTitle:       rule.Title,
+			RuleID:          rule.ID,
+			RuleUID:         rule.UID,
+			InstanceLabels:  sanitizedLabels,
+			Acknowledgement: state.Acknowledgement,
 		}
 		if state.State.State == eval.Error {
 			entry.Error = state.Error.Error()
@@ -346,6 +376,8 @@ type LokiEntry struct {
 	// InstanceLabels is exactly the set of labels associated with the alert instance in Alertmanager.
 	// These should not be conflated with labels associated with log streams.
 	InstanceLabels map[string]string `json:"labels"`
+	// Acknowledgement is set if the alert instance was acknowledged at the time of the transition.
+	Acknowledgement *models.Acknowledgement `json:"acknowledgement,omitempty"`
 }
 
 func valuesAsDataBlob(state *state.State) *simplejson.Json {
@@ -465,6 +497,9 @@ func buildQueryTail(query models.HistoryQuery) (string, error) {
 	}
 
 	requiredSize := 0
+	for _, m := range query.Matchers {
+		requiredSize += len(m.Name) + len(m.Value) + 14 // 14 all literals below
+	}
 	labelKeys := make([]string, 0, len(query.Labels))
 	for k, v := range query.Labels {
 		requiredSize += len(k) + len(v) + 13 // 13 all literals below
@@ -482,6 +517,16 @@ func buildQueryTail(query models.HistoryQuery) (string, error) {
 			return "", err
 		}
 	}
+	// Matchers use the same match types as LogQL label filters, which are also anchored.
+	for _, m := range query.Matchers {
+		b.WriteString(" | labels_")
+		b.WriteString(m.Name)
+		b.WriteString(m.Type.String())
+		_, err := fmt.Fprintf(&b, "%q", m.Value)
+		if err != nil {
+			return "", err
+		}
+	}
 	return b.String(), nil
 }
 
@@ -489,51 +534,10 @@ func queryHasLogFilters(query models.HistoryQuery) bool {
 	return query.RuleUID != "" ||
 		query.DashboardUID != "" ||
 		query.PanelID != 0 ||
-		len(query.Labels) > 0
+		len(query.Labels) > 0 ||
+		len(query.Matchers) > 0
 }
 
 func (h *RemoteLokiBackend) getFolderUIDsForFilter(ctx context.Context, query models.HistoryQuery) ([]string, error) {
-	bypass, err := h.ac.CanReadAllRules(ctx, query.SignedInUser)
-	if err != nil {
-		return nil, err
-	}
-	if bypass { // if user has access to all rules and folder, remove filter
-		return nil, nil
-	}
-	// if there is a filter by rule UID, find that rule UID and make sure that user has access to it.
-	if query.RuleUID != "" {
-		rule, err := h.ruleStore.GetAlertRuleByUID(ctx, &models.GetAlertRuleByUIDQuery{
-			UID:   query.RuleUID,
-			OrgID: query.OrgID,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch alert rule by UID: %w", err)
-		}
-		if rule == nil {
-			return nil, models.ErrAlertRuleNotFound
-		}
-		return nil, h.ac.AuthorizeAccessInFolder(ctx, query.SignedInUser, rule)
-	}
-	// if no filter, then we need to get all namespaces user has access to
-	folders, err := h.ruleStore.GetUserVisibleNamespaces(ctx, query.OrgID, query.SignedInUser)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch folders that user can access: %w", err)
-	}
-	uids := make([]string, 0, len(folders))
-	// now keep only UIDs of folder in which user can read rules.
-	for _, f := range folders {
-		hasAccess, err := h.ac.HasAccessInFolder(ctx, query.SignedInUser, models.Namespace(*f))
-		if err != nil {
-			return nil, err
-		}
-		if !hasAccess {
-			continue
-		}
-		uids = append(uids, f.UID)
-	}
-	if len(uids) == 0 {
-		return nil, accesscontrol.NewAuthorizationErrorGeneric("read rules in any folder")
-	}
-	sort.Strings(uids)
-	return uids, nil
+	return getFolderUIDsForFilter(ctx, query, h.ac, h.ruleStore)
 }
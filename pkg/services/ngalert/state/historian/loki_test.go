@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
@@ -271,6 +272,17 @@ func TestBuildLogQuery(t *testing.T) {
 			},
 			exp: []string{`{orgID="123",from="state-history"} | json | ruleUID="rule-uid" | labels_customlabel="customvalue"`},
 		},
+		{
+			name: "filters instance labels by matchers in log line",
+			query: models.HistoryQuery{
+				OrgID: 123,
+				Matchers: labels.Matchers{
+					{Type: labels.MatchNotEqual, Name: "team", Value: "ops"},
+					{Type: labels.MatchRegexp, Name: "env", Value: `prod-\d+`},
+				},
+			},
+			exp: []string{`{orgID="123",from="state-history"} | json | labels_team!="ops" | labels_env=~"prod-\\d+"`},
+		},
 		{
 			name: "should return if query does not exceed max limit",
 			query: models.HistoryQuery{
@@ -622,6 +634,45 @@ func TestMerge(t *testing.T) {
 	}
 }
 
+func TestQuery(t *testing.T) {
+	t.Run("skips the most recent entries by offset", func(t *testing.T) {
+		body := `{"data": {"result": [{"stream": {"from": "state-history"}, "values": [
+			["1000000000", "{\"schemaVersion\": 1, \"current\": \"Normal\"}"],
+			["2000000000", "{\"schemaVersion\": 1, \"current\": \"Alerting\"}"],
+			["3000000000", "{\"schemaVersion\": 1, \"current\": \"Normal\"}"]
+		]}]}}`
+		req := NewFakeRequester().WithResponse(&http.Response{
+			Status:        "200 OK",
+			StatusCode:    200,
+			Body:          io.NopCloser(bytes.NewBufferString(body)),
+			ContentLength: int64(len(body)),
+			Header:        make(http.Header, 0),
+		})
+		loki := createTestLokiBackend(t, req, metrics.NewHistorianMetrics(prometheus.NewRegistry(), metrics.Subsystem))
+		loki.ac = &acfakes.FakeRuleService{
+			CanReadAllRulesFunc: func(context.Context, identity.Requester) (bool, error) { return true, nil },
+		}
+
+		frame, err := loki.Query(context.Background(), models.HistoryQuery{OrgID: 1, Limit: 1, Offset: 1})
+
+		require.NoError(t, err)
+		require.Equal(t, "2", req.lastRequest.URL.Query().Get("limit"))
+		require.Equal(t, 1, frame.Rows())
+		require.Equal(t, time.Unix(2, 0), frame.Fields[0].At(0))
+	})
+
+	t.Run("rejects offsets beyond the maximum page size", func(t *testing.T) {
+		loki := createTestLokiBackend(t, NewFakeRequester(), metrics.NewHistorianMetrics(prometheus.NewRegistry(), metrics.Subsystem))
+		loki.ac = &acfakes.FakeRuleService{
+			CanReadAllRulesFunc: func(context.Context, identity.Requester) (bool, error) { return true, nil },
+		}
+
+		_, err := loki.Query(context.Background(), models.HistoryQuery{OrgID: 1, Limit: maximumPageSize, Offset: 1})
+
+		require.ErrorIs(t, err, ErrUnsupportedHistoryQuery)
+	})
+}
+
 func TestRecordStates(t *testing.T) {
 	t.Run("writes state transitions to loki", func(t *testing.T) {
 		req := NewFakeRequester()
@@ -886,6 +937,7 @@ func createTestLokiBackend(t *testing.T, req client.Requester, met *metrics.Hist
 		ReadPathURL:    url,
 		Encoder:        JsonEncoder{},
 		ExternalLabels: map[string]string{"externalLabelKey": "externalLabelValue"},
+		MaxQuerySize:   65536,
 	}
 	lokiBackendLogger := log.New("ngalert.state.historian", "backend", "loki")
 	rules := fakes.NewRuleStore(t)
@@ -2,9 +2,13 @@ package historian
 
 import (
 	"context"
+	"fmt"
+	"sort"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
@@ -14,3 +18,65 @@ import (
 type Querier interface {
 	Query(ctx context.Context, query models.HistoryQuery) (*data.Frame, error)
 }
+
+// ErrUnsupportedHistoryQuery is returned when the state history backend cannot apply a filter of the query.
+var ErrUnsupportedHistoryQuery = errutil.BadRequest("alerting.state-history.unsupportedQuery").MustTemplate(
+	"{{.Public.Reason}}",
+	errutil.WithPublic("{{.Public.Reason}}"),
+)
+
+func newErrUnsupportedHistoryQuery(reason string) error {
+	return ErrUnsupportedHistoryQuery.Build(errutil.TemplateData{
+		Public: map[string]any{
+			"Reason": reason,
+		},
+	})
+}
+
+// getFolderUIDsForFilter returns the UIDs of the folders the user can read rules in, which the history must be filtered by.
+// It returns nil if the user can read all rules, or if the query is filtered by a rule the user has access to.
+func getFolderUIDsForFilter(ctx context.Context, query models.HistoryQuery, ac AccessControl, ruleStore RuleStore) ([]string, error) {
+	bypass, err := ac.CanReadAllRules(ctx, query.SignedInUser)
+	if err != nil {
+		return nil, err
+	}
+	if bypass { // if user has access to all rules and folder, remove filter
+		return nil, nil
+	}
+	// if there is a filter by rule UID, find that rule UID and make sure that user has access to it.
+	if query.RuleUID != "" {
+		rule, err := ruleStore.GetAlertRuleByUID(ctx, &models.GetAlertRuleByUIDQuery{
+			UID:   query.RuleUID,
+			OrgID: query.OrgID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch alert rule by UID: %w", err)
+		}
+		if rule == nil {
+			return nil, models.ErrAlertRuleNotFound
+		}
+		return nil, ac.AuthorizeAccessInFolder(ctx, query.SignedInUser, rule)
+	}
+	// if no filter, then we need to get all namespaces user has access to
+	folders, err := ruleStore.GetUserVisibleNamespaces(ctx, query.OrgID, query.SignedInUser)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch folders that user can access: %w", err)
+	}
+	uids := make([]string, 0, len(folders))
+	// now keep only UIDs of folder in which user can read rules.
+	for _, f := range folders {
+		hasAccess, err := ac.HasAccessInFolder(ctx, query.SignedInUser, models.Namespace(*f))
+		if err != nil {
+			return nil, err
+		}
+		if !hasAccess {
+			continue
+		}
+		uids = append(uids, f.UID)
+	}
+	if len(uids) == 0 {
+		return nil, accesscontrol.NewAuthorizationErrorGeneric("read rules in any folder")
+	}
+	sort.Strings(uids)
+	return uids, nil
+}
@@ -0,0 +1,445 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+const (
+	stateHistoryTable      = "alert_state_history"
+	stateHistoryLabelTable = "alert_state_history_label"
+	// maxIndexedLabelLength is the maximum length of the name and the value of a label that is indexed.
+	// It matches the size of the columns of the label table.
+	maxIndexedLabelLength = 190
+	// maxFolderFilterSize is the maximum number of folders the query is filtered by in the database.
+	// Queries of users that can access more folders are filtered after they are fetched.
+	maxFolderFilterSize = 500
+)
+
+// stateHistoryRow is a state transition stored in the alert_state_history table.
+type stateHistoryRow struct {
+	ID            int64  `xorm:"pk autoincr 'id'"`
+	OrgID         int64  `xorm:"org_id"`
+	RuleUID       string `xorm:"rule_uid"`
+	FolderUID     string `xorm:"folder_uid"`
+	RuleGroup     string `xorm:"rule_group"`
+	DashboardUID  string `xorm:"dashboard_uid"`
+	PanelID       int64  `xorm:"panel_id"`
+	Fingerprint   string `xorm:"fingerprint"`
+	PreviousState string `xorm:"previous_state"`
+	CurrentState  string `xorm:"current_state"`
+	// Epoch is the time of the transition in nanoseconds since the Unix epoch.
+	Epoch int64  `xorm:"epoch"`
+	Line  string `xorm:"line"`
+}
+
+// stateHistoryLabelRow is a label of an alert instance, stored in the alert_state_history_label table to filter history by labels.
+type stateHistoryLabelRow struct {
+	ID        int64  `xorm:"pk autoincr 'id'"`
+	HistoryID int64  `xorm:"history_id"`
+	OrgID     int64  `xorm:"org_id"`
+	Key       string `xorm:"label_key"`
+	Value     string `xorm:"label_value"`
+}
+
+// SQLBackend is a state.Historian that records state history to dedicated tables of the Grafana database.
+// Unlike the annotation backend, history can be queried across rules and filtered by the labels of the alert instances.
+type SQLBackend struct {
+	store     db.DB
+	clock     clock.Clock
+	metrics   *metrics.Historian
+	log       log.Logger
+	ac        AccessControl
+	ruleStore RuleStore
+}
+
+func NewSQLBackend(logger log.Logger, store db.DB, metrics *metrics.Historian, ruleStore RuleStore, ac AccessControl) *SQLBackend {
+	return &SQLBackend{
+		store:     store,
+		clock:     clock.New(),
+		metrics:   metrics,
+		log:       logger,
+		ac:        ac,
+		ruleStore: ruleStore,
+	}
+}
+
+// Record writes a number of state transitions for a given rule to the database.
+func (h *SQLBackend) Record(ctx context.Context, rule history_model.RuleMeta, states []state.StateTransition) <-chan error {
+	logger := h.log.FromContext(ctx)
+	// Build the rows before starting goroutine, to make sure all data is copied and won't mutate underneath us.
+	rows, rowLabels := buildStateHistoryRows(rule, states, logger)
+
+	errCh := make(chan error, 1)
+	if len(rows) == 0 {
+		close(errCh)
+		return errCh
+	}
+
+	// This is a new background job, so let's create a brand new context for it.
+	// We want it to be isolated, i.e. we don't want grafana shutdowns to interrupt this work
+	// immediately but rather try to flush writes.
+	// This also prevents timeouts or other lingering objects (like transactions) from being
+	// incorrectly propagated here from other areas.
+	writeCtx := context.Background()
+	writeCtx, cancel := context.WithTimeout(writeCtx, StateHistoryWriteTimeout)
+	writeCtx = history_model.WithRuleData(writeCtx, rule)
+	writeCtx = trace.ContextWithSpan(writeCtx, trace.SpanFromContext(ctx))
+
+	go func(ctx context.Context) {
+		defer cancel()
+		defer close(errCh)
+		logger := h.log.FromContext(ctx)
+		logger.Debug("Saving state history batch", "samples", len(rows))
+		org := fmt.Sprint(rule.OrgID)
+		h.metrics.WritesTotal.WithLabelValues(org, "sql").Inc()
+		h.metrics.TransitionsTotal.WithLabelValues(org).Add(float64(len(rows)))
+
+		if err := h.save(ctx, rows, rowLabels); err != nil {
+			logger.Error("Failed to save alert state history batch", "error", err)
+			h.metrics.WritesFailed.WithLabelValues(org, "sql").Inc()
+			h.metrics.TransitionsFailed.WithLabelValues(org).Add(float64(len(rows)))
+			errCh <- fmt.Errorf("failed to save alert state history batch: %w", err)
+			return
+		}
+		logger.Debug("Done saving alert state history batch", "samples", len(rows))
+	}(writeCtx)
+	return errCh
+}
+
+func (h *SQLBackend) save(ctx context.Context, rows []*stateHistoryRow, rowLabels [][]stateHistoryLabelRow) error {
+	return h.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var lbls []stateHistoryLabelRow
+		for i, row := range rows {
+			if _, err := sess.Table(stateHistoryTable).Insert(row); err != nil {
+				return err
+			}
+			for _, lbl := range rowLabels[i] {
+				lbl.HistoryID = row.ID
+				lbls = append(lbls, lbl)
+			}
+		}
+		if len(lbls) == 0 {
+			return nil
+		}
+		_, err := sess.BulkInsert(stateHistoryLabelTable, lbls, sqlstore.NativeSettingsForDialect(h.store.GetDialect()))
+		return err
+	})
+}
+
+// Query fetches state history from the database and formats it into a dataframe with the same shape as the Loki backend.
+// The most recent entries that match the query are returned in ascending order of time. Query.Offset skips the most recent
+// entries, which allows paging through history with Query.Limit.
+func (h *SQLBackend) Query(ctx context.Context, query models.HistoryQuery) (*data.Frame, error) {
+	uids, err := getFolderUIDsForFilter(ctx, query, h.ac, h.ruleStore)
+	if err != nil {
+		return nil, err
+	}
+
+	now := h.clock.Now().UTC()
+	if query.To.IsZero() {
+		query.To = now
+	}
+	if query.From.IsZero() {
+		query.From = now.Add(-defaultQueryRange)
+	}
+	if query.Limit < 1 {
+		query.Limit = defaultPageSize
+	}
+	if query.Limit > maximumPageSize {
+		query.Limit = maximumPageSize
+	}
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+
+	matchers := queryMatchers(query)
+	filter := newStateHistoryFilter(query, matchers, uids)
+
+	// Matchers that cannot be checked with the label index, and folders that are not filtered in the database,
+	// are checked after the rows are fetched. Rows are fetched in batches until the requested page is full.
+	want := query.Offset + query.Limit
+	var (
+		matched []*stateHistoryRow
+		cursor  *stateHistoryRow
+	)
+	for len(matched) < want {
+		rows, err := h.fetch(ctx, filter, cursor, want)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			ok, err := filter.matches(row)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				matched = append(matched, row)
+			}
+		}
+		if len(rows) < want {
+			break
+		}
+		cursor = rows[len(rows)-1]
+	}
+
+	if len(matched) <= query.Offset {
+		matched = nil
+	} else {
+		matched = matched[query.Offset:min(len(matched), want)]
+	}
+	// Rows are fetched in descending order of time.
+	slices.Reverse(matched)
+	return stateHistoryRowsToFrame(matched)
+}
+
+func (h *SQLBackend) fetch(ctx context.Context, filter *stateHistoryFilter, cursor *stateHistoryRow, limit int) ([]*stateHistoryRow, error) {
+	var rows []*stateHistoryRow
+	err := h.store.WithDbSession(ctx, func(sess *db.Session) error {
+		sql, args := filter.toSQL(cursor)
+		sql += " ORDER BY h.epoch DESC, h.id DESC" + h.store.GetDialect().Limit(int64(limit))
+		return sess.SQL(sql, args...).Find(&rows)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query state history: %w", err)
+	}
+	return rows, nil
+}
+
+// DeleteExpiredSQLStateHistory deletes the state history recorded by the SQL backend before the given time.
+// Rows are deleted in batches of batchSize to avoid long-running transactions.
+func DeleteExpiredSQLStateHistory(ctx context.Context, store db.DB, before time.Time, batchSize int) (int64, error) {
+	var deleted int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return deleted, err
+		}
+		var ids []int64
+		err := store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+			err := sess.SQL(
+				"SELECT id FROM "+stateHistoryTable+" WHERE epoch < ? ORDER BY epoch ASC"+store.GetDialect().Limit(int64(batchSize)),
+				before.UnixNano(),
+			).Find(&ids)
+			if err != nil || len(ids) == 0 {
+				return err
+			}
+			if _, err := sess.Table(stateHistoryLabelTable).In("history_id", ids).Delete(&stateHistoryLabelRow{}); err != nil {
+				return err
+			}
+			_, err = sess.Table(stateHistoryTable).In("id", ids).Delete(&stateHistoryRow{})
+			return err
+		})
+		if err != nil {
+			return deleted, err
+		}
+		deleted += int64(len(ids))
+		if len(ids) < batchSize {
+			return deleted, nil
+		}
+	}
+}
+
+func buildStateHistoryRows(rule history_model.RuleMeta, states []state.StateTransition, logger log.Logger) ([]*stateHistoryRow, [][]stateHistoryLabelRow) {
+	rows := make([]*stateHistoryRow, 0, len(states))
+	rowLabels := make([][]stateHistoryLabelRow, 0, len(states))
+	for _, st := range states {
+		if !shouldRecord(st) {
+			continue
+		}
+
+		sanitizedLabels := removePrivateLabels(st.Labels)
+		entry := LokiEntry{
+			SchemaVersion:   1,
+			Previous:        st.PreviousFormatted(),
+			Current:         st.Formatted(),
+			Values:          valuesAsDataBlob(st.State),
+			Condition:       rule.Condition,
+			DashboardUID:    rule.DashboardUID,
+			PanelID:         rule.PanelID,
+			Fingerprint:     labelFingerprint(sanitizedLabels),
+			RuleTitle:       rule.Title,
+			RuleID:          rule.ID,
+			RuleUID:         rule.UID,
+			InstanceLabels:  sanitizedLabels,
+			Acknowledgement: st.Acknowledgement,
+		}
+		if st.State.State == eval.Error {
+			entry.Error = st.Error.Error()
+		}
+
+		line, err := json.Marshal(entry)
+		if err != nil {
+			logger.Error("Failed to construct history record for state, skipping", "error", err)
+			continue
+		}
+
+		rows = append(rows, &stateHistoryRow{
+			OrgID:         rule.OrgID,
+			RuleUID:       rule.UID,
+			FolderUID:     rule.NamespaceUID,
+			RuleGroup:     rule.Group,
+			DashboardUID:  rule.DashboardUID,
+			PanelID:       rule.PanelID,
+			Fingerprint:   entry.Fingerprint,
+			PreviousState: entry.Previous,
+			CurrentState:  entry.Current,
+			Epoch:         st.State.LastEvaluationTime.UnixNano(),
+			Line:          string(line),
+		})
+
+		lbls := make([]stateHistoryLabelRow, 0, len(sanitizedLabels))
+		for k, v := range sanitizedLabels {
+			// Labels that do not fit in the index are still matched, but after the rows are fetched.
+			if !isIndexable(k) || !isIndexable(v) {
+				continue
+			}
+			lbls = append(lbls, stateHistoryLabelRow{OrgID: rule.OrgID, Key: k, Value: v})
+		}
+		rowLabels = append(rowLabels, lbls)
+	}
+	return rows, rowLabels
+}
+
+func isIndexable(s string) bool {
+	return utf8.RuneCountInString(s) <= maxIndexedLabelLength
+}
+
+// queryMatchers returns the matchers of the query, including the equality matchers of Query.Labels.
+func queryMatchers(query models.HistoryQuery) labels.Matchers {
+	matchers := make(labels.Matchers, 0, len(query.Labels)+len(query.Matchers))
+	for k, v := range query.Labels {
+		matchers = append(matchers, &labels.Matcher{Type: labels.MatchEqual, Name: k, Value: v})
+	}
+	matchers = append(matchers, query.Matchers...)
+	return matchers
+}
+
+// stateHistoryFilter filters state history by the fields of the query.
+type stateHistoryFilter struct {
+	query    models.HistoryQuery
+	matchers labels.Matchers
+	folders  []string
+	// folderSet is set when there are too many folders to filter by in the database.
+	folderSet map[string]struct{}
+}
+
+func newStateHistoryFilter(query models.HistoryQuery, matchers labels.Matchers, folders []string) *stateHistoryFilter {
+	f := &stateHistoryFilter{query: query, matchers: matchers, folders: folders}
+	if len(folders) > maxFolderFilterSize {
+		f.folders = nil
+		f.folderSet = make(map[string]struct{}, len(folders))
+		for _, uid := range folders {
+			f.folderSet[uid] = struct{}{}
+		}
+	}
+	return f
+}
+
+// toSQL returns the query of the rows that match the filter, and precede the cursor if it is not nil.
+// Equality matchers are checked with the label index, the other matchers are checked by matches.
+func (f *stateHistoryFilter) toSQL(cursor *stateHistoryRow) (string, []any) {
+	var sql strings.Builder
+	args := []any{f.query.OrgID, f.query.From.UnixNano(), f.query.To.UnixNano()}
+	sql.WriteString("SELECT h.* FROM " + stateHistoryTable + " AS h WHERE h.org_id = ? AND h.epoch >= ? AND h.epoch <= ?")
+
+	if f.query.RuleUID != "" {
+		sql.WriteString(" AND h.rule_uid = ?")
+		args = append(args, f.query.RuleUID)
+	}
+	if f.query.DashboardUID != "" {
+		sql.WriteString(" AND h.dashboard_uid = ?")
+		args = append(args, f.query.DashboardUID)
+	}
+	if f.query.PanelID != 0 {
+		sql.WriteString(" AND h.panel_id = ?")
+		args = append(args, f.query.PanelID)
+	}
+	if len(f.folders) > 0 {
+		sql.WriteString(" AND h.folder_uid IN (?" + strings.Repeat(",?", len(f.folders)-1) + ")")
+		for _, uid := range f.folders {
+			args = append(args, uid)
+		}
+	}
+	for _, m := range f.matchers {
+		if m.Type != labels.MatchEqual || m.Value == "" || !isIndexable(m.Name) || !isIndexable(m.Value) {
+			continue
+		}
+		sql.WriteString(" AND EXISTS (SELECT 1 FROM " + stateHistoryLabelTable + " AS l WHERE l.history_id = h.id AND l.org_id = ? AND l.label_key = ? AND l.label_value = ?)")
+		args = append(args, f.query.OrgID, m.Name, m.Value)
+	}
+	if cursor != nil {
+		sql.WriteString(" AND (h.epoch < ? OR (h.epoch = ? AND h.id < ?))")
+		args = append(args, cursor.Epoch, cursor.Epoch, cursor.ID)
+	}
+	return sql.String(), args
+}
+
+// matches checks the conditions of the filter that are not checked in the database.
+func (f *stateHistoryFilter) matches(row *stateHistoryRow) (bool, error) {
+	if f.folderSet != nil {
+		if _, ok := f.folderSet[row.FolderUID]; !ok {
+			return false, nil
+		}
+	}
+	if len(f.matchers) == 0 {
+		return true, nil
+	}
+	var entry LokiEntry
+	if err := json.Unmarshal([]byte(row.Line), &entry); err != nil {
+		return false, fmt.Errorf("failed to unmarshal entry: %w", err)
+	}
+	for _, m := range f.matchers {
+		if !m.Matches(entry.InstanceLabels[m.Name]) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// stateHistoryRowsToFrame formats the rows into the same dataframe as the Loki backend.
+func stateHistoryRowsToFrame(rows []*stateHistoryRow) (*data.Frame, error) {
+	frame := data.NewFrame("states")
+	lbls := data.Labels(map[string]string{})
+
+	times := make([]time.Time, 0, len(rows))
+	lines := make([]json.RawMessage, 0, len(rows))
+	labelsJSON := make([]json.RawMessage, 0, len(rows))
+	for _, row := range rows {
+		streamLbls, err := json.Marshal(map[string]string{
+			StateHistoryLabelKey: StateHistoryLabelValue,
+			OrgIDLabel:           fmt.Sprint(row.OrgID),
+			GroupLabel:           row.RuleGroup,
+			FolderUIDLabel:       row.FolderUID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize stream labels: %w", err)
+		}
+		times = append(times, time.Unix(0, row.Epoch))
+		lines = append(lines, json.RawMessage(row.Line))
+		labelsJSON = append(labelsJSON, streamLbls)
+	}
+
+	frame.Fields = append(frame.Fields, data.NewField(dfTime, lbls, times))
+	frame.Fields = append(frame.Fields, data.NewField(dfLine, lbls, lines))
+	frame.Fields = append(frame.Fields, data.NewField(dfLabels, lbls, labelsJSON))
+	return frame, nil
+}
@@ -0,0 +1,259 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/folder"
+	acfakes "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol/fakes"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationSQLBackend(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	usr := &user.SignedInUser{OrgID: 1}
+
+	setup := func(t *testing.T) (*SQLBackend, *acfakes.FakeRuleService, *fakes.RuleStore) {
+		t.Helper()
+		store := db.InitTestDB(t)
+		rules := fakes.NewRuleStore(t)
+		ac := &acfakes.FakeRuleService{
+			CanReadAllRulesFunc: func(ctx context.Context, requester identity.Requester) (bool, error) {
+				return true, nil
+			},
+		}
+		h := NewSQLBackend(log.NewNopLogger(), store, metrics.NewHistorianMetrics(prometheus.NewRegistry(), metrics.Subsystem), rules, ac)
+		clk := clock.NewMock()
+		clk.Set(start.Add(time.Hour))
+		h.clock = clk
+
+		rule := createTestRule()
+		var transitions []state.StateTransition
+		for i, team := range []string{"ops", "dev", "ops", "db", "ops"} {
+			transitions = append(transitions, state.StateTransition{
+				PreviousState: eval.Normal,
+				State: &state.State{
+					State:              eval.Alerting,
+					Labels:             data.Labels{"team": team, "instance": strings.Repeat("i", i+1), "__private__": "x"},
+					LastEvaluationTime: start.Add(time.Duration(i) * time.Minute),
+				},
+			})
+		}
+		require.NoError(t, <-h.Record(context.Background(), rule, transitions))
+
+		other := createTestRule()
+		other.UID = "other-rule"
+		other.NamespaceUID = "other-folder"
+		require.NoError(t, <-h.Record(context.Background(), other, singleFromNormal(&state.State{
+			State:              eval.Alerting,
+			Labels:             data.Labels{"team": "ops"},
+			LastEvaluationTime: start.Add(10 * time.Minute),
+		})))
+		return h, ac, rules
+	}
+
+	instances := func(t *testing.T, frame *data.Frame) []string {
+		t.Helper()
+		require.Len(t, frame.Fields, 3)
+		var result []string
+		for i := 0; i < frame.Rows(); i++ {
+			var entry LokiEntry
+			require.NoError(t, json.Unmarshal(frame.Fields[1].At(i).(json.RawMessage), &entry))
+			require.NotContains(t, entry.InstanceLabels, "__private__")
+			result = append(result, entry.RuleUID+"/"+entry.InstanceLabels["instance"])
+		}
+		return result
+	}
+
+	h, ac, rules := setup(t)
+
+	t.Run("returns history of all rules in ascending order", func(t *testing.T) {
+		frame, err := h.Query(context.Background(), models.HistoryQuery{OrgID: 1, SignedInUser: usr})
+		require.NoError(t, err)
+		require.Equal(t, []string{"rule-uid/i", "rule-uid/ii", "rule-uid/iii", "rule-uid/iiii", "rule-uid/iiiii", "other-rule/"}, instances(t, frame))
+
+		require.Equal(t, start, frame.Fields[0].At(0).(time.Time).UTC())
+		var lbls map[string]string
+		require.NoError(t, json.Unmarshal(frame.Fields[2].At(0).(json.RawMessage), &lbls))
+		require.Equal(t, map[string]string{
+			StateHistoryLabelKey: StateHistoryLabelValue,
+			OrgIDLabel:           "1",
+			GroupLabel:           "my-group",
+			FolderUIDLabel:       "my-folder",
+		}, lbls)
+	})
+
+	t.Run("filters by rule and time range", func(t *testing.T) {
+		frame, err := h.Query(context.Background(), models.HistoryQuery{
+			OrgID:        1,
+			RuleUID:      "rule-uid",
+			From:         start.Add(time.Minute),
+			To:           start.Add(3 * time.Minute),
+			SignedInUser: usr,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"rule-uid/ii", "rule-uid/iii", "rule-uid/iiii"}, instances(t, frame))
+	})
+
+	t.Run("filters by labels and matchers", func(t *testing.T) {
+		frame, err := h.Query(context.Background(), models.HistoryQuery{OrgID: 1, Labels: map[string]string{"team": "ops"}, SignedInUser: usr})
+		require.NoError(t, err)
+		require.Equal(t, []string{"rule-uid/i", "rule-uid/iii", "rule-uid/iiiii", "other-rule/"}, instances(t, frame))
+
+		frame, err = h.Query(context.Background(), models.HistoryQuery{
+			OrgID: 1,
+			Matchers: labels.Matchers{
+				newMatcher(t, labels.MatchRegexp, "team", "d.*"),
+				newMatcher(t, labels.MatchNotEqual, "instance", "ii"),
+			},
+			SignedInUser: usr,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"rule-uid/iiii"}, instances(t, frame))
+
+		frame, err = h.Query(context.Background(), models.HistoryQuery{
+			OrgID:        1,
+			Matchers:     labels.Matchers{newMatcher(t, labels.MatchEqual, "instance", "")},
+			SignedInUser: usr,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"other-rule/"}, instances(t, frame))
+	})
+
+	t.Run("pages through history from the most recent entries", func(t *testing.T) {
+		query := models.HistoryQuery{OrgID: 1, RuleUID: "rule-uid", Limit: 2, SignedInUser: usr}
+		var pages [][]string
+		for offset := 0; offset < 6; offset += 2 {
+			query.Offset = offset
+			frame, err := h.Query(context.Background(), query)
+			require.NoError(t, err)
+			pages = append(pages, instances(t, frame))
+		}
+		require.Equal(t, [][]string{
+			{"rule-uid/iiii", "rule-uid/iiiii"},
+			{"rule-uid/ii", "rule-uid/iii"},
+			{"rule-uid/i"},
+		}, pages)
+
+		query = models.HistoryQuery{
+			OrgID:        1,
+			Matchers:     labels.Matchers{newMatcher(t, labels.MatchRegexp, "team", "ops")},
+			Limit:        1,
+			Offset:       2,
+			SignedInUser: usr,
+		}
+		frame, err := h.Query(context.Background(), query)
+		require.NoError(t, err)
+		require.Equal(t, []string{"rule-uid/iii"}, instances(t, frame))
+	})
+
+	t.Run("filters by folders the user can access", func(t *testing.T) {
+		ac.CanReadAllRulesFunc = func(ctx context.Context, requester identity.Requester) (bool, error) {
+			return false, nil
+		}
+		ac.HasAccessInFolderFunc = func(ctx context.Context, requester identity.Requester, namespaced models.Namespaced) (bool, error) {
+			return namespaced.GetNamespaceUID() == "other-folder", nil
+		}
+		rules.Rules = map[int64][]*models.AlertRule{
+			1: {models.RuleGen.With(models.RuleMuts.WithNamespaceUID("other-folder")).GenerateRef()},
+		}
+		rules.Folders = map[int64][]*folder.Folder{
+			1: {{UID: "my-folder", OrgID: 1}, {UID: "other-folder", OrgID: 1}},
+		}
+		t.Cleanup(func() {
+			ac.CanReadAllRulesFunc = func(ctx context.Context, requester identity.Requester) (bool, error) {
+				return true, nil
+			}
+		})
+
+		frame, err := h.Query(context.Background(), models.HistoryQuery{OrgID: 1, SignedInUser: usr})
+		require.NoError(t, err)
+		require.Equal(t, []string{"other-rule/"}, instances(t, frame))
+	})
+
+	t.Run("does not return history of other organizations", func(t *testing.T) {
+		frame, err := h.Query(context.Background(), models.HistoryQuery{OrgID: 2, SignedInUser: usr})
+		require.NoError(t, err)
+		require.Zero(t, frame.Rows())
+	})
+
+	t.Run("deletes expired history", func(t *testing.T) {
+		deleted, err := DeleteExpiredSQLStateHistory(context.Background(), h.store, start.Add(2*time.Minute), 2)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, deleted)
+
+		frame, err := h.Query(context.Background(), models.HistoryQuery{OrgID: 1, Labels: map[string]string{"team": "ops"}, SignedInUser: usr})
+		require.NoError(t, err)
+		require.Equal(t, []string{"rule-uid/iii", "rule-uid/iiiii", "other-rule/"}, instances(t, frame))
+
+		var orphans int64
+		err = h.store.WithDbSession(context.Background(), func(sess *db.Session) error {
+			_, err := sess.SQL("SELECT COUNT(*) FROM alert_state_history_label WHERE history_id NOT IN (SELECT id FROM alert_state_history)").Get(&orphans)
+			return err
+		})
+		require.NoError(t, err)
+		require.Zero(t, orphans)
+	})
+}
+
+func TestBuildStateHistoryRows(t *testing.T) {
+	rule := createTestRule()
+	long := strings.Repeat("x", maxIndexedLabelLength+1)
+	rows, rowLabels := buildStateHistoryRows(rule, []state.StateTransition{
+		{
+			PreviousState: eval.Normal,
+			State: &state.State{
+				State:  eval.Alerting,
+				Labels: data.Labels{"a": "b", "long": long, "__private__": "x"},
+			},
+		},
+		{
+			// Not recorded, the state did not change.
+			PreviousState: eval.Normal,
+			State:         &state.State{State: eval.Normal},
+		},
+	}, log.NewNopLogger())
+
+	require.Len(t, rows, 1)
+	require.Equal(t, rule.UID, rows[0].RuleUID)
+	require.Equal(t, rule.NamespaceUID, rows[0].FolderUID)
+	require.Equal(t, "Normal", rows[0].PreviousState)
+	require.Equal(t, "Alerting", rows[0].CurrentState)
+
+	entry := requireEntry(t, Sample{V: rows[0].Line})
+	require.Equal(t, map[string]string{"a": "b", "long": long}, entry.InstanceLabels)
+	require.Equal(t, []stateHistoryLabelRow{{OrgID: rule.OrgID, Key: "a", Value: "b"}}, rowLabels[0])
+}
+
+func newMatcher(t *testing.T, mt labels.MatchType, name, value string) *labels.Matcher {
+	t.Helper()
+	m, err := labels.NewMatcher(mt, name, value)
+	require.NoError(t, err)
+	return m
+}
@@ -2,9 +2,11 @@ package state
 
 import (
 	"context"
+	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/benbjohnson/clock"
@@ -12,6 +14,7 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
@@ -58,6 +61,9 @@ type Manager struct {
 	rulesPerRuleGroupLimit         int64
 
 	persister StatePersister
+
+	// ruleMtx serializes the changes of the states of a rule, see lockRule.
+	ruleMtx sync.Map
 }
 
 type ManagerCfg struct {
@@ -217,7 +223,13 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 				Annotations:          ruleForEntry.Annotations,
 				ResultFingerprint:    resultFp,
 				ResolvedAt:           entry.ResolvedAt,
+				KeepFiringSince:      entry.KeepFiringSince,
+				Acknowledgement:      acknowledgementFromInstance(entry),
 				LastSentAt:           entry.LastSentAt,
+				Hysteresis: expr.HysteresisSeriesState{
+					Level:     int(entry.HysteresisLevel),
+					Unloading: int(entry.HysteresisUnloading),
+				},
 			}
 			statesCount++
 		}
@@ -302,6 +314,78 @@ func (st *Manager) ResetStateByRuleUID(ctx context.Context, rule *ngModels.Alert
 	return transitions
 }
 
+// lockRule locks the states of the rule and returns the function that unlocks them. The states are changed in place by
+// the evaluation of the rule, changes made outside of it must hold the lock.
+func (st *Manager) lockRule(key ngModels.AlertRuleKey) func() {
+	mtx, _ := st.ruleMtx.LoadOrStore(key, &sync.Mutex{})
+	mtx.(*sync.Mutex).Lock()
+	return mtx.(*sync.Mutex).Unlock
+}
+
+// Acknowledge sets the acknowledgement of the firing state of the rule with the given cache ID, or removes it if ack
+// is nil. The time of the acknowledgement is set to the current time. The state is saved to the database right away
+// and the change is recorded in the state history. The next notification sent to the Alertmanager carries the
+// acknowledgement. It returns a copy of the changed state.
+func (st *Manager) Acknowledge(ctx context.Context, rule *ngModels.AlertRule, cacheID data.Fingerprint, ack *ngModels.Acknowledgement) (*State, error) {
+	logger := st.log.FromContext(ctx).New(append(rule.GetKey().LogContext(), "cacheID", cacheID)...)
+	unlock := st.lockRule(rule.GetKey())
+	defer unlock()
+	s := st.cache.get(rule.OrgID, rule.UID, cacheID)
+	if s == nil {
+		return nil, ngModels.ErrAlertInstanceNotFound.Errorf("alert instance %s of rule %s not found", cacheID, rule.UID)
+	}
+	if s.State != eval.Alerting {
+		return nil, ngModels.ErrAlertInstanceNotFiring.Errorf("alert instance %s of rule %s is %s", cacheID, rule.UID, s.State)
+	}
+
+	now := st.clock.Now()
+	if ack != nil {
+		ack.At = now
+		if err := ack.Validate(now); err != nil {
+			return nil, err
+		}
+	}
+
+	oldReason := s.StateReason
+	s.StateReason = withoutAcknowledgedReason(s.StateReason)
+	s.Acknowledgement = ack
+	if ack != nil {
+		s.StateReason = withAcknowledgedReason(s.StateReason)
+	}
+	// Make sure the acknowledgement is sent to the Alertmanager with the next evaluation.
+	s.LastSentAt = nil
+	st.cache.set(s)
+
+	if st.instanceStore != nil {
+		key, err := s.GetAlertInstanceKey()
+		if err != nil {
+			return nil, err
+		}
+		if err := st.instanceStore.SaveAlertInstance(ctx, s.ToAlertInstance(key)); err != nil {
+			return nil, fmt.Errorf("failed to save the state: %w", err)
+		}
+	}
+	logger.Info("Alert instance acknowledgement changed", "acknowledged", ack != nil)
+
+	if st.historian != nil && oldReason != s.StateReason {
+		// Record the transition at the time of the acknowledgement rather than the time of the last evaluation.
+		recorded := *s
+		recorded.LastEvaluationTime = now
+		errCh := st.historian.Record(ctx, history_model.NewRuleMeta(rule, st.log), []StateTransition{{
+			State:               &recorded,
+			PreviousState:       s.State,
+			PreviousStateReason: oldReason,
+		}})
+		go func() {
+			if err := <-errCh; err != nil {
+				logger.Error("Error recording acknowledgement in state history", "error", err)
+			}
+		}()
+	}
+	acked := *s
+	return &acked, nil
+}
+
 // ProcessEvalResults updates the current states that belong to a rule with the evaluation results.
 // if extraLabels is not empty, those labels will be added to every state. The extraLabels take precedence over rule labels and result labels
 // This will update the states in cache/store and return the state transitions that need to be sent to the alertmanager.
@@ -322,9 +406,19 @@ func (st *Manager) ProcessEvalResults(
 		attribute.Int("results", len(results))))
 	defer span.End()
 
+	unlock := st.lockRule(alertRule.GetKey())
+	defer unlock()
+
 	logger := st.log.FromContext(ctx)
 	logger.Debug("State manager processing evaluation results", "resultCount", len(results))
-	states := st.setNextStateForRule(ctx, alertRule, results, extraLabels, logger)
+	inhibitedBy := st.firingDependencies(alertRule)
+	if len(inhibitedBy) > 0 {
+		logger.Debug("Rules the alert rule depends on are firing", "inhibitedBy", inhibitedBy)
+		span.AddEvent("rule inhibited", trace.WithAttributes(
+			attribute.StringSlice("inhibited_by", inhibitedBy),
+		))
+	}
+	states := st.setNextStateForRule(ctx, alertRule, results, extraLabels, inhibitedBy, logger)
 
 	staleStates := st.deleteStaleStatesFromCache(ctx, logger, evaluatedAt, alertRule)
 	span.AddEvent("results processed", trace.WithAttributes(
@@ -368,7 +462,21 @@ func (st *Manager) updateLastSentAt(states StateTransitions, evaluatedAt time.Ti
 	return result
 }
 
-func (st *Manager) setNextStateForRule(ctx context.Context, alertRule *ngModels.AlertRule, results eval.Results, extraLabels data.Labels, logger log.Logger) []StateTransition {
+// firingDependencies returns the UIDs of the rules the alert rule depends on that have at least one Alerting state.
+func (st *Manager) firingDependencies(alertRule *ngModels.AlertRule) []string {
+	var firing []string
+	for _, uid := range alertRule.DependsOn {
+		for _, s := range st.cache.getStatesForRuleUID(alertRule.OrgID, uid, false) {
+			if s.State == eval.Alerting {
+				firing = append(firing, uid)
+				break
+			}
+		}
+	}
+	return firing
+}
+
+func (st *Manager) setNextStateForRule(ctx context.Context, alertRule *ngModels.AlertRule, results eval.Results, extraLabels data.Labels, inhibitedBy []string, logger log.Logger) []StateTransition {
 	if st.applyNoDataAndErrorToAllStates && results.IsNoData() && (alertRule.NoDataState == ngModels.Alerting || alertRule.NoDataState == ngModels.OK || alertRule.NoDataState == ngModels.KeepLast) { // If it is no data, check the mapping and switch all results to the new state
 		// aggregate UID of datasources that returned NoData into one and provide as auxiliary info via annotationa. See: https://github.com/grafana/grafana/issues/88184
 		var refIds strings.Builder
@@ -392,7 +500,7 @@ func (st *Manager) setNextStateForRule(ctx context.Context, alertRule *ngModels.
 				}
 			}
 		}
-		transitions := st.setNextStateForAll(ctx, alertRule, results[0], logger)
+		transitions := st.setNextStateForAll(ctx, alertRule, results[0], inhibitedBy, logger)
 		if len(transitions) > 0 {
 			for _, t := range transitions {
 				t.State.Annotations["datasource_uid"] = datasourceUIDs.String()
@@ -403,7 +511,7 @@ func (st *Manager) setNextStateForRule(ctx context.Context, alertRule *ngModels.
 	}
 	if st.applyNoDataAndErrorToAllStates && results.IsError() && (alertRule.ExecErrState == ngModels.AlertingErrState || alertRule.ExecErrState == ngModels.OkErrState || alertRule.ExecErrState == ngModels.KeepLastErrState) {
 		// TODO squash all errors into one, and provide as annotation
-		transitions := st.setNextStateForAll(ctx, alertRule, results[0], logger)
+		transitions := st.setNextStateForAll(ctx, alertRule, results[0], inhibitedBy, logger)
 		if len(transitions) > 0 {
 			return transitions // if there are no current states for the rule. Create ones for each result
 		}
@@ -411,24 +519,25 @@ func (st *Manager) setNextStateForRule(ctx context.Context, alertRule *ngModels.
 	transitions := make([]StateTransition, 0, len(results))
 	for _, result := range results {
 		currentState := st.cache.getOrCreate(ctx, logger, alertRule, result, extraLabels, st.externalURL)
-		s := st.setNextState(ctx, alertRule, currentState, result, logger)
+		s := st.setNextState(ctx, alertRule, currentState, result, inhibitedBy, logger)
 		transitions = append(transitions, s)
 	}
 	return transitions
 }
 
-func (st *Manager) setNextStateForAll(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result, logger log.Logger) []StateTransition {
+func (st *Manager) setNextStateForAll(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result, inhibitedBy []string, logger log.Logger) []StateTransition {
 	currentStates := st.cache.getStatesForRuleUID(alertRule.OrgID, alertRule.UID, false)
 	transitions := make([]StateTransition, 0, len(currentStates))
 	for _, currentState := range currentStates {
-		t := st.setNextState(ctx, alertRule, currentState, result, logger)
+		t := st.setNextState(ctx, alertRule, currentState, result, inhibitedBy, logger)
 		transitions = append(transitions, t)
 	}
 	return transitions
 }
 
-// Set the current state based on evaluation results
-func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRule, currentState *State, result eval.Result, logger log.Logger) StateTransition {
+// Set the current state based on evaluation results. If inhibitedBy is not empty, states that would be Pending or Alerting
+// are Inhibited instead.
+func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRule, currentState *State, result eval.Result, inhibitedBy []string, logger log.Logger) StateTransition {
 	start := st.clock.Now()
 
 	currentState.LastEvaluationTime = result.EvaluatedAt
@@ -441,8 +550,12 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 		Condition:       alertRule.Condition,
 	}
 	currentState.LastEvaluationString = result.EvaluationString
+	if result.Hysteresis != nil {
+		currentState.Hysteresis = *result.Hysteresis
+	}
 	oldState := currentState.State
 	oldReason := currentState.StateReason
+	oldStartsAt := currentState.StartsAt
 
 	// Add the instance to the log context to help correlate log lines for a state
 	logger = logger.New("instance", result.Instance)
@@ -485,6 +598,18 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 		logger.Debug("Ignoring set next state as result is pending")
 	}
 
+	if len(inhibitedBy) > 0 && (currentState.State == eval.Alerting || currentState.State == eval.Pending) {
+		startsAt := result.EvaluatedAt
+		if oldState == eval.Inhibited {
+			startsAt = oldStartsAt
+		}
+		logger.Debug("Inhibiting state", "previous_state", currentState.State, "inhibitedBy", inhibitedBy)
+		// Inhibited states have the same end timestamp as Normal states
+		currentState.SetInhibited(inhibitedBy, startsAt, result.EvaluatedAt)
+	} else if currentState.State != eval.Inhibited {
+		currentState.InhibitedBy = nil
+	}
+
 	// Set reason iff: result and state are different, reason is not Alerting or Normal
 	currentState.StateReason = ""
 
@@ -494,13 +619,26 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 		currentState.StateReason = resultStateReason(result, alertRule)
 	}
 
+	if currentState.State != eval.Alerting {
+		currentState.KeepFiringSince = nil
+	} else if currentState.KeepFiringSince != nil {
+		currentState.StateReason = ngModels.StateReasonKeepFiring
+	}
+
+	// The acknowledgement is kept only while the state keeps firing.
+	if currentState.State != eval.Alerting || !currentState.Acknowledgement.IsActive(result.EvaluatedAt) {
+		currentState.Acknowledgement = nil
+	} else {
+		currentState.StateReason = withAcknowledgedReason(currentState.StateReason)
+	}
+
 	// Set Resolved property so the scheduler knows to send a postable alert
 	// to Alertmanager.
 	newlyResolved := false
-	if oldState == eval.Alerting && currentState.State == eval.Normal {
+	if oldState == eval.Alerting && (currentState.State == eval.Normal || currentState.State == eval.Inhibited) {
 		currentState.ResolvedAt = &result.EvaluatedAt
 		newlyResolved = true
-	} else if currentState.State != eval.Normal && currentState.State != eval.Pending { // Retain the last resolved time for Normal->Normal and Normal->Pending.
+	} else if currentState.State != eval.Normal && currentState.State != eval.Pending && currentState.State != eval.Inhibited { // Retain the last resolved time for Normal->Normal, Normal->Pending and Normal->Inhibited.
 		currentState.ResolvedAt = nil
 	}
 
@@ -565,6 +703,8 @@ func translateInstanceState(state ngModels.InstanceStateType) eval.State {
 		return eval.NoData
 	case ngModels.InstanceStatePending:
 		return eval.Pending
+	case ngModels.InstanceStateInhibited:
+		return eval.Inhibited
 	default:
 		return eval.Error
 	}
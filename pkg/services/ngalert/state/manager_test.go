@@ -1958,6 +1958,285 @@ func TestStaleResults(t *testing.T) {
 	})
 }
 
+func TestProcessEvalResults_KeepFiringFor(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock()
+
+	cfg := state.ManagerCfg{
+		Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		ExternalURL:   nil,
+		InstanceStore: &state.FakeInstanceStore{},
+		Images:        &state.NoopImageService{},
+		Clock:         clk,
+		Historian:     &state.FakeHistorian{},
+		Tracer:        tracing.InitializeTracerForTest(),
+		Log:           log.New("ngalert.state.manager"),
+	}
+	st := state.NewManager(cfg, state.NewNoopPersister())
+
+	gen := models.RuleGen
+	rule := gen.With(gen.WithFor(0), gen.WithKeepFiringFor(20*time.Second), gen.WithInterval(10*time.Second)).GenerateRef()
+	interval := time.Duration(rule.IntervalSeconds) * time.Second
+
+	process := func(s eval.State) *state.State {
+		t.Helper()
+		clk.Add(interval)
+		res := eval.ResultGen(eval.WithState(s), eval.WithLabels(data.Labels{"test": "keep-firing"}), eval.WithEvaluatedAt(clk.Now()))()
+		processed := st.ProcessEvalResults(ctx, clk.Now(), rule, eval.Results{res}, nil, nil)
+		require.Len(t, processed, 1)
+		return processed[0].State
+	}
+
+	s := process(eval.Alerting)
+	require.Equal(t, eval.Alerting, s.State)
+	require.Nil(t, s.KeepFiringSince)
+
+	t.Run("should keep firing until keep_firing_for elapses", func(t *testing.T) {
+		s := process(eval.Normal)
+		require.Equal(t, eval.Alerting, s.State)
+		require.Equal(t, models.StateReasonKeepFiring, s.StateReason)
+		require.NotNil(t, s.KeepFiringSince)
+		require.Equal(t, clk.Now(), *s.KeepFiringSince)
+
+		s = process(eval.Normal)
+		require.Equal(t, eval.Alerting, s.State)
+		require.Equal(t, models.StateReasonKeepFiring, s.StateReason)
+	})
+
+	t.Run("should reset when the condition fires again", func(t *testing.T) {
+		s := process(eval.Alerting)
+		require.Equal(t, eval.Alerting, s.State)
+		require.Empty(t, s.StateReason)
+		require.Nil(t, s.KeepFiringSince)
+	})
+
+	t.Run("should resolve after keep_firing_for", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			s := process(eval.Normal)
+			require.Equal(t, eval.Alerting, s.State)
+		}
+		s := process(eval.Normal)
+		require.Equal(t, eval.Normal, s.State)
+		require.Empty(t, s.StateReason)
+		require.Nil(t, s.KeepFiringSince)
+		require.NotNil(t, s.ResolvedAt)
+		require.Equal(t, clk.Now(), *s.ResolvedAt)
+	})
+}
+
+func TestAcknowledge(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock()
+	instanceStore := &state.FakeInstanceStore{}
+	fakeHistorian := &state.FakeHistorian{}
+
+	cfg := state.ManagerCfg{
+		Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		ExternalURL:   nil,
+		InstanceStore: instanceStore,
+		Images:        &state.NoopImageService{},
+		Clock:         clk,
+		Historian:     fakeHistorian,
+		Tracer:        tracing.InitializeTracerForTest(),
+		Log:           log.New("ngalert.state.manager"),
+	}
+	st := state.NewManager(cfg, state.NewNoopPersister())
+
+	gen := models.RuleGen
+	rule := gen.With(gen.WithFor(0), gen.WithInterval(10*time.Second)).GenerateRef()
+	interval := time.Duration(rule.IntervalSeconds) * time.Second
+
+	process := func(s eval.State) *state.State {
+		t.Helper()
+		clk.Add(interval)
+		res := eval.ResultGen(eval.WithState(s), eval.WithLabels(data.Labels{"test": "ack"}), eval.WithEvaluatedAt(clk.Now()))()
+		processed := st.ProcessEvalResults(ctx, clk.Now(), rule, eval.Results{res}, nil, nil)
+		require.Len(t, processed, 1)
+		return processed[0].State
+	}
+
+	s := process(eval.Normal)
+	_, err := st.Acknowledge(ctx, rule, s.CacheID, &models.Acknowledgement{By: "test", ExpiresAt: clk.Now().Add(time.Hour)})
+	require.ErrorIs(t, err, models.ErrAlertInstanceNotFiring)
+
+	_, err = st.Acknowledge(ctx, rule, s.CacheID+1, &models.Acknowledgement{By: "test", ExpiresAt: clk.Now().Add(time.Hour)})
+	require.ErrorIs(t, err, models.ErrAlertInstanceNotFound)
+
+	s = process(eval.Alerting)
+	_, err = st.Acknowledge(ctx, rule, s.CacheID, &models.Acknowledgement{By: "test", ExpiresAt: clk.Now()})
+	require.ErrorIs(t, err, models.ErrInvalidAcknowledgement)
+
+	t.Run("should acknowledge firing state and record it", func(t *testing.T) {
+		ack := &models.Acknowledgement{By: "test", Comment: "looking into it", ExpiresAt: clk.Now().Add(time.Minute)}
+		acked, err := st.Acknowledge(ctx, rule, s.CacheID, ack)
+		require.NoError(t, err)
+		require.Equal(t, ack, acked.Acknowledgement)
+		require.Equal(t, clk.Now(), acked.Acknowledgement.At)
+		require.Equal(t, models.StateReasonAcknowledged, acked.StateReason)
+		require.Nil(t, acked.LastSentAt)
+
+		ops := instanceStore.RecordedOps()
+		saved, ok := ops[len(ops)-1].(models.AlertInstance)
+		require.True(t, ok)
+		require.Equal(t, "test", saved.AcknowledgedBy)
+		require.Equal(t, "looking into it", saved.AcknowledgementComment)
+		require.Equal(t, clk.Now().Add(time.Minute), *saved.AcknowledgementExpiresAt)
+
+		require.NotEmpty(t, fakeHistorian.StateTransitions)
+		transition := fakeHistorian.StateTransitions[len(fakeHistorian.StateTransitions)-1]
+		require.Equal(t, "Alerting", transition.PreviousFormatted())
+		require.Equal(t, "Alerting (Acknowledged)", transition.Formatted())
+	})
+
+	t.Run("should keep acknowledgement while firing", func(t *testing.T) {
+		s := process(eval.Alerting)
+		require.NotNil(t, s.Acknowledgement)
+		require.Equal(t, models.StateReasonAcknowledged, s.StateReason)
+	})
+
+	t.Run("should remove acknowledgement when it expires", func(t *testing.T) {
+		clk.Add(time.Minute)
+		s := process(eval.Alerting)
+		require.Nil(t, s.Acknowledgement)
+		require.Empty(t, s.StateReason)
+	})
+
+	t.Run("should remove acknowledgement when the state is resolved", func(t *testing.T) {
+		_, err := st.Acknowledge(ctx, rule, s.CacheID, &models.Acknowledgement{By: "test", ExpiresAt: clk.Now().Add(time.Hour)})
+		require.NoError(t, err)
+		s := process(eval.Normal)
+		require.Nil(t, s.Acknowledgement)
+		require.Empty(t, s.StateReason)
+	})
+
+	t.Run("should remove acknowledgement when it is deleted", func(t *testing.T) {
+		s := process(eval.Alerting)
+		_, err := st.Acknowledge(ctx, rule, s.CacheID, &models.Acknowledgement{By: "test", ExpiresAt: clk.Now().Add(time.Hour)})
+		require.NoError(t, err)
+		s, err = st.Acknowledge(ctx, rule, s.CacheID, nil)
+		require.NoError(t, err)
+		require.Nil(t, s.Acknowledgement)
+		require.Empty(t, s.StateReason)
+	})
+}
+
+func TestAcknowledge_ConcurrentEvaluation(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock()
+
+	cfg := state.ManagerCfg{
+		Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		ExternalURL:   nil,
+		InstanceStore: &state.FakeInstanceStore{},
+		Images:        &state.NoopImageService{},
+		Clock:         clk,
+		Historian:     &state.FakeHistorian{},
+		Tracer:        tracing.InitializeTracerForTest(),
+		Log:           log.New("ngalert.state.manager"),
+	}
+	st := state.NewManager(cfg, state.NewNoopPersister())
+
+	gen := models.RuleGen
+	rule := gen.With(gen.WithFor(0), gen.WithInterval(10*time.Second)).GenerateRef()
+	evaluate := func() *state.State {
+		res := eval.ResultGen(eval.WithState(eval.Alerting), eval.WithLabels(data.Labels{"test": "ack"}), eval.WithEvaluatedAt(clk.Now()))()
+		return st.ProcessEvalResults(ctx, clk.Now(), rule, eval.Results{res}, nil, nil)[0].State
+	}
+	s := evaluate()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			evaluate()
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		var ack *models.Acknowledgement
+		if i%2 == 0 {
+			ack = &models.Acknowledgement{By: "test", ExpiresAt: clk.Now().Add(time.Hour)}
+		}
+		acked, err := st.Acknowledge(ctx, rule, s.CacheID, ack)
+		require.NoError(t, err)
+		require.Equal(t, ack, acked.Acknowledgement)
+	}
+	<-done
+
+	// The last acknowledgement removed the previous one, and no evaluation brought it back.
+	s = evaluate()
+	require.Nil(t, s.Acknowledgement)
+	require.Empty(t, s.StateReason)
+}
+
+func TestProcessEvalResults_DependsOn(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock()
+
+	cfg := state.ManagerCfg{
+		Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		ExternalURL:   nil,
+		InstanceStore: &state.FakeInstanceStore{},
+		Images:        &state.NoopImageService{},
+		Clock:         clk,
+		Historian:     &state.FakeHistorian{},
+		Tracer:        tracing.InitializeTracerForTest(),
+		Log:           log.New("ngalert.state.manager"),
+	}
+	st := state.NewManager(cfg, state.NewNoopPersister())
+
+	gen := models.RuleGen.With(models.RuleGen.WithOrgID(1), models.RuleGen.WithInterval(10*time.Second))
+	upstream := gen.With(gen.WithFor(0)).GenerateRef()
+	dependent := gen.With(gen.WithFor(0), gen.WithDependsOn(upstream.UID)).GenerateRef()
+	interval := time.Duration(dependent.IntervalSeconds) * time.Second
+
+	process := func(rule *models.AlertRule, s eval.State) *state.State {
+		t.Helper()
+		res := eval.ResultGen(eval.WithState(s), eval.WithLabels(data.Labels{"test": "depends-on"}), eval.WithEvaluatedAt(clk.Now()))()
+		processed := st.ProcessEvalResults(ctx, clk.Now(), rule, eval.Results{res}, nil, nil)
+		require.Len(t, processed, 1)
+		return processed[0].State
+	}
+
+	s := process(dependent, eval.Alerting)
+	require.Equal(t, eval.Alerting, s.State)
+	require.Nil(t, s.InhibitedBy)
+	require.Equal(t, eval.Alerting, process(upstream, eval.Alerting).State)
+
+	t.Run("should inhibit the state while the upstream rule is firing", func(t *testing.T) {
+		clk.Add(interval)
+		s := process(dependent, eval.Alerting)
+		require.Equal(t, eval.Inhibited, s.State)
+		require.Equal(t, []string{upstream.UID}, s.InhibitedBy)
+		require.Equal(t, clk.Now(), s.StartsAt)
+		require.Equal(t, clk.Now(), s.EndsAt)
+		require.NotNil(t, s.ResolvedAt, "firing state should be resolved when it is inhibited")
+		require.Equal(t, clk.Now(), *s.ResolvedAt)
+
+		startsAt := s.StartsAt
+		clk.Add(interval)
+		s = process(dependent, eval.Alerting)
+		require.Equal(t, eval.Inhibited, s.State)
+		require.Equal(t, startsAt, s.StartsAt)
+	})
+
+	t.Run("should fire again when the upstream rule resolves", func(t *testing.T) {
+		clk.Add(interval)
+		require.Equal(t, eval.Normal, process(upstream, eval.Normal).State)
+		s := process(dependent, eval.Alerting)
+		require.Equal(t, eval.Alerting, s.State)
+		require.Nil(t, s.InhibitedBy)
+		require.Nil(t, s.ResolvedAt)
+	})
+
+	t.Run("should not inhibit normal states", func(t *testing.T) {
+		clk.Add(interval)
+		require.Equal(t, eval.Alerting, process(upstream, eval.Alerting).State)
+		s := process(dependent, eval.Normal)
+		require.Equal(t, eval.Normal, s.State)
+		require.Nil(t, s.InhibitedBy)
+	})
+}
+
 func TestDeleteStateByRuleUID(t *testing.T) {
 	interval := time.Minute
 	ctx := context.Background()
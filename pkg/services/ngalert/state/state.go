@@ -41,6 +41,9 @@ type State struct {
 	// LatestResult contains the result of the most recent evaluation, if available.
 	LatestResult *Evaluation
 
+	// Hysteresis is the state of a multi-level hysteresis condition for the result.
+	Hysteresis expr.HysteresisSeriesState
+
 	// Error is set if the current evaluation returned an error. If error is non-nil results
 	// can still contain the results of previous evaluations.
 	Error error
@@ -66,9 +69,19 @@ type State struct {
 	// and states that have been resolved. It cannot be used to determine when a state was resolved.
 	EndsAt time.Time
 	// ResolvedAt is set when the state is first resolved. That is to say, when the state first transitions
-	// from Alerting, NoData, or Error to Normal. It is reset to zero when the state transitions from Normal
-	// to any other state.
-	ResolvedAt           *time.Time
+	// from Alerting, NoData, or Error to Normal, or from Alerting to Inhibited. It is reset to zero when the state
+	// transitions from Normal to any other state.
+	ResolvedAt *time.Time
+	// KeepFiringSince is set when the condition of an Alerting state is no longer met, and the state keeps firing
+	// because of the keep firing for duration of the rule. It is reset when the state stops firing or the condition
+	// is met again.
+	KeepFiringSince *time.Time
+	// InhibitedBy contains the UIDs of the rules the alert rule depends on that were firing when the state was
+	// inhibited. It is only set for Inhibited states.
+	InhibitedBy []string
+	// Acknowledgement is set when a user acknowledged the state while it was firing. It is removed when it expires
+	// or the state stops firing.
+	Acknowledgement      *models.Acknowledgement
 	LastSentAt           *time.Time
 	LastEvaluationString string
 	LastEvaluationTime   time.Time
@@ -91,6 +104,33 @@ func (a *State) GetAlertInstanceKey() (models.AlertInstanceKey, error) {
 	return models.AlertInstanceKey{RuleOrgID: a.OrgID, RuleUID: a.AlertRuleUID, LabelsHash: labelsHash}, nil
 }
 
+// ToAlertInstance converts the state to an alert instance that can be saved to the database.
+func (a *State) ToAlertInstance(key models.AlertInstanceKey) models.AlertInstance {
+	instance := models.AlertInstance{
+		AlertInstanceKey:    key,
+		Labels:              models.InstanceLabels(a.Labels),
+		CurrentState:        models.InstanceStateType(a.State.String()),
+		CurrentReason:       a.StateReason,
+		LastEvalTime:        a.LastEvaluationTime,
+		CurrentStateSince:   a.StartsAt,
+		CurrentStateEnd:     a.EndsAt,
+		ResolvedAt:          a.ResolvedAt,
+		KeepFiringSince:     a.KeepFiringSince,
+		LastSentAt:          a.LastSentAt,
+		ResultFingerprint:   a.ResultFingerprint.String(),
+		HysteresisLevel:     int64(a.Hysteresis.Level),
+		HysteresisUnloading: int64(a.Hysteresis.Unloading),
+	}
+	if ack := a.Acknowledgement; ack != nil {
+		at, expiresAt := ack.At, ack.ExpiresAt
+		instance.AcknowledgedBy = ack.By
+		instance.AcknowledgementComment = ack.Comment
+		instance.AcknowledgedAt = &at
+		instance.AcknowledgementExpiresAt = &expiresAt
+	}
+	return instance
+}
+
 // SetAlerting sets the state to Alerting. It changes both the start and end time.
 func (a *State) SetAlerting(reason string, startsAt, endsAt time.Time) {
 	a.State = eval.Alerting
@@ -127,6 +167,15 @@ func (a *State) SetError(err error, startsAt, endsAt time.Time) {
 	a.Error = err
 }
 
+// SetInhibited sets the state to Inhibited. It changes both the start and end time.
+func (a *State) SetInhibited(inhibitedBy []string, startsAt, endsAt time.Time) {
+	a.State = eval.Inhibited
+	a.InhibitedBy = inhibitedBy
+	a.StartsAt = startsAt
+	a.EndsAt = endsAt
+	a.Error = nil
+}
+
 // SetNormal sets the state to Normal. It changes both the start and end time.
 func (a *State) SetNormal(reason string, startsAt, endsAt time.Time) {
 	a.State = eval.Normal
@@ -250,7 +299,29 @@ func NewEvaluationValues(m map[string]eval.NumberValueCapture) map[string]float6
 	return result
 }
 
-func resultNormal(state *State, _ *models.AlertRule, result eval.Result, logger log.Logger, reason string) {
+func resultNormal(state *State, rule *models.AlertRule, result eval.Result, logger log.Logger, reason string) {
+	if state.State == eval.Alerting && rule.KeepFiringFor > 0 {
+		if state.KeepFiringSince == nil {
+			state.KeepFiringSince = &result.EvaluatedAt
+		}
+		// If the keep firing for duration has not been observed then the state should keep firing
+		if result.EvaluatedAt.Sub(*state.KeepFiringSince) < rule.KeepFiringFor {
+			prevEndsAt := state.EndsAt
+			state.Maintain(rule.IntervalSeconds, result.EvaluatedAt)
+			logger.Debug("Keeping state because of keep firing for",
+				"state",
+				state.State,
+				"keep_firing_since",
+				*state.KeepFiringSince,
+				"previous_ends_at",
+				prevEndsAt,
+				"next_ends_at",
+				state.EndsAt)
+			return
+		}
+	}
+	state.KeepFiringSince = nil
+
 	if state.State == eval.Normal {
 		logger.Debug("Keeping state", "state", state.State)
 	} else {
@@ -270,6 +341,9 @@ func resultNormal(state *State, _ *models.AlertRule, result eval.Result, logger
 }
 
 func resultAlerting(state *State, rule *models.AlertRule, result eval.Result, logger log.Logger, reason string) {
+	// The condition is met again so the state stops keeping firing
+	state.KeepFiringSince = nil
+
 	switch state.State {
 	case eval.Alerting:
 		prevEndsAt := state.EndsAt
@@ -438,6 +512,10 @@ func resultKeepLast(state *State, rule *models.AlertRule, result eval.Result, lo
 	case eval.Normal:
 		logger.Debug("Execution keep last state is Normal", "handler", "resultNormal")
 		resultNormal(state, rule, result, logger, reason)
+	case eval.Inhibited:
+		// the condition of inhibited states was met, so the state is kept as if the condition is met again
+		logger.Debug("Execution keep last state is Inhibited", "handler", "resultAlerting")
+		resultAlerting(state, rule, result, logger, reason)
 	default:
 		// this should not happen, add as failsafe
 		logger.Debug("Reverting invalid state to normal", "handler", "resultNormal")
@@ -461,9 +539,9 @@ func (a *State) NeedsSending(resendDelay time.Duration, resolvedRetention time.D
 		return true
 	}
 
-	// For normal states, we should only be sending if this is a resolved notification or a re-send of the resolved
-	// notification within the resolvedRetention period.
-	if a.State == eval.Normal && (a.ResolvedAt == nil || a.LastEvaluationTime.Sub(*a.ResolvedAt) > resolvedRetention) {
+	// For normal and inhibited states, we should only be sending if this is a resolved notification or a re-send of the
+	// resolved notification within the resolvedRetention period.
+	if (a.State == eval.Normal || a.State == eval.Inhibited) && (a.ResolvedAt == nil || a.LastEvaluationTime.Sub(*a.ResolvedAt) > resolvedRetention) {
 		return false
 	}
 
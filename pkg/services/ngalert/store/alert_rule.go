@@ -175,10 +175,12 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 				NoDataState:          r.NoDataState,
 				ExecErrState:         r.ExecErrState,
 				For:                  r.For,
+				KeepFiringFor:        r.KeepFiringFor,
 				Annotations:          r.Annotations,
 				Labels:               r.Labels,
 				Record:               r.Record,
 				NotificationSettings: r.NotificationSettings,
+				DependsOn:            r.DependsOn,
 			})
 		}
 		if len(newRules) > 0 {
@@ -257,9 +259,11 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 				ExecErrState:         r.New.ExecErrState,
 				Record:               r.New.Record,
 				For:                  r.New.For,
+				KeepFiringFor:        r.New.KeepFiringFor,
 				Annotations:          r.New.Annotations,
 				Labels:               r.New.Labels,
 				NotificationSettings: r.New.NotificationSettings,
+				DependsOn:            r.New.DependsOn,
 			})
 		}
 		if len(ruleVersions) > 0 {
@@ -652,6 +656,7 @@ func (st DBstore) GetAlertRulesForScheduling(ctx context.Context, query *ngmodel
 }
 
 // DeleteInFolder deletes the rules contained in a given folder along with their associated data.
+// It fails if rules in other folders depend on the deleted rules.
 func (st DBstore) DeleteInFolders(ctx context.Context, orgID int64, folderUIDs []string, user identity.Requester) error {
 	for _, folderUID := range folderUIDs {
 		evaluator := accesscontrol.EvalPermission(accesscontrol.ActionAlertingRuleDelete, dashboards.ScopeFoldersProvider.GetResourceScopeUID(folderUID))
@@ -664,27 +669,34 @@ func (st DBstore) DeleteInFolders(ctx context.Context, orgID int64, folderUIDs [
 			st.Logger.Error("user is not allowed to delete alert rules in folder", "folder", folderUID, "user")
 			return dashboards.ErrFolderAccessDenied
 		}
+	}
 
-		rules, err := st.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{
-			OrgID:         orgID,
-			NamespaceUIDs: []string{folderUID},
-		})
-		if err != nil {
-			return err
-		}
+	if len(folderUIDs) == 0 {
+		return nil
+	}
+	rules, err := st.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{
+		OrgID:         orgID,
+		NamespaceUIDs: folderUIDs,
+	})
+	if err != nil {
+		return err
+	}
 
-		uids := make([]string, 0, len(rules))
-		for _, tgt := range rules {
-			if tgt != nil {
-				uids = append(uids, tgt.UID)
-			}
+	uids := make([]string, 0, len(rules))
+	for _, tgt := range rules {
+		if tgt != nil {
+			uids = append(uids, tgt.UID)
 		}
+	}
+	if len(uids) == 0 {
+		return nil
+	}
 
-		if err := st.DeleteAlertRulesByUID(ctx, orgID, uids...); err != nil {
-			return err
-		}
+	if err := ValidateRuleDependencies(ctx, st, &GroupDelta{GroupKey: ngmodels.AlertRuleGroupKey{OrgID: orgID}, Delete: rules}); err != nil {
+		return err
 	}
-	return nil
+
+	return st.DeleteAlertRulesByUID(ctx, orgID, uids...)
 }
 
 // Kind returns the name of the alert rule type of entity.
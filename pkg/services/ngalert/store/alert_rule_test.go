@@ -580,6 +580,22 @@ func TestIntegration_DeleteInFolder(t *testing.T) {
 		require.ErrorIs(t, err, dashboards.ErrFolderAccessDenied)
 	})
 
+	t.Run("should not be able to delete folder if rules in other folders depend on its rules", func(t *testing.T) {
+		store.AccessControl = acmock.New().WithPermissions([]accesscontrol.Permission{
+			{Action: accesscontrol.ActionAlertingRuleDelete, Scope: dashboards.ScopeFoldersAll},
+		})
+		dependent := createRule(t, store, models.RuleGen.With(
+			models.RuleMuts.WithIntervalMatching(store.Cfg.BaseInterval),
+			models.RuleMuts.WithOrgID(rule.OrgID),
+			models.RuleMuts.WithDependsOn(rule.UID),
+		))
+		err := store.DeleteInFolders(context.Background(), rule.OrgID, []string{rule.NamespaceUID}, &user.SignedInUser{})
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, dependent.Title)
+
+		require.NoError(t, store.DeleteAlertRulesByUID(context.Background(), rule.OrgID, dependent.UID))
+	})
+
 	t.Run("should be able to delete folder with permissions to delete rules", func(t *testing.T) {
 		store.AccessControl = acmock.New().WithPermissions([]accesscontrol.Permission{
 			{Action: accesscontrol.ActionAlertingRuleDelete, Scope: dashboards.ScopeFoldersAll},
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/util/cmputil"
@@ -31,6 +32,136 @@ func (c *GroupDelta) IsEmpty() bool {
 	return len(c.Update)+len(c.New)+len(c.Delete) == 0
 }
 
+// AffectsRuleDependencies returns true if the changes of the group can break rule dependencies: a new or updated rule
+// depends on other rules, a rule is deleted, or the type of a rule changes.
+func (c *GroupDelta) AffectsRuleDependencies() bool {
+	if len(c.Delete) > 0 {
+		return true
+	}
+	for _, rule := range c.New {
+		if len(rule.DependsOn) > 0 {
+			return true
+		}
+	}
+	for _, delta := range c.Update {
+		if len(delta.New.DependsOn) > 0 || delta.Existing.Type() != delta.New.Type() {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateRuleDependencies checks that the new and updated rules of the group changes depend only on existing alerting
+// rules, that the changes do not introduce dependency cycles, and that the rules that depend on the deleted or updated
+// rules are still valid. It must be called by every path that writes or deletes rules.
+func ValidateRuleDependencies(ctx context.Context, ruleReader RuleReader, changes *GroupDelta) error {
+	if !changes.AffectsRuleDependencies() {
+		return nil
+	}
+	orgRules, err := ruleReader.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: changes.GroupKey.OrgID})
+	if err != nil {
+		return fmt.Errorf("failed to fetch rules of the organization: %w", err)
+	}
+	return validateRuleDependencies(changes, orgRules)
+}
+
+// validateRuleDependencies checks that the new and updated rules of the group changes depend only on existing alerting
+// rules, that the changes do not introduce dependency cycles, and that the rules that depend on the deleted or updated
+// rules are still valid. existingRules must contain all rules of the organization.
+func validateRuleDependencies(changes *GroupDelta, existingRules []*models.AlertRule) error {
+	rules := make(map[string]*models.AlertRule, len(existingRules)+len(changes.New))
+	for _, rule := range existingRules {
+		rules[rule.UID] = rule
+	}
+	deleted := make(map[string]*models.AlertRule, len(changes.Delete))
+	for _, rule := range changes.Delete {
+		if existing, ok := rules[rule.UID]; ok {
+			rule = existing
+		}
+		deleted[rule.UID] = rule
+		delete(rules, rule.UID)
+	}
+	changed := make([]*models.AlertRule, 0, len(changes.New)+len(changes.Update))
+	changed = append(changed, changes.New...)
+	updated := make(map[string]bool, len(changes.Update))
+	for _, delta := range changes.Update {
+		changed = append(changed, delta.New)
+		updated[delta.New.UID] = true
+	}
+	for _, rule := range changed {
+		if rule.UID != "" {
+			rules[rule.UID] = rule
+		}
+	}
+
+	// The rules to check are the changed rules and the existing rules that depend on a deleted or updated rule.
+	toCheck := changed
+	for _, rule := range existingRules {
+		if _, ok := rules[rule.UID]; !ok || rules[rule.UID] != rule {
+			continue
+		}
+		for _, uid := range rule.DependsOn {
+			if _, ok := deleted[uid]; ok || updated[uid] {
+				toCheck = append(toCheck, rule)
+				break
+			}
+		}
+	}
+
+	for _, rule := range toCheck {
+		for _, uid := range rule.DependsOn {
+			upstream, ok := rules[uid]
+			if !ok {
+				if d, ok := deleted[uid]; ok {
+					return fmt.Errorf("%w: rule '%s' cannot be deleted because rule '%s' depends on it", models.ErrAlertRuleFailedValidation, d.Title, rule.Title)
+				}
+				return fmt.Errorf("%w: rule '%s' depends on rule with UID %s that does not exist", models.ErrAlertRuleFailedValidation, rule.Title, uid)
+			}
+			if upstream.Type() == models.RuleTypeRecording {
+				return fmt.Errorf("%w: rule '%s' depends on recording rule '%s'", models.ErrAlertRuleFailedValidation, rule.Title, upstream.Title)
+			}
+		}
+	}
+
+	// Depth-first search of the dependency graph. A rule that is visited again before all its dependencies
+	// are visited is part of a cycle.
+	const (
+		visiting = iota + 1
+		visited
+	)
+	marks := make(map[string]int, len(rules))
+	var visit func(uid string, path []string) error
+	visit = func(uid string, path []string) error {
+		switch marks[uid] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("%w: rule dependencies cannot have cycles: %s", models.ErrAlertRuleFailedValidation, strings.Join(append(path, uid), " -> "))
+		}
+		rule, ok := rules[uid]
+		if !ok {
+			return nil
+		}
+		marks[uid] = visiting
+		for _, dep := range rule.DependsOn {
+			if err := visit(dep, append(path, uid)); err != nil {
+				return err
+			}
+		}
+		marks[uid] = visited
+		return nil
+	}
+	for _, rule := range changed {
+		if rule.UID == "" {
+			continue
+		}
+		if err := visit(rule.UID, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // NewOrUpdatedNotificationSettings returns a list of notification settings that are either new or updated in the group.
 func (c *GroupDelta) NewOrUpdatedNotificationSettings() []models.NotificationSettings {
 	var settings []models.NotificationSettings
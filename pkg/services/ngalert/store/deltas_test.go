@@ -684,3 +684,64 @@ func groupByUID(t *testing.T, list []*models.AlertRule) map[string]*models.Alert
 	}
 	return result
 }
+
+func TestValidateRuleDependencies(t *testing.T) {
+	gen := models.RuleGen
+	a := gen.With(gen.WithUID("a"), gen.WithTitle("a"), gen.WithDependsOn()).GenerateRef()
+	b := gen.With(gen.WithUID("b"), gen.WithTitle("b"), gen.WithDependsOn("a")).GenerateRef()
+	c := gen.With(gen.WithUID("c"), gen.WithTitle("c"), gen.WithDependsOn("b")).GenerateRef()
+	recording := gen.With(gen.WithUID("rec"), gen.WithTitle("rec"), gen.WithAllRecordingRules()).GenerateRef()
+	existing := []*models.AlertRule{a, b, c, recording}
+
+	t.Run("accepts dependencies without cycles", func(t *testing.T) {
+		d := gen.With(gen.WithUID("d"), gen.WithDependsOn("a", "c")).GenerateRef()
+		require.NoError(t, validateRuleDependencies(&GroupDelta{New: []*models.AlertRule{d}}, existing))
+	})
+
+	t.Run("rejects dependencies on rules that do not exist", func(t *testing.T) {
+		d := gen.With(gen.WithUID("d"), gen.WithDependsOn("unknown")).GenerateRef()
+		err := validateRuleDependencies(&GroupDelta{New: []*models.AlertRule{d}}, existing)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "does not exist")
+	})
+
+	t.Run("rejects dependencies on deleted rules", func(t *testing.T) {
+		d := gen.With(gen.WithUID("d"), gen.WithDependsOn("c")).GenerateRef()
+		err := validateRuleDependencies(&GroupDelta{New: []*models.AlertRule{d}, Delete: []*models.AlertRule{c}}, existing)
+		require.ErrorContains(t, err, "rule 'c' cannot be deleted because rule '"+d.Title+"' depends on it")
+	})
+
+	t.Run("rejects deleting rules that other rules depend on", func(t *testing.T) {
+		delta := &GroupDelta{Delete: []*models.AlertRule{{UID: "a"}}}
+		require.True(t, delta.AffectsRuleDependencies())
+		err := validateRuleDependencies(delta, existing)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "rule 'a' cannot be deleted because rule 'b' depends on it")
+	})
+
+	t.Run("accepts deleting rules together with the rules that depend on them", func(t *testing.T) {
+		require.NoError(t, validateRuleDependencies(&GroupDelta{Delete: []*models.AlertRule{a, b, c}}, existing))
+	})
+
+	t.Run("rejects changing rules that other rules depend on to recording rules", func(t *testing.T) {
+		updated := models.CopyRule(a, gen.WithAllRecordingRules())
+		delta := &GroupDelta{Update: []RuleDelta{{Existing: a, New: updated}}}
+		require.True(t, delta.AffectsRuleDependencies())
+		err := validateRuleDependencies(delta, existing)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "rule 'b' depends on recording rule 'a'")
+	})
+
+	t.Run("rejects dependencies on recording rules", func(t *testing.T) {
+		d := gen.With(gen.WithUID("d"), gen.WithDependsOn("rec")).GenerateRef()
+		err := validateRuleDependencies(&GroupDelta{New: []*models.AlertRule{d}}, existing)
+		require.ErrorContains(t, err, "recording rule")
+	})
+
+	t.Run("rejects updates that introduce cycles", func(t *testing.T) {
+		updated := models.CopyRule(a, gen.WithDependsOn("c"))
+		err := validateRuleDependencies(&GroupDelta{Update: []RuleDelta{{Existing: a, New: updated}}}, existing)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "a -> c -> b -> a")
+	})
+}
@@ -68,13 +68,20 @@ func (st DBstore) SaveAlertInstance(ctx context.Context, alertInstance models.Al
 			alertInstance.LastEvalTime.Unix(),
 			nullableTimeToUnix(alertInstance.ResolvedAt),
 			nullableTimeToUnix(alertInstance.LastSentAt),
+			nullableTimeToUnix(alertInstance.KeepFiringSince),
 			alertInstance.ResultFingerprint,
+			alertInstance.HysteresisLevel,
+			alertInstance.HysteresisUnloading,
+			alertInstance.AcknowledgedBy,
+			alertInstance.AcknowledgementComment,
+			nullableTimeToUnix(alertInstance.AcknowledgedAt),
+			nullableTimeToUnix(alertInstance.AcknowledgementExpiresAt),
 		)
 
 		upsertSQL := st.SQLStore.GetDialect().UpsertSQL(
 			"alert_instance",
 			[]string{"rule_org_id", "rule_uid", "labels_hash"},
-			[]string{"rule_org_id", "rule_uid", "labels", "labels_hash", "current_state", "current_reason", "current_state_since", "current_state_end", "last_eval_time", "resolved_at", "last_sent_at", "result_fingerprint"})
+			[]string{"rule_org_id", "rule_uid", "labels", "labels_hash", "current_state", "current_reason", "current_state_since", "current_state_end", "last_eval_time", "resolved_at", "last_sent_at", "keep_firing_since", "result_fingerprint", "hysteresis_level", "hysteresis_unloading", "acknowledged_by", "acknowledgement_comment", "acknowledged_at", "acknowledgement_expires_at"})
 		_, err = sess.SQL(upsertSQL, params...).Query()
 		if err != nil {
 			return err
@@ -234,7 +241,7 @@ func (st DBstore) FullSync(ctx context.Context, instances []models.AlertInstance
 			}
 
 			_, err = sess.Exec(
-				"INSERT INTO alert_instance (rule_org_id, rule_uid, labels, labels_hash, current_state, current_reason, current_state_since, current_state_end, last_eval_time, resolved_at, last_sent_at) VALUES (?,?,?,?,?,?,?,?,?,?,?)",
+				"INSERT INTO alert_instance (rule_org_id, rule_uid, labels, labels_hash, current_state, current_reason, current_state_since, current_state_end, last_eval_time, resolved_at, last_sent_at, keep_firing_since, hysteresis_level, hysteresis_unloading, acknowledged_by, acknowledgement_comment, acknowledged_at, acknowledgement_expires_at) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)",
 				alertInstance.RuleOrgID,
 				alertInstance.RuleUID,
 				labelTupleJSON,
@@ -246,6 +253,13 @@ func (st DBstore) FullSync(ctx context.Context, instances []models.AlertInstance
 				alertInstance.LastEvalTime.Unix(),
 				nullableTimeToUnix(alertInstance.ResolvedAt),
 				nullableTimeToUnix(alertInstance.LastSentAt),
+				nullableTimeToUnix(alertInstance.KeepFiringSince),
+				alertInstance.HysteresisLevel,
+				alertInstance.HysteresisUnloading,
+				alertInstance.AcknowledgedBy,
+				alertInstance.AcknowledgementComment,
+				nullableTimeToUnix(alertInstance.AcknowledgedAt),
+				nullableTimeToUnix(alertInstance.AcknowledgementExpiresAt),
 			)
 			if err != nil {
 				return fmt.Errorf("failed to insert into alert_instance table: %w", err)
@@ -294,6 +294,45 @@ func TestIntegrationAlertInstanceOperations(t *testing.T) {
 		require.Equal(t, instance2.Labels, alerts[0].Labels)
 		require.Equal(t, instance2.CurrentState, alerts[0].CurrentState)
 	})
+
+	t.Run("can save and read acknowledgement of alert instance", func(t *testing.T) {
+		labels := models.InstanceLabels{"test": "acknowledged"}
+		_, hash, _ := labels.StringAndHash()
+		acknowledgedAt := time.Unix(1700000000, 0)
+		expiresAt := acknowledgedAt.Add(time.Hour)
+		instance := models.AlertInstance{
+			AlertInstanceKey: models.AlertInstanceKey{
+				RuleOrgID:  alertRule1.OrgID,
+				RuleUID:    alertRule1.UID,
+				LabelsHash: hash,
+			},
+			CurrentState:             models.InstanceStateFiring,
+			CurrentReason:            models.StateReasonAcknowledged,
+			Labels:                   labels,
+			AcknowledgedBy:           "admin",
+			AcknowledgementComment:   "looking into it",
+			AcknowledgedAt:           &acknowledgedAt,
+			AcknowledgementExpiresAt: &expiresAt,
+		}
+		err := dbstore.SaveAlertInstance(ctx, instance)
+		require.NoError(t, err)
+
+		alerts, err := dbstore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{
+			RuleOrgID: instance.RuleOrgID,
+			RuleUID:   instance.RuleUID,
+		})
+		require.NoError(t, err)
+		containsHash(t, alerts, hash)
+		for _, a := range alerts {
+			if a.LabelsHash != hash {
+				continue
+			}
+			require.Equal(t, "admin", a.AcknowledgedBy)
+			require.Equal(t, "looking into it", a.AcknowledgementComment)
+			require.Equal(t, acknowledgedAt.Unix(), a.AcknowledgedAt.Unix())
+			require.Equal(t, expiresAt.Unix(), a.AcknowledgementExpiresAt.Unix())
+		}
+	})
 }
 
 func TestIntegrationFullSync(t *testing.T) {
@@ -369,6 +408,25 @@ func TestIntegrationFullSync(t *testing.T) {
 			}
 		}
 	})
+	t.Run("Should save all fields of instances", func(t *testing.T) {
+		instance := generateTestAlertInstance(orgID, "z")
+		instance.HysteresisLevel = 2
+		instance.HysteresisUnloading = 1
+		keepFiringSince := time.Unix(1700000000, 0)
+		instance.KeepFiringSince = &keepFiringSince
+		err := dbstore.FullSync(ctx, []models.AlertInstance{instance})
+		require.NoError(t, err)
+
+		res, err := dbstore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{
+			RuleOrgID: orgID,
+		})
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.Equal(t, int64(2), res[0].HysteresisLevel)
+		require.Equal(t, int64(1), res[0].HysteresisUnloading)
+		require.NotNil(t, res[0].KeepFiringSince)
+		require.Equal(t, keepFiringSince.Unix(), res[0].KeepFiringSince.Unix())
+	})
 }
 
 func generateTestAlertInstance(orgID int64, ruleID string) models.AlertInstance {
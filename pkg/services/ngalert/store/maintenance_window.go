@@ -0,0 +1,198 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/alerting/definition"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type maintenanceWindow struct {
+	ID      int64     `xorm:"pk autoincr 'id'"`
+	OrgID   int64     `xorm:"'org_id'"`
+	UID     string    `xorm:"'uid'"`
+	Title   string    `xorm:"'title'"`
+	Mode    string    `xorm:"'mode'"`
+	Data    string    `xorm:"'data'"`
+	Version int64     `xorm:"'version'"`
+	Updated time.Time `xorm:"'updated'"`
+}
+
+func (w maintenanceWindow) TableName() string {
+	return "alert_maintenance_window"
+}
+
+// maintenanceWindowData is the part of the maintenance window that is stored as JSON.
+type maintenanceWindowData struct {
+	TimeIntervals []timeinterval.TimeInterval `json:"time_intervals"`
+	Matchers      definition.ObjectMatchers   `json:"matchers,omitempty"`
+	FolderUIDs    []string                    `json:"folder_uids,omitempty"`
+}
+
+// ListMaintenanceWindows returns all maintenance windows of the organization ordered by title.
+func (st DBstore) ListMaintenanceWindows(ctx context.Context, orgID int64) ([]models.MaintenanceWindow, error) {
+	return st.listMaintenanceWindows(ctx, "org_id = ?", orgID)
+}
+
+// ListAllMaintenanceWindows returns maintenance windows of all organizations.
+func (st DBstore) ListAllMaintenanceWindows(ctx context.Context) ([]models.MaintenanceWindow, error) {
+	return st.listMaintenanceWindows(ctx, "")
+}
+
+func (st DBstore) listMaintenanceWindows(ctx context.Context, where string, args ...any) ([]models.MaintenanceWindow, error) {
+	var result []models.MaintenanceWindow
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Table(maintenanceWindow{})
+		if where != "" {
+			q = q.Where(where, args...)
+		}
+		var rows []maintenanceWindow
+		if err := q.Asc("org_id", "title").Find(&rows); err != nil {
+			return fmt.Errorf("failed to list maintenance windows: %w", err)
+		}
+		result = make([]models.MaintenanceWindow, 0, len(rows))
+		for _, row := range rows {
+			w, err := maintenanceWindowToModel(row)
+			if err != nil {
+				return err
+			}
+			result = append(result, w)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// GetMaintenanceWindow returns the maintenance window with the given UID, or models.ErrMaintenanceWindowNotFound.
+func (st DBstore) GetMaintenanceWindow(ctx context.Context, orgID int64, uid string) (models.MaintenanceWindow, error) {
+	var result models.MaintenanceWindow
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var row maintenanceWindow
+		has, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(&row)
+		if err != nil {
+			return fmt.Errorf("failed to get maintenance window: %w", err)
+		}
+		if !has {
+			return models.ErrMaintenanceWindowNotFound.Errorf("maintenance window %s not found", uid)
+		}
+		result, err = maintenanceWindowToModel(row)
+		return err
+	})
+	return result, err
+}
+
+// InsertMaintenanceWindow stores a new maintenance window with version 1. The UID must be set by the caller.
+func (st DBstore) InsertMaintenanceWindow(ctx context.Context, w models.MaintenanceWindow) (models.MaintenanceWindow, error) {
+	w.Version = 1
+	w.Updated = TimeNow().UTC()
+	row, err := maintenanceWindowFromModel(w)
+	if err != nil {
+		return models.MaintenanceWindow{}, err
+	}
+	err = st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Table(maintenanceWindow{}).Where("org_id = ? AND (uid = ? OR title = ?)", w.OrgID, w.UID, w.Title).Exist()
+		if err != nil {
+			return fmt.Errorf("failed to check for existing maintenance window: %w", err)
+		}
+		if exists {
+			return models.ErrMaintenanceWindowExists.Errorf("maintenance window with uid %s or title %s already exists", w.UID, w.Title)
+		}
+		if _, err := sess.Insert(&row); err != nil {
+			return fmt.Errorf("failed to insert maintenance window: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return models.MaintenanceWindow{}, err
+	}
+	return w, nil
+}
+
+// UpdateMaintenanceWindow replaces the maintenance window with the same UID if its stored version equals the version
+// of w, and increments the version. It returns models.ErrMaintenanceWindowVersionConflict if the versions differ.
+func (st DBstore) UpdateMaintenanceWindow(ctx context.Context, w models.MaintenanceWindow) (models.MaintenanceWindow, error) {
+	current := w.Version
+	w.Version++
+	w.Updated = TimeNow().UTC()
+	row, err := maintenanceWindowFromModel(w)
+	if err != nil {
+		return models.MaintenanceWindow{}, err
+	}
+	err = st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Table(maintenanceWindow{}).Where("org_id = ? AND uid <> ? AND title = ?", w.OrgID, w.UID, w.Title).Exist()
+		if err != nil {
+			return fmt.Errorf("failed to check for existing maintenance window: %w", err)
+		}
+		if exists {
+			return models.ErrMaintenanceWindowExists.Errorf("maintenance window with title %s already exists", w.Title)
+		}
+		affected, err := sess.Table(maintenanceWindow{}).
+			Where("org_id = ? AND uid = ? AND version = ?", w.OrgID, w.UID, current).
+			Cols("title", "mode", "data", "version", "updated").
+			Update(&row)
+		if err != nil {
+			return fmt.Errorf("failed to update maintenance window: %w", err)
+		}
+		if affected == 0 {
+			return models.ErrMaintenanceWindowVersionConflict.Errorf("maintenance window %s was changed since version %d", w.UID, current)
+		}
+		return nil
+	})
+	if err != nil {
+		return models.MaintenanceWindow{}, err
+	}
+	return w, nil
+}
+
+// DeleteMaintenanceWindow deletes the maintenance window with the given UID. It does nothing if the window does not exist.
+func (st DBstore) DeleteMaintenanceWindow(ctx context.Context, orgID int64, uid string) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Delete(maintenanceWindow{})
+		return err
+	})
+}
+
+func maintenanceWindowFromModel(w models.MaintenanceWindow) (maintenanceWindow, error) {
+	data, err := json.Marshal(maintenanceWindowData{
+		TimeIntervals: w.TimeIntervals,
+		Matchers:      definition.ObjectMatchers(w.Matchers),
+		FolderUIDs:    w.FolderUIDs,
+	})
+	if err != nil {
+		return maintenanceWindow{}, fmt.Errorf("failed to marshal maintenance window: %w", err)
+	}
+	return maintenanceWindow{
+		OrgID:   w.OrgID,
+		UID:     w.UID,
+		Title:   w.Title,
+		Mode:    string(w.Mode),
+		Data:    string(data),
+		Version: w.Version,
+		Updated: w.Updated,
+	}, nil
+}
+
+func maintenanceWindowToModel(row maintenanceWindow) (models.MaintenanceWindow, error) {
+	var data maintenanceWindowData
+	if err := json.Unmarshal([]byte(row.Data), &data); err != nil {
+		return models.MaintenanceWindow{}, fmt.Errorf("failed to unmarshal maintenance window %s: %w", row.UID, err)
+	}
+	return models.MaintenanceWindow{
+		UID:           row.UID,
+		OrgID:         row.OrgID,
+		Title:         row.Title,
+		Mode:          models.MaintenanceWindowMode(row.Mode),
+		TimeIntervals: data.TimeIntervals,
+		Matchers:      labels.Matchers(data.Matchers),
+		FolderUIDs:    data.FolderUIDs,
+		Version:       row.Version,
+		Updated:       row.Updated.UTC(),
+	}, nil
+}
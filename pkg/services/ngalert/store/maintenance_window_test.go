@@ -0,0 +1,105 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationMaintenanceWindows(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	matcher, err := labels.NewMatcher(labels.MatchRegexp, "team", "ops|db")
+	require.NoError(t, err)
+	window := models.MaintenanceWindow{
+		UID:   "window-1",
+		OrgID: 1,
+		Title: "Database maintenance",
+		Mode:  models.MaintenanceWindowModePause,
+		TimeIntervals: []timeinterval.TimeInterval{{
+			Weekdays: []timeinterval.WeekdayRange{{InclusiveRange: timeinterval.InclusiveRange{Begin: 6, End: 6}}},
+			Times:    []timeinterval.TimeRange{{StartMinute: 60, EndMinute: 180}},
+		}},
+		Matchers:   labels.Matchers{matcher},
+		FolderUIDs: []string{"folder-1"},
+	}
+
+	created, err := dbstore.InsertMaintenanceWindow(ctx, window)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, created.Version)
+
+	t.Run("should get stored window", func(t *testing.T) {
+		stored, err := dbstore.GetMaintenanceWindow(ctx, 1, "window-1")
+		require.NoError(t, err)
+		require.Equal(t, window.Title, stored.Title)
+		require.Equal(t, window.Mode, stored.Mode)
+		require.Equal(t, window.TimeIntervals, stored.TimeIntervals)
+		require.Equal(t, window.FolderUIDs, stored.FolderUIDs)
+		require.Len(t, stored.Matchers, 1)
+		require.Equal(t, matcher.String(), stored.Matchers[0].String())
+		require.True(t, stored.Matchers[0].Matches("db"))
+
+		_, err = dbstore.GetMaintenanceWindow(ctx, 2, "window-1")
+		require.ErrorIs(t, err, models.ErrMaintenanceWindowNotFound)
+	})
+
+	t.Run("should reject windows with the same uid or title", func(t *testing.T) {
+		dup := window
+		dup.UID = "window-2"
+		_, err := dbstore.InsertMaintenanceWindow(ctx, dup)
+		require.ErrorIs(t, err, models.ErrMaintenanceWindowExists)
+
+		dup = window
+		dup.Title = "Other"
+		_, err = dbstore.InsertMaintenanceWindow(ctx, dup)
+		require.ErrorIs(t, err, models.ErrMaintenanceWindowExists)
+
+		dup = window
+		dup.OrgID = 2
+		_, err = dbstore.InsertMaintenanceWindow(ctx, dup)
+		require.NoError(t, err)
+	})
+
+	t.Run("should update window if version matches", func(t *testing.T) {
+		upd := created
+		upd.Mode = models.MaintenanceWindowModeMute
+		updated, err := dbstore.UpdateMaintenanceWindow(ctx, upd)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, updated.Version)
+
+		_, err = dbstore.UpdateMaintenanceWindow(ctx, upd)
+		require.ErrorIs(t, err, models.ErrMaintenanceWindowVersionConflict)
+
+		stored, err := dbstore.GetMaintenanceWindow(ctx, 1, "window-1")
+		require.NoError(t, err)
+		require.Equal(t, models.MaintenanceWindowModeMute, stored.Mode)
+	})
+
+	t.Run("should list windows", func(t *testing.T) {
+		windows, err := dbstore.ListMaintenanceWindows(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, windows, 1)
+
+		windows, err = dbstore.ListAllMaintenanceWindows(ctx)
+		require.NoError(t, err)
+		require.Len(t, windows, 2)
+	})
+
+	t.Run("should delete window", func(t *testing.T) {
+		require.NoError(t, dbstore.DeleteMaintenanceWindow(ctx, 1, "window-1"))
+		require.NoError(t, dbstore.DeleteMaintenanceWindow(ctx, 1, "window-1"))
+		_, err := dbstore.GetMaintenanceWindow(ctx, 1, "window-1")
+		require.ErrorIs(t, err, models.ErrMaintenanceWindowNotFound)
+	})
+}
@@ -0,0 +1,37 @@
+package writer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BackendType identifies different kinds of recording rule write targets.
+type BackendType string
+
+// String implements Stringer for BackendType.
+func (bt BackendType) String() string {
+	return string(bt)
+}
+
+const (
+	BackendTypePrometheus BackendType = "prometheus"
+	BackendTypeLive       BackendType = "live"
+	BackendTypeInflux     BackendType = "influxdb"
+	BackendTypeSQL        BackendType = "sql"
+)
+
+func ParseBackendType(s string) (BackendType, error) {
+	norm := strings.ToLower(strings.TrimSpace(s))
+
+	types := map[BackendType]struct{}{
+		BackendTypePrometheus: {},
+		BackendTypeLive:       {},
+		BackendTypeInflux:     {},
+		BackendTypeSQL:        {},
+	}
+	p := BackendType(norm)
+	if _, ok := types[p]; !ok {
+		return "", fmt.Errorf("unrecognized recording rule backend: %s", p)
+	}
+	return p, nil
+}
@@ -0,0 +1,146 @@
+package writer
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// retryableError marks an error of a write attempt that can succeed if the write is retried.
+type retryableError struct {
+	err error
+}
+
+func (e retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e retryableError) Unwrap() error {
+	return e.err
+}
+
+func retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return retryableError{err: err}
+}
+
+// retryableIfTransient marks err as retryable if it is caused by a broken connection, a network error
+// or a timeout. Other errors, such as rejected statements, would fail again if the write were retried.
+func retryableIfTransient(err error) error {
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) || errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) {
+		return retryable(err)
+	}
+	return err
+}
+
+func isRetryable(err error) bool {
+	var r retryableError
+	return errors.As(err, &r)
+}
+
+// delivery applies backpressure and retries to the writes of a backend.
+// A write waits until the number of writes in progress is below the limit and is retried with an exponential backoff
+// as long as its attempts fail with a retryable error.
+type delivery struct {
+	backend    BackendType
+	inflight   chan struct{}
+	maxRetries int
+	backoff    time.Duration
+	clock      clock.Clock
+	metrics    *metrics.RemoteWriter
+}
+
+func newDelivery(backend BackendType, settings setting.RecordingRuleSettings, clock clock.Clock, metrics *metrics.RemoteWriter) (*delivery, error) {
+	if settings.MaxInflightWrites <= 0 {
+		return nil, fmt.Errorf("max inflight writes must be greater than 0")
+	}
+	if settings.MaxRetries < 0 {
+		return nil, fmt.Errorf("max retries must not be negative")
+	}
+	if settings.MaxRetries > 0 && settings.RetryBackoff <= 0 {
+		return nil, fmt.Errorf("retry backoff must be greater than 0")
+	}
+	return &delivery{
+		backend:    backend,
+		inflight:   make(chan struct{}, settings.MaxInflightWrites),
+		maxRetries: settings.MaxRetries,
+		backoff:    settings.RetryBackoff,
+		clock:      clock,
+		metrics:    metrics,
+	}, nil
+}
+
+// do runs attempt until it succeeds, fails with an error that is not retryable, or runs out of retries.
+func (d *delivery) do(ctx context.Context, orgID int64, attempt func(ctx context.Context) error) error {
+	org := fmt.Sprint(orgID)
+
+	waitStart := d.clock.Now()
+	select {
+	case d.inflight <- struct{}{}:
+	case <-ctx.Done():
+		d.metrics.DroppedWritesTotal.WithLabelValues(org, d.backend.String()).Inc()
+		return fmt.Errorf("failed to wait for a free write slot: %w", ctx.Err())
+	}
+	d.metrics.BackpressureWaitDuration.WithLabelValues(d.backend.String()).Observe(d.clock.Now().Sub(waitStart).Seconds())
+
+	d.metrics.InflightWrites.WithLabelValues(d.backend.String()).Inc()
+	defer func() {
+		d.metrics.InflightWrites.WithLabelValues(d.backend.String()).Dec()
+		<-d.inflight
+	}()
+
+	backoff := d.backoff
+	for retries := 0; ; retries++ {
+		writeStart := d.clock.Now()
+		err := attempt(ctx)
+		d.metrics.WriteDuration.WithLabelValues(org, d.backend.String()).Observe(d.clock.Now().Sub(writeStart).Seconds())
+		d.metrics.WritesTotal.WithLabelValues(org, d.backend.String(), statusLabel(err)).Inc()
+
+		if err == nil || !isRetryable(err) || retries >= d.maxRetries {
+			return err
+		}
+
+		d.metrics.RetriesTotal.WithLabelValues(org, d.backend.String()).Inc()
+		select {
+		case <-d.clock.After(backoff):
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		}
+		backoff *= 2
+	}
+}
+
+// statusLabel returns the value of the status_code label of a write attempt.
+// Backends that do not write over HTTP report either success or error.
+func statusLabel(err error) string {
+	var statusErr statusCodeError
+	switch {
+	case err == nil:
+		return "success"
+	case errors.As(err, &statusErr):
+		return fmt.Sprint(statusErr.StatusCode)
+	default:
+		return "error"
+	}
+}
+
+// statusCodeError is an error returned by an HTTP write target.
+type statusCodeError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e statusCodeError) Error() string {
+	return fmt.Sprintf("unexpected status code %d: %s", e.StatusCode, e.Body)
+}
@@ -0,0 +1,31 @@
+package writer
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryableIfTransient(t *testing.T) {
+	for _, err := range []error{
+		driver.ErrBadConn,
+		fmt.Errorf("failed to insert: %w", driver.ErrBadConn),
+		&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")},
+		context.DeadlineExceeded,
+	} {
+		require.True(t, isRetryable(retryableIfTransient(err)), err.Error())
+	}
+
+	for _, err := range []error{
+		errors.New("relation \"recorded\" does not exist"),
+		context.Canceled,
+	} {
+		require.False(t, isRetryable(retryableIfTransient(err)), err.Error())
+	}
+	require.NoError(t, retryableIfTransient(nil))
+}
@@ -0,0 +1,181 @@
+package writer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// influxFieldKey is the field key of the single field of every written point.
+const influxFieldKey = "value"
+
+// InfluxWriter writes recording rule results in InfluxDB line protocol to the write API of InfluxDB.
+type InfluxWriter struct {
+	client   *http.Client
+	url      string
+	delivery *delivery
+	logger   log.Logger
+}
+
+func NewInfluxWriter(
+	settings setting.RecordingRuleSettings,
+	httpClientProvider HttpClientProvider,
+	clock clock.Clock,
+	tracer tracing.Tracer,
+	l log.Logger,
+	metrics *metrics.RemoteWriter,
+) (*InfluxWriter, error) {
+	if settings.URL == "" {
+		return nil, fmt.Errorf("URL is required")
+	}
+	if err := validateSettings(settings); err != nil {
+		return nil, err
+	}
+
+	headers := make(http.Header)
+	for k, v := range settings.CustomHeaders {
+		headers.Add(k, v)
+	}
+
+	cl, err := httpClientProvider.New(httpclient.Options{
+		Middlewares: []httpclient.Middleware{
+			httpclient.TracingMiddleware(tracer),
+		},
+		BasicAuth: createAuthOpts(settings.BasicAuthUsername, settings.BasicAuthPassword),
+		Header:    headers,
+	})
+	if err != nil {
+		return nil, err
+	}
+	cl.Timeout = settings.Timeout
+
+	d, err := newDelivery(BackendTypeInflux, settings, clock, metrics)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InfluxWriter{
+		client:   cl,
+		url:      settings.URL,
+		delivery: d,
+		logger:   l,
+	}, nil
+}
+
+// Write writes the given frames to the InfluxDB write API.
+func (w *InfluxWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, extraLabels map[string]string) error {
+	l := w.logger.FromContext(ctx)
+	ruleKey, found := models.RuleKeyFromContext(ctx)
+	if !found {
+		// sanity check, this should never happen
+		return fmt.Errorf("rule key not found in context")
+	}
+
+	points, err := PointsFromFrames(name, t, frames, extraLabels)
+	if err != nil {
+		return err
+	}
+
+	body := encodeLineProtocol(points)
+	if len(body) == 0 {
+		l.Debug("No points to write", "name", name)
+		return nil
+	}
+
+	l.Debug("Writing metric", "name", name)
+	if err := w.delivery.do(ctx, ruleKey.OrgID, func(ctx context.Context) error {
+		return w.send(ctx, body)
+	}); err != nil {
+		return fmt.Errorf("failed to write points: %w", err)
+	}
+	return nil
+}
+
+func (w *InfluxWriter) send(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	req.Header.Set("User-Agent", "grafana-recording-rule")
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return retryable(err)
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return nil
+	}
+
+	msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+	statusErr := statusCodeError{StatusCode: res.StatusCode, Body: string(msg)}
+	if res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500 {
+		return retryable(statusErr)
+	}
+	return statusErr
+}
+
+// encodeLineProtocol encodes the points in InfluxDB line protocol with nanosecond precision.
+// The name of a point is the measurement, its labels are the tags and its value is the field "value".
+// Points with values that cannot be represented in line protocol (NaN and infinities) are skipped.
+func encodeLineProtocol(points []Point) []byte {
+	var buf bytes.Buffer
+	for _, p := range points {
+		if math.IsNaN(p.Metric.V) || math.IsInf(p.Metric.V, 0) {
+			continue
+		}
+
+		buf.WriteString(influxMeasurementEscaper.Replace(p.Name))
+
+		keys := make([]string, 0, len(p.Labels))
+		for k, v := range p.Labels {
+			// empty tag values are not allowed
+			if v == "" {
+				continue
+			}
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			buf.WriteByte(',')
+			buf.WriteString(influxTagEscaper.Replace(k))
+			buf.WriteByte('=')
+			buf.WriteString(influxTagEscaper.Replace(p.Labels[k]))
+		}
+
+		buf.WriteByte(' ')
+		buf.WriteString(influxFieldKey)
+		buf.WriteByte('=')
+		buf.WriteString(strconv.FormatFloat(p.Metric.V, 'g', -1, 64))
+		buf.WriteByte(' ')
+		buf.WriteString(strconv.FormatInt(p.Metric.T.UnixNano(), 10))
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+var (
+	influxMeasurementEscaper = strings.NewReplacer(`,`, `\,`, ` `, `\ `, "\n", `\n`)
+	influxTagEscaper         = strings.NewReplacer(`,`, `\,`, `=`, `\=`, ` `, `\ `, "\n", `\n`)
+)
@@ -0,0 +1,144 @@
+package writer
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestEncodeLineProtocol(t *testing.T) {
+	ts := time.Unix(1700000000, 5)
+	points := []Point{
+		{
+			Name:   "cpu usage",
+			Labels: map[string]string{"host": "a,b", "zone": "eu=1", "empty": ""},
+			Metric: Metric{T: ts, V: 1.5},
+		},
+		{
+			Name:   "skipped",
+			Labels: map[string]string{"host": "a"},
+			Metric: Metric{T: ts, V: math.NaN()},
+		},
+		{
+			Name:   "mem",
+			Labels: map[string]string{},
+			Metric: Metric{T: ts, V: math.Inf(1)},
+		},
+		{
+			Name:   "mem",
+			Labels: map[string]string{"host": "c d"},
+			Metric: Metric{T: ts, V: 2},
+		},
+	}
+
+	require.Equal(t, "cpu\\ usage,host=a\\,b,zone=eu\\=1 value=1.5 1700000000000000005\nmem,host=c\\ d value=2 1700000000000000005\n", string(encodeLineProtocol(points)))
+}
+
+func TestInfluxWriter_Write(t *testing.T) {
+	var (
+		mtx      sync.Mutex
+		bodies   []string
+		statuses []int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, string(b))
+		status := http.StatusNoContent
+		if len(statuses) > 0 {
+			status, statuses = statuses[0], statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+
+	reg := prometheus.NewRegistry()
+	m := metrics.NewRemoteWriterMetrics(reg)
+	writer, err := NewInfluxWriter(setting.RecordingRuleSettings{
+		URL:               srv.URL,
+		Timeout:           10 * time.Second,
+		MaxRetries:        2,
+		RetryBackoff:      time.Millisecond,
+		MaxInflightWrites: 1,
+	}, testHTTPClientProvider{}, clock.New(), tracing.InitializeTracerForTest(), log.NewNopLogger(), m)
+	require.NoError(t, err)
+
+	now := time.Unix(10, 0)
+	frames := frameGenFromLabels(t, data.FrameTypeNumericWide, []map[string]string{{"foo": "1"}})
+	ctx := ngmodels.WithRuleKey(context.Background(), ngmodels.GenerateRuleKey(1))
+	reset := func(s ...int) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		bodies, statuses = nil, s
+	}
+
+	t.Run("writes points in line protocol", func(t *testing.T) {
+		reset()
+		require.NoError(t, writer.Write(ctx, "test", now, frames, map[string]string{"extra": "label"}))
+		require.Len(t, bodies, 1)
+		v := extractValue(t, frames, map[string]string{"foo": "1"}, data.FrameTypeNumericWide)
+		require.Equal(t, string(encodeLineProtocol([]Point{{
+			Name:   "test",
+			Labels: map[string]string{"foo": "1", "extra": "label"},
+			Metric: Metric{T: now, V: v},
+		}})), bodies[0])
+	})
+
+	t.Run("retries when server fails", func(t *testing.T) {
+		reset(http.StatusInternalServerError, http.StatusTooManyRequests)
+		require.NoError(t, writer.Write(ctx, "test", now, frames, nil))
+		require.Len(t, bodies, 3)
+		require.Equal(t, 2.0, testutil.ToFloat64(m.RetriesTotal.WithLabelValues("1", "influxdb")))
+	})
+
+	t.Run("fails when retries are exhausted", func(t *testing.T) {
+		reset(http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+		err := writer.Write(ctx, "test", now, frames, nil)
+		require.ErrorContains(t, err, "unexpected status code 502")
+		require.Len(t, bodies, 3)
+		require.Equal(t, 3.0, testutil.ToFloat64(m.WritesTotal.WithLabelValues("1", "influxdb", "502")))
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		reset(http.StatusBadRequest)
+		err := writer.Write(ctx, "test", now, frames, nil)
+		require.ErrorContains(t, err, "unexpected status code 400")
+		require.Len(t, bodies, 1)
+	})
+
+	t.Run("drops writes when no slot is free before the context is done", func(t *testing.T) {
+		writer.delivery.inflight <- struct{}{}
+		t.Cleanup(func() { <-writer.delivery.inflight })
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, writer.Write(ctx, "test", now, frames, nil), context.DeadlineExceeded)
+		require.Equal(t, 1.0, testutil.ToFloat64(m.DroppedWritesTotal.WithLabelValues("1", "influxdb")))
+	})
+}
+
+type testHTTPClientProvider struct{}
+
+func (testHTTPClientProvider) New(options ...httpclient.Options) (*http.Client, error) {
+	return &http.Client{}, nil
+}
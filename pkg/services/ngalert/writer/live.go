@@ -0,0 +1,93 @@
+package writer
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana-plugin-sdk-go/live"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/live/managedstream"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// LiveNamespace is the namespace of the managed streams recording rules are written to.
+// The results of a recording rule are published to the channel stream/recording_rules/<metric>.
+const LiveNamespace = "recording_rules"
+
+var invalidLivePathChars = regexp.MustCompile(`[^A-Za-z0-9_\-=.]`)
+
+type ManagedStreamRunner interface {
+	GetOrCreateStream(orgID int64, scope string, namespace string) (*managedstream.NamespaceStream, error)
+}
+
+// LiveWriter writes recording rule results to Grafana Live managed streams.
+// Managed streams keep the last frame of every channel, so the latest results can be read by new subscribers.
+type LiveWriter struct {
+	runner   ManagedStreamRunner
+	delivery *delivery
+	logger   log.Logger
+}
+
+func NewLiveWriter(settings setting.RecordingRuleSettings, runner ManagedStreamRunner, clock clock.Clock, l log.Logger, metrics *metrics.RemoteWriter) (*LiveWriter, error) {
+	if runner == nil {
+		return nil, fmt.Errorf("grafana live is not available")
+	}
+
+	d, err := newDelivery(BackendTypeLive, settings, clock, metrics)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LiveWriter{
+		runner:   runner,
+		delivery: d,
+		logger:   l,
+	}, nil
+}
+
+// Write pushes the given frames as a single wide frame to the managed stream channel of the metric.
+func (w *LiveWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, extraLabels map[string]string) error {
+	l := w.logger.FromContext(ctx)
+	ruleKey, found := models.RuleKeyFromContext(ctx)
+	if !found {
+		// sanity check, this should never happen
+		return fmt.Errorf("rule key not found in context")
+	}
+
+	points, err := PointsFromFrames(name, t, frames, extraLabels)
+	if err != nil {
+		return err
+	}
+
+	frame := data.NewFrame(name, data.NewField("time", nil, []time.Time{t}))
+	for _, p := range points {
+		frame.Fields = append(frame.Fields, data.NewField(name, p.Labels, []float64{p.Metric.V}))
+	}
+
+	stream, err := w.runner.GetOrCreateStream(ruleKey.OrgID, live.ScopeStream, LiveNamespace)
+	if err != nil {
+		return fmt.Errorf("failed to get managed stream: %w", err)
+	}
+
+	path := LivePath(name)
+	l.Debug("Writing metric", "name", name, "path", path)
+	if err := w.delivery.do(ctx, ruleKey.OrgID, func(ctx context.Context) error {
+		return retryableIfTransient(stream.Push(ctx, path, frame))
+	}); err != nil {
+		return fmt.Errorf("failed to push frame: %w", err)
+	}
+	return nil
+}
+
+// LivePath returns the path of the managed stream channel a metric is written to.
+// Characters that are not allowed in channel paths, such as colons, are replaced with underscores.
+func LivePath(name string) string {
+	return invalidLivePathChars.ReplaceAllString(name, "_")
+}
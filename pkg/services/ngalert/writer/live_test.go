@@ -0,0 +1,67 @@
+package writer
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/live/managedstream"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestLivePath(t *testing.T) {
+	require.Equal(t, "job_up", LivePath("job:up"))
+	require.Equal(t, "a_b-c.d=e", LivePath("a b-c.d=e"))
+}
+
+func TestLiveWriter_Write(t *testing.T) {
+	published := map[string][]byte{}
+	publisher := func(orgID int64, channel string, data []byte) error {
+		published[channel] = data
+		return nil
+	}
+	cache := managedstream.NewMemoryFrameCache(managedstream.HistoryConfig{})
+	runner := managedstream.NewRunner(publisher, nil, cache)
+
+	settings := setting.RecordingRuleSettings{MaxInflightWrites: 1}
+	writer, err := NewLiveWriter(settings, runner, clock.New(), log.NewNopLogger(), metrics.NewRemoteWriterMetrics(prometheus.NewRegistry()))
+	require.NoError(t, err)
+
+	now := time.Unix(10, 0).UTC()
+	series := []map[string]string{{"foo": "1"}, {"foo": "2"}}
+	frames := frameGenFromLabels(t, data.FrameTypeNumericWide, series)
+	ctx := ngmodels.WithRuleKey(context.Background(), ngmodels.GenerateRuleKey(1))
+
+	require.NoError(t, writer.Write(ctx, "job:up", now, frames, map[string]string{"extra": "label"}))
+
+	channel := "stream/recording_rules/job_up"
+	require.Contains(t, published, channel)
+
+	frameJSON, ok, err := cache.GetFrame(ctx, 1, channel)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	var frame data.Frame
+	require.NoError(t, json.Unmarshal(frameJSON, &frame))
+	require.Len(t, frame.Fields, 3)
+	require.Equal(t, now, frame.Fields[0].At(0))
+	for i, s := range series {
+		f := frame.Fields[i+1]
+		require.Equal(t, data.Labels{"extra": "label", "foo": s["foo"]}, f.Labels)
+		require.Equal(t, extractValue(t, frames, s, data.FrameTypeNumericWide), f.At(0))
+	}
+
+	t.Run("fails when live is not available", func(t *testing.T) {
+		_, err := NewLiveWriter(settings, nil, clock.New(), log.NewNopLogger(), nil)
+		require.Error(t, err)
+	})
+}
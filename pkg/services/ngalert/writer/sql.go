@@ -0,0 +1,203 @@
+package writer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	_ "github.com/lib/pq"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/datasources/sqlconn"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+var validTableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+type DataSourceService interface {
+	GetDataSource(ctx context.Context, query *datasources.GetDataSourceQuery) (*datasources.DataSource, error)
+	DecryptedValues(ctx context.Context, ds *datasources.DataSource) (map[string]string, error)
+}
+
+// SQLWriter writes recording rule results into a table through a PostgreSQL or MySQL data source.
+// Every point is a row with the columns metric, labels (JSON encoded), ts and value.
+type SQLWriter struct {
+	dataSourceUID string
+	table         string
+	dataSources   DataSourceService
+	delivery      *delivery
+	logger        log.Logger
+
+	// openDB opens a database handle. It is replaced in tests.
+	openDB func(driverName, dataSourceName string) (*sql.DB, error)
+
+	mtx   sync.Mutex
+	conns map[int64]*sqlConn
+}
+
+// sqlConn is a database handle of an organization's data source.
+// It is reopened when the data source changes.
+type sqlConn struct {
+	db      *sql.DB
+	dsType  string
+	version int
+}
+
+func NewSQLWriter(settings setting.RecordingRuleSettings, dataSources DataSourceService, clock clock.Clock, l log.Logger, metrics *metrics.RemoteWriter) (*SQLWriter, error) {
+	if settings.DatasourceUID == "" {
+		return nil, fmt.Errorf("datasource UID is required")
+	}
+	if !validTableName.MatchString(settings.Table) {
+		return nil, fmt.Errorf("invalid table name: %q", settings.Table)
+	}
+
+	d, err := newDelivery(BackendTypeSQL, settings, clock, metrics)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SQLWriter{
+		dataSourceUID: settings.DatasourceUID,
+		table:         settings.Table,
+		dataSources:   dataSources,
+		delivery:      d,
+		logger:        l,
+		openDB:        sql.Open,
+		conns:         make(map[int64]*sqlConn),
+	}, nil
+}
+
+// Write inserts the given frames into the table with a single statement.
+func (w *SQLWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, extraLabels map[string]string) error {
+	l := w.logger.FromContext(ctx)
+	ruleKey, found := models.RuleKeyFromContext(ctx)
+	if !found {
+		// sanity check, this should never happen
+		return fmt.Errorf("rule key not found in context")
+	}
+
+	points, err := PointsFromFrames(name, t, frames, extraLabels)
+	if err != nil {
+		return err
+	}
+	if len(points) == 0 {
+		return nil
+	}
+
+	conn, err := w.conn(ctx, ruleKey.OrgID)
+	if err != nil {
+		return err
+	}
+
+	query, args, err := w.insertStatement(conn.dsType, points)
+	if err != nil {
+		return err
+	}
+
+	l.Debug("Writing metric", "name", name)
+	if err := w.delivery.do(ctx, ruleKey.OrgID, func(ctx context.Context) error {
+		_, err := conn.db.ExecContext(ctx, query, args...)
+		return retryableIfTransient(err)
+	}); err != nil {
+		return fmt.Errorf("failed to insert points: %w", err)
+	}
+	return nil
+}
+
+func (w *SQLWriter) insertStatement(dsType string, points []Point) (string, []any, error) {
+	var sb strings.Builder
+	sb.WriteString("INSERT INTO ")
+	sb.WriteString(w.table)
+	sb.WriteString(" (metric, labels, ts, value) VALUES ")
+
+	args := make([]any, 0, len(points)*4)
+	for i, p := range points {
+		labels, err := json.Marshal(p.Labels)
+		if err != nil {
+			return "", nil, err
+		}
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("(")
+		for j := 0; j < 4; j++ {
+			if j > 0 {
+				sb.WriteString(", ")
+			}
+			if dsType == datasources.DS_MYSQL {
+				sb.WriteString("?")
+			} else {
+				sb.WriteString("$" + strconv.Itoa(len(args)+j+1))
+			}
+		}
+		sb.WriteString(")")
+		args = append(args, p.Name, string(labels), p.Metric.T.UTC(), p.Metric.V)
+	}
+	return sb.String(), args, nil
+}
+
+// Close closes the database handles of all organizations. It is called when the rules are no
+// longer evaluated.
+func (w *SQLWriter) Close() error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	var errs []error
+	for orgID, c := range w.conns {
+		if err := c.db.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close database of organization %d: %w", orgID, err))
+		}
+		delete(w.conns, orgID)
+	}
+	return errors.Join(errs...)
+}
+
+// conn returns the database handle of the data source of the organization, opening it if necessary.
+func (w *SQLWriter) conn(ctx context.Context, orgID int64) (*sqlConn, error) {
+	ds, err := w.dataSources.GetDataSource(ctx, &datasources.GetDataSourceQuery{UID: w.dataSourceUID, OrgID: orgID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data source %s: %w", w.dataSourceUID, err)
+	}
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if c, ok := w.conns[orgID]; ok {
+		if c.version == ds.Version && c.dsType == ds.Type {
+			return c, nil
+		}
+		_ = c.db.Close()
+		delete(w.conns, orgID)
+	}
+
+	secrets, err := w.dataSources.DecryptedValues(ctx, ds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data source secrets: %w", err)
+	}
+
+	driverName, dsn, err := sqlconn.ConnectionString(ds, secrets)
+	if err != nil {
+		return nil, fmt.Errorf("data source %s: %w", ds.UID, err)
+	}
+
+	db, err := w.openDB(driverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	c := &sqlConn{db: db, dsType: ds.Type, version: ds.Version}
+	w.conns[orgID] = c
+	return c, nil
+}
@@ -0,0 +1,122 @@
+package writer
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakes "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestSQLWriter_Write(t *testing.T) {
+	// every handle opened by the writer connects to the same in-memory database
+	dsn := "file:" + t.Name() + "?mode=memory&cache=shared"
+	db, err := sql.Open("sqlite3", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	_, err = db.Exec("CREATE TABLE recorded (metric TEXT, labels TEXT, ts TIMESTAMP, value REAL)")
+	require.NoError(t, err)
+
+	ds := &datasources.DataSource{UID: "sql", OrgID: 1, Type: datasources.DS_MYSQL, URL: "localhost:3306", Database: "grafana", Version: 1}
+	dsService := &fakes.FakeDataSourceService{DataSources: []*datasources.DataSource{ds}}
+
+	settings := setting.RecordingRuleSettings{DatasourceUID: "sql", Table: "recorded", MaxInflightWrites: 1}
+	writer, err := NewSQLWriter(settings, dsService, clock.New(), log.NewNopLogger(), metrics.NewRemoteWriterMetrics(prometheus.NewRegistry()))
+	require.NoError(t, err)
+
+	var opened []string
+	writer.openDB = func(driverName, dataSourceName string) (*sql.DB, error) {
+		opened = append(opened, driverName)
+		return sql.Open("sqlite3", dsn)
+	}
+
+	now := time.Unix(10, 0).UTC()
+	series := []map[string]string{{"foo": "1"}, {"foo": "2"}}
+	frames := frameGenFromLabels(t, data.FrameTypeNumericWide, series)
+	ctx := ngmodels.WithRuleKey(context.Background(), ngmodels.GenerateRuleKey(1))
+
+	t.Run("inserts a row per point", func(t *testing.T) {
+		require.NoError(t, writer.Write(ctx, "test", now, frames, map[string]string{"extra": "label"}))
+		require.NoError(t, writer.Write(ctx, "test", now, frames, map[string]string{"extra": "label"}))
+		require.Equal(t, []string{"mysql"}, opened, "should reuse the connection")
+
+		rows, err := db.Query("SELECT metric, labels, value FROM recorded ORDER BY labels")
+		require.NoError(t, err)
+		defer func() { _ = rows.Close() }()
+		var got []string
+		for rows.Next() {
+			var metric, labels string
+			var value float64
+			require.NoError(t, rows.Scan(&metric, &labels, &value))
+			got = append(got, metric+" "+labels)
+		}
+		require.NoError(t, rows.Err())
+		require.Equal(t, []string{
+			`test {"extra":"label","foo":"1"}`,
+			`test {"extra":"label","foo":"1"}`,
+			`test {"extra":"label","foo":"2"}`,
+			`test {"extra":"label","foo":"2"}`,
+		}, got)
+	})
+
+	t.Run("reopens the connection when the data source changes", func(t *testing.T) {
+		ds.Version = 2
+		require.NoError(t, writer.Write(ctx, "test", now, frames, nil))
+		require.Len(t, opened, 2)
+	})
+
+	t.Run("closes the connections", func(t *testing.T) {
+		conn, err := writer.conn(ctx, 1)
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+		require.Empty(t, writer.conns)
+		require.ErrorContains(t, conn.db.Ping(), "closed")
+
+		require.NoError(t, writer.Write(ctx, "test", now, frames, nil))
+		require.Len(t, opened, 3, "should reopen the connection")
+	})
+
+	t.Run("fails when data source type is not supported", func(t *testing.T) {
+		ds.Type = datasources.DS_PROMETHEUS
+		t.Cleanup(func() { ds.Type = datasources.DS_MYSQL })
+		require.ErrorContains(t, writer.Write(ctx, "test", now, frames, nil), "is not supported")
+	})
+}
+
+func TestSQLWriter_InsertStatement(t *testing.T) {
+	w := &SQLWriter{table: "recorded"}
+	points := []Point{
+		{Name: "a", Labels: map[string]string{}, Metric: Metric{T: time.Unix(1, 0), V: 1}},
+		{Name: "b", Labels: map[string]string{}, Metric: Metric{T: time.Unix(1, 0), V: 2}},
+	}
+
+	q, args, err := w.insertStatement(datasources.DS_POSTGRES, points)
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO recorded (metric, labels, ts, value) VALUES ($1, $2, $3, $4), ($5, $6, $7, $8)", q)
+	require.Len(t, args, 8)
+
+	q, _, err = w.insertStatement(datasources.DS_MYSQL, points)
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO recorded (metric, labels, ts, value) VALUES (?, ?, ?, ?), (?, ?, ?, ?)", q)
+}
+
+func TestNewSQLWriter(t *testing.T) {
+	for _, table := range []string{"", "drop table;", "a.b.c"} {
+		_, err := NewSQLWriter(setting.RecordingRuleSettings{DatasourceUID: "sql", Table: table, MaxInflightWrites: 1}, nil, clock.New(), log.NewNopLogger(), nil)
+		require.Error(t, err, table)
+	}
+	_, err := NewSQLWriter(setting.RecordingRuleSettings{Table: "recorded", MaxInflightWrites: 1}, nil, clock.New(), log.NewNopLogger(), nil)
+	require.Error(t, err)
+}
@@ -33,6 +33,7 @@ const (
 	testFileCorrectProperties_t         = "./testdata/templates/correct-properties"
 	testFileCorrectPropertiesWithOrg_t  = "./testdata/templates/correct-properties-with-org"
 	testFileMultipleTs                  = "./testdata/templates/multiple-templates"
+	testFileCorrectProperties_mw        = "./testdata/maintenance_windows/correct-properties"
 )
 
 func TestConfigReader(t *testing.T) {
@@ -159,4 +160,17 @@ func TestConfigReader(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, file[0].Templates, 2)
 	})
+	t.Run("a maintenance windows file with correct properties should not error", func(t *testing.T) {
+		file, err := configReader.readConfig(ctx, testFileCorrectProperties_mw)
+		require.NoError(t, err)
+		require.Len(t, file[0].MaintenanceWindows, 1)
+		mw := file[0].MaintenanceWindows[0]
+		require.Equal(t, int64(1337), mw.OrgID)
+		require.Equal(t, "db-maintenance", mw.MaintenanceWindow.UID)
+		require.Equal(t, "pause", mw.MaintenanceWindow.Mode)
+		require.Len(t, mw.MaintenanceWindow.TimeIntervals, 1)
+		require.Len(t, mw.MaintenanceWindow.ObjectMatchers, 1)
+		require.Equal(t, []string{"databases"}, mw.MaintenanceWindow.FolderUIDs)
+		require.Equal(t, []DeleteMaintenanceWindow{{OrgID: 1, UID: "old-maintenance"}}, file[0].DeleteMaintenanceWindows)
+	})
 }
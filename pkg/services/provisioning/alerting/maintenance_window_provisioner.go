@@ -0,0 +1,87 @@
+package alerting
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+type MaintenanceWindowProvisioner interface {
+	Provision(ctx context.Context, files []*AlertingFile) error
+	Unprovision(ctx context.Context, files []*AlertingFile) error
+}
+
+type defaultMaintenanceWindowProvisioner struct {
+	logger                   log.Logger
+	maintenanceWindowService provisioning.MaintenanceWindowService
+}
+
+func NewMaintenanceWindowProvisioner(logger log.Logger,
+	maintenanceWindowService provisioning.MaintenanceWindowService) MaintenanceWindowProvisioner {
+	return &defaultMaintenanceWindowProvisioner{
+		logger:                   logger,
+		maintenanceWindowService: maintenanceWindowService,
+	}
+}
+
+// Provision creates or updates the maintenance windows of the files. A window that has no UID is matched with the
+// existing windows by its title.
+func (c *defaultMaintenanceWindowProvisioner) Provision(ctx context.Context,
+	files []*AlertingFile) error {
+	cache := map[int64][]definitions.MaintenanceWindow{}
+	for _, file := range files {
+		for _, window := range file.MaintenanceWindows {
+			if _, exists := cache[window.OrgID]; !exists {
+				windows, err := c.maintenanceWindowService.GetMaintenanceWindows(ctx, window.OrgID)
+				if err != nil {
+					return err
+				}
+				cache[window.OrgID] = windows
+			}
+			window.MaintenanceWindow.Provenance = definitions.Provenance(models.ProvenanceFile)
+			window.MaintenanceWindow.Version = 0
+			if existing, ok := findMaintenanceWindow(cache[window.OrgID], window.MaintenanceWindow); ok {
+				window.MaintenanceWindow.UID = existing.UID
+				_, err := c.maintenanceWindowService.UpdateMaintenanceWindow(ctx, window.MaintenanceWindow, window.OrgID)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			created, err := c.maintenanceWindowService.CreateMaintenanceWindow(ctx, window.MaintenanceWindow, window.OrgID)
+			if err != nil {
+				return err
+			}
+			cache[window.OrgID] = append(cache[window.OrgID], created)
+		}
+	}
+	return nil
+}
+
+func (c *defaultMaintenanceWindowProvisioner) Unprovision(ctx context.Context,
+	files []*AlertingFile) error {
+	for _, file := range files {
+		for _, deleteWindow := range file.DeleteMaintenanceWindows {
+			err := c.maintenanceWindowService.DeleteMaintenanceWindow(ctx, deleteWindow.UID, deleteWindow.OrgID, definitions.Provenance(models.ProvenanceFile), 0)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func findMaintenanceWindow(windows []definitions.MaintenanceWindow, window definitions.MaintenanceWindow) (definitions.MaintenanceWindow, bool) {
+	for _, w := range windows {
+		if window.UID != "" && w.UID == window.UID {
+			return w, true
+		}
+		if window.UID == "" && w.Title == window.Title {
+			return w, true
+		}
+	}
+	return definitions.MaintenanceWindow{}, false
+}
@@ -0,0 +1,58 @@
+package alerting
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+type MaintenanceWindowV1 struct {
+	OrgID             values.Int64Value             `json:"orgId" yaml:"orgId"`
+	MaintenanceWindow definitions.MaintenanceWindow `json:",inline" yaml:",inline"`
+}
+
+func (v1 *MaintenanceWindowV1) mapToModel() (MaintenanceWindow, error) {
+	if strings.TrimSpace(v1.MaintenanceWindow.UID) == "" && strings.TrimSpace(v1.MaintenanceWindow.Title) == "" {
+		return MaintenanceWindow{}, errors.New("maintenance window missing uid and title")
+	}
+	orgID := v1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	return MaintenanceWindow{
+		OrgID:             orgID,
+		MaintenanceWindow: v1.MaintenanceWindow,
+	}, nil
+}
+
+type MaintenanceWindow struct {
+	OrgID             int64
+	MaintenanceWindow definitions.MaintenanceWindow
+}
+
+type DeleteMaintenanceWindowV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	UID   values.StringValue `json:"uid" yaml:"uid"`
+}
+
+func (v1 *DeleteMaintenanceWindowV1) mapToModel() (DeleteMaintenanceWindow, error) {
+	uid := strings.TrimSpace(v1.UID.Value())
+	if uid == "" {
+		return DeleteMaintenanceWindow{}, errors.New("delete maintenance window missing uid")
+	}
+	orgID := v1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	return DeleteMaintenanceWindow{
+		OrgID: orgID,
+		UID:   uid,
+	}, nil
+}
+
+type DeleteMaintenanceWindow struct {
+	OrgID int64
+	UID   string
+}
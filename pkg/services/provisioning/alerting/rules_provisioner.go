@@ -56,7 +56,7 @@ func (prov *defaultAlertRuleProvisioner) Provision(ctx context.Context,
 				"folder", group.FolderFullpath,
 				"folderUID", folderUID,
 				"name", group.Title)
-			for _, rule := range group.Rules {
+			for _, rule := range orderByDependencies(group.Rules) {
 				rule.NamespaceUID = folderUID
 				rule.RuleGroup = group.Title
 				err = prov.provisionRule(ctx, u, rule)
@@ -79,6 +79,38 @@ func (prov *defaultAlertRuleProvisioner) Provision(ctx context.Context,
 	return nil
 }
 
+// orderByDependencies orders the rules so that the rules they depend on in the same group come first. Every rule is
+// validated against the rules that are already provisioned, so a rule must be provisioned after the rules it depends
+// on. Rules that are part of a dependency cycle keep their order, their validation fails.
+func orderByDependencies(rules []alert_models.AlertRule) []alert_models.AlertRule {
+	indexes := make(map[string]int, len(rules))
+	for i, rule := range rules {
+		indexes[rule.UID] = i
+	}
+	result := make([]alert_models.AlertRule, 0, len(rules))
+	added := make([]bool, len(rules))
+	visiting := make([]bool, len(rules))
+	var add func(i int)
+	add = func(i int) {
+		if added[i] || visiting[i] {
+			return
+		}
+		visiting[i] = true
+		for _, uid := range rules[i].DependsOn {
+			if dep, ok := indexes[uid]; ok {
+				add(dep)
+			}
+		}
+		visiting[i] = false
+		added[i] = true
+		result = append(result, rules[i])
+	}
+	for i := range rules {
+		add(i)
+	}
+	return result
+}
+
 func (prov *defaultAlertRuleProvisioner) provisionRule(
 	ctx context.Context,
 	user identity.Requester,
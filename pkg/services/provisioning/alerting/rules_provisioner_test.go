@@ -0,0 +1,32 @@
+package alerting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	alert_models "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestOrderByDependencies(t *testing.T) {
+	rule := func(uid string, dependsOn ...string) alert_models.AlertRule {
+		return alert_models.AlertRule{UID: uid, DependsOn: dependsOn}
+	}
+	uids := func(rules []alert_models.AlertRule) []string {
+		result := make([]string, 0, len(rules))
+		for _, r := range rules {
+			result = append(result, r.UID)
+		}
+		return result
+	}
+
+	t.Run("should provision dependencies first", func(t *testing.T) {
+		rules := []alert_models.AlertRule{rule("c", "b"), rule("b", "a", "other-group"), rule("a"), rule("d")}
+		require.Equal(t, []string{"a", "b", "c", "d"}, uids(orderByDependencies(rules)))
+	})
+
+	t.Run("should keep all rules of cycles", func(t *testing.T) {
+		rules := []alert_models.AlertRule{rule("a", "b"), rule("b", "a")}
+		require.Equal(t, []string{"b", "a"}, uids(orderByDependencies(rules)))
+	})
+}
@@ -0,0 +1,69 @@
+package live
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+)
+
+// Provision scans a directory for provisioning config files
+// and provisions the Live channel rules in those files.
+func Provision(ctx context.Context, configDirectory string, storage pipeline.Storage) error {
+	logger := log.New("provisioning.live")
+	p := ChannelRuleProvisioner{
+		log:         logger,
+		cfgProvider: newConfigReader(logger),
+		storage:     storage,
+	}
+	return p.applyChanges(ctx, configDirectory)
+}
+
+// ChannelRuleProvisioner is responsible for provisioning Live channel rules based on
+// configuration read by the `configReader`. Provisioned channel rules replace the
+// channel rules with the same pattern, including the changes made through the API.
+type ChannelRuleProvisioner struct {
+	log         log.Logger
+	cfgProvider configReader
+	storage     pipeline.Storage
+}
+
+func (p *ChannelRuleProvisioner) apply(ctx context.Context, cfg *channelRulesAsConfig) error {
+	for _, rule := range cfg.DeleteChannelRules {
+		p.log.Info("Deleting channel rule from configuration", "orgId", rule.OrgID, "pattern", rule.Pattern)
+		err := p.storage.DeleteChannelRule(ctx, rule.OrgID, pipeline.ChannelRuleDeleteCmd{Pattern: rule.Pattern})
+		if err != nil && !errors.Is(err, pipeline.ErrChannelRuleNotFound) {
+			return fmt.Errorf("failed to delete channel rule %s: %w", rule.Pattern, err)
+		}
+	}
+
+	for _, rule := range cfg.ChannelRules {
+		p.log.Info("Updating channel rule from configuration", "orgId", rule.OrgID, "pattern", rule.Pattern)
+		_, err := p.storage.UpdateChannelRule(ctx, rule.OrgID, pipeline.ChannelRuleUpdateCmd{
+			Pattern:  rule.Pattern,
+			Settings: rule.Settings,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to provision channel rule %s: %w", rule.Pattern, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *ChannelRuleProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	configs, err := p.cfgProvider.readConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	for _, cfg := range configs {
+		if err := p.apply(ctx, cfg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
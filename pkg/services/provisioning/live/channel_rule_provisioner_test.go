@@ -0,0 +1,90 @@
+package live
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+)
+
+func TestChannelRuleProvisioner(t *testing.T) {
+	t.Run("Should return error when config reader returns error", func(t *testing.T) {
+		expectedErr := errors.New("test")
+		p := ChannelRuleProvisioner{log: log.New("test"), cfgProvider: &testConfigReader{err: expectedErr}}
+		err := p.applyChanges(context.Background(), "")
+		require.Equal(t, expectedErr, err)
+	})
+
+	t.Run("Should apply configurations", func(t *testing.T) {
+		settings := pipeline.ChannelRuleSettings{Converter: &pipeline.ConverterConfig{Type: "jsonAuto"}}
+		reader := &testConfigReader{result: []*channelRulesAsConfig{{
+			ChannelRules: []*channelRuleFromConfig{
+				{OrgID: 1, Pattern: "stream/sensors/:id", Settings: settings},
+				{OrgID: 2, Pattern: "stream/sensors/room1"},
+			},
+			DeleteChannelRules: []*deleteChannelRuleConfig{
+				{OrgID: 1, Pattern: "stream/sensors/old"},
+				{OrgID: 2, Pattern: "stream/sensors/missing"},
+			},
+		}}}
+		storage := &fakeStorage{}
+		p := ChannelRuleProvisioner{log: log.New("test"), cfgProvider: reader, storage: storage}
+
+		err := p.applyChanges(context.Background(), "")
+		require.NoError(t, err)
+		require.Equal(t, []string{"1:stream/sensors/old", "2:stream/sensors/missing"}, storage.deleted)
+		require.Equal(t, []pipeline.ChannelRule{
+			{OrgId: 1, Pattern: "stream/sensors/:id", Settings: settings},
+			{OrgId: 2, Pattern: "stream/sensors/room1"},
+		}, storage.updated)
+	})
+
+	t.Run("Should return storage errors", func(t *testing.T) {
+		reader := &testConfigReader{result: []*channelRulesAsConfig{{
+			ChannelRules: []*channelRuleFromConfig{{OrgID: 1, Pattern: "stream/sensors/:id"}},
+		}}}
+		storage := &fakeStorage{updateErr: pipeline.ErrVersionConflict}
+		p := ChannelRuleProvisioner{log: log.New("test"), cfgProvider: reader, storage: storage}
+
+		err := p.applyChanges(context.Background(), "")
+		require.ErrorIs(t, err, pipeline.ErrVersionConflict)
+	})
+}
+
+type testConfigReader struct {
+	result []*channelRulesAsConfig
+	err    error
+}
+
+func (tcr *testConfigReader) readConfig(_ string) ([]*channelRulesAsConfig, error) {
+	return tcr.result, tcr.err
+}
+
+type fakeStorage struct {
+	pipeline.Storage
+	updated   []pipeline.ChannelRule
+	deleted   []string
+	updateErr error
+}
+
+func (s *fakeStorage) UpdateChannelRule(_ context.Context, orgID int64, cmd pipeline.ChannelRuleUpdateCmd) (pipeline.ChannelRule, error) {
+	if s.updateErr != nil {
+		return pipeline.ChannelRule{}, s.updateErr
+	}
+	rule := pipeline.ChannelRule{OrgId: orgID, Pattern: cmd.Pattern, Settings: cmd.Settings}
+	s.updated = append(s.updated, rule)
+	return rule, nil
+}
+
+func (s *fakeStorage) DeleteChannelRule(_ context.Context, orgID int64, cmd pipeline.ChannelRuleDeleteCmd) error {
+	s.deleted = append(s.deleted, fmt.Sprintf("%d:%s", orgID, cmd.Pattern))
+	if cmd.Pattern == "stream/sensors/missing" {
+		return pipeline.ErrChannelRuleNotFound
+	}
+	return nil
+}
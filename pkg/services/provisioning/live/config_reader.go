@@ -0,0 +1,107 @@
+package live
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+type configReader interface {
+	readConfig(path string) ([]*channelRulesAsConfig, error)
+}
+
+type configReaderImpl struct {
+	log log.Logger
+}
+
+func newConfigReader(logger log.Logger) configReader {
+	return &configReaderImpl{log: logger}
+}
+
+func (cr *configReaderImpl) readConfig(path string) ([]*channelRulesAsConfig, error) {
+	var configs []*channelRulesAsConfig
+	cr.log.Debug("Looking for Live provisioning files", "path", path)
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return configs, nil
+		}
+		cr.log.Error("Failed to read Live provisioning files from directory", "path", path, "error", err)
+		return configs, nil
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
+			cr.log.Debug("Parsing Live provisioning file", "path", path, "file.Name", file.Name())
+			cfg, err := cr.parseConfig(path, file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", file.Name(), err)
+			}
+			configs = append(configs, cfg)
+		}
+	}
+
+	if err := validateChannelRules(configs); err != nil {
+		return nil, err
+	}
+
+	return configs, nil
+}
+
+func (cr *configReaderImpl) parseConfig(path string, file fs.DirEntry) (*channelRulesAsConfig, error) {
+	filename, err := filepath.Abs(filepath.Join(path, file.Name()))
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg *channelRulesAsConfigV1
+	err = yaml.Unmarshal(yamlFile, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg.mapToChannelRulesFromConfig()
+}
+
+// validateChannelRules checks that all channel rules have a pattern, and that a pattern is not
+// declared twice in an organization.
+func validateChannelRules(configs []*channelRulesAsConfig) error {
+	type orgPattern struct {
+		orgID   int64
+		pattern string
+	}
+	seen := map[orgPattern]struct{}{}
+	for _, cfg := range configs {
+		for index, rule := range cfg.ChannelRules {
+			if rule.Pattern == "" {
+				return fmt.Errorf("channel rule %d in configuration doesn't contain required field pattern", index+1)
+			}
+			key := orgPattern{orgID: rule.OrgID, pattern: rule.Pattern}
+			if _, ok := seen[key]; ok {
+				return fmt.Errorf("channel rule %s is declared more than once in organization %d", rule.Pattern, rule.OrgID)
+			}
+			seen[key] = struct{}{}
+		}
+		for index, rule := range cfg.DeleteChannelRules {
+			if rule.Pattern == "" {
+				return fmt.Errorf("deleted channel rule %d in configuration doesn't contain required field pattern", index+1)
+			}
+		}
+	}
+	return nil
+}
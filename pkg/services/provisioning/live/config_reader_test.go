@@ -0,0 +1,86 @@
+package live
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+const (
+	correctProperties = "./testdata/correct-properties"
+	brokenYaml        = "./testdata/broken-yaml"
+	missingPattern    = "./testdata/missing-pattern"
+	duplicatePattern  = "./testdata/duplicate-pattern"
+	unknownField      = "./testdata/unknown-field"
+)
+
+func TestConfigReader(t *testing.T) {
+	t.Run("Broken yaml should return error", func(t *testing.T) {
+		reader := newConfigReader(log.New("test logger"))
+		_, err := reader.readConfig(brokenYaml)
+		require.Error(t, err)
+	})
+
+	t.Run("Skip missing directory", func(t *testing.T) {
+		reader := newConfigReader(log.New("test logger"))
+		cfg, err := reader.readConfig("./testdata/missing")
+		require.NoError(t, err)
+		require.Len(t, cfg, 0)
+	})
+
+	t.Run("Channel rule without pattern should return error", func(t *testing.T) {
+		reader := newConfigReader(log.New("test logger"))
+		_, err := reader.readConfig(missingPattern)
+		require.EqualError(t, err, "channel rule 1 in configuration doesn't contain required field pattern")
+	})
+
+	t.Run("Channel rule declared twice should return error", func(t *testing.T) {
+		reader := newConfigReader(log.New("test logger"))
+		_, err := reader.readConfig(duplicatePattern)
+		require.EqualError(t, err, "channel rule stream/sensors/:id is declared more than once in organization 1")
+	})
+
+	t.Run("Unknown settings field should return error", func(t *testing.T) {
+		reader := newConfigReader(log.New("test logger"))
+		_, err := reader.readConfig(unknownField)
+		require.ErrorContains(t, err, "convertor")
+	})
+
+	t.Run("Can read correct properties", func(t *testing.T) {
+		t.Setenv("SENSORS_NAMESPACE", "office")
+
+		reader := newConfigReader(log.New("test logger"))
+		cfg, err := reader.readConfig(correctProperties)
+		require.NoError(t, err)
+		require.Len(t, cfg, 1)
+
+		require.Equal(t, []*deleteChannelRuleConfig{{OrgID: 2, Pattern: "stream/sensors/old"}}, cfg[0].DeleteChannelRules)
+		require.Equal(t, []*channelRuleFromConfig{
+			{
+				OrgID:   1,
+				Pattern: "stream/sensors/:id",
+				Settings: pipeline.ChannelRuleSettings{
+					Converter:       &pipeline.ConverterConfig{Type: "jsonAuto"},
+					FrameOutputters: []*pipeline.FrameOutputterConfig{{Type: "managedStream"}},
+				},
+			},
+			{
+				OrgID:   2,
+				Pattern: "stream/office/room1",
+				Settings: pipeline.ChannelRuleSettings{
+					Auth: &pipeline.ChannelAuthConfig{
+						Publish: &pipeline.ChannelAuthCheckConfig{RequireRole: org.RoleEditor},
+					},
+					FrameOutputters: []*pipeline.FrameOutputterConfig{{
+						Type:                    "remoteWrite",
+						RemoteWriteOutputConfig: &pipeline.RemoteWriteOutputConfig{UID: "prometheus", SampleMilliseconds: 1000},
+					}},
+				},
+			},
+		}, cfg[0].ChannelRules)
+	})
+}
@@ -0,0 +1,95 @@
+package live
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+// channelRulesAsConfig is a normalized data object for Live channel rules config data. Any config version
+// should be mappable to this type.
+type channelRulesAsConfig struct {
+	ChannelRules       []*channelRuleFromConfig
+	DeleteChannelRules []*deleteChannelRuleConfig
+}
+
+type channelRuleFromConfig struct {
+	OrgID    int64
+	Pattern  string
+	Settings pipeline.ChannelRuleSettings
+}
+
+type deleteChannelRuleConfig struct {
+	OrgID   int64
+	Pattern string
+}
+
+// channelRulesAsConfigV1 is a mapping for version 1 configs.
+type channelRulesAsConfigV1 struct {
+	APIVersion         values.Int64Value            `json:"apiVersion" yaml:"apiVersion"`
+	ChannelRules       []*channelRuleFromConfigV1   `json:"channelRules" yaml:"channelRules"`
+	DeleteChannelRules []*deleteChannelRuleConfigV1 `json:"deleteChannelRules" yaml:"deleteChannelRules"`
+}
+
+type channelRuleFromConfigV1 struct {
+	OrgID    values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Pattern  values.StringValue `json:"pattern" yaml:"pattern"`
+	Settings values.JSONValue   `json:"settings" yaml:"settings"`
+}
+
+type deleteChannelRuleConfigV1 struct {
+	OrgID   values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Pattern values.StringValue `json:"pattern" yaml:"pattern"`
+}
+
+// mapToChannelRulesFromConfig maps config syntax to a normalized channelRulesAsConfig object. Settings are
+// decoded like the channel rules of the HTTP API.
+func (cfg *channelRulesAsConfigV1) mapToChannelRulesFromConfig() (*channelRulesAsConfig, error) {
+	r := &channelRulesAsConfig{}
+	if cfg == nil {
+		return r, nil
+	}
+
+	for _, rule := range cfg.ChannelRules {
+		settings, err := decodeSettings(rule.Settings.Value())
+		if err != nil {
+			return nil, fmt.Errorf("invalid settings of channel rule %s: %w", rule.Pattern.Value(), err)
+		}
+		r.ChannelRules = append(r.ChannelRules, &channelRuleFromConfig{
+			OrgID:    orgIDOrDefault(rule.OrgID.Value()),
+			Pattern:  rule.Pattern.Value(),
+			Settings: settings,
+		})
+	}
+
+	for _, rule := range cfg.DeleteChannelRules {
+		r.DeleteChannelRules = append(r.DeleteChannelRules, &deleteChannelRuleConfig{
+			OrgID:   orgIDOrDefault(rule.OrgID.Value()),
+			Pattern: rule.Pattern.Value(),
+		})
+	}
+
+	return r, nil
+}
+
+func decodeSettings(raw map[string]any) (pipeline.ChannelRuleSettings, error) {
+	var settings pipeline.ChannelRuleSettings
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return settings, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(&settings)
+	return settings, err
+}
+
+func orgIDOrDefault(orgID int64) int64 {
+	if orgID < 1 {
+		return 1
+	}
+	return orgID
+}
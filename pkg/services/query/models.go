@@ -21,6 +21,8 @@ type parsedRequest struct {
 	hasExpression bool
 	parsedQueries map[string][]parsedQuery
 	dsTypes       map[string]bool
+	// debug is true if the results of expressions should include the debug information of the pipeline.
+	debug bool
 }
 
 func (pr parsedRequest) getFlattenedQueries() []parsedQuery {
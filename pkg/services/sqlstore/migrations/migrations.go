@@ -125,6 +125,18 @@ func (oss *OSSMigrations) AddMigration(mg *Migrator) {
 	ualert.AddRecordingRuleColumns(mg)
 
 	ualert.AddStateResolvedAtColumns(mg)
+
+	ualert.AddKeepFiringForColumns(mg)
+
+	ualert.AddRuleDependenciesColumns(mg)
+
+	ualert.AddStateHistoryTables(mg)
+
+	ualert.AddMaintenanceWindowTable(mg)
+
+	ualert.AddAlertInstanceAcknowledgementColumns(mg)
+
+	addLivePipelineMigrations(mg)
 }
 
 func addStarMigrations(mg *Migrator) {
@@ -0,0 +1,33 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddAlertInstanceAcknowledgementColumns adds columns to alert_instance for the acknowledgement of a firing instance.
+func AddAlertInstanceAcknowledgementColumns(mg *migrator.Migrator) {
+	alertInstance := migrator.Table{Name: "alert_instance"}
+
+	mg.AddMigration("add acknowledged_by column to alert_instance table", migrator.NewAddColumnMigration(alertInstance, &migrator.Column{
+		Name:     "acknowledged_by",
+		Type:     migrator.DB_NVarchar,
+		Length:   DefaultFieldMaxLength,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add acknowledgement_comment column to alert_instance table", migrator.NewAddColumnMigration(alertInstance, &migrator.Column{
+		Name:     "acknowledgement_comment",
+		Type:     migrator.DB_Text,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add acknowledged_at column to alert_instance table", migrator.NewAddColumnMigration(alertInstance, &migrator.Column{
+		Name:     "acknowledged_at",
+		Type:     migrator.DB_BigInt, // BigInt, to match existing time fields.
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add acknowledgement_expires_at column to alert_instance table", migrator.NewAddColumnMigration(alertInstance, &migrator.Column{
+		Name:     "acknowledgement_expires_at",
+		Type:     migrator.DB_BigInt,
+		Nullable: true,
+	}))
+}
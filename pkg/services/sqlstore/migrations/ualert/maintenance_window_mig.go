@@ -0,0 +1,28 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddMaintenanceWindowTable creates the table that stores the maintenance windows of alert rules.
+func AddMaintenanceWindowTable(mg *migrator.Migrator) {
+	maintenanceWindow := migrator.Table{
+		Name: "alert_maintenance_window",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "title", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "mode", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "data", Type: migrator.DB_Text, Nullable: false},
+			{Name: "version", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "uid"}, Type: migrator.UniqueIndex},
+			{Cols: []string{"org_id", "title"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_maintenance_window table", migrator.NewAddTableMigration(maintenanceWindow))
+	mg.AddMigration("add unique index in alert_maintenance_window on org_id and uid columns", migrator.NewAddIndexMigration(maintenanceWindow, maintenanceWindow.Indices[0]))
+	mg.AddMigration("add unique index in alert_maintenance_window on org_id and title columns", migrator.NewAddIndexMigration(maintenanceWindow, maintenanceWindow.Indices[1]))
+}
@@ -0,0 +1,18 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddRuleDependenciesColumns adds columns to alert_rule and alert_rule_version for the UIDs of the rules a rule depends on.
+func AddRuleDependenciesColumns(mg *migrator.Migrator) {
+	mg.AddMigration("add depends_on column to alert_rule", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name:     "depends_on",
+		Type:     migrator.DB_Text,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add depends_on column to alert_rule_version", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name:     "depends_on",
+		Type:     migrator.DB_Text,
+		Nullable: true,
+	}))
+}
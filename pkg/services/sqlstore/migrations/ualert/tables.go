@@ -193,6 +193,14 @@ func alertInstanceMigration(mg *migrator.Migrator) {
 	mg.AddMigration("add result_fingerprint column to alert_instance", migrator.NewAddColumnMigration(alertInstance, &migrator.Column{
 		Name: "result_fingerprint", Type: migrator.DB_NVarchar, Length: 16, Nullable: true,
 	}))
+
+	mg.AddMigration("add hysteresis_level column to alert_instance", migrator.NewAddColumnMigration(alertInstance, &migrator.Column{
+		Name: "hysteresis_level", Type: migrator.DB_BigInt, Nullable: false, Default: "0",
+	}))
+
+	mg.AddMigration("add hysteresis_unloading column to alert_instance", migrator.NewAddColumnMigration(alertInstance, &migrator.Column{
+		Name: "hysteresis_unloading", Type: migrator.DB_BigInt, Nullable: false, Default: "0",
+	}))
 }
 
 func addAlertRuleMigrations(mg *migrator.Migrator, defaultIntervalSeconds int64) {
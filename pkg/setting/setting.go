@@ -409,6 +409,12 @@ type Cfg struct {
 
 	// ExpressionsEnabled specifies whether expressions are enabled.
 	ExpressionsEnabled bool
+	// SQLExpressionsEngine is the engine that runs SQL expressions, duckdb or embedded.
+	SQLExpressionsEngine string
+	// SQLExpressionsMaxRows is the maximum number of rows a SQL expression can return with the embedded engine.
+	SQLExpressionsMaxRows int64
+	// SQLExpressionsMaxDatabaseBytes is the maximum size of the input tables of a SQL expression with the embedded engine.
+	SQLExpressionsMaxDatabaseBytes int64
 
 	ImageUploadProvider string
 
@@ -425,6 +431,19 @@ type Cfg struct {
 	// LiveAllowedOrigins is a set of origins accepted by Live. If not provided
 	// then Live uses AppURL as the only allowed origin.
 	LiveAllowedOrigins []string
+	// LiveManagedStreamHistorySize is the maximum number of recent frames kept per managed stream
+	// channel and replayed to new subscribers.
+	LiveManagedStreamHistorySize int
+	// LiveManagedStreamHistoryDuration is the maximum age of the recent frames kept per managed
+	// stream channel. The history is disabled if both its size and duration are zero.
+	LiveManagedStreamHistoryDuration time.Duration
+	// LiveManagedStreamHistoryMaxBytesPerOrg limits the size of the managed stream history of an org.
+	LiveManagedStreamHistoryMaxBytesPerOrg int64
+	// LivePipelineEnabled enables Live pipeline that processes the data pushed to
+	// channels according to channel rules.
+	LivePipelineEnabled bool
+	// LiveMQTT configures the MQTT listener that pushes to Live pipeline channels.
+	LiveMQTT LiveMQTTSettings
 
 	// Grafana.com URL, used for OAuth redirect.
 	GrafanaComURL string
@@ -733,6 +752,9 @@ func (cfg *Cfg) readAnnotationSettings() error {
 func (cfg *Cfg) readExpressionsSettings() {
 	expressions := cfg.Raw.Section("expressions")
 	cfg.ExpressionsEnabled = expressions.Key("enabled").MustBool(true)
+	cfg.SQLExpressionsEngine = valueAsString(expressions, "sql_engine", "duckdb")
+	cfg.SQLExpressionsMaxRows = expressions.Key("sql_max_rows").MustInt64(100000)
+	cfg.SQLExpressionsMaxDatabaseBytes = expressions.Key("sql_max_database_bytes").MustInt64(64 * 1024 * 1024)
 }
 
 type AnnotationCleanupSettings struct {
@@ -2022,7 +2044,22 @@ func (cfg *Cfg) readLiveSettings(iniFile *ini.File) error {
 	}
 
 	cfg.LiveAllowedOrigins = originPatterns
-	return nil
+	cfg.LiveManagedStreamHistorySize = section.Key("managed_stream_history_size").MustInt(0)
+	if cfg.LiveManagedStreamHistorySize < 0 {
+		return fmt.Errorf("unexpected value %d for [live] managed_stream_history_size", cfg.LiveManagedStreamHistorySize)
+	}
+	historyDuration, err := gtime.ParseDuration(valueAsString(section, "managed_stream_history_duration", "0s"))
+	if err != nil {
+		return fmt.Errorf("invalid value for [live] managed_stream_history_duration: %w", err)
+	}
+	if historyDuration < 0 {
+		return fmt.Errorf("unexpected value %s for [live] managed_stream_history_duration", historyDuration)
+	}
+	cfg.LiveManagedStreamHistoryDuration = historyDuration
+	cfg.LiveManagedStreamHistoryMaxBytesPerOrg = section.Key("managed_stream_history_max_bytes_per_org").MustInt64(10 * 1024 * 1024)
+	cfg.LivePipelineEnabled = section.Key("pipeline_enabled").MustBool(false)
+
+	return cfg.readLiveMQTTSettings(iniFile)
 }
 
 func (cfg *Cfg) readPublicDashboardsSettings() {
@@ -0,0 +1,41 @@
+package setting
+
+import (
+	"errors"
+	"fmt"
+
+	"gopkg.in/ini.v1"
+)
+
+type LiveMQTTSettings struct {
+	Enabled bool
+	// ListenAddress is the address the MQTT listener binds to.
+	ListenAddress string
+	// CertFile and KeyFile enable MQTT over TLS when both are set.
+	CertFile string
+	KeyFile  string
+	// MaxPayloadSize is the maximum size of a PUBLISH payload in bytes.
+	MaxPayloadSize int
+}
+
+func (cfg *Cfg) readLiveMQTTSettings(iniFile *ini.File) error {
+	section := iniFile.Section("live.mqtt")
+	cfg.LiveMQTT.Enabled = section.Key("enabled").MustBool(false)
+	cfg.LiveMQTT.ListenAddress = section.Key("listen_address").MustString("0.0.0.0:1883")
+	cfg.LiveMQTT.CertFile = section.Key("cert_file").MustString("")
+	cfg.LiveMQTT.KeyFile = section.Key("key_file").MustString("")
+	cfg.LiveMQTT.MaxPayloadSize = section.Key("max_payload_size").MustInt(64 * 1024)
+	if !cfg.LiveMQTT.Enabled {
+		return nil
+	}
+	if !cfg.LivePipelineEnabled {
+		return errors.New("[live.mqtt] requires Live pipeline, set [live] pipeline_enabled to true")
+	}
+	if (cfg.LiveMQTT.CertFile == "") != (cfg.LiveMQTT.KeyFile == "") {
+		return errors.New("[live.mqtt] cert_file and key_file must be set together")
+	}
+	if cfg.LiveMQTT.MaxPayloadSize <= 0 {
+		return fmt.Errorf("unexpected value %d for [live.mqtt] max_payload_size", cfg.LiveMQTT.MaxPayloadSize)
+	}
+	return nil
+}
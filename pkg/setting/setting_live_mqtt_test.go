@@ -0,0 +1,57 @@
+package setting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+)
+
+func TestReadLiveMQTTSettings(t *testing.T) {
+	load := func(t *testing.T, content string) (*Cfg, error) {
+		t.Helper()
+		f, err := ini.Load([]byte(content))
+		require.NoError(t, err)
+		cfg := NewCfg()
+		return cfg, cfg.readLiveSettings(f)
+	}
+
+	t.Run("should be disabled by default", func(t *testing.T) {
+		cfg, err := load(t, "")
+		require.NoError(t, err)
+		require.False(t, cfg.LivePipelineEnabled)
+		require.Equal(t, LiveMQTTSettings{ListenAddress: "0.0.0.0:1883", MaxPayloadSize: 65536}, cfg.LiveMQTT)
+	})
+
+	t.Run("should read settings", func(t *testing.T) {
+		cfg, err := load(t, `
+[live]
+pipeline_enabled = true
+[live.mqtt]
+enabled = true
+listen_address = 127.0.0.1:8883
+cert_file = cert.pem
+key_file = key.pem
+max_payload_size = 1024
+`)
+		require.NoError(t, err)
+		require.True(t, cfg.LivePipelineEnabled)
+		require.Equal(t, LiveMQTTSettings{
+			Enabled:        true,
+			ListenAddress:  "127.0.0.1:8883",
+			CertFile:       "cert.pem",
+			KeyFile:        "key.pem",
+			MaxPayloadSize: 1024,
+		}, cfg.LiveMQTT)
+	})
+
+	t.Run("should require pipeline", func(t *testing.T) {
+		_, err := load(t, "[live.mqtt]\nenabled = true")
+		require.ErrorContains(t, err, "pipeline_enabled")
+	})
+
+	t.Run("should require both certificate and key", func(t *testing.T) {
+		_, err := load(t, "[live]\npipeline_enabled = true\n[live.mqtt]\nenabled = true\ncert_file = cert.pem")
+		require.ErrorContains(t, err, "cert_file and key_file")
+	})
+}
@@ -6,6 +6,9 @@ type OrgQuota struct {
 	Dashboard  int64 `target:"dashboard"`
 	ApiKey     int64 `target:"api_key"`
 	AlertRule  int64 `target:"alert_rule"`
+
+	// AlertRuleEvaluationCost limits the milliseconds spent evaluating alert rules per minute.
+	AlertRuleEvaluationCost int64 `target:"alert_rule_evaluation_cost"`
 }
 
 type UserQuota struct {
@@ -22,6 +25,9 @@ type GlobalQuota struct {
 	AlertRule    int64 `target:"alert_rule"`
 	File         int64 `target:"file"`
 	Correlations int64 `target:"correlations"`
+
+	// AlertRuleEvaluationCost limits the milliseconds spent evaluating alert rules per minute.
+	AlertRuleEvaluationCost int64 `target:"alert_rule_evaluation_cost"`
 }
 
 type QuotaSettings struct {
@@ -43,6 +49,8 @@ func (cfg *Cfg) readQuotaSettings() {
 		Dashboard:  quota.Key("org_dashboard").MustInt64(10),
 		ApiKey:     quota.Key("org_api_key").MustInt64(10),
 		AlertRule:  quota.Key("org_alert_rule").MustInt64(100),
+
+		AlertRuleEvaluationCost: quota.Key("org_alert_rule_evaluation_cost").MustInt64(-1),
 	}
 
 	// per User limits
@@ -61,5 +69,7 @@ func (cfg *Cfg) readQuotaSettings() {
 		File:         quota.Key("global_file").MustInt64(-1),
 		AlertRule:    quota.Key("global_alert_rule").MustInt64(-1),
 		Correlations: quota.Key("global_correlations").MustInt64(-1),
+
+		AlertRuleEvaluationCost: quota.Key("global_alert_rule_evaluation_cost").MustInt64(-1),
 	}
 }
@@ -681,3 +681,36 @@ func TestDynamicSection(t *testing.T) {
 		assert.Equal(t, value, ds.section.Key(key).String())
 	})
 }
+
+func TestReadLiveManagedStreamHistorySettings(t *testing.T) {
+	load := func(t *testing.T, content string) (*Cfg, error) {
+		t.Helper()
+		f, err := ini.Load([]byte(content))
+		require.NoError(t, err)
+		cfg := NewCfg()
+		return cfg, cfg.readLiveSettings(f)
+	}
+
+	t.Run("should disable history by default", func(t *testing.T) {
+		cfg, err := load(t, "")
+		require.NoError(t, err)
+		require.Zero(t, cfg.LiveManagedStreamHistorySize)
+		require.Zero(t, cfg.LiveManagedStreamHistoryDuration)
+		require.Equal(t, int64(10*1024*1024), cfg.LiveManagedStreamHistoryMaxBytesPerOrg)
+	})
+
+	t.Run("should read history settings", func(t *testing.T) {
+		cfg, err := load(t, "[live]\nmanaged_stream_history_size = 100\nmanaged_stream_history_duration = 5m\nmanaged_stream_history_max_bytes_per_org = 1024")
+		require.NoError(t, err)
+		require.Equal(t, 100, cfg.LiveManagedStreamHistorySize)
+		require.Equal(t, 5*time.Minute, cfg.LiveManagedStreamHistoryDuration)
+		require.Equal(t, int64(1024), cfg.LiveManagedStreamHistoryMaxBytesPerOrg)
+	})
+
+	t.Run("should fail on invalid values", func(t *testing.T) {
+		_, err := load(t, "[live]\nmanaged_stream_history_size = -1")
+		require.Error(t, err)
+		_, err = load(t, "[live]\nmanaged_stream_history_duration = -5m")
+		require.Error(t, err)
+	})
+}
@@ -64,7 +64,13 @@ const (
 	stateHistoryDefaultEnabled     = true
 	lokiDefaultMaxQueryLength      = 721 * time.Hour // 30d1h, matches the default value in Loki
 	defaultRecordingRequestTimeout = 10 * time.Second
-	lokiDefaultMaxQuerySize        = 65536 // 64kb
+	defaultRecordingBackend        = "prometheus"
+	defaultRecordingTable          = "grafana_recording_rules"
+	defaultRecordingMaxRetries     = 3
+	defaultRecordingRetryBackoff   = time.Second
+	defaultRecordingMaxInflight    = 10
+	lokiDefaultMaxQuerySize        = 65536           // 64kb
+	sqlHistoryDefaultMaxAge        = 720 * time.Hour // 30d
 )
 
 type UnifiedAlertingSettings struct {
@@ -91,6 +97,7 @@ type UnifiedAlertingSettings struct {
 	HARedisTLSEnabled               bool
 	HARedisTLSConfig                dstls.ClientConfig
 	MaxAttempts                     int64
+	EvaluationBudgetSlowDown        bool
 	MinInterval                     time.Duration
 	EvaluationTimeout               time.Duration
 	EvaluationResultLimit           int
@@ -124,11 +131,23 @@ type UnifiedAlertingSettings struct {
 }
 
 type RecordingRuleSettings struct {
+	// Backend is the target recording rules are written to: prometheus, live, influxdb or sql.
+	Backend           string
 	URL               string
 	BasicAuthUsername string
 	BasicAuthPassword string
 	CustomHeaders     map[string]string
 	Timeout           time.Duration
+	// DatasourceUID is the UID of the PostgreSQL or MySQL data source the sql backend writes through.
+	DatasourceUID string
+	// Table is the table the sql backend writes into.
+	Table string
+	// MaxRetries is the number of times a failed write is retried by the live, influxdb and sql backends.
+	MaxRetries int
+	// RetryBackoff is the initial delay between retries. It doubles with every retry.
+	RetryBackoff time.Duration
+	// MaxInflightWrites is the maximum number of concurrent writes. Further writes wait for a write to finish.
+	MaxInflightWrites int
 }
 
 // RemoteAlertmanagerSettings contains the configuration needed
@@ -168,6 +187,8 @@ type UnifiedAlertingStateHistorySettings struct {
 	MultiPrimary          string
 	MultiSecondaries      []string
 	ExternalLabels        map[string]string
+	// SQLMaxAge is how long the state history of the SQL backend is kept. Zero keeps it forever.
+	SQLMaxAge time.Duration
 }
 
 // IsEnabled returns true if UnifiedAlertingSettings.Enabled is either nil or true.
@@ -305,6 +326,8 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 
 	uaCfg.MaxAttempts = ua.Key("max_attempts").MustInt64(schedulerDefaultMaxAttempts)
 
+	uaCfg.EvaluationBudgetSlowDown = ua.Key("evaluation_budget_slow_down").MustBool(false)
+
 	uaCfg.BaseInterval = SchedulerBaseInterval
 
 	// TODO: This was promoted from a feature toggle and is now the default behavior.
@@ -416,15 +439,22 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		MultiPrimary:          stateHistory.Key("primary").MustString(""),
 		MultiSecondaries:      splitTrim(stateHistory.Key("secondaries").MustString(""), ","),
 		ExternalLabels:        stateHistoryLabels.KeysHash(),
+		SQLMaxAge:             stateHistory.Key("sql_max_age").MustDuration(sqlHistoryDefaultMaxAge),
 	}
 	uaCfg.StateHistory = uaCfgStateHistory
 
 	rr := iniFile.Section("recording_rules")
 	uaCfgRecordingRules := RecordingRuleSettings{
+		Backend:           rr.Key("backend").MustString(defaultRecordingBackend),
 		URL:               rr.Key("url").MustString(""),
 		BasicAuthUsername: rr.Key("basic_auth_username").MustString(""),
 		BasicAuthPassword: rr.Key("basic_auth_password").MustString(""),
 		Timeout:           rr.Key("timeout").MustDuration(defaultRecordingRequestTimeout),
+		DatasourceUID:     rr.Key("datasource_uid").MustString(""),
+		Table:             rr.Key("table").MustString(defaultRecordingTable),
+		MaxRetries:        rr.Key("max_retries").MustInt(defaultRecordingMaxRetries),
+		RetryBackoff:      rr.Key("retry_backoff").MustDuration(defaultRecordingRetryBackoff),
+		MaxInflightWrites: rr.Key("max_inflight_writes").MustInt(defaultRecordingMaxInflight),
 	}
 
 	rrHeaders := iniFile.Section("recording_rules.custom_headers")
@@ -63,6 +63,14 @@ const reducerTypes = [
   { text: 'percent_diff()', value: 'percent_diff' },
   { text: 'percent_diff_abs()', value: 'percent_diff_abs' },
   { text: 'count_non_null()', value: 'count_non_null' },
+  { text: 'first()', value: 'first' },
+  { text: 'last_non_null()', value: 'last_non_null' },
+  { text: 'range()', value: 'range' },
+  { text: 'stddev()', value: 'stddev' },
+  { text: 'p50()', value: 'p50' },
+  { text: 'p90()', value: 'p90' },
+  { text: 'p95()', value: 'p95' },
+  { text: 'p99()', value: 'p99' },
 ] as const;
 
 const noDataModes = [
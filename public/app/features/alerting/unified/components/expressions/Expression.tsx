@@ -5,7 +5,9 @@ import { useFormContext } from 'react-hook-form';
 
 import { DataFrame, dateTimeFormat, GrafanaTheme2, isTimeSeriesFrames, LoadingState, PanelData } from '@grafana/data';
 import { Alert, AutoSizeInput, Button, clearButtonStyles, IconButton, Stack, useStyles2 } from '@grafana/ui';
+import { Anomaly } from 'app/features/expressions/components/Anomaly';
 import { ClassicConditions } from 'app/features/expressions/components/ClassicConditions';
+import { Forecast } from 'app/features/expressions/components/Forecast';
 import { Math } from 'app/features/expressions/components/Math';
 import { Reduce } from 'app/features/expressions/components/Reduce';
 import { Resample } from 'app/features/expressions/components/Resample';
@@ -114,6 +116,12 @@ export const Expression: FC<ExpressionProps> = ({
         case ExpressionQueryType.sql:
           return <SqlExpr onChange={onChangeQuery} query={query} refIds={availableRefIds} />;
 
+        case ExpressionQueryType.forecast:
+          return <Forecast onChange={onChangeQuery} query={query} labelWidth={'auto'} refIds={availableRefIds} />;
+
+        case ExpressionQueryType.anomaly:
+          return <Anomaly onChange={onChangeQuery} query={query} labelWidth={'auto'} refIds={availableRefIds} />;
+
         default:
           return <>Expression not supported: {query.type}</>;
       }
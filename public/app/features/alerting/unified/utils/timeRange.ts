@@ -34,6 +34,8 @@ const getReferencedIds = (model: ExpressionQuery, queries: AlertQuery[]): string
     case ExpressionQueryType.resample:
     case ExpressionQueryType.reduce:
     case ExpressionQueryType.threshold:
+    case ExpressionQueryType.anomaly:
+    case ExpressionQueryType.forecast:
       return getReferencedIdsForReduce(model);
   }
 };
@@ -3,7 +3,9 @@ import { useCallback, useEffect, useRef } from 'react';
 import { DataSourceApi, QueryEditorProps, SelectableValue } from '@grafana/data';
 import { InlineField, Select } from '@grafana/ui';
 
+import { Anomaly } from './components/Anomaly';
 import { ClassicConditions } from './components/ClassicConditions';
+import { Forecast } from './components/Forecast';
 import { Math } from './components/Math';
 import { Reduce } from './components/Reduce';
 import { Resample } from './components/Resample';
@@ -29,6 +31,8 @@ function useExpressionsCache() {
       case ExpressionQueryType.resample:
       case ExpressionQueryType.threshold:
       case ExpressionQueryType.sql:
+      case ExpressionQueryType.anomaly:
+      case ExpressionQueryType.forecast:
         return expressionCache.current[queryType];
       case ExpressionQueryType.classic:
         return undefined;
@@ -48,6 +52,8 @@ function useExpressionsCache() {
         expressionCache.current.reduce = value;
         expressionCache.current.resample = value;
         expressionCache.current.threshold = value;
+        expressionCache.current.anomaly = value;
+        expressionCache.current.forecast = value;
         break;
       case ExpressionQueryType.sql:
         expressionCache.current.sql = value;
@@ -96,6 +102,12 @@ export function ExpressionQueryEditor(props: Props) {
 
       case ExpressionQueryType.sql:
         return <SqlExpr onChange={onChange} query={query} refIds={refIds} />;
+
+      case ExpressionQueryType.forecast:
+        return <Forecast query={query} labelWidth={labelWidth} onChange={onChange} refIds={refIds} />;
+
+      case ExpressionQueryType.anomaly:
+        return <Anomaly query={query} labelWidth={labelWidth} onChange={onChange} refIds={refIds} />;
     }
   };
 
@@ -0,0 +1,77 @@
+import { ChangeEvent } from 'react';
+
+import { SelectableValue } from '@grafana/data';
+import { InlineField, InlineFieldRow, Input, Select } from '@grafana/ui';
+
+import { anomalyMethods, anomalyOutputs, ExpressionQuery } from '../types';
+
+interface Props {
+  refIds: Array<SelectableValue<string>>;
+  query: ExpressionQuery;
+  labelWidth?: number | 'auto';
+  onChange: (query: ExpressionQuery) => void;
+}
+
+export const Anomaly = ({ labelWidth = 'auto', onChange, refIds, query }: Props) => {
+  const method = anomalyMethods.find((o) => o.value === query.method);
+  const output = anomalyOutputs.find((o) => o.value === (query.output || 'all'));
+
+  const onRefIdChange = (value: SelectableValue<string>) => {
+    onChange({ ...query, expression: value.value });
+  };
+
+  const onSelectMethod = (value: SelectableValue<string>) => {
+    onChange({ ...query, method: value.value });
+  };
+
+  const onDeviationsChange = (event: ChangeEvent<HTMLInputElement>) => {
+    const deviations = parseFloat(event.target.value);
+    onChange({ ...query, deviations: isNaN(deviations) ? undefined : deviations });
+  };
+
+  const onWindowChange = (event: ChangeEvent<HTMLInputElement>) => {
+    onChange({ ...query, window: event.target.value });
+  };
+
+  const onSeasonChange = (event: ChangeEvent<HTMLInputElement>) => {
+    onChange({ ...query, season: event.target.value });
+  };
+
+  const onSelectOutput = (value: SelectableValue<string>) => {
+    onChange({ ...query, output: value.value });
+  };
+
+  return (
+    <>
+      <InlineFieldRow>
+        <InlineField label="Input" labelWidth={labelWidth}>
+          <Select onChange={onRefIdChange} options={refIds} value={query.expression} width={20} />
+        </InlineField>
+        <InlineField label="Method">
+          <Select options={anomalyMethods} value={method} onChange={onSelectMethod} width={20} />
+        </InlineField>
+        <InlineField label="Deviations" tooltip="Width of the band in standard deviations, defaults to 3">
+          <Input type="number" onChange={onDeviationsChange} value={query.deviations} placeholder="3" width={10} />
+        </InlineField>
+      </InlineFieldRow>
+      <InlineFieldRow>
+        {query.method === 'seasonal' ? (
+          <InlineField label="Season" labelWidth={labelWidth} tooltip="1d, 1w">
+            <Input onChange={onSeasonChange} value={query.season} width={15} />
+          </InlineField>
+        ) : (
+          <InlineField
+            label="Window"
+            labelWidth={labelWidth}
+            tooltip="Only compare each point to the points in this duration before it. Leave empty to use the whole series."
+          >
+            <Input onChange={onWindowChange} value={query.window} placeholder="1h" width={15} />
+          </InlineField>
+        )}
+        <InlineField label="Output">
+          <Select options={anomalyOutputs} value={output} onChange={onSelectOutput} width={20} />
+        </InlineField>
+      </InlineFieldRow>
+    </>
+  );
+};
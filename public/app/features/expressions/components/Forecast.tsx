@@ -0,0 +1,113 @@
+import { ChangeEvent } from 'react';
+
+import { SelectableValue } from '@grafana/data';
+import { InlineField, InlineFieldRow, Input, Select } from '@grafana/ui';
+
+import { ExpressionQuery, ExpressionQuerySettings, forecastMethods, forecastOutputs } from '../types';
+
+interface Props {
+  refIds: Array<SelectableValue<string>>;
+  query: ExpressionQuery;
+  labelWidth?: number | 'auto';
+  onChange: (query: ExpressionQuery) => void;
+}
+
+const parseNumber = (value: string) => {
+  const number = parseFloat(value);
+  return isNaN(number) ? undefined : number;
+};
+
+export const Forecast = ({ labelWidth = 'auto', onChange, refIds, query }: Props) => {
+  const method = forecastMethods.find((o) => o.value === query.method);
+  const output = forecastOutputs.find((o) => o.value === (query.output || 'value'));
+
+  const onRefIdChange = (value: SelectableValue<string>) => {
+    onChange({ ...query, expression: value.value });
+  };
+
+  const onSelectMethod = (value: SelectableValue<string>) => {
+    onChange({ ...query, method: value.value });
+  };
+
+  const onHorizonChange = (event: ChangeEvent<HTMLInputElement>) => {
+    onChange({ ...query, horizon: event.target.value });
+  };
+
+  const onSelectOutput = (value: SelectableValue<string>) => {
+    onChange({ ...query, output: value.value });
+  };
+
+  const onThresholdChange = (event: ChangeEvent<HTMLInputElement>) => {
+    onChange({ ...query, threshold: parseNumber(event.target.value) });
+  };
+
+  const onSettingsChange = (settings: ExpressionQuerySettings) => {
+    onChange({ ...query, settings: { ...query.settings, ...settings } });
+  };
+
+  return (
+    <>
+      <InlineFieldRow>
+        <InlineField label="Input" labelWidth={labelWidth}>
+          <Select onChange={onRefIdChange} options={refIds} value={query.expression} width={20} />
+        </InlineField>
+        <InlineField label="Method">
+          <Select options={forecastMethods} value={method} onChange={onSelectMethod} width={20} />
+        </InlineField>
+        <InlineField label="Horizon" tooltip="How far ahead to forecast, for example 4h or 7d">
+          <Input onChange={onHorizonChange} value={query.horizon} width={10} />
+        </InlineField>
+      </InlineFieldRow>
+      <InlineFieldRow>
+        <InlineField label="Output" labelWidth={labelWidth}>
+          <Select options={forecastOutputs} value={output} onChange={onSelectOutput} width={20} />
+        </InlineField>
+        {query.output === 'time_to_threshold' && (
+          <InlineField label="Threshold">
+            <Input type="number" onChange={onThresholdChange} value={query.threshold} width={10} />
+          </InlineField>
+        )}
+      </InlineFieldRow>
+      {query.method === 'holt_winters' && (
+        <InlineFieldRow>
+          <InlineField label="Alpha" labelWidth={labelWidth} tooltip="Level smoothing factor between 0 and 1">
+            <Input
+              type="number"
+              onChange={(event) => onSettingsChange({ alpha: parseNumber(event.currentTarget.value) })}
+              value={query.settings?.alpha}
+              placeholder="0.5"
+              width={10}
+            />
+          </InlineField>
+          <InlineField label="Beta" tooltip="Trend smoothing factor between 0 and 1">
+            <Input
+              type="number"
+              onChange={(event) => onSettingsChange({ beta: parseNumber(event.currentTarget.value) })}
+              value={query.settings?.beta}
+              placeholder="0.1"
+              width={10}
+            />
+          </InlineField>
+          <InlineField label="Season" tooltip="Duration of a season, for example 1d. Leave empty for no seasonality.">
+            <Input
+              onChange={(event) => onSettingsChange({ season: event.currentTarget.value })}
+              value={query.settings?.season}
+              width={10}
+            />
+          </InlineField>
+          {query.settings?.season && (
+            <InlineField label="Gamma" tooltip="Seasonal smoothing factor between 0 and 1">
+              <Input
+                type="number"
+                onChange={(event) => onSettingsChange({ gamma: parseNumber(event.currentTarget.value) })}
+                value={query.settings?.gamma}
+                placeholder="0.1"
+                width={10}
+              />
+            </InlineField>
+          )}
+        </InlineFieldRow>
+      )}
+    </>
+  );
+};
@@ -3,7 +3,7 @@ import { ChangeEvent } from 'react';
 import { SelectableValue } from '@grafana/data';
 import { InlineField, InlineFieldRow, Input, Select } from '@grafana/ui';
 
-import { downsamplingTypes, ExpressionQuery, upsamplingTypes } from '../types';
+import { downsamplingTypes, ExpressionQuery, resampleAlignments, upsamplingTypes } from '../types';
 
 interface Props {
   refIds: Array<SelectableValue<string>>;
@@ -15,6 +15,7 @@ interface Props {
 export const Resample = ({ labelWidth = 'auto', onChange, refIds, query }: Props) => {
   const downsampler = downsamplingTypes.find((o) => o.value === query.downsampler);
   const upsampler = upsamplingTypes.find((o) => o.value === query.upsampler);
+  const alignment = resampleAlignments.find((o) => o.value === (query.alignment || 'from'));
 
   const onWindowChange = (event: ChangeEvent<HTMLInputElement>) => {
     onChange({ ...query, window: event.target.value });
@@ -32,6 +33,14 @@ export const Resample = ({ labelWidth = 'auto', onChange, refIds, query }: Props
     onChange({ ...query, upsampler: value.value });
   };
 
+  const onSelectAlignment = (value: SelectableValue<string>) => {
+    onChange({ ...query, alignment: value.value });
+  };
+
+  const onTimezoneChange = (event: ChangeEvent<HTMLInputElement>) => {
+    onChange({ ...query, timezone: event.target.value });
+  };
+
   return (
     <>
       <InlineFieldRow>
@@ -50,6 +59,16 @@ export const Resample = ({ labelWidth = 'auto', onChange, refIds, query }: Props
           <Select options={upsamplingTypes} value={upsampler} onChange={onSelectUpsampler} width={25} />
         </InlineField>
       </InlineFieldRow>
+      <InlineFieldRow>
+        <InlineField label="Align to" labelWidth={labelWidth}>
+          <Select options={resampleAlignments} value={alignment} onChange={onSelectAlignment} width={20} />
+        </InlineField>
+        {query.alignment === 'calendar' && (
+          <InlineField label="Time zone" tooltip="IANA time zone such as Europe/Berlin, defaults to UTC">
+            <Input onChange={onTimezoneChange} value={query.timezone} placeholder="UTC" width={25} />
+          </InlineField>
+        )}
+      </InlineFieldRow>
     </>
   );
 };
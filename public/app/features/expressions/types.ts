@@ -15,6 +15,8 @@ export enum ExpressionQueryType {
   classic = 'classic_conditions',
   threshold = 'threshold',
   sql = 'sql',
+  anomaly = 'anomaly',
+  forecast = 'forecast',
 }
 
 export const getExpressionLabel = (type: ExpressionQueryType) => {
@@ -31,6 +33,10 @@ export const getExpressionLabel = (type: ExpressionQueryType) => {
       return 'Threshold';
     case ExpressionQueryType.sql:
       return 'SQL';
+    case ExpressionQueryType.anomaly:
+      return 'Anomaly';
+    case ExpressionQueryType.forecast:
+      return 'Forecast';
   }
 };
 
@@ -63,6 +69,18 @@ export const expressionTypes: Array<SelectableValue<ExpressionQueryType>> = [
     description:
       'Takes one or more time series returned from a query or an expression and checks if any of the series match the threshold condition.',
   },
+  {
+    value: ExpressionQueryType.forecast,
+    label: 'Forecast',
+    description:
+      'Extrapolates each time series and returns the predicted value at the horizon or the time until a threshold is reached.',
+  },
+  {
+    value: ExpressionQueryType.anomaly,
+    label: 'Anomaly',
+    description:
+      'Flags the points of each time series that are outside the band of values expected from the rest of the series.',
+  },
   {
     value: ExpressionQueryType.sql,
     label: 'SQL',
@@ -82,6 +100,15 @@ export const reducerTypes: Array<SelectableValue<string>> = [
   { value: ReducerID.sum, label: 'Sum', description: 'Get the sum of all values' },
   { value: ReducerID.count, label: 'Count', description: 'Get the number of values' },
   { value: ReducerID.last, label: 'Last', description: 'Get the last value' },
+  { value: 'last_non_null', label: 'Last *', description: 'Get the last non-null value' },
+  { value: ReducerID.first, label: 'First', description: 'Get the first value' },
+  { value: 'count_non_null', label: 'Count non-null', description: 'Get the number of non-null values' },
+  { value: 'median', label: 'Median', description: 'Get the median value' },
+  { value: ReducerID.p90, label: '90th %', description: 'Get the 90th percentile value' },
+  { value: ReducerID.p95, label: '95th %', description: 'Get the 95th percentile value' },
+  { value: ReducerID.p99, label: '99th %', description: 'Get the 99th percentile value' },
+  { value: 'stddev', label: 'StdDev', description: 'Get the standard deviation of all values' },
+  { value: ReducerID.range, label: 'Range', description: 'Get the difference between the maximum and minimum value' },
 ];
 
 export enum ReducerMode {
@@ -120,6 +147,42 @@ export const upsamplingTypes: Array<SelectableValue<string>> = [
   { value: 'pad', label: 'pad', description: 'fill with the last known value' },
   { value: 'backfilling', label: 'backfilling', description: 'fill with the next known value' },
   { value: 'fillna', label: 'fillna', description: 'Fill with NaNs' },
+  { value: 'linear', label: 'linear', description: 'Interpolate between the last and the next known value' },
+  { value: 'nearest', label: 'nearest', description: 'Fill with the closest known value' },
+];
+
+export const resampleAlignments: Array<SelectableValue<string>> = [
+  { value: 'from', label: 'Time range', description: 'Align to the start of the time range' },
+  { value: 'calendar', label: 'Calendar', description: 'Align to minute, hour or day boundaries' },
+];
+
+export const forecastMethods: Array<SelectableValue<string>> = [
+  { value: 'linear', label: 'Linear', description: 'Fit a straight line with least squares' },
+  {
+    value: 'holt_winters',
+    label: 'Holt-Winters',
+    description: 'Exponential smoothing of level, trend and an optional season',
+  },
+];
+
+export const forecastOutputs: Array<SelectableValue<string>> = [
+  { value: 'value', label: 'Value at horizon', description: 'The predicted value at the end of the horizon' },
+  {
+    value: 'time_to_threshold',
+    label: 'Time to threshold',
+    description: 'The number of seconds until the predicted value reaches the threshold',
+  },
+];
+
+export const anomalyMethods: Array<SelectableValue<string>> = [
+  { value: 'zscore', label: 'Z-score', description: 'Band around the mean, in standard deviations' },
+  { value: 'mad', label: 'MAD', description: 'Band around the median, robust against outliers' },
+  { value: 'seasonal', label: 'Seasonal', description: 'Band around the value one season earlier' },
+];
+
+export const anomalyOutputs: Array<SelectableValue<string>> = [
+  { value: 'all', label: 'Flag and band', description: 'Return the anomaly flag and the upper and lower band' },
+  { value: 'flag', label: 'Flag', description: 'Return only the anomaly flag' },
 ];
 
 export const thresholdFunctions: Array<SelectableValue<EvalFunction>> = [
@@ -140,6 +203,14 @@ export interface ExpressionQuery extends DataQuery {
   window?: string;
   downsampler?: string;
   upsampler?: string;
+  alignment?: string;
+  timezone?: string;
+  method?: string;
+  deviations?: number;
+  season?: string;
+  output?: string;
+  horizon?: string;
+  threshold?: number;
   conditions?: ClassicCondition[];
   settings?: ExpressionQuerySettings;
 }
@@ -150,6 +221,10 @@ export interface ThresholdExpressionQuery extends ExpressionQuery {
 export interface ExpressionQuerySettings {
   mode?: ReducerMode;
   replaceWithValue?: number;
+  alpha?: number;
+  beta?: number;
+  gamma?: number;
+  season?: string;
 }
 
 export interface ClassicCondition {
@@ -186,4 +261,12 @@ export type ReducerType =
   | 'diff_abs'
   | 'percent_diff'
   | 'percent_diff_abs'
-  | 'count_non_null';
+  | 'count_non_null'
+  | 'first'
+  | 'last_non_null'
+  | 'range'
+  | 'stddev'
+  | 'p50'
+  | 'p90'
+  | 'p95'
+  | 'p99';
@@ -35,6 +35,26 @@ export const getDefaults = (query: ExpressionQuery) => {
 
       break;
 
+    case ExpressionQueryType.forecast:
+      if (!query.method) {
+        query.method = 'linear';
+      }
+
+      if (!query.horizon) {
+        query.horizon = '1h';
+      }
+
+      query.reducer = undefined;
+      break;
+
+    case ExpressionQueryType.anomaly:
+      if (!query.method) {
+        query.method = 'zscore';
+      }
+
+      query.reducer = undefined;
+      break;
+
     default:
       query.reducer = undefined;
   }